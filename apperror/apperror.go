@@ -0,0 +1,368 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Kind mengelompokkan error domain, dipakai handler untuk menentukan HTTP status
+type Kind string
+
+const (
+	KindNotFound          Kind = "not_found"
+	KindConflict          Kind = "conflict"
+	KindForeignKey        Kind = "foreign_key_violation"
+	KindInsufficientStock Kind = "insufficient_stock"
+	KindValidation        Kind = "validation_error"
+	KindPrecondition      Kind = "precondition_failed"
+	KindPreconditionReq   Kind = "precondition_required"
+	KindUnauthorized      Kind = "unauthorized"
+	KindForbidden         Kind = "forbidden"
+	KindTooManyRequests   Kind = "too_many_requests"
+	KindInternal          Kind = "internal_error"
+)
+
+// Error adalah error domain yang dikembalikan repository dan service.
+// Code adalah kode machine-readable untuk client (misal "item_not_found").
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Details any
+	Err     error
+	// RetryAfter diisi untuk KindTooManyRequests (header Retry-After)
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFound dipakai saat entity dengan id tertentu tidak ada
+func NotFound(entity string) *Error {
+	return &Error{
+		Kind:    KindNotFound,
+		Code:    entity + "_not_found",
+		Message: entity + " not found",
+	}
+}
+
+// Conflict dipakai untuk pelanggaran unique constraint (SKU, email, ...)
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// ForeignKeyViolation dipakai saat data masih direferensikan atau referensinya tidak ada
+func ForeignKeyViolation(code, message string) *Error {
+	return &Error{Kind: KindForeignKey, Code: code, Message: message}
+}
+
+// InsufficientStock dipakai saat stock tidak cukup untuk transaksi
+func InsufficientStock(message string, details any) *Error {
+	return &Error{Kind: KindInsufficientStock, Code: "insufficient_stock", Message: message, Details: details}
+}
+
+// Validation dipakai untuk input yang tidak valid secara bisnis
+func Validation(message string, details any) *Error {
+	return &Error{Kind: KindValidation, Code: "validation_error", Message: message, Details: details}
+}
+
+// PreconditionFailed dipakai saat versi (If-Match) tidak sama dengan versi terbaru di database
+func PreconditionFailed(entity string, currentVersion int) *Error {
+	return &Error{
+		Kind:    KindPrecondition,
+		Code:    entity + "_version_mismatch",
+		Message: entity + " has been modified by another request",
+		Details: map[string]int{"current_version": currentVersion},
+	}
+}
+
+// PreconditionRequired dipakai saat update tidak menyertakan header If-Match
+func PreconditionRequired() *Error {
+	return &Error{
+		Kind:    KindPreconditionReq,
+		Code:    "precondition_required",
+		Message: "If-Match header is required",
+	}
+}
+
+// Unauthorized dipakai saat caller tidak terautentikasi (token tidak ada, salah, atau kedaluwarsa)
+func Unauthorized(message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: "unauthorized", Message: message}
+}
+
+// Forbidden dipakai saat caller terautentikasi tetapi role-nya tidak diizinkan
+func Forbidden(message string) *Error {
+	return &Error{Kind: KindForbidden, Code: "forbidden", Message: message}
+}
+
+// TwoFactorRequired dipakai saat role wajib 2FA tetapi user belum enroll
+func TwoFactorRequired() *Error {
+	return &Error{
+		Kind:    KindForbidden,
+		Code:    "two_factor_required",
+		Message: "two-factor authentication must be enabled for this role",
+	}
+}
+
+// IdempotencyKeyReused dipakai saat Idempotency-Key dipakai ulang untuk request yang berbeda
+func IdempotencyKeyReused() *Error {
+	return &Error{
+		Kind:    KindValidation,
+		Code:    "idempotency_key_reused",
+		Message: "Idempotency-Key has already been used with a different request",
+	}
+}
+
+// CreditLimitExceeded dipakai saat penjualan kredit melebihi sisa credit limit customer
+func CreditLimitExceeded(details any) *Error {
+	return &Error{
+		Kind:    KindConflict,
+		Code:    "credit_limit_exceeded",
+		Message: "credit limit exceeded for this customer",
+		Details: details,
+	}
+}
+
+// SaleAlreadyPaid dipakai saat pembayaran dicatat untuk sale yang sudah lunas
+func SaleAlreadyPaid() *Error {
+	return &Error{Kind: KindConflict, Code: "sale_already_paid", Message: "sale is already fully paid"}
+}
+
+// SaleVoided dipakai saat sale yang sudah dibatalkan akan diubah, dibayar atau di-void lagi
+func SaleVoided() *Error {
+	return &Error{Kind: KindConflict, Code: "sale_voided", Message: "sale has been voided"}
+}
+
+// InvalidSaleStatus dipakai saat aksi hanya berlaku untuk status sale tertentu
+// (misal hard delete hanya untuk draft)
+func InvalidSaleStatus(status, expected string) *Error {
+	return &Error{
+		Kind:    KindConflict,
+		Code:    "invalid_sale_status",
+		Message: fmt.Sprintf("sale is %s, expected %s", status, expected),
+		Details: map[string]string{"status": status, "expected": expected},
+	}
+}
+
+// PaymentExceedsBalance dipakai saat tender non-tunai melebihi sisa tagihan
+// (kembalian hanya bisa diberikan untuk cash)
+func PaymentExceedsBalance(details any) *Error {
+	return &Error{
+		Kind:    KindValidation,
+		Code:    "payment_exceeds_balance",
+		Message: "payment exceeds the balance due",
+		Details: details,
+	}
+}
+
+// ReservationNotActive dipakai saat reservasi yang sudah confirmed, released
+// atau expired akan di-confirm / di-release lagi
+func ReservationNotActive(status string) *Error {
+	return &Error{
+		Kind:    KindConflict,
+		Code:    "reservation_not_active",
+		Message: fmt.Sprintf("reservation is %s", status),
+		Details: map[string]string{"status": status},
+	}
+}
+
+// ReservationExpired dipakai saat reservasi melewati expires_at sebelum di-confirm
+// (stock-nya dilepas oleh sweeper)
+func ReservationExpired() *Error {
+	return &Error{Kind: KindConflict, Code: "reservation_expired", Message: "reservation has expired"}
+}
+
+// TooManyRequests dipakai saat request ditolak sementara (akun terkunci, terlalu banyak percobaan).
+// retryAfter dibulatkan ke atas ke detik.
+func TooManyRequests(code, message string, retryAfter time.Duration) *Error {
+	seconds := int((retryAfter + time.Second - 1) / time.Second)
+	return &Error{
+		Kind:       KindTooManyRequests,
+		Code:       code,
+		Message:    message,
+		Details:    map[string]int{"retry_after_seconds": seconds},
+		RetryAfter: time.Duration(seconds) * time.Second,
+	}
+}
+
+// Postgres error codes yang dipetakan ke error domain
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgInvalidTextRepr     = "22P02"
+)
+
+// saleVoidedConstraint dipakai trigger di database yang menolak perubahan sale voided
+const saleVoidedConstraint = "sales_voided_immutable"
+
+// reservedWithinStockConstraint menolak stock yang lebih kecil dari jumlah yang dipesan
+const reservedWithinStockConstraint = "items_reserved_within_stock"
+
+// IsNoRows true untuk pgx.ErrNoRows maupun sql.ErrNoRows
+func IsNoRows(err error) bool {
+	return errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows)
+}
+
+// FromDB memetakan error dari pgx/pgconn ke error domain untuk entity tertentu.
+// Error lain dikembalikan apa adanya.
+func FromDB(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+
+	if IsNoRows(err) {
+		notFound := NotFound(entity)
+		notFound.Err = err
+		return notFound
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return &Error{
+			Kind:    KindConflict,
+			Code:    entity + "_already_exists",
+			Message: fmt.Sprintf("%s already exists", entity),
+			Details: constraintDetails(pgErr),
+			Err:     err,
+		}
+	case pgForeignKeyViolation:
+		message := fmt.Sprintf("%s references data that does not exist", entity)
+		if strings.Contains(pgErr.Detail, "is still referenced") {
+			// DELETE/UPDATE pada baris yang masih dipakai tabel lain (misal category yang masih punya items)
+			message = fmt.Sprintf("%s is still in use", entity)
+		}
+		return &Error{
+			Kind:    KindForeignKey,
+			Code:    entity + "_foreign_key_violation",
+			Message: message,
+			Details: constraintDetails(pgErr),
+			Err:     err,
+		}
+	case pgNotNullViolation, pgCheckViolation, pgInvalidTextRepr:
+		if pgErr.ConstraintName == saleVoidedConstraint {
+			voided := SaleVoided()
+			voided.Err = err
+			return voided
+		}
+		if pgErr.ConstraintName == reservedWithinStockConstraint {
+			insufficient := InsufficientStock("stock cannot be lower than the reserved quantity", constraintDetails(pgErr))
+			insufficient.Err = err
+			return insufficient
+		}
+		return &Error{
+			Kind:    KindValidation,
+			Code:    "validation_error",
+			Message: pgErr.Message,
+			Details: constraintDetails(pgErr),
+			Err:     err,
+		}
+	}
+
+	return err
+}
+
+func constraintDetails(pgErr *pgconn.PgError) map[string]string {
+	details := map[string]string{}
+	if pgErr.ConstraintName != "" {
+		details["constraint"] = pgErr.ConstraintName
+	}
+	if pgErr.ColumnName != "" {
+		details["column"] = pgErr.ColumnName
+	}
+	if pgErr.Detail != "" {
+		details["detail"] = pgErr.Detail
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return details
+}
+
+// Is memeriksa apakah err adalah error domain dengan kind tertentu
+func Is(err error, kind Kind) bool {
+	var appErr *Error
+	return errors.As(err, &appErr) && appErr.Kind == kind
+}
+
+func IsNotFound(err error) bool {
+	return Is(err, KindNotFound)
+}
+
+// HTTPStatus mengembalikan status code untuk err (500 untuk error non-domain)
+func HTTPStatus(err error) int {
+	var appErr *Error
+	if !errors.As(err, &appErr) {
+		return http.StatusInternalServerError
+	}
+
+	switch appErr.Kind {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict, KindForeignKey, KindInsufficientStock:
+		return http.StatusConflict
+	case KindValidation:
+		return http.StatusUnprocessableEntity
+	case KindPrecondition:
+		return http.StatusPreconditionFailed
+	case KindPreconditionReq:
+		return http.StatusPreconditionRequired
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
+
+// Code mengembalikan kode machine-readable untuk err
+func Code(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Code != "" {
+		return appErr.Code
+	}
+	return string(KindInternal)
+}
+
+// Details mengembalikan detail tambahan dari error domain (jika ada)
+func Details(err error) any {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Details
+	}
+	return nil
+}
+
+// RetryAfter mengembalikan durasi tunggu dari error domain (0 jika tidak ada)
+func RetryAfter(err error) time.Duration {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.RetryAfter
+	}
+	return 0
+}
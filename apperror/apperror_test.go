@@ -0,0 +1,121 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromDB_NoRows(t *testing.T) {
+	for _, noRows := range []error{pgx.ErrNoRows, sql.ErrNoRows, fmt.Errorf("scan: %w", pgx.ErrNoRows)} {
+		err := FromDB(noRows, "item")
+
+		assert.True(t, IsNotFound(err))
+		assert.Equal(t, "item not found", err.Error())
+		assert.Equal(t, "item_not_found", Code(err))
+		assert.Equal(t, http.StatusNotFound, HTTPStatus(err))
+	}
+}
+
+func TestFromDB_UniqueViolation(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: "23505", ConstraintName: "items_sku_key", Detail: "Key (sku)=(SKU-1) already exists."}
+
+	err := FromDB(pgErr, "item")
+
+	assert.True(t, Is(err, KindConflict))
+	assert.Equal(t, "item_already_exists", Code(err))
+	assert.Equal(t, http.StatusConflict, HTTPStatus(err))
+	assert.Equal(t, "items_sku_key", Details(err).(map[string]string)["constraint"])
+	assert.True(t, errors.Is(err, pgErr))
+}
+
+func TestFromDB_ForeignKeyViolation(t *testing.T) {
+	stillReferenced := &pgconn.PgError{
+		Code:           "23503",
+		ConstraintName: "items_category_id_fkey",
+		Detail:         `Key (id)=(1) is still referenced from table "items".`,
+	}
+	missingParent := &pgconn.PgError{
+		Code:           "23503",
+		ConstraintName: "items_category_id_fkey",
+		Detail:         `Key (category_id)=(99) is not present in table "categories".`,
+	}
+
+	err := FromDB(stillReferenced, "category")
+	assert.True(t, Is(err, KindForeignKey))
+	assert.Equal(t, "category is still in use", err.Error())
+	assert.Equal(t, http.StatusConflict, HTTPStatus(err))
+
+	err = FromDB(missingParent, "item")
+	assert.True(t, Is(err, KindForeignKey))
+	assert.Equal(t, "item references data that does not exist", err.Error())
+}
+
+func TestFromDB_SaleVoidedTrigger(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: "23514", ConstraintName: "sales_voided_immutable", Message: "sale 5 is voided and cannot be modified"}
+
+	err := FromDB(pgErr, "payment")
+
+	assert.Equal(t, "sale_voided", Code(err))
+	assert.Equal(t, http.StatusConflict, HTTPStatus(err))
+	assert.True(t, errors.Is(err, pgErr))
+
+	check := FromDB(&pgconn.PgError{Code: "23514", ConstraintName: "payments_amount_check"}, "payment")
+	assert.Equal(t, "validation_error", Code(check))
+}
+
+func TestFromDB_ReservedWithinStock(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: "23514", ConstraintName: "items_reserved_within_stock", Message: "new row violates check constraint"}
+
+	err := FromDB(pgErr, "item")
+
+	assert.Equal(t, "insufficient_stock", Code(err))
+	assert.Equal(t, http.StatusConflict, HTTPStatus(err))
+	assert.True(t, errors.Is(err, pgErr))
+}
+
+func TestFromDB_PassesThroughOtherErrors(t *testing.T) {
+	plain := errors.New("connection refused")
+	assert.Same(t, plain, FromDB(plain, "item"))
+	assert.Equal(t, http.StatusInternalServerError, HTTPStatus(plain))
+	assert.Equal(t, "internal_error", Code(plain))
+
+	domain := InsufficientStock("insufficient stock", nil)
+	assert.Same(t, domain, FromDB(domain, "sale"))
+	assert.Nil(t, FromDB(nil, "item"))
+}
+
+func TestHTTPStatus_Kinds(t *testing.T) {
+	assert.Equal(t, http.StatusConflict, HTTPStatus(InsufficientStock("insufficient stock", nil)))
+	assert.Equal(t, http.StatusUnprocessableEntity, HTTPStatus(Validation("quantity must be greater than 0", nil)))
+	assert.Equal(t, http.StatusConflict, HTTPStatus(fmt.Errorf("wrapped: %w", Conflict("user_already_exists", "user already exists"))))
+	assert.Equal(t, http.StatusUnauthorized, HTTPStatus(Unauthorized("invalid or expired token")))
+	assert.Equal(t, http.StatusForbidden, HTTPStatus(Forbidden("insufficient role")))
+}
+
+func TestTooManyRequests_RoundsRetryAfterUp(t *testing.T) {
+	err := TooManyRequests("account_locked", "account is temporarily locked", 90*time.Second+time.Millisecond)
+
+	assert.Equal(t, http.StatusTooManyRequests, HTTPStatus(err))
+	assert.Equal(t, "account_locked", Code(err))
+	assert.Equal(t, 91*time.Second, RetryAfter(err))
+	assert.Equal(t, 91, Details(err).(map[string]int)["retry_after_seconds"])
+	assert.Zero(t, RetryAfter(NotFound("user")))
+}
+
+func TestPrecondition(t *testing.T) {
+	err := PreconditionFailed("item", 4)
+	assert.Equal(t, http.StatusPreconditionFailed, HTTPStatus(err))
+	assert.Equal(t, "item_version_mismatch", Code(err))
+	assert.Equal(t, 4, Details(err).(map[string]int)["current_version"])
+
+	assert.Equal(t, http.StatusPreconditionRequired, HTTPStatus(PreconditionRequired()))
+	assert.Equal(t, "precondition_required", Code(PreconditionRequired()))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type PgxIface interface {
@@ -16,20 +17,38 @@ type PgxIface interface {
 	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
-func InitDB(config utils.Configuration) (PgxIface, error) {
+var _ PgxIface = (*pgxpool.Pool)(nil)
+
+// InitDB membuka connection pool; satu *pgx.Conn tidak aman dipakai bersamaan
+// oleh request handler dan background job (sweeper hold/reservasi, purge).
+// Ukuran pool diatur lewat DATABASE_MAX_CONN (0 = default pgxpool).
+func InitDB(config utils.Configuration) (*pgxpool.Pool, error) {
 	connStr := fmt.Sprintf("user=%s password=%s dbname=%s sslmode=disable host=%s port=%d",
 		config.DB.Username, config.DB.Password, config.DB.Name, config.DB.Host, config.DB.Port)
 
-	conn, err := pgx.Connect(context.Background(), connStr)
-	
+	poolConfig, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, err
+	}
+	if config.DB.MaxConn > 0 {
+		poolConfig.MaxConns = config.DB.MaxConn
+	}
+	// trace setiap SQL statement (noop jika tracing tidak aktif)
+	poolConfig.ConnConfig.Tracer = NewQueryTracer()
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		fmt.Printf("Gagal terhubung ke database: %s\n", err)
+		return nil, err
+	}
+
 	//test connection
-	ping := conn.Ping(context.Background())
+	ping := pool.Ping(context.Background())
 	if ping != nil {
+		pool.Close()
 		fmt.Printf("Gagal terhubung ke database: %s\n", ping)
 		return nil, ping
 	}
 	fmt.Println("Berhasil terhubung ke database")
-	return conn, err
-	
-
-}
\ No newline at end of file
+	return pool, nil
+}
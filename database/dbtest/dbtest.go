@@ -0,0 +1,100 @@
+// Package dbtest membuka connection pool ke Postgres sungguhan untuk
+// integration test (invoice bernomor paralel, reservasi tanpa oversell).
+//
+// Test di-skip jika TEST_DATABASE_URL kosong. Database-nya harus database
+// khusus test yang sudah berisi backup_1.sql dan semua migration; data yang
+// dibuat test tidak dihapus.
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MaxConns adalah ukuran pool test, cukup besar supaya goroutine paralel
+// benar-benar jalan di koneksi berbeda
+const MaxConns = 20
+
+// Open mengembalikan pool ke TEST_DATABASE_URL; ditutup otomatis di akhir test
+func Open(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL tidak di-set, integration test dilewati")
+	}
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("parse TEST_DATABASE_URL: %v", err)
+	}
+	config.MaxConns = MaxConns
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("connect test database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	if err := pool.Ping(context.Background()); err != nil {
+		t.Fatalf("ping test database: %v", err)
+	}
+	return pool
+}
+
+// Fixture adalah data minimal untuk membuat sale/reservasi
+type Fixture struct {
+	UserId      int
+	WarehouseId int
+	ItemId      int
+}
+
+var seq atomic.Int64
+
+// Seed membuat user, warehouse, rack, category dan satu item dengan stock
+// tertentu. Nama dibuat unik supaya test bisa dijalankan berulang di database
+// yang sama, dan warehouse baru berarti seri invoice per gudang selalu mulai dari 1.
+func Seed(t testing.TB, pool *pgxpool.Pool, stock int) Fixture {
+	t.Helper()
+	ctx := context.Background()
+	suffix := fmt.Sprintf("%d-%d", time.Now().UnixNano(), seq.Add(1))
+
+	var f Fixture
+	var rackId, categoryId int
+	steps := []struct {
+		query string
+		args  []any
+		dest  *int
+	}{
+		{`INSERT INTO users (username, email, password, role) VALUES ($1, $2, 'x', 'staff') RETURNING id`,
+			[]any{"test-" + suffix, "test-" + suffix + "@example.com"}, &f.UserId},
+		{`INSERT INTO warehouses (name, location) VALUES ($1, 'test') RETURNING id`,
+			[]any{"test-" + suffix}, &f.WarehouseId},
+		{`INSERT INTO racks (warehouse_id, name) VALUES ($1, $2) RETURNING id`,
+			[]any{&f.WarehouseId, "test-" + suffix}, &rackId},
+		{`INSERT INTO categories (name) VALUES ($1) RETURNING id`,
+			[]any{"test-" + suffix}, &categoryId},
+		{`INSERT INTO items (category_id, rack_id, name, sku, stock, price) VALUES ($1, $2, $3, $4, $5, 10) RETURNING id`,
+			[]any{&categoryId, &rackId, "test-" + suffix, "T-" + suffix, stock}, &f.ItemId},
+	}
+	for _, step := range steps {
+		if err := pool.QueryRow(ctx, step.query, step.args...).Scan(step.dest); err != nil {
+			t.Fatalf("seed test data: %v", err)
+		}
+	}
+	return f
+}
+
+// Stock membaca stock dan reserved item langsung dari tabel
+func Stock(t testing.TB, pool *pgxpool.Pool, itemId int) (stock, reserved int) {
+	t.Helper()
+	err := pool.QueryRow(context.Background(), `SELECT stock, reserved FROM items WHERE id = $1`, itemId).Scan(&stock, &reserved)
+	if err != nil {
+		t.Fatalf("read stock: %v", err)
+	}
+	return stock, reserved
+}
@@ -0,0 +1,7 @@
+-- Optimistic concurrency: setiap UPDATE menaikkan version dan
+-- client wajib mengirim versi terakhir lewat header If-Match.
+ALTER TABLE public.items      ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE public.categories ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE public.racks      ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE public.warehouses ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE public.users      ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
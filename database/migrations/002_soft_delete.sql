@@ -0,0 +1,31 @@
+-- Soft delete untuk master data: DELETE mengisi deleted_at, baris tetap ada
+-- sampai dibersihkan oleh purge job setelah masa retensi.
+ALTER TABLE public.items      ADD COLUMN IF NOT EXISTS deleted_at timestamp without time zone;
+ALTER TABLE public.categories ADD COLUMN IF NOT EXISTS deleted_at timestamp without time zone;
+ALTER TABLE public.racks      ADD COLUMN IF NOT EXISTS deleted_at timestamp without time zone;
+ALTER TABLE public.warehouses ADD COLUMN IF NOT EXISTS deleted_at timestamp without time zone;
+ALTER TABLE public.users      ADD COLUMN IF NOT EXISTS deleted_at timestamp without time zone;
+
+-- SKU / email / username boleh dipakai ulang setelah data lama dihapus:
+-- unique constraint diganti partial unique index (nama tetap sama).
+ALTER TABLE public.items DROP CONSTRAINT IF EXISTS items_sku_key;
+CREATE UNIQUE INDEX IF NOT EXISTS items_sku_key ON public.items (sku) WHERE deleted_at IS NULL;
+
+ALTER TABLE public.users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON public.users (email) WHERE deleted_at IS NULL;
+
+ALTER TABLE public.users DROP CONSTRAINT IF EXISTS users_username_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON public.users (username) WHERE deleted_at IS NULL;
+
+-- Gudang tidak lagi menghapus rak secara cascade; soft delete gudang ditolak
+-- selama masih ada rak aktif.
+ALTER TABLE public.racks DROP CONSTRAINT IF EXISTS racks_warehouse_id_fkey;
+ALTER TABLE public.racks ADD CONSTRAINT racks_warehouse_id_fkey
+    FOREIGN KEY (warehouse_id) REFERENCES public.warehouses(id) ON DELETE RESTRICT;
+
+-- Index untuk purge job
+CREATE INDEX IF NOT EXISTS items_deleted_at_idx      ON public.items (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS categories_deleted_at_idx ON public.categories (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS racks_deleted_at_idx      ON public.racks (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS warehouses_deleted_at_idx ON public.warehouses (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx      ON public.users (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,19 @@
+-- Audit trail: siapa mengubah apa. Ditulis dalam transaksi yang sama dengan perubahannya.
+CREATE TABLE IF NOT EXISTS public.audit_log (
+    id bigserial PRIMARY KEY,
+    actor_id integer,                       -- NULL untuk request tanpa login
+    actor_username character varying(50),   -- snapshot, tetap terbaca walau user di-purge
+    actor_role character varying(20),
+    action character varying(20) NOT NULL,  -- create, update, delete, restore
+    entity character varying(50) NOT NULL,  -- item, category, rack, warehouse, user, sale
+    entity_id integer NOT NULL,
+    changes jsonb NOT NULL DEFAULT '{}'::jsonb, -- {"field": {"before": ..., "after": ...}}
+    ip character varying(64),
+    request_id character varying(128),
+    created_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON public.audit_log (entity, entity_id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON public.audit_log (actor_id);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON public.audit_log (created_at);
+
@@ -0,0 +1,13 @@
+-- Token reset password yang dibuat admin. Hanya hash SHA-256 yang disimpan,
+-- token asli cuma ditampilkan sekali di response.
+CREATE TABLE IF NOT EXISTS public.password_reset_tokens (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    token_hash character(64) NOT NULL UNIQUE,
+    expired_at timestamp without time zone NOT NULL,
+    used_at timestamp without time zone,       -- terisi saat dipakai atau dibatalkan token baru
+    created_by integer REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS password_reset_tokens_user_idx ON public.password_reset_tokens (user_id);
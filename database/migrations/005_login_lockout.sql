@@ -0,0 +1,4 @@
+-- Lockout akun setelah terlalu banyak login gagal
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS failed_login_attempts integer NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS locked_until timestamp without time zone;
@@ -0,0 +1,32 @@
+-- TOTP (RFC 6238). enabled_at NULL = enrollment belum dikonfirmasi dengan kode pertama.
+-- last_used_step mencegah kode yang sama dipakai dua kali.
+CREATE TABLE IF NOT EXISTS public.user_totp (
+    user_id integer PRIMARY KEY REFERENCES public.users(id) ON DELETE CASCADE,
+    secret text NOT NULL,
+    enabled_at timestamp without time zone,
+    last_used_step bigint NOT NULL DEFAULT 0,
+    created_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Recovery code sekali pakai, hanya hash SHA-256 yang disimpan
+CREATE TABLE IF NOT EXISTS public.recovery_codes (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    code_hash character(64) NOT NULL,
+    used_at timestamp without time zone,
+    created_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_idx ON public.recovery_codes (user_id);
+
+-- Challenge login setelah password benar, ditukar dengan session lewat kode TOTP
+CREATE TABLE IF NOT EXISTS public.login_challenges (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    token_hash character(64) NOT NULL UNIQUE,
+    expired_at timestamp without time zone NOT NULL,
+    used_at timestamp without time zone,
+    created_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS login_challenges_user_idx ON public.login_challenges (user_id);
@@ -0,0 +1,19 @@
+-- API key untuk integrasi mesin (POS, sync ERP). Hanya hash SHA-256 yang disimpan;
+-- prefix dipakai untuk mengenali key di daftar tanpa menampilkan key-nya.
+-- role disalin dari user saat key dibuat: jika role user berubah, key tidak berlaku.
+CREATE TABLE IF NOT EXISTS public.api_keys (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    name character varying(100) NOT NULL,
+    prefix character varying(16) NOT NULL,
+    key_hash character(64) NOT NULL UNIQUE,
+    role character varying(50) NOT NULL,
+    scopes text[] NOT NULL DEFAULT '{}',
+    expired_at timestamp without time zone,    -- NULL = tidak kedaluwarsa
+    last_used_at timestamp without time zone,
+    revoked_at timestamp without time zone,
+    created_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_idx ON public.api_keys (user_id);
@@ -0,0 +1,19 @@
+-- Idempotency-Key untuk POST: retry dengan key yang sama mengembalikan response
+-- pertama tanpa menjalankan ulang request. scope memisahkan key antar client
+-- (api_key:<id>, user:<id> atau ip:<ip>). status_code NULL = request masih diproses.
+CREATE TABLE IF NOT EXISTS public.idempotency_keys (
+    id serial PRIMARY KEY,
+    scope character varying(100) NOT NULL,
+    idempotency_key character varying(255) NOT NULL,
+    method character varying(10) NOT NULL,
+    path text NOT NULL,
+    request_hash character(64) NOT NULL,
+    status_code integer,
+    response_headers jsonb,
+    response_body bytea,
+    created_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expired_at timestamp without time zone NOT NULL,
+    UNIQUE (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expired_idx ON public.idempotency_keys (expired_at);
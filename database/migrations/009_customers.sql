@@ -0,0 +1,27 @@
+-- Customer untuk penjualan B2B / kredit. Kolom opsional disimpan '' (bukan NULL)
+-- supaya sama dengan entity lain; tax_id unik hanya jika diisi.
+CREATE TABLE IF NOT EXISTS public.customers (
+    id serial PRIMARY KEY,
+    name character varying(150) NOT NULL,
+    email character varying(150) NOT NULL DEFAULT '',
+    phone character varying(50) NOT NULL DEFAULT '',
+    tax_id character varying(50) NOT NULL DEFAULT '',
+    address text NOT NULL DEFAULT '',
+    credit_limit numeric(15,2) NOT NULL DEFAULT 0 CHECK (credit_limit >= 0),
+    created_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version integer NOT NULL DEFAULT 1,
+    deleted_at timestamp without time zone
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS customers_tax_id_key ON public.customers (tax_id)
+    WHERE tax_id <> '' AND deleted_at IS NULL;
+
+-- customer_id opsional (penjualan tunai tanpa customer tetap boleh).
+-- payment_type credit wajib punya customer dan dibatasi credit_limit customer.
+ALTER TABLE public.sales
+    ADD COLUMN IF NOT EXISTS customer_id integer REFERENCES public.customers(id),
+    ADD COLUMN IF NOT EXISTS payment_type character varying(20) NOT NULL DEFAULT 'cash'
+        CHECK (payment_type IN ('cash', 'credit'));
+
+CREATE INDEX IF NOT EXISTS sales_customer_idx ON public.sales (customer_id);
@@ -0,0 +1,32 @@
+-- Pajak (PPN) dan diskon pada penjualan.
+-- Tarif pajak diatur per category; item boleh override (NULL = ikut category).
+-- tax_inclusive: harga jual sudah termasuk pajak.
+ALTER TABLE public.categories
+    ADD COLUMN IF NOT EXISTS tax_rate numeric(5,2) NOT NULL DEFAULT 0 CHECK (tax_rate >= 0 AND tax_rate <= 100),
+    ADD COLUMN IF NOT EXISTS tax_inclusive boolean NOT NULL DEFAULT false;
+
+ALTER TABLE public.items
+    ADD COLUMN IF NOT EXISTS tax_rate numeric(5,2) CHECK (tax_rate >= 0 AND tax_rate <= 100),
+    ADD COLUMN IF NOT EXISTS tax_inclusive boolean;
+
+-- sale_items: subtotal tetap quantity x price; discount_amount = diskon baris +
+-- bagian diskon order; total = nilai akhir baris (termasuk pajak).
+-- Tarif disalin saat transaksi supaya perubahan tarif tidak mengubah riwayat.
+ALTER TABLE public.sale_items
+    ADD COLUMN IF NOT EXISTS discount_amount numeric(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_rate numeric(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_inclusive boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS tax_amount numeric(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS total numeric(15,2);
+
+UPDATE public.sale_items SET total = subtotal WHERE total IS NULL;
+ALTER TABLE public.sale_items ALTER COLUMN total SET NOT NULL;
+
+-- sales: total_amount tetap grand total (jumlah total semua baris)
+ALTER TABLE public.sales
+    ADD COLUMN IF NOT EXISTS subtotal numeric(15,2),
+    ADD COLUMN IF NOT EXISTS discount_amount numeric(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_amount numeric(15,2) NOT NULL DEFAULT 0;
+
+UPDATE public.sales SET subtotal = total_amount WHERE subtotal IS NULL;
+ALTER TABLE public.sales ALTER COLUMN subtotal SET NOT NULL;
@@ -0,0 +1,30 @@
+-- Pembayaran sale, satu baris per tender (split tender = beberapa baris).
+-- amount adalah bagian yang mengurangi tagihan; untuk cash, tendered adalah uang
+-- yang diterima dan change_amount kembaliannya (tendered = amount + change_amount).
+CREATE TABLE IF NOT EXISTS public.payments (
+    id serial PRIMARY KEY,
+    sale_id integer NOT NULL REFERENCES public.sales(id),
+    user_id integer NOT NULL REFERENCES public.users(id),
+    method character varying(20) NOT NULL CHECK (method IN ('cash', 'card', 'qris', 'transfer')),
+    amount numeric(15,2) NOT NULL CHECK (amount > 0),
+    tendered numeric(15,2) NOT NULL,
+    change_amount numeric(15,2) NOT NULL DEFAULT 0 CHECK (change_amount >= 0),
+    reference character varying(100) NOT NULL DEFAULT '',
+    created_at timestamp without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (tendered = amount + change_amount)
+);
+
+CREATE INDEX IF NOT EXISTS payments_sale_idx ON public.payments (sale_id);
+-- rekap laci kas harian per kasir
+CREATE INDEX IF NOT EXISTS payments_created_user_idx ON public.payments (created_at, user_id);
+
+ALTER TABLE public.sales
+    ADD COLUMN IF NOT EXISTS paid_amount numeric(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS payment_status character varying(20) NOT NULL DEFAULT 'unpaid'
+        CHECK (payment_status IN ('unpaid', 'partial', 'paid'));
+
+-- Sale tunai sebelum ada pencatatan pembayaran dianggap sudah lunas di kasir
+-- (tanpa baris payments). Sale kredit tetap unpaid.
+UPDATE public.sales
+SET paid_amount = total_amount, payment_status = 'paid'
+WHERE payment_type = 'cash' AND payment_status = 'unpaid' AND paid_amount = 0;
@@ -0,0 +1,39 @@
+-- Nomor invoice berurutan tanpa celah per tahun, misal INV/2026/10/000123
+-- (atau per gudang: INV/W2/2026/10/000045 jika INVOICE_PER_WAREHOUSE=true).
+-- Counter di-increment di transaksi yang sama dengan insert sale, jadi baris
+-- counter terkunci sampai commit dan rollback ikut mengembalikan nomornya.
+CREATE TABLE IF NOT EXISTS public.invoice_counters (
+    year integer NOT NULL,
+    -- 0 = satu seri untuk semua gudang
+    warehouse_id integer NOT NULL DEFAULT 0,
+    last_no integer NOT NULL CHECK (last_no > 0),
+    PRIMARY KEY (year, warehouse_id)
+);
+
+ALTER TABLE public.sales
+    ADD COLUMN IF NOT EXISTS warehouse_id integer REFERENCES public.warehouses(id),
+    ADD COLUMN IF NOT EXISTS invoice_no character varying(50);
+
+-- Sale lama diberi nomor sesuai urutan id dalam tahunnya
+WITH numbered AS (
+    SELECT id, COALESCE(created_at, LOCALTIMESTAMP) AS created_at,
+        row_number() OVER (PARTITION BY EXTRACT(YEAR FROM COALESCE(created_at, LOCALTIMESTAMP)) ORDER BY id) AS no
+    FROM public.sales
+    WHERE invoice_no IS NULL
+)
+UPDATE public.sales s
+SET invoice_no = 'INV/' || to_char(n.created_at, 'YYYY/MM') || '/' || lpad(n.no::text, 6, '0')
+FROM numbered n
+WHERE s.id = n.id;
+
+INSERT INTO public.invoice_counters (year, warehouse_id, last_no)
+SELECT EXTRACT(YEAR FROM COALESCE(created_at, LOCALTIMESTAMP))::int, 0, COUNT(*)
+FROM public.sales
+GROUP BY 1
+ON CONFLICT (year, warehouse_id) DO NOTHING;
+
+ALTER TABLE public.sales ALTER COLUMN invoice_no SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS sales_invoice_no_key ON public.sales (invoice_no);
+-- pencarian prefix ?invoice_no=INV/2026/10
+CREATE INDEX IF NOT EXISTS sales_invoice_no_pattern_idx ON public.sales (invoice_no varchar_pattern_ops);
@@ -0,0 +1,61 @@
+-- Void sale menggantikan hard delete: sale tetap tersimpan dengan status voided,
+-- alasan dan siapa yang membatalkan. Hard delete hanya untuk draft.
+ALTER TABLE public.sales
+    ADD COLUMN IF NOT EXISTS status character varying(20) NOT NULL DEFAULT 'completed'
+        CHECK (status IN ('draft', 'completed', 'voided')),
+    ADD COLUMN IF NOT EXISTS voided_at timestamp without time zone,
+    ADD COLUMN IF NOT EXISTS voided_by integer REFERENCES public.users(id),
+    ADD COLUMN IF NOT EXISTS void_reason text;
+
+ALTER TABLE public.sales DROP CONSTRAINT IF EXISTS sales_void_check;
+ALTER TABLE public.sales ADD CONSTRAINT sales_void_check CHECK (
+    (status = 'voided') = (voided_at IS NOT NULL AND voided_by IS NOT NULL AND void_reason IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS sales_status_idx ON public.sales (status);
+
+-- Sale yang sudah void tidak boleh diubah atau dihapus, termasuk item dan
+-- pembayarannya (immutable record untuk audit keuangan)
+CREATE OR REPLACE FUNCTION public.sales_reject_voided() RETURNS trigger AS $$
+DECLARE
+    sale_id integer;
+    voided boolean;
+BEGIN
+    IF TG_TABLE_NAME = 'sales' THEN
+        sale_id := OLD.id;
+        voided := OLD.status = 'voided';
+    ELSE
+        IF TG_OP = 'INSERT' THEN
+            sale_id := NEW.sale_id;
+        ELSE
+            sale_id := OLD.sale_id;
+        END IF;
+        voided := EXISTS (SELECT 1 FROM public.sales WHERE id = sale_id AND status = 'voided');
+    END IF;
+
+    IF voided THEN
+        RAISE EXCEPTION 'sale % is voided and cannot be modified', sale_id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'sales_voided_immutable';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS sales_voided_immutable ON public.sales;
+CREATE TRIGGER sales_voided_immutable
+    BEFORE UPDATE OR DELETE ON public.sales
+    FOR EACH ROW EXECUTE FUNCTION public.sales_reject_voided();
+
+DROP TRIGGER IF EXISTS sale_items_voided_immutable ON public.sale_items;
+CREATE TRIGGER sale_items_voided_immutable
+    BEFORE INSERT OR UPDATE OR DELETE ON public.sale_items
+    FOR EACH ROW EXECUTE FUNCTION public.sales_reject_voided();
+
+DROP TRIGGER IF EXISTS payments_voided_immutable ON public.payments;
+CREATE TRIGGER payments_voided_immutable
+    BEFORE INSERT OR UPDATE OR DELETE ON public.payments
+    FOR EACH ROW EXECUTE FUNCTION public.sales_reject_voided();
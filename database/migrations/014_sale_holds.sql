@@ -0,0 +1,26 @@
+-- Lifecycle sale: draft/quote (keranjang parkir / penawaran, tidak memesan
+-- stock) -> held (stock dipesan sampai hold_expires_at) -> completed (stock
+-- dikurangi, nomor invoice diberikan) -> voided.
+ALTER TABLE public.sales DROP CONSTRAINT IF EXISTS sales_status_check;
+ALTER TABLE public.sales ADD CONSTRAINT sales_status_check
+    CHECK (status IN ('draft', 'quote', 'held', 'completed', 'voided'));
+
+ALTER TABLE public.sales ADD COLUMN IF NOT EXISTS hold_expires_at timestamp without time zone;
+
+ALTER TABLE public.sales DROP CONSTRAINT IF EXISTS sales_hold_check;
+ALTER TABLE public.sales ADD CONSTRAINT sales_hold_check
+    CHECK ((status = 'held') = (hold_expires_at IS NOT NULL));
+
+-- nomor invoice baru diambil saat sale completed, jadi draft yang dihapus
+-- tidak meninggalkan celah di seri
+ALTER TABLE public.sales ALTER COLUMN invoice_no DROP NOT NULL;
+ALTER TABLE public.sales DROP CONSTRAINT IF EXISTS sales_invoice_no_check;
+ALTER TABLE public.sales ADD CONSTRAINT sales_invoice_no_check
+    CHECK (status IN ('draft', 'quote', 'held') OR invoice_no IS NOT NULL);
+
+-- sweeper hold kedaluwarsa
+CREATE INDEX IF NOT EXISTS sales_hold_expires_idx ON public.sales (hold_expires_at) WHERE status = 'held';
+
+-- jumlah stock yang sedang dipesan oleh sale held
+ALTER TABLE public.items
+    ADD COLUMN IF NOT EXISTS reserved integer NOT NULL DEFAULT 0 CHECK (reserved >= 0);
@@ -0,0 +1,36 @@
+-- Reservasi stock untuk order online: quantity dipesan (items.reserved) tanpa
+-- mengurangi stock, lalu di-confirm (stock dikurangi), di-release atau
+-- kedaluwarsa. items.reserved = sale held + reservasi active.
+CREATE TABLE IF NOT EXISTS public.stock_reservations (
+    id serial PRIMARY KEY,
+    -- nomor order dari channel penjualan, misal nomor order marketplace
+    reference character varying(100) NOT NULL,
+    status character varying(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'confirmed', 'released', 'expired')),
+    expires_at timestamp without time zone NOT NULL,
+    created_by integer NOT NULL REFERENCES public.users(id),
+    created_at timestamp without time zone NOT NULL DEFAULT LOCALTIMESTAMP,
+    -- waktu status terakhir berubah
+    updated_at timestamp without time zone NOT NULL DEFAULT LOCALTIMESTAMP
+);
+
+-- satu reference hanya boleh punya satu reservasi active (retry order tidak memesan dua kali)
+CREATE UNIQUE INDEX IF NOT EXISTS stock_reservations_active_reference_key
+    ON public.stock_reservations (reference) WHERE status = 'active';
+-- sweeper reservasi kedaluwarsa
+CREATE INDEX IF NOT EXISTS stock_reservations_expires_idx
+    ON public.stock_reservations (expires_at) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS public.stock_reservation_items (
+    id serial PRIMARY KEY,
+    reservation_id integer NOT NULL REFERENCES public.stock_reservations(id) ON DELETE CASCADE,
+    item_id integer NOT NULL REFERENCES public.items(id),
+    quantity integer NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS stock_reservation_items_reservation_idx ON public.stock_reservation_items (reservation_id);
+
+-- stock tidak boleh turun di bawah jumlah yang dipesan (misal koreksi stock
+-- manual). NOT VALID: baris lama yang sudah terlanjur oversold tidak diperiksa.
+ALTER TABLE public.items DROP CONSTRAINT IF EXISTS items_reserved_within_stock;
+ALTER TABLE public.items ADD CONSTRAINT items_reserved_within_stock CHECK (reserved <= stock) NOT VALID;
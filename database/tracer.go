@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "project-app-inventory-restapi-golang-azwin/database"
+
+// QueryTracer implements pgx.QueryTracer and records one span per SQL
+// statement, with the statement text and affected/returned row count.
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{tracer: otel.Tracer(tracerName)}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = t.tracer.Start(ctx, spanName(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.query.text", strings.TrimSpace(data.SQL)),
+			attribute.Int("db.query.args", len(data.Args)),
+		),
+	)
+	return ctx
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	span.SetAttributes(attribute.Int64("db.response.rows", data.CommandTag.RowsAffected()))
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+}
+
+// spanName builds a low-cardinality span name from the statement keyword and
+// target table, e.g. "INSERT sale_items" or "WITH UPDATE items" for the stock CTE.
+func spanName(sql string) string {
+	fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ", ",", " ").Replace(sql))
+	if len(fields) == 0 {
+		return "SQL"
+	}
+
+	keyword := strings.ToUpper(fields[0])
+	if keyword == "WITH" {
+		for i, f := range fields {
+			switch strings.ToUpper(f) {
+			case "INSERT", "UPDATE", "DELETE":
+				return "WITH " + spanName(strings.Join(fields[i:], " "))
+			}
+		}
+		return "WITH"
+	}
+
+	// kata kunci sebelum nama tabel
+	marker := map[string]string{
+		"SELECT": "FROM",
+		"INSERT": "INTO",
+		"UPDATE": "UPDATE",
+		"DELETE": "FROM",
+	}[keyword]
+	if marker == "" {
+		return keyword
+	}
+	for i, f := range fields {
+		if strings.ToUpper(f) == marker && i+1 < len(fields) {
+			return keyword + " " + fields[i+1]
+		}
+	}
+	return keyword
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestTracer() (*QueryTracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return &QueryTracer{tracer: provider.Tracer(tracerName)}, recorder
+}
+
+func attrValue(attrs []attribute.KeyValue, key string) attribute.Value {
+	for _, a := range attrs {
+		if string(a.Key) == key {
+			return a.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestQueryTracer_RecordsSQLAndRowCount(t *testing.T) {
+	tracer, recorder := newTestTracer()
+	sql := `
+		INSERT INTO sale_items (sale_id, item_id, quantity, price, subtotal)
+		VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)
+	`
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("INSERT 0 2")})
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "INSERT sale_items", spans[0].Name())
+	assert.Contains(t, attrValue(spans[0].Attributes(), "db.query.text").AsString(), "INSERT INTO sale_items")
+	assert.Equal(t, int64(2), attrValue(spans[0].Attributes(), "db.response.rows").AsInt64())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestQueryTracer_RecordsError(t *testing.T) {
+	tracer, recorder := newTestTracer()
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT id FROM sales WHERE id = $1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "SELECT sales", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestSpanName(t *testing.T) {
+	cases := map[string]string{
+		"SELECT COUNT(*) FROM items":                                  "SELECT items",
+		"INSERT INTO sales (user_id, total_amount) VALUES ($1, $2)":   "INSERT sales",
+		"UPDATE items SET stock = $1 WHERE id = $2":                   "UPDATE items",
+		"DELETE FROM sale_items WHERE sale_id = $1":                   "DELETE sale_items",
+		"WITH updated AS (UPDATE items SET stock = stock - 1) SELECT": "WITH UPDATE items",
+		"BEGIN": "BEGIN",
+		"":      "SQL",
+	}
+	for sql, expected := range cases {
+		assert.Equal(t, expected, spanName(sql), sql)
+	}
+}
@@ -0,0 +1,38 @@
+package docs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+//go:embed ui.html
+var uiHTML []byte
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+	specErr  error
+)
+
+// ServeSpec mengirim dokumen OpenAPI (dibangun sekali lalu di-cache)
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	specOnce.Do(func() {
+		specJSON, specErr = json.MarshalIndent(Spec(), "", "  ")
+	})
+	if specErr != nil {
+		http.Error(w, "failed to build openapi document", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(specJSON)
+}
+
+// ServeUI mengirim halaman dokumentasi yang membaca /openapi.json.
+// Asset di-embed sehingga tidak butuh CDN.
+func ServeUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(uiHTML)
+}
@@ -0,0 +1,455 @@
+package docs
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+)
+
+// Document adalah root OpenAPI 3 (hanya field yang dipakai)
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem dikunci dengan method lowercase (get, post, ...)
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Route mendeskripsikan satu endpoint di router.ApiV1.
+// Request adalah tipe body (dto), Response adalah tipe field "data" pada envelope.
+type Route struct {
+	Method      string
+	Path        string
+	Tag         string
+	OperationID string
+	Summary     string
+	Query       []Parameter
+	Request     any
+	Response    any
+	Paginated   bool
+	Status      int
+	// Versioned: resource punya kolom version (ETag, If-Match, If-None-Match)
+	Versioned bool
+	// Patch: body adalah JSON Merge Patch, semua field opsional
+	Patch bool
+	// Auth: wajib login (Bearer token); Roles membatasi role yang boleh mengakses
+	Auth  bool
+	Roles []string
+	// Idempotent: menerima header Idempotency-Key (retry me-replay response pertama)
+	Idempotent bool
+	// Produces: response sukses bukan JSON envelope (misal text/plain, application/pdf)
+	Produces []string
+}
+
+var (
+	pageParam  = Parameter{Name: "page", In: "query", Description: "halaman, mulai dari 1", Schema: &Schema{Type: "integer", Format: "int32"}}
+	limitParam = Parameter{Name: "limit", In: "query", Description: "jumlah data per halaman (maks 100)", Schema: &Schema{Type: "integer", Format: "int32"}}
+
+	dateFilterDescription = "RFC3339 atau YYYY-MM-DD"
+
+	idempotencyKeyParam = Parameter{Name: "Idempotency-Key", In: "header", Description: "key unik per transaksi (maks 255 karakter); retry dengan key yang sama mengembalikan response pertama", Schema: &Schema{Type: "string"}}
+
+	includeItemsParam = Parameter{Name: "include", In: "query", Description: "items: sertakan item tiap sale (default tanpa item)", Schema: &Schema{Type: "string", Enum: []any{"items"}}}
+
+	invoiceNoParam  = Parameter{Name: "invoice_no", In: "query", Description: "prefix nomor invoice, misal INV/2026/10", Schema: &Schema{Type: "string"}}
+	saleStatusParam = Parameter{Name: "status", In: "query", Description: "filter status sale", Schema: &Schema{Type: "string", Enum: []any{"draft", "quote", "held", "completed", "voided"}}}
+
+	reservationStatusParam    = Parameter{Name: "status", In: "query", Description: "filter status reservasi", Schema: &Schema{Type: "string", Enum: []any{"active", "confirmed", "released", "expired"}}}
+	reservationReferenceParam = Parameter{Name: "reference", In: "query", Description: "nomor order dari channel penjualan (exact match)", Schema: &Schema{Type: "string"}}
+
+	receiptFormatParam = Parameter{Name: "format", In: "query", Description: "text: struk printer thermal (default), pdf: invoice A4", Schema: &Schema{Type: "string", Enum: []any{"text", "pdf"}}}
+	receiptWidthParam  = Parameter{Name: "width", In: "query", Description: "lebar struk text dalam karakter (24-80, default RECEIPT_WIDTH)", Schema: &Schema{Type: "integer", Format: "int32"}}
+
+	includeDeletedParam = Parameter{Name: "include_deleted", In: "query", Description: "sertakan data yang sudah di-soft delete (default false)", Schema: &Schema{Type: "boolean"}}
+
+	// role yang boleh mengubah data, sama dengan router.ApiV1
+	staffRoles = []string{"super_admin", "admin", "staff"}
+	adminRoles = []string{"super_admin", "admin"}
+)
+
+// Routes adalah daftar endpoint yang didokumentasikan. Setiap route baru di
+// router.ApiV1 wajib ditambahkan di sini (dicek oleh router test).
+var Routes = []Route{
+	// auth
+	{Method: http.MethodPost, Path: "/auth/login", Tag: "auth", OperationID: "Login", Summary: "Login with email and password", Request: dto.LoginRequest{}, Response: dto.LoginResponse{}},
+	{Method: http.MethodPost, Path: "/auth/login/2fa", Tag: "auth", OperationID: "VerifyTwoFactor", Summary: "Exchange a login challenge and TOTP or recovery code for a session", Request: dto.TwoFactorLoginRequest{}, Response: dto.LoginResponse{}},
+	{Method: http.MethodPost, Path: "/auth/logout", Tag: "auth", OperationID: "Logout", Summary: "Revoke the current session token", Auth: true},
+	{Method: http.MethodPost, Path: "/auth/password-reset", Tag: "auth", OperationID: "ResetPassword", Summary: "Set a new password with a reset token", Request: dto.ResetPasswordRequest{}},
+
+	// items
+	{Method: http.MethodGet, Path: "/items/low-stock", Tag: "items", OperationID: "GetLowStockItems", Summary: "Get items with available stock (stock - reserved) below threshold",
+		Query: []Parameter{{Name: "threshold", In: "query", Description: "default 5", Schema: &Schema{Type: "integer", Format: "int32"}}}, Response: dto.LowStockResponse{}},
+	{Method: http.MethodGet, Path: "/items/{id}", Tag: "items", OperationID: "GetItemsById", Summary: "Get item by id", Response: model.Items{}, Versioned: true},
+	{Method: http.MethodGet, Path: "/items", Tag: "items", OperationID: "GetAllItems", Summary: "Get all items", Query: []Parameter{pageParam, limitParam, includeDeletedParam}, Response: []model.Items{}, Paginated: true},
+	{Method: http.MethodPost, Path: "/items", Tag: "items", OperationID: "CreateItems", Summary: "Create item", Request: dto.ItemsRequest{}, Response: model.Items{}, Status: http.StatusCreated, Versioned: true, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPut, Path: "/items/{id}", Tag: "items", OperationID: "UpdateItems", Summary: "Update item", Request: dto.ItemsRequest{}, Response: model.Items{}, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodPatch, Path: "/items/{id}", Tag: "items", OperationID: "PatchItems", Summary: "Partial update item (JSON Merge Patch)", Request: dto.ItemsRequest{}, Response: model.Items{}, Patch: true, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodDelete, Path: "/items/{id}", Tag: "items", OperationID: "DeleteItems", Summary: "Soft delete item", Auth: true, Roles: adminRoles},
+	{Method: http.MethodPost, Path: "/items/{id}/restore", Tag: "items", OperationID: "RestoreItems", Summary: "Restore soft deleted item", Response: model.Items{}, Versioned: true, Auth: true, Roles: adminRoles, Idempotent: true},
+
+	// categories
+	{Method: http.MethodGet, Path: "/categories/{id}", Tag: "categories", OperationID: "GetCategoriesById", Summary: "Get category by id", Response: model.Categories{}, Versioned: true},
+	{Method: http.MethodGet, Path: "/categories", Tag: "categories", OperationID: "GetAllCategories", Summary: "Get all categories", Query: []Parameter{pageParam, limitParam, includeDeletedParam}, Response: []model.Categories{}, Paginated: true},
+	{Method: http.MethodPost, Path: "/categories", Tag: "categories", OperationID: "CreateCategories", Summary: "Create category", Request: dto.CategoriesRequest{}, Response: model.Categories{}, Status: http.StatusCreated, Versioned: true, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPut, Path: "/categories/{id}", Tag: "categories", OperationID: "UpdateCategories", Summary: "Update category", Request: dto.CategoriesRequest{}, Response: model.Categories{}, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodPatch, Path: "/categories/{id}", Tag: "categories", OperationID: "PatchCategories", Summary: "Partial update category (JSON Merge Patch)", Request: dto.CategoriesRequest{}, Response: model.Categories{}, Patch: true, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodDelete, Path: "/categories/{id}", Tag: "categories", OperationID: "DeleteCategories", Summary: "Soft delete category", Auth: true, Roles: adminRoles},
+	{Method: http.MethodPost, Path: "/categories/{id}/restore", Tag: "categories", OperationID: "RestoreCategories", Summary: "Restore soft deleted category", Response: model.Categories{}, Versioned: true, Auth: true, Roles: adminRoles, Idempotent: true},
+
+	// racks
+	{Method: http.MethodGet, Path: "/racks/{id}", Tag: "racks", OperationID: "GetRacksById", Summary: "Get rack by id", Response: model.Racks{}, Versioned: true},
+	{Method: http.MethodGet, Path: "/racks", Tag: "racks", OperationID: "GetAllRacks", Summary: "Get all racks", Query: []Parameter{pageParam, limitParam, includeDeletedParam}, Response: []model.Racks{}, Paginated: true},
+	{Method: http.MethodPost, Path: "/racks", Tag: "racks", OperationID: "CreateRacks", Summary: "Create rack", Request: dto.RacksRequest{}, Response: model.Racks{}, Status: http.StatusCreated, Versioned: true, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPut, Path: "/racks/{id}", Tag: "racks", OperationID: "UpdateRacks", Summary: "Update rack", Request: dto.RacksRequest{}, Response: model.Racks{}, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodPatch, Path: "/racks/{id}", Tag: "racks", OperationID: "PatchRacks", Summary: "Partial update rack (JSON Merge Patch)", Request: dto.RacksRequest{}, Response: model.Racks{}, Patch: true, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodDelete, Path: "/racks/{id}", Tag: "racks", OperationID: "DeleteRacks", Summary: "Soft delete rack", Auth: true, Roles: adminRoles},
+	{Method: http.MethodPost, Path: "/racks/{id}/restore", Tag: "racks", OperationID: "RestoreRacks", Summary: "Restore soft deleted rack", Response: model.Racks{}, Versioned: true, Auth: true, Roles: adminRoles, Idempotent: true},
+
+	// warehouses
+	{Method: http.MethodGet, Path: "/warehouses/{id}", Tag: "warehouses", OperationID: "GetWarehousesById", Summary: "Get warehouse by id", Response: model.Warehouses{}, Versioned: true},
+	{Method: http.MethodGet, Path: "/warehouses", Tag: "warehouses", OperationID: "GetAllWarehouses", Summary: "Get all warehouses", Query: []Parameter{pageParam, limitParam, includeDeletedParam}, Response: []model.Warehouses{}, Paginated: true},
+	{Method: http.MethodPost, Path: "/warehouses", Tag: "warehouses", OperationID: "CreateWarehouses", Summary: "Create warehouse", Request: dto.WarehousesRequest{}, Response: model.Warehouses{}, Status: http.StatusCreated, Versioned: true, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPut, Path: "/warehouses/{id}", Tag: "warehouses", OperationID: "UpdateWarehouses", Summary: "Update warehouse", Request: dto.WarehousesRequest{}, Response: model.Warehouses{}, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodPatch, Path: "/warehouses/{id}", Tag: "warehouses", OperationID: "PatchWarehouses", Summary: "Partial update warehouse (JSON Merge Patch)", Request: dto.WarehousesRequest{}, Response: model.Warehouses{}, Patch: true, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodDelete, Path: "/warehouses/{id}", Tag: "warehouses", OperationID: "DeleteWarehouses", Summary: "Soft delete warehouse", Auth: true, Roles: adminRoles},
+	{Method: http.MethodPost, Path: "/warehouses/{id}/restore", Tag: "warehouses", OperationID: "RestoreWarehouses", Summary: "Restore soft deleted warehouse", Response: model.Warehouses{}, Versioned: true, Auth: true, Roles: adminRoles, Idempotent: true},
+
+	// users
+	{Method: http.MethodGet, Path: "/users/{id}", Tag: "users", OperationID: "GetUsersByID", Summary: "Get user by id", Response: model.Users{}, Versioned: true},
+	{Method: http.MethodGet, Path: "/users", Tag: "users", OperationID: "GetAllUsers", Summary: "Get all users", Query: []Parameter{includeDeletedParam}, Response: []model.Users{}},
+	{Method: http.MethodGet, Path: "/users/email", Tag: "users", OperationID: "GetUsersByEmail", Summary: "Get user by email",
+		Query: []Parameter{{Name: "email", In: "query", Required: true, Schema: &Schema{Type: "string", Format: "email"}}}, Response: model.Users{}},
+	{Method: http.MethodPost, Path: "/users", Tag: "users", OperationID: "CreateUsers", Summary: "Create user", Request: dto.Usersrequest{}, Response: model.Users{}, Status: http.StatusCreated, Versioned: true, Auth: true, Roles: []string{"super_admin"}, Idempotent: true},
+	{Method: http.MethodPut, Path: "/users/{id}", Tag: "users", OperationID: "UpdateUsers", Summary: "Update user", Request: dto.UsersUpdateRequest{}, Response: model.Users{}, Versioned: true, Auth: true, Roles: []string{"super_admin"}},
+	{Method: http.MethodPatch, Path: "/users/{id}", Tag: "users", OperationID: "PatchUsers", Summary: "Partial update user (JSON Merge Patch)", Request: dto.UsersUpdateRequest{}, Response: model.Users{}, Patch: true, Versioned: true, Auth: true, Roles: []string{"super_admin"}},
+	{Method: http.MethodDelete, Path: "/users/{id}", Tag: "users", OperationID: "DeleteUsers", Summary: "Soft delete user", Auth: true, Roles: []string{"super_admin"}},
+	{Method: http.MethodPost, Path: "/users/{id}/restore", Tag: "users", OperationID: "RestoreUsers", Summary: "Restore soft deleted user", Response: model.Users{}, Versioned: true, Auth: true, Roles: []string{"super_admin"}, Idempotent: true},
+	{Method: http.MethodPost, Path: "/users/me/password", Tag: "users", OperationID: "ChangeMyPassword", Summary: "Change own password (revokes all sessions)", Request: dto.ChangePasswordRequest{}, Auth: true},
+	{Method: http.MethodPost, Path: "/users/me/2fa", Tag: "users", OperationID: "EnrollTwoFactor", Summary: "Start TOTP enrollment (secret and otpauth provisioning URI)", Response: dto.TwoFactorEnrollResponse{}, Status: http.StatusCreated, Auth: true},
+	{Method: http.MethodPost, Path: "/users/me/2fa/confirm", Tag: "users", OperationID: "ConfirmTwoFactor", Summary: "Enable TOTP with the first code (returns recovery codes)", Request: dto.TwoFactorCodeRequest{}, Response: dto.RecoveryCodesResponse{}, Auth: true},
+	{Method: http.MethodPost, Path: "/users/me/2fa/disable", Tag: "users", OperationID: "DisableTwoFactor", Summary: "Disable TOTP", Request: dto.TwoFactorCodeRequest{}, Auth: true},
+	{Method: http.MethodPost, Path: "/users/me/2fa/recovery-codes", Tag: "users", OperationID: "RegenerateRecoveryCodes", Summary: "Replace all recovery codes", Request: dto.TwoFactorCodeRequest{}, Response: dto.RecoveryCodesResponse{}, Auth: true},
+	{Method: http.MethodPost, Path: "/users/{id}/password-reset", Tag: "users", OperationID: "IssuePasswordReset", Summary: "Issue a one-time password reset token", Response: dto.PasswordResetResponse{}, Status: http.StatusCreated, Auth: true, Roles: []string{"super_admin"}},
+	{Method: http.MethodPost, Path: "/users/{id}/unlock", Tag: "users", OperationID: "UnlockUser", Summary: "Unlock an account locked by failed logins", Auth: true, Roles: []string{"super_admin"}},
+
+	// api keys
+	{Method: http.MethodGet, Path: "/api-keys", Tag: "api-keys", OperationID: "GetAllAPIKeys", Summary: "Get own API keys (super_admin: all keys)", Query: []Parameter{pageParam, limitParam}, Response: []model.APIKey{}, Paginated: true, Auth: true},
+	{Method: http.MethodGet, Path: "/api-keys/{id}", Tag: "api-keys", OperationID: "GetAPIKeyById", Summary: "Get API key by id", Response: model.APIKey{}, Auth: true},
+	{Method: http.MethodPost, Path: "/api-keys", Tag: "api-keys", OperationID: "CreateAPIKey", Summary: "Create API key (the key is only returned once)", Request: dto.APIKeyRequest{}, Response: dto.APIKeyCreatedResponse{}, Status: http.StatusCreated, Auth: true},
+	{Method: http.MethodPut, Path: "/api-keys/{id}", Tag: "api-keys", OperationID: "UpdateAPIKey", Summary: "Update API key name, scopes and expiry", Request: dto.APIKeyUpdateRequest{}, Response: model.APIKey{}, Auth: true},
+	{Method: http.MethodDelete, Path: "/api-keys/{id}", Tag: "api-keys", OperationID: "RevokeAPIKey", Summary: "Revoke API key", Auth: true},
+
+	// sales
+	{Method: http.MethodGet, Path: "/sales/{id}", Tag: "sales", OperationID: "GetSalesById", Summary: "Get sale by id", Response: model.Sales{}},
+	{Method: http.MethodGet, Path: "/sales", Tag: "sales", OperationID: "GetAllSales", Summary: "Get all sales", Query: []Parameter{pageParam, limitParam, includeItemsParam, invoiceNoParam, saleStatusParam}, Response: []model.Sales{}, Paginated: true},
+	{Method: http.MethodPost, Path: "/sales", Tag: "sales", OperationID: "CreateSales", Summary: "Create sale", Request: dto.SalesRequest{}, Response: dto.SalesResponse{}, Status: http.StatusCreated, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPut, Path: "/sales/{id}", Tag: "sales", OperationID: "UpdateSales", Summary: "Update draft/quote sale (replaces all items)", Request: dto.SalesRequest{}, Auth: true, Roles: staffRoles},
+	{Method: http.MethodDelete, Path: "/sales/{id}", Tag: "sales", OperationID: "DeleteSales", Summary: "Hard delete draft sale", Auth: true, Roles: []string{"super_admin"}},
+	{Method: http.MethodPost, Path: "/sales/{id}/void", Tag: "sales", OperationID: "VoidSale", Summary: "Void sale (restore stock, keep record)", Request: dto.VoidSaleRequest{}, Response: dto.SalesResponse{}, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPost, Path: "/sales/{id}/hold", Tag: "sales", OperationID: "HoldSale", Summary: "Hold draft/quote sale (reserve stock until SALE_HOLD_TTL)", Response: dto.SalesResponse{}, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPost, Path: "/sales/{id}/release", Tag: "sales", OperationID: "ReleaseHold", Summary: "Release held sale back to draft", Response: dto.SalesResponse{}, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPost, Path: "/sales/{id}/complete", Tag: "sales", OperationID: "CompleteSale", Summary: "Complete draft/quote/held sale (assign invoice, decrement stock)", Response: dto.SalesResponse{}, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodGet, Path: "/sales/{id}/payments", Tag: "sales", OperationID: "GetSalePayments", Summary: "Get sale payments and balance", Response: dto.PaymentsResponse{}},
+	{Method: http.MethodGet, Path: "/sales/{id}/receipt", Tag: "sales", OperationID: "GetSaleReceipt", Summary: "Print sale receipt (thermal text) or PDF invoice", Query: []Parameter{receiptFormatParam, receiptWidthParam}, Produces: []string{"text/plain", "application/pdf"}},
+	{Method: http.MethodPost, Path: "/sales/{id}/payments", Tag: "sales", OperationID: "CreatePayments", Summary: "Record payments (split tender, cash change)", Request: dto.PaymentsRequest{}, Response: dto.PaymentsResponse{}, Status: http.StatusCreated, Auth: true, Roles: staffRoles, Idempotent: true},
+
+	// reservations
+	{Method: http.MethodGet, Path: "/reservations", Tag: "reservations", OperationID: "GetAllReservations", Summary: "Get all stock reservations", Query: []Parameter{pageParam, limitParam, reservationStatusParam, reservationReferenceParam}, Response: []model.StockReservation{}, Paginated: true, Auth: true},
+	{Method: http.MethodGet, Path: "/reservations/{id}", Tag: "reservations", OperationID: "GetReservationById", Summary: "Get stock reservation by id", Response: model.StockReservation{}, Auth: true},
+	{Method: http.MethodPost, Path: "/reservations", Tag: "reservations", OperationID: "CreateReservation", Summary: "Reserve stock without decrementing it (until RESERVATION_TTL)", Request: dto.ReservationRequest{}, Response: model.StockReservation{}, Status: http.StatusCreated, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPost, Path: "/reservations/{id}/confirm", Tag: "reservations", OperationID: "ConfirmReservation", Summary: "Confirm reservation (decrement stock)", Response: model.StockReservation{}, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPost, Path: "/reservations/{id}/release", Tag: "reservations", OperationID: "ReleaseReservation", Summary: "Release reserved stock", Response: model.StockReservation{}, Auth: true, Roles: staffRoles, Idempotent: true},
+
+	// customers
+	{Method: http.MethodGet, Path: "/customers/{id}", Tag: "customers", OperationID: "GetCustomersById", Summary: "Get customer by id", Response: model.Customers{}, Versioned: true},
+	{Method: http.MethodGet, Path: "/customers", Tag: "customers", OperationID: "GetAllCustomers", Summary: "Get all customers", Query: []Parameter{pageParam, limitParam, includeDeletedParam}, Response: []model.Customers{}, Paginated: true},
+	{Method: http.MethodGet, Path: "/customers/{id}/sales", Tag: "customers", OperationID: "GetCustomerSales", Summary: "Get customer sales history (with items)", Query: []Parameter{pageParam, limitParam}, Response: []dto.SalesResponse{}, Paginated: true},
+	{Method: http.MethodPost, Path: "/customers", Tag: "customers", OperationID: "CreateCustomers", Summary: "Create customer", Request: dto.CustomersRequest{}, Response: model.Customers{}, Status: http.StatusCreated, Versioned: true, Auth: true, Roles: staffRoles, Idempotent: true},
+	{Method: http.MethodPut, Path: "/customers/{id}", Tag: "customers", OperationID: "UpdateCustomers", Summary: "Update customer", Request: dto.CustomersRequest{}, Response: model.Customers{}, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodPatch, Path: "/customers/{id}", Tag: "customers", OperationID: "PatchCustomers", Summary: "Partial update customer (JSON Merge Patch)", Request: dto.CustomersRequest{}, Response: model.Customers{}, Patch: true, Versioned: true, Auth: true, Roles: staffRoles},
+	{Method: http.MethodDelete, Path: "/customers/{id}", Tag: "customers", OperationID: "DeleteCustomers", Summary: "Soft delete customer", Auth: true, Roles: adminRoles},
+	{Method: http.MethodPost, Path: "/customers/{id}/restore", Tag: "customers", OperationID: "RestoreCustomers", Summary: "Restore soft deleted customer", Response: model.Customers{}, Versioned: true, Auth: true, Roles: adminRoles, Idempotent: true},
+
+	// reports
+	{Method: http.MethodGet, Path: "/reports/items", Tag: "reports", OperationID: "GetItemsReport", Summary: "Items report", Response: repository.ItemsReport{}},
+	{Method: http.MethodGet, Path: "/reports/sales", Tag: "reports", OperationID: "GetSalesReport", Summary: "Sales report", Response: repository.SalesReport{}},
+	{Method: http.MethodGet, Path: "/reports/revenue", Tag: "reports", OperationID: "GetRevenueReport", Summary: "Revenue report", Response: repository.RevenueReport{}},
+	{Method: http.MethodGet, Path: "/reports/customers/{id}", Tag: "reports", OperationID: "GetCustomerReport", Summary: "Customer purchase history report", Response: repository.CustomerPurchaseReport{}},
+	{Method: http.MethodGet, Path: "/reports/cash-drawer", Tag: "reports", OperationID: "GetCashDrawerReport", Summary: "Daily cash drawer reconciliation per user (payments of voided sales excluded)",
+		Query: []Parameter{
+			{Name: "date", In: "query", Description: "YYYY-MM-DD, default hari ini", Schema: &Schema{Type: "string", Format: "date"}},
+			{Name: "user_id", In: "query", Description: "hanya pembayaran yang dicatat user ini", Schema: &Schema{Type: "integer", Format: "int32"}},
+		},
+		Response: repository.CashDrawerReport{}},
+
+	// audit
+	{Method: http.MethodGet, Path: "/audit", Tag: "audit", OperationID: "GetAuditLogs", Summary: "Get audit log", Auth: true, Roles: []string{"super_admin"},
+		Query: []Parameter{
+			pageParam, limitParam,
+			{Name: "entity", In: "query", Description: "item, category, rack, warehouse, user, sale, customer, payment", Schema: &Schema{Type: "string"}},
+			{Name: "entity_id", In: "query", Schema: &Schema{Type: "integer", Format: "int32"}},
+			{Name: "actor_id", In: "query", Description: "id user yang melakukan perubahan", Schema: &Schema{Type: "integer", Format: "int32"}},
+			{Name: "from", In: "query", Description: dateFilterDescription, Schema: &Schema{Type: "string"}},
+			{Name: "to", In: "query", Description: dateFilterDescription + ", tanggal saja berarti sampai akhir hari itu", Schema: &Schema{Type: "string"}},
+		},
+		Response: []model.AuditLog{}, Paginated: true},
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Spec membangun dokumen OpenAPI dari Routes
+func Spec() *Document {
+	reg := newSchemaRegistry()
+	envelope := reg.ref(utils.Response{})
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "Inventory REST API", Version: "1.0.0"},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Responses: map[string]*Response{
+				"BadRequest":           errorResponse("request body atau parameter tidak valid", envelope),
+				"NotFound":             errorResponse("data tidak ditemukan", envelope),
+				"Conflict":             errorResponse("konflik dengan data yang ada (duplikat, relasi, stok)", envelope),
+				"Unprocessable":        errorResponse("data tidak lolos validasi bisnis", envelope),
+				"InternalError":        errorResponse("kesalahan server", envelope),
+				"PreconditionFailed":   errorResponse("If-Match tidak sama dengan versi terbaru (details.current_version)", envelope),
+				"PreconditionRequired": errorResponse("header If-Match wajib dikirim", envelope),
+				"Unauthorized":         errorResponse("token tidak ada, tidak valid atau kedaluwarsa", envelope),
+				"Forbidden":            errorResponse("role tidak diizinkan mengakses endpoint ini", envelope),
+				"TooManyRequests": {
+					Description: "rate limit terlampaui atau terlalu banyak login gagal (details.retry_after_seconds)",
+					Headers:     map[string]Header{"Retry-After": {Description: "detik sampai boleh mencoba lagi", Schema: &Schema{Type: "integer"}}},
+					Content:     jsonContent(envelope),
+				},
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", Description: "token dari POST /auth/login"},
+				"apiKeyAuth": {Type: "apiKey", In: "header", Name: "Authorization", Description: "\"ApiKey <key>\" dari POST /api-keys, dibatasi scope key"},
+			},
+		},
+	}
+
+	seenTags := map[string]bool{}
+	for _, route := range Routes {
+		if !seenTags[route.Tag] {
+			seenTags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+
+		item, ok := doc.Paths[route.Path]
+		if !ok {
+			item = PathItem{}
+			doc.Paths[route.Path] = item
+		}
+		item[strings.ToLower(route.Method)] = buildOperation(reg, envelope, route)
+	}
+
+	doc.Components.Schemas = reg.schemas
+	return doc
+}
+
+func buildOperation(reg *schemaRegistry, envelope *Schema, route Route) *Operation {
+	op := &Operation{
+		Tags:        []string{route.Tag},
+		Summary:     route.Summary,
+		OperationID: route.OperationID,
+		Responses:   map[string]*Response{},
+	}
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "integer", Format: "int32"},
+		})
+	}
+	op.Parameters = append(op.Parameters, route.Query...)
+	if route.Idempotent {
+		op.Parameters = append(op.Parameters, idempotencyKeyParam)
+		// 409: request pertama masih diproses; 422: key dipakai untuk body lain
+		op.Responses["409"] = refResponse("Conflict")
+		op.Responses["422"] = refResponse("Unprocessable")
+	}
+
+	if route.Versioned {
+		switch route.Method {
+		case http.MethodGet:
+			op.Parameters = append(op.Parameters, Parameter{Name: "If-None-Match", In: "header", Description: "ETag dari response sebelumnya; 304 jika belum berubah", Schema: &Schema{Type: "string"}})
+			op.Responses["304"] = &Response{Description: "not modified"}
+		case http.MethodPut, http.MethodPatch:
+			op.Parameters = append(op.Parameters, Parameter{Name: "If-Match", In: "header", Required: true, Description: `ETag terakhir, misal "3" (atau * untuk melewati cek versi)`, Schema: &Schema{Type: "string"}})
+			op.Responses["412"] = refResponse("PreconditionFailed")
+			op.Responses["428"] = refResponse("PreconditionRequired")
+		}
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(reg.ref(route.Request)),
+		}
+		if route.Patch {
+			op.RequestBody.Content = map[string]MediaType{
+				"application/merge-patch+json": {Schema: reg.patchRef(route.Request)},
+				"application/json":             {Schema: reg.patchRef(route.Request)},
+			}
+		}
+		op.Responses["400"] = refResponse("BadRequest")
+		op.Responses["422"] = refResponse("Unprocessable")
+		op.Responses["409"] = refResponse("Conflict")
+	}
+	if len(op.Parameters) > 0 {
+		op.Responses["400"] = refResponse("BadRequest")
+	}
+	if strings.Contains(route.Path, "{id}") {
+		op.Responses["404"] = refResponse("NotFound")
+	}
+	if route.Method == http.MethodDelete || strings.HasSuffix(route.Path, "/restore") {
+		// delete: data masih dipakai; restore: data tidak sedang terhapus / unique bentrok
+		op.Responses["409"] = refResponse("Conflict")
+	}
+	if route.Auth {
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+		op.Responses["401"] = refResponse("Unauthorized")
+		if len(route.Roles) > 0 {
+			op.Summary += " (role: " + strings.Join(route.Roles, ", ") + ")"
+			op.Responses["403"] = refResponse("Forbidden")
+		}
+	}
+	// semua route melewati rate limiter
+	op.Responses["429"] = refResponse("TooManyRequests")
+	op.Responses["500"] = refResponse("InternalError")
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = &Response{
+		Description: route.Summary,
+		Headers:     etagHeader(route),
+		Content:     jsonContent(successSchema(reg, envelope, route)),
+	}
+	if len(route.Produces) > 0 {
+		content := map[string]MediaType{}
+		for _, mediaType := range route.Produces {
+			schema := &Schema{Type: "string"}
+			if !strings.HasPrefix(mediaType, "text/") {
+				schema.Format = "binary"
+			}
+			content[mediaType] = MediaType{Schema: schema}
+		}
+		op.Responses[strconv.Itoa(status)].Content = content
+	}
+
+	return op
+}
+
+// successSchema menggabungkan envelope dengan tipe data spesifik endpoint
+func successSchema(reg *schemaRegistry, envelope *Schema, route Route) *Schema {
+	if route.Response == nil {
+		return envelope
+	}
+
+	properties := map[string]*Schema{"data": reg.ref(route.Response)}
+	required := []string{"status", "message", "data"}
+	if route.Paginated {
+		properties["pagination"] = reg.ref(dto.Pagination{})
+		required = append(required, "pagination")
+	}
+
+	return &Schema{
+		AllOf: []*Schema{envelope, {Type: "object", Properties: properties, Required: required}},
+	}
+}
+
+func etagHeader(route Route) map[string]Header {
+	if !route.Versioned {
+		return nil
+	}
+	return map[string]Header{"ETag": {Description: "versi resource, kirim kembali lewat If-Match", Schema: &Schema{Type: "string"}}}
+}
+
+func errorResponse(description string, envelope *Schema) *Response {
+	return &Response{Description: description, Content: jsonContent(envelope)}
+}
+
+func refResponse(name string) *Response {
+	return &Response{Ref: "#/components/responses/" + name}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
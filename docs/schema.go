@@ -0,0 +1,275 @@
+package docs
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+)
+
+// Schema adalah subset JSON Schema yang dipakai OpenAPI 3
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// schemaRegistry mengumpulkan schema komponen dari struct dto/model
+type schemaRegistry struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: map[string]*Schema{}}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// ref mendaftarkan tipe (struct) ke components dan mengembalikan $ref ke sana
+func (reg *schemaRegistry) ref(v any) *Schema {
+	if v == nil {
+		return nil
+	}
+	return reg.schemaOf(reflect.TypeOf(v))
+}
+
+// patchRef mendaftarkan varian "<Name>Patch" dari struct v tanpa daftar required
+// (constraint lain tetap berlaku untuk field yang dikirim)
+func (reg *schemaRegistry) patchRef(v any) *Schema {
+	base := reg.ref(v)
+	name := strings.TrimPrefix(base.Ref, "#/components/schemas/")
+
+	patchName := name + "Patch"
+	if _, ok := reg.schemas[patchName]; !ok {
+		patch := *reg.schemas[name]
+		patch.Required = nil
+		reg.schemas[patchName] = &patch
+	}
+	return &Schema{Ref: "#/components/schemas/" + patchName}
+}
+
+func (reg *schemaRegistry) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	if schema, ok := customSchema(t); ok {
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: reg.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: reg.schemaOf(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		name := componentName(t)
+		if _, ok := reg.schemas[name]; !ok {
+			// daftarkan placeholder dulu agar tipe rekursif tidak loop
+			reg.schemas[name] = &Schema{}
+			*reg.schemas[name] = *reg.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+// componentName memakai nama tipe Go, misal dto.ItemsRequest -> ItemsRequest
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return "Object"
+	}
+	return name
+}
+
+func (reg *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonName, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		// embedded struct tanpa json tag: properties digabung
+		if field.Anonymous && field.Tag.Get("json") == "" && field.Type.Kind() == reflect.Struct {
+			embedded := reg.structSchema(field.Type)
+			for k, v := range embedded.Properties {
+				schema.Properties[k] = v
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		prop := reg.schemaOf(field.Type)
+		if field.Type.Kind() == reflect.Pointer && prop.Ref == "" {
+			prop.Nullable = true
+		}
+
+		if applyValidateTag(prop, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, jsonName)
+		}
+
+		schema.Properties[jsonName] = prop
+	}
+
+	return schema
+}
+
+func jsonFieldName(field reflect.StructField) (name string, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+var moneyType = reflect.TypeOf(model.Money(0))
+
+var taxRateType = reflect.TypeOf(model.TaxRate(0))
+
+var jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// customSchema menangani tipe yang punya MarshalJSON sendiri; diasumsikan ter-encode sebagai string
+func customSchema(t reflect.Type) (*Schema, bool) {
+	// json.RawMessage dipakai untuk dokumen JSON bebas (misal changes di audit log)
+	if t == rawMessageType {
+		return &Schema{Type: "object"}, true
+	}
+	// model.Money dan model.TaxRate ter-encode sebagai angka fixed-point 2 desimal
+	if t == moneyType || t == taxRateType {
+		return &Schema{Type: "number", Format: "decimal"}, true
+	}
+	if t.Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(jsonMarshaler) {
+		return &Schema{Type: "string"}, true
+	}
+	return nil, false
+}
+
+// applyValidateTag menerjemahkan tag go-playground/validator ke constraint JSON Schema.
+// Mengembalikan true jika field wajib (required).
+func applyValidateTag(s *Schema, tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	required := false
+	target := s
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "omitempty":
+		case "dive":
+			// rule setelah dive berlaku untuk elemen array
+			if s.Items != nil && s.Items.Ref == "" {
+				target = s.Items
+			}
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "uuid", "uuid4":
+			target.Format = "uuid"
+		case "password":
+			// policy lengkap (huruf besar, angka, ...) ada di pesan validasi
+			length := utils.CurrentPasswordPolicy().MinLength
+			target.Format = "password"
+			target.MinLength = &length
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				target.Enum = append(target.Enum, v)
+			}
+		case "min", "gte", "gt", "max", "lte", "lt", "len":
+			applyBound(target, name, param)
+		case "percent":
+			applyBound(target, "min", "0")
+			applyBound(target, "max", "100")
+		}
+	}
+	return required
+}
+
+func applyBound(s *Schema, rule, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch s.Type {
+	case "string":
+		length := int(n)
+		switch rule {
+		case "min", "gte":
+			s.MinLength = &length
+		case "max", "lte":
+			s.MaxLength = &length
+		case "len":
+			s.MinLength, s.MaxLength = &length, &length
+		}
+	case "array":
+		length := int(n)
+		if rule == "min" || rule == "gte" {
+			s.MinItems = &length
+		}
+	default:
+		switch rule {
+		case "min", "gte":
+			s.Minimum = &n
+		case "gt":
+			s.Minimum = &n
+			s.ExclusiveMinimum = true
+		case "max", "lte", "lt":
+			s.Maximum = &n
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"project-app-inventory-restapi-golang-azwin/model"
+	"time"
+)
+
+type APIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=items:read items:write categories:read categories:write racks:read racks:write warehouses:read warehouses:write sales:read sales:write customers:read customers:write reservations:read reservations:write reports:read"`
+	ExpiredAt *time.Time `json:"expired_at,omitempty"`
+	// pemilik key, hanya super_admin yang boleh mengisi user lain (default: diri sendiri)
+	UserId int `json:"user_id,omitempty" validate:"omitempty,min=1"`
+}
+
+type APIKeyUpdateRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=items:read items:write categories:read categories:write racks:read racks:write warehouses:read warehouses:write sales:read sales:write customers:read customers:write reservations:read reservations:write reports:read"`
+	ExpiredAt *time.Time `json:"expired_at,omitempty"`
+}
+
+// APIKeyCreatedResponse berisi key lengkap, hanya ditampilkan sekali saat dibuat
+type APIKeyCreatedResponse struct {
+	model.APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"project-app-inventory-restapi-golang-azwin/model"
+	"time"
+)
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// LoginResponse berisi token session. Untuk user dengan 2FA aktif, token kosong
+// dan client harus menukar ChallengeToken + kode TOTP di POST /auth/login/2fa;
+// ExpiredAt saat itu adalah masa berlaku challenge.
+type LoginResponse struct {
+	Token     string      `json:"token,omitempty"`
+	ExpiredAt time.Time   `json:"expired_at"`
+	User      model.Users `json:"user"`
+
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+	// role wajib 2FA tapi belum enroll: hanya endpoint tanpa batasan role yang bisa dipakai
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+}
@@ -1,10 +1,15 @@
 package dto
 
-import "time"
+import (
+	"project-app-inventory-restapi-golang-azwin/model"
+	"time"
+)
 
 type CategoriesRequest struct {
 		Id	   int    `json:"id"`
 	Name     string `json:"name" validate:"required,min=3"`
+	TaxRate      model.TaxRate `json:"tax_rate" validate:"percent"`
+	TaxInclusive bool    `json:"tax_inclusive"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -12,6 +17,8 @@ type CategoriesRequest struct {
 type CategoriesResponse struct {
 		Id	   int    `json:"id"`
 	Name     string `json:"name"`
+	TaxRate      model.TaxRate `json:"tax_rate"`
+	TaxInclusive bool    `json:"tax_inclusive"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
\ No newline at end of file
@@ -0,0 +1,12 @@
+package dto
+
+import "project-app-inventory-restapi-golang-azwin/model"
+
+type CustomersRequest struct {
+	Name        string      `json:"name" validate:"required,max=150"`
+	Email       string      `json:"email" validate:"omitempty,email,max=150"`
+	Phone       string      `json:"phone" validate:"max=50"`
+	TaxId       string      `json:"tax_id" validate:"max=50"`
+	Address     string      `json:"address"`
+	CreditLimit model.Money `json:"credit_limit" validate:"gte=0"`
+}
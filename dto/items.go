@@ -1,29 +1,45 @@
 package dto
 
-import "time"
+import (
+	"project-app-inventory-restapi-golang-azwin/model"
+	"time"
+)
 
 type ItemsRequest struct {
-	Id         int       `json:"id"`
-	CategoryId int       `json:"category_id" validate:"required"`
-	RackId     int       `json:"rack_id" validate:"required"`
-	Name       string    `json:"name" validate:"required,min=3"`
-	Sku        string    `json:"sku" validate:"required"`
-	Stock      int       `json:"stock" validate:"gte=0"`
-	MinStock   int       `json:"min_stock" validate:"gte=0"`
-	Price      float64   `json:"price" validate:"required,gte=0"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	Id         int         `json:"id"`
+	CategoryId int         `json:"category_id" validate:"required"`
+	RackId     int         `json:"rack_id" validate:"required"`
+	Name       string      `json:"name" validate:"required,min=3"`
+	Sku        string      `json:"sku" validate:"required"`
+	Stock      int         `json:"stock" validate:"gte=0"`
+	MinStock   int         `json:"min_stock" validate:"gte=0"`
+	Price      model.Money `json:"price" validate:"required,gte=0"`
+	// kosong / null = ikut tarif category
+	TaxRate      *model.TaxRate `json:"tax_rate" validate:"omitempty,percent"`
+	TaxInclusive *bool          `json:"tax_inclusive"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
 }
 
 type ItemsResponse struct {
-	Id         int       `json:"id"`
-	CategoryId int       `json:"category_id"`
-	RackId     int       `json:"rack_id"`
-	Name       string    `json:"name"`
-	Sku        string    `json:"sku"`
-	Stock      int       `json:"stock"`
-	MinStock   int       `json:"min_stock"`
-	Price      float64   `json:"price"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-}
\ No newline at end of file
+	Id           int            `json:"id"`
+	CategoryId   int            `json:"category_id"`
+	RackId       int            `json:"rack_id"`
+	Name         string         `json:"name"`
+	Sku          string         `json:"sku"`
+	Stock        int            `json:"stock"`
+	Reserved     int            `json:"reserved"`
+	Available    int            `json:"available"`
+	MinStock     int            `json:"min_stock"`
+	Price        model.Money    `json:"price"`
+	TaxRate      *model.TaxRate `json:"tax_rate"`
+	TaxInclusive *bool          `json:"tax_inclusive"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+type LowStockResponse struct {
+	Threshold int           `json:"threshold"`
+	Count     int           `json:"count"`
+	Items     []model.Items `json:"items"`
+}
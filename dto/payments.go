@@ -0,0 +1,25 @@
+package dto
+
+import "project-app-inventory-restapi-golang-azwin/model"
+
+// PaymentsRequest berisi satu atau beberapa tender (split tender) untuk satu sale
+type PaymentsRequest struct {
+	Payments []TenderRequest `json:"payments" validate:"required,min=1,dive"`
+}
+
+// TenderRequest: untuk cash, amount adalah uang yang diterima (boleh lebih, sisanya kembalian)
+type TenderRequest struct {
+	Method    string      `json:"method" validate:"required,oneof=cash card qris transfer"`
+	Amount    model.Money `json:"amount" validate:"gt=0"`
+	Reference string      `json:"reference" validate:"max=100"`
+}
+
+type PaymentsResponse struct {
+	SaleId        int              `json:"sale_id"`
+	PaymentStatus string           `json:"payment_status"`
+	TotalAmount   model.Money      `json:"total_amount"`
+	PaidAmount    model.Money      `json:"paid_amount"`
+	BalanceDue    model.Money      `json:"balance_due"`
+	Change        model.Money      `json:"change"`
+	Payments      []model.Payments `json:"payments"`
+}
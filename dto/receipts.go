@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"project-app-inventory-restapi-golang-azwin/model"
+	"time"
+)
+
+// Receipt adalah data struk / invoice yang siap dicetak (lihat package receipt)
+type Receipt struct {
+	StoreName         string
+	StoreAddress      string
+	StorePhone        string
+	Warehouse         string
+	WarehouseLocation string
+	Footer            string
+
+	SaleId      int
+	InvoiceNo   string
+	CreatedAt   time.Time
+	Cashier     string
+	Customer    string
+	PaymentType string
+	// Status sale; struk sale voided diberi tanda VOID beserta alasannya,
+	// draft/quote/held diberi judul sesuai statusnya (belum ada nomor invoice)
+	Status        string
+	VoidReason    string
+	HoldExpiresAt *time.Time
+
+	Lines          []ReceiptLine
+	Subtotal       model.Money
+	DiscountAmount model.Money
+	TaxAmount      model.Money
+	TotalAmount    model.Money
+
+	PaymentStatus string
+	Payments      []model.Payments
+	PaidAmount    model.Money
+	BalanceDue    model.Money
+	Change        model.Money
+}
+
+type ReceiptLine struct {
+	Name           string
+	Quantity       int
+	Price          model.Money
+	DiscountAmount model.Money
+	TaxRate        model.TaxRate
+	TaxInclusive   bool
+	TaxAmount      model.Money
+	Total          model.Money
+}
@@ -0,0 +1,15 @@
+package dto
+
+// ReservationRequest memesan stock untuk satu order. Item yang sama di beberapa
+// baris dijumlahkan.
+type ReservationRequest struct {
+	Reference string                   `json:"reference" validate:"required,max=100"`
+	Items     []ReservationItemRequest `json:"items" validate:"required,min=1,dive"`
+	// kosong = RESERVATION_TTL; maksimal 7 hari
+	ExpiresInMinutes int `json:"expires_in_minutes" validate:"omitempty,gte=1,lte=10080"`
+}
+
+type ReservationItemRequest struct {
+	ItemId   int `json:"item_id" validate:"required"`
+	Quantity int `json:"quantity" validate:"required,gte=1"`
+}
@@ -1,30 +1,73 @@
 package dto
 
-import "time"
+import (
+    "project-app-inventory-restapi-golang-azwin/model"
+    "time"
+)
 
 type SalesRequest struct {
-    UserId int                    `json:"user_id" validate:"required"`
+    // UserId kasir; default user yang login, user lain hanya untuk admin
+    UserId int                    `json:"user_id" validate:"omitempty,gte=1"`
+    CustomerId  *int              `json:"customer_id" validate:"omitempty,gte=1"`
+    WarehouseId *int              `json:"warehouse_id" validate:"omitempty,gte=1"` // wajib jika INVOICE_PER_WAREHOUSE=true
+    PaymentType string            `json:"payment_type" validate:"omitempty,oneof=cash credit"` // default cash; credit wajib customer_id
     Items  []SaleItemRequest      `json:"items" validate:"required,dive"`  // Gabung detail items
+    // Discount diskon order, dibagi ke semua baris sebelum pajak
+    Discount *DiscountRequest     `json:"discount,omitempty"`
+    // Status awal, default completed (langsung mengurangi stock). Hanya dipakai saat create.
+    Status string                 `json:"status" validate:"omitempty,oneof=draft quote held completed"`
+}
+
+// DiscountRequest: percent (0-100) dari nilai setelah diskon sebelumnya, atau fixed (nominal).
+// Keduanya maksimal 2 desimal, jadi Value memakai model.Money juga untuk percent.
+type DiscountRequest struct {
+    Type  string      `json:"type" validate:"required,oneof=percent fixed"`
+    Value model.Money `json:"value" validate:"gte=0"`
 }
 
 type SaleItemRequest struct {
     ItemId   int     `json:"item_id" validate:"required"`
     Quantity int     `json:"quantity" validate:"required,gte=1"`
-    Price    float64 `json:"price" validate:"required,gte=0"`
+    Price    model.Money `json:"price" validate:"required,gte=0"`
+    Discount *DiscountRequest `json:"discount,omitempty"`
 }
 
 type SalesResponse struct {
     Id          int               `json:"id"`
+    InvoiceNo   string            `json:"invoice_no"`
     UserId      int               `json:"user_id"`
-    TotalAmount float64           `json:"total_amount"`
-    Items       []SaleItemResponse `json:"items"`  // Include detail
+    CustomerId  *int              `json:"customer_id,omitempty"`
+    WarehouseId *int              `json:"warehouse_id,omitempty"`
+    PaymentType string            `json:"payment_type"`
+    Subtotal       model.Money        `json:"subtotal"`
+    DiscountAmount model.Money        `json:"discount_amount"`
+    TaxAmount      model.Money        `json:"tax_amount"`
+    TotalAmount model.Money           `json:"total_amount"`
+    PaidAmount    model.Money         `json:"paid_amount"`
+    PaymentStatus string              `json:"payment_status"`
+    Status      string                `json:"status"`
+    VoidedAt    *time.Time            `json:"voided_at,omitempty"`
+    VoidedBy    *int                  `json:"voided_by,omitempty"`
+    VoidReason  string                `json:"void_reason,omitempty"`
+    HoldExpiresAt *time.Time          `json:"hold_expires_at,omitempty"`
+    Items       []SaleItemResponse `json:"items,omitempty"`  // Include detail (list: hanya dengan ?include=items)
     CreatedAt   time.Time         `json:"created_at"`
 }
 
+// VoidSaleRequest - alasan wajib diisi, disimpan permanen di sale
+type VoidSaleRequest struct {
+    Reason string `json:"reason" validate:"required,max=500"`
+}
+
 type SaleItemResponse struct {
     Id       int     `json:"id"`
     ItemId   int     `json:"item_id"`
     Quantity int     `json:"quantity"`
-    Price    float64 `json:"price"`
-    Subtotal float64 `json:"subtotal"`
+    Price    model.Money `json:"price"`
+    Subtotal model.Money `json:"subtotal"`
+    DiscountAmount model.Money `json:"discount_amount"`
+    TaxRate        model.TaxRate `json:"tax_rate"`
+    TaxInclusive   bool    `json:"tax_inclusive"`
+    TaxAmount      model.Money `json:"tax_amount"`
+    Total          model.Money `json:"total"`
 }
\ No newline at end of file
@@ -0,0 +1,23 @@
+package dto
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	// kode TOTP 6 digit atau recovery code
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TwoFactorEnrollResponse hanya ditampilkan sekali. ProvisioningURI (otpauth://)
+// dirender client sebagai QR code untuk authenticator app.
+type TwoFactorEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResponse hanya ditampilkan sekali, yang disimpan hanya hash-nya
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -1,11 +1,37 @@
 package dto
 
+import "time"
+
 type Usersrequest struct {
 	Id        int    `json:"id"`
 	Username  string `json:"username" validate:"required,min=3"`
 	Email     string `json:"email" validate:"required,email"`
-	Password  string `json:"password" validate:"required,min=6"`
-	Role      string `json:"role" validate:"required"`
+	Password  string `json:"password" validate:"required,password"`
+	Role      string `json:"role" validate:"required,oneof=super_admin admin staff"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
+
+// UsersUpdateRequest dipakai PUT/PATCH; password hanya bisa diganti lewat
+// POST /users/me/password atau reset password
+type UsersUpdateRequest struct {
+	Username string `json:"username" validate:"required,min=3"`
+	Email    string `json:"email" validate:"required,email"`
+	Role     string `json:"role" validate:"required,oneof=super_admin admin staff"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,password"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,password"`
+}
+
+// PasswordResetResponse berisi token reset; hanya ditampilkan sekali
+type PasswordResetResponse struct {
+	Token     string    `json:"token"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type APIKeysHandler struct {
+	APIKeysHandlerService service.APIKeysService
+	config                utils.Configuration
+}
+
+func NewAPIKeysHandler(apiKeysService service.APIKeysService, config utils.Configuration) APIKeysHandler {
+	return APIKeysHandler{
+		APIKeysHandlerService: apiKeysService,
+		config:                config,
+	}
+}
+
+// GetAllAPIKeys - daftar API key milik user (super_admin: semua key)
+func (h *APIKeysHandler) GetAllAPIKeys(w http.ResponseWriter, r *http.Request) {
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		utils.ResponseError(w, r, apperror.Unauthorized("authentication required"), "error get api keys")
+		return
+	}
+
+	page, limit := parsePagination(r, h.config.Limit)
+	keys, total, err := h.APIKeysHandlerService.GetAllAPIKeys(r.Context(), actor, page, limit)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error get api keys")
+		return
+	}
+
+	utils.ResponsePagination(w, http.StatusOK, "success get api keys", keys, utils.NewPagination(page, limit, total))
+}
+
+func (h *APIKeysHandler) GetAPIKeyById(w http.ResponseWriter, r *http.Request) {
+	actor, id, ok := h.actorAndId(w, r, "error get api key")
+	if !ok {
+		return
+	}
+
+	key, err := h.APIKeysHandlerService.GetAPIKeyById(r.Context(), actor, id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error get api key")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success get api key", key)
+}
+
+// CreateAPIKey - key lengkap hanya dikembalikan di response ini
+func (h *APIKeysHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		utils.ResponseError(w, r, apperror.Unauthorized("authentication required"), "error creating api key")
+		return
+	}
+
+	var req dto.APIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	created, err := h.APIKeysHandlerService.CreateAPIKey(r.Context(), actor, req)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error creating api key")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "success create api key, store the key now because it will not be shown again", created)
+}
+
+func (h *APIKeysHandler) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
+	actor, id, ok := h.actorAndId(w, r, "error updating api key")
+	if !ok {
+		return
+	}
+
+	var req dto.APIKeyUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	key, err := h.APIKeysHandlerService.UpdateAPIKey(r.Context(), actor, id, req)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error updating api key")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success update api key", key)
+}
+
+// RevokeAPIKey - cabut key (tidak bisa dipakai lagi)
+func (h *APIKeysHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	actor, id, ok := h.actorAndId(w, r, "error revoking api key")
+	if !ok {
+		return
+	}
+
+	if err := h.APIKeysHandlerService.RevokeAPIKey(r.Context(), actor, id); err != nil {
+		utils.ResponseError(w, r, err, "error revoking api key")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success revoke api key", nil)
+}
+
+// actorAndId membaca actor dan path param id; response error sudah ditulis jika ok false
+func (h *APIKeysHandler) actorAndId(w http.ResponseWriter, r *http.Request, failMessage string) (utils.Actor, int, bool) {
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		utils.ResponseError(w, r, apperror.Unauthorized("authentication required"), failMessage)
+		return actor, 0, false
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return actor, 0, false
+	}
+	return actor, id, true
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAPIKeysService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeysService) GetAllAPIKeys(ctx context.Context, actor utils.Actor, page, limit int) ([]model.APIKey, int, error) {
+	args := m.Called(actor, page, limit)
+	return args.Get(0).([]model.APIKey), args.Int(1), args.Error(2)
+}
+
+func (m *MockAPIKeysService) GetAPIKeyById(ctx context.Context, actor utils.Actor, id int) (*model.APIKey, error) {
+	args := m.Called(actor, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeysService) CreateAPIKey(ctx context.Context, actor utils.Actor, req dto.APIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	args := m.Called(actor, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.APIKeyCreatedResponse), args.Error(1)
+}
+
+func (m *MockAPIKeysService) UpdateAPIKey(ctx context.Context, actor utils.Actor, id int, req dto.APIKeyUpdateRequest) (*model.APIKey, error) {
+	args := m.Called(actor, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeysService) RevokeAPIKey(ctx context.Context, actor utils.Actor, id int) error {
+	args := m.Called(actor, id)
+	return args.Error(0)
+}
+
+func TestAPIKeysHandler_CreateAPIKey(t *testing.T) {
+	mockService := new(MockAPIKeysService)
+	h := NewAPIKeysHandler(mockService, testConfig)
+	actor := utils.Actor{UserId: 9, Role: "cashier"}
+	req := dto.APIKeyRequest{Name: "POS 1", Scopes: []string{"items:read", "sales:write"}}
+	mockService.On("CreateAPIKey", actor, req).
+		Return(&dto.APIKeyCreatedResponse{APIKey: model.APIKey{Id: 3, Name: "POS 1", Prefix: "ik_0123abcd"}, Key: "ik_0123abcdef"}, nil)
+
+	rec, r := newRequest(http.MethodPost, "/api-keys", `{"name":"POS 1","scopes":["items:read","sales:write"]}`, nil)
+	r = r.WithContext(utils.WithActor(r.Context(), actor))
+	h.CreateAPIKey(rec, r)
+
+	env := assertSuccess(t, rec, http.StatusCreated)
+	var got dto.APIKeyCreatedResponse
+	require.NoError(t, json.Unmarshal(env.Data, &got))
+	assert.Equal(t, "ik_0123abcdef", got.Key)
+	assert.Equal(t, 3, got.Id)
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIKeysHandler_CreateAPIKey_UnknownScope(t *testing.T) {
+	mockService := new(MockAPIKeysService)
+	h := NewAPIKeysHandler(mockService, testConfig)
+
+	rec, r := newRequest(http.MethodPost, "/api-keys", `{"name":"POS 1","scopes":["audit:read"]}`, nil)
+	r = r.WithContext(utils.WithActor(r.Context(), utils.Actor{UserId: 9}))
+	h.CreateAPIKey(rec, r)
+
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+	mockService.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+	"time"
+)
+
+type AuditHandler struct {
+	AuditHandlerService service.AuditService
+	config              utils.Configuration
+}
+
+func NewAuditHandler(auditService service.AuditService, config utils.Configuration) AuditHandler {
+	return AuditHandler{
+		AuditHandlerService: auditService,
+		config:              config,
+	}
+}
+
+// GetAuditLogs - list audit log dengan filter entity, entity_id, actor_id, from dan to
+func (h *AuditHandler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	page, limit := parsePagination(r, h.config.Limit)
+
+	logs, total, err := h.AuditHandlerService.GetAuditLogs(r.Context(), filter, page, limit)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting audit logs")
+		return
+	}
+
+	utils.ResponsePagination(w, http.StatusOK, "success get audit logs", logs, utils.NewPagination(page, limit, total))
+}
+
+func parseAuditFilter(r *http.Request) (repository.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := repository.AuditFilter{Entity: query.Get("entity")}
+
+	var err error
+	if filter.EntityId, err = parseOptionalId(query.Get("entity_id")); err != nil {
+		return filter, errors.New("invalid entity_id value")
+	}
+	if filter.ActorId, err = parseOptionalId(query.Get("actor_id")); err != nil {
+		return filter, errors.New("invalid actor_id value")
+	}
+	if filter.From, err = parseAuditTime(query.Get("from"), false); err != nil {
+		return filter, errors.New("invalid from value, use RFC3339 or YYYY-MM-DD")
+	}
+	if filter.To, err = parseAuditTime(query.Get("to"), true); err != nil {
+		return filter, errors.New("invalid to value, use RFC3339 or YYYY-MM-DD")
+	}
+	return filter, nil
+}
+
+func parseOptionalId(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 1 {
+		return 0, strconv.ErrSyntax
+	}
+	return id, nil
+}
+
+// parseAuditTime menerima RFC3339 atau tanggal saja. Untuk batas "to" berupa
+// tanggal, seluruh hari itu ikut (batas atas eksklusif di hari berikutnya).
+func parseAuditTime(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
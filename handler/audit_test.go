@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) GetAuditLogs(ctx context.Context, filter repository.AuditFilter, page, limit int) ([]model.AuditLog, int, error) {
+	args := m.Called(filter, page, limit)
+	return args.Get(0).([]model.AuditLog), args.Int(1), args.Error(2)
+}
+
+func TestAuditHandler_GetAuditLogs_Filters(t *testing.T) {
+	mockService := new(MockAuditService)
+	h := NewAuditHandler(mockService, testConfig)
+
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC) // tanggal "to" ikut satu hari penuh
+	filter := repository.AuditFilter{Entity: "item", EntityId: 5, ActorId: 2, From: &from, To: &to}
+	mockService.On("GetAuditLogs", filter, 1, 10).Return([]model.AuditLog{{Id: 1, Entity: "item", EntityId: 5}}, 1, nil)
+
+	rec, req := newRequest(http.MethodGet, "/audit?entity=item&entity_id=5&actor_id=2&from=2026-02-01&to=2026-02-02", "", nil)
+	h.GetAuditLogs(rec, req)
+
+	assertPaginated(t, rec, 1, 10, 1, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditHandler_GetAuditLogs_InvalidFilter(t *testing.T) {
+	h := NewAuditHandler(new(MockAuditService), testConfig)
+
+	for _, query := range []string{"entity_id=abc", "actor_id=-1", "from=kemarin", "to=2026-13-01"} {
+		rec, req := newRequest(http.MethodGet, "/audit?"+query, "", nil)
+		h.GetAuditLogs(rec, req)
+
+		assertError(t, rec, http.StatusBadRequest, "bad_request")
+	}
+}
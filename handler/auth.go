@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AuthHandler struct {
+	AuthHandlerService service.AuthService
+	config             utils.Configuration
+}
+
+func NewAuthHandler(authService service.AuthService, config utils.Configuration) AuthHandler {
+	return AuthHandler{
+		AuthHandlerService: authService,
+		config:             config,
+	}
+}
+
+// Login - tukar email dan password dengan token session
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	login, err := h.AuthHandlerService.Login(r.Context(), req.Email, req.Password)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error login")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success login", login)
+}
+
+// VerifyTwoFactor - tukar challenge token dari login + kode TOTP dengan token session
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req dto.TwoFactorLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	login, err := h.AuthHandlerService.VerifyTwoFactor(r.Context(), req.ChallengeToken, req.Code)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error login")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success login", login)
+}
+
+// Logout - cabut token session yang sedang dipakai
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	err := h.AuthHandlerService.Logout(r.Context(), utils.BearerToken(r))
+	if err != nil {
+		utils.ResponseError(w, r, err, "error logout")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success logout", nil)
+}
+
+// UnlockUser - buka akun yang terkunci karena terlalu banyak login gagal
+func (h *AuthHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	if err := h.AuthHandlerService.UnlockAccount(r.Context(), id); err != nil {
+		utils.ResponseError(w, r, err, "error unlock user")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success unlock user", nil)
+}
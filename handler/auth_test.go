@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) Login(ctx context.Context, email, password string) (*dto.LoginResponse, error) {
+	args := m.Called(email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoginResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Authenticate(ctx context.Context, token string) (*utils.Actor, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*utils.Actor), args.Error(1)
+}
+
+func (m *MockAuthService) AuthenticateAPIKey(ctx context.Context, key string) (*utils.Actor, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*utils.Actor), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*dto.LoginResponse, error) {
+	args := m.Called(challengeToken, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoginResponse), args.Error(1)
+}
+
+func (m *MockAuthService) UnlockAccount(ctx context.Context, userId int) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
+
+func TestAuthHandler_Login_AccountLocked(t *testing.T) {
+	mockService := new(MockAuthService)
+	h := NewAuthHandler(mockService, testConfig)
+	mockService.On("Login", "ani@example.com", "salah").
+		Return(nil, apperror.TooManyRequests("account_locked", "account is temporarily locked", 90*time.Second+300*time.Millisecond))
+
+	rec, req := newRequest(http.MethodPost, "/auth/login", `{"email":"ani@example.com","password":"salah"}`, nil)
+	h.Login(rec, req)
+
+	env := assertError(t, rec, http.StatusTooManyRequests, "account_locked")
+	assert.Equal(t, "91", rec.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"retry_after_seconds":91}`, string(env.Error.Details))
+}
+
+func TestAuthHandler_Login_RetryAfterRoundsUp(t *testing.T) {
+	mockService := new(MockAuthService)
+	h := NewAuthHandler(mockService, testConfig)
+	mockService.On("Login", "ani@example.com", "salah").
+		Return(nil, &apperror.Error{Kind: apperror.KindTooManyRequests, Code: "account_locked", Message: "account is temporarily locked", RetryAfter: 300 * time.Millisecond})
+
+	rec, req := newRequest(http.MethodPost, "/auth/login", `{"email":"ani@example.com","password":"salah"}`, nil)
+	h.Login(rec, req)
+
+	assertError(t, rec, http.StatusTooManyRequests, "account_locked")
+	// tunggu kurang dari satu detik tidak boleh menjadi Retry-After: 0
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
+
+func TestAuthHandler_UnlockUser(t *testing.T) {
+	mockService := new(MockAuthService)
+	h := NewAuthHandler(mockService, testConfig)
+	mockService.On("UnlockAccount", 4).Return(nil)
+	mockService.On("UnlockAccount", 99).Return(apperror.NotFound("user"))
+
+	rec, req := newRequest(http.MethodPost, "/users/4/unlock", "", map[string]string{"id": "4"})
+	h.UnlockUser(rec, req)
+	assertSuccess(t, rec, http.StatusOK)
+
+	rec, req = newRequest(http.MethodPost, "/users/99/unlock", "", map[string]string{"id": "99"})
+	h.UnlockUser(rec, req)
+	assertError(t, rec, http.StatusNotFound, "user_not_found")
+
+	rec, req = newRequest(http.MethodPost, "/users/abc/unlock", "", map[string]string{"id": "abc"})
+	h.UnlockUser(rec, req)
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+	mockService.AssertExpectations(t)
+}
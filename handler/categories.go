@@ -34,9 +34,13 @@ func (c *CategoriesHandler) GetCategoriesById(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	response, err := c.CategoriesHandlerService.GetCategoriesById(categoriesID)
+	response, err := c.CategoriesHandlerService.GetCategoriesById(r.Context(), categoriesID)
 	if err != nil {
-		utils.ResponseBadRequest(w, http.StatusNotFound, "category not found", nil)
+		utils.ResponseError(w, r, err, "error getting category")
+		return
+	}
+
+	if utils.NotModified(w, r, response.Version) {
 		return
 	}
 
@@ -44,51 +48,27 @@ func (c *CategoriesHandler) GetCategoriesById(w http.ResponseWriter, r *http.Req
 }
 
 func (c *CategoriesHandler) GetAllCategories(w http.ResponseWriter, r *http.Request) {
-	// Ambil query param page dan limit
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
+	page, limit := parsePagination(r, c.config.Limit)
 
-	page := 1
-	limit := c.config.Limit
-	var err error
-	if pageStr != "" {
-		page, err = strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			page = 1
-		}
-	}
-	if limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil || limit < 1 {
-			limit = c.config.Limit
-		}
+	includeDeleted, err := parseIncludeDeleted(r)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid include_deleted value", nil)
+		return
 	}
 
-	categories, total, err := c.CategoriesHandlerService.GetAllCategories(page, limit)
+	categories, total, err := c.CategoriesHandlerService.GetAllCategories(r.Context(), page, limit, includeDeleted)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting categories")
 		return
 	}
 
-	response := map[string]interface{}{
-		"data": categories,
-		"pagination": map[string]interface{}{
-			"page": page,
-			"limit": limit,
-			"total": total,
-		},
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	utils.ResponsePagination(w, http.StatusOK, "success get all categories", categories, utils.NewPagination(page, limit, total))
 }
 
 func (c *CategoriesHandler) CreateCategories(w http.ResponseWriter, r *http.Request) {
 	var newCategories dto.CategoriesRequest
 	if err := json.NewDecoder(r.Body).Decode(&newCategories); err != nil {
-		utils.ResponseBadRequest(w, http.StatusBadRequest, "error data", nil)
+		utils.ResponseDecodeError(w, err, "error data")
 		return
 	}
 
@@ -103,22 +83,20 @@ func (c *CategoriesHandler) CreateCategories(w http.ResponseWriter, r *http.Requ
 	// parsing to model assignment
 	categories := model.Categories{
 		Name: newCategories.Name,
+		TaxRate: newCategories.TaxRate,
+		TaxInclusive: newCategories.TaxInclusive,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	// create assignment service
-	err = c.CategoriesHandlerService.CreateCategories(&categories)
+	err = c.CategoriesHandlerService.CreateCategories(r.Context(), &categories)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error creating category",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error creating category")
 		return
 	}
 
+	utils.SetETag(w, categories.Version)
 	utils.ResponseSuccess(w, http.StatusCreated, "success created category", categories)
 }
 
@@ -131,9 +109,15 @@ func (c *CategoriesHandler) UpdateCategories(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
 	var newCategories dto.CategoriesRequest
 	if err := json.NewDecoder(r.Body).Decode(&newCategories); err != nil {
-		utils.ResponseBadRequest(w, http.StatusBadRequest, "error data :"+err.Error(), nil)
+		utils.ResponseDecodeError(w, err, "error data :"+err.Error())
 		return
 	}
 
@@ -146,44 +130,92 @@ func (c *CategoriesHandler) UpdateCategories(w http.ResponseWriter, r *http.Requ
 
 	// parsing to model assignment
 	categories := model.Categories{
+		Version: version,
 		Name: newCategories.Name,
+		TaxRate: newCategories.TaxRate,
+		TaxInclusive: newCategories.TaxInclusive,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 
-	err = c.CategoriesHandlerService.UpdateCategories(categoriesID, &categories)
+	err = c.CategoriesHandlerService.UpdateCategories(r.Context(), categoriesID, &categories)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error updating category",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error updating category")
 		return
 	}
 
+	utils.SetETag(w, categories.Version)
 	utils.ResponseSuccess(w, http.StatusOK, "success update category", categories)
 }
 
+func (c *CategoriesHandler) PatchCategories(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
+	var req dto.CategoriesRequest
+	patch, err := utils.DecodeMergePatch(r.Body, &req)
+	if err != nil {
+		utils.ResponseDecodeError(w, err, err.Error())
+		return
+	}
+
+	messages, err := utils.ValidatePartialErrors(req, patch.Fields...)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	category, err := c.CategoriesHandlerService.PatchCategories(r.Context(), id, version, patch.Values)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error patching category")
+		return
+	}
+
+	utils.SetETag(w, category.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success patch category", category)
+}
+
 func (c *CategoriesHandler) DeleteCategories(w http.ResponseWriter, r *http.Request) {
 	categoriesIDstr := chi.URLParam(r, "id")
 
 	categoriesID, err := strconv.Atoi(categoriesIDstr)
 	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
 		return
 	}
 
-	err = c.CategoriesHandlerService.DeleteCategories(categoriesID)
+	err = c.CategoriesHandlerService.DeleteCategories(r.Context(), categoriesID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error deleting category",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error deleting category")
 		return
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "success delete category", nil)
+}
+
+func (c *CategoriesHandler) RestoreCategories(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	category, err := c.CategoriesHandlerService.RestoreCategories(r.Context(), id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error restoring category")
+		return
+	}
+
+	utils.SetETag(w, category.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success restore category", category)
 }
\ No newline at end of file
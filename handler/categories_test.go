@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCategoriesService is a mock implementation of the CategoriesService interface
+type MockCategoriesService struct {
+	mock.Mock
+}
+
+func (m *MockCategoriesService) GetCategoriesById(ctx context.Context, id int) (*model.Categories, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Categories), args.Error(1)
+}
+
+func (m *MockCategoriesService) GetAllCategories(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Categories, int, error) {
+	args := m.Called(page, limit, includeDeleted)
+	return args.Get(0).([]model.Categories), args.Int(1), args.Error(2)
+}
+
+func (m *MockCategoriesService) CreateCategories(ctx context.Context, data *model.Categories) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockCategoriesService) UpdateCategories(ctx context.Context, id int, data *model.Categories) error {
+	args := m.Called(id, data)
+	return args.Error(0)
+}
+
+func (m *MockCategoriesService) PatchCategories(ctx context.Context, id, version int, changes map[string]any) (*model.Categories, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Categories), args.Error(1)
+}
+
+func (m *MockCategoriesService) DeleteCategories(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCategoriesService) RestoreCategories(ctx context.Context, id int) (*model.Categories, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Categories), args.Error(1)
+}
+
+func TestCategoriesHandler_GetCategoriesById_Success(t *testing.T) {
+	mockService := new(MockCategoriesService)
+	h := NewCategoriesHandler(mockService, testConfig)
+	mockService.On("GetCategoriesById", 1).Return(&model.Categories{Id: 1, Name: "Elektronik"}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/categories/1", "", map[string]string{"id": "1"})
+	h.GetCategoriesById(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var data model.Categories
+	require.NoError(t, json.Unmarshal(env.Data, &data))
+	assert.Equal(t, "Elektronik", data.Name)
+}
+
+func TestCategoriesHandler_GetCategoriesById_NotFound(t *testing.T) {
+	mockService := new(MockCategoriesService)
+	h := NewCategoriesHandler(mockService, testConfig)
+	mockService.On("GetCategoriesById", 999).Return(nil, apperror.NotFound("category"))
+
+	rec, req := newRequest(http.MethodGet, "/categories/999", "", map[string]string{"id": "999"})
+	h.GetCategoriesById(rec, req)
+
+	assertError(t, rec, http.StatusNotFound, "category_not_found")
+}
+
+func TestCategoriesHandler_GetAllCategories_Paginated(t *testing.T) {
+	mockService := new(MockCategoriesService)
+	h := NewCategoriesHandler(mockService, testConfig)
+	mockService.On("GetAllCategories", 1, 5, false).Return([]model.Categories{{Id: 1}, {Id: 2}}, 7, nil)
+
+	rec, req := newRequest(http.MethodGet, "/categories?limit=5", "", nil)
+	h.GetAllCategories(rec, req)
+
+	assertPaginated(t, rec, 1, 5, 2, 7)
+}
+
+func TestCategoriesHandler_CreateCategories_ValidationError(t *testing.T) {
+	h := NewCategoriesHandler(new(MockCategoriesService), testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/categories", `{}`, nil)
+	h.CreateCategories(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+}
+
+func TestCategoriesHandler_CreateCategories_TaxRatePrecision(t *testing.T) {
+	h := NewCategoriesHandler(new(MockCategoriesService), testConfig)
+
+	for _, body := range []string{`{"name":"Minuman","tax_rate":11.005}`, `{"name":"Minuman","tax_rate":101}`} {
+		rec, req := newRequest(http.MethodPost, "/categories", body, nil)
+		h.CreateCategories(rec, req)
+
+		assertError(t, rec, http.StatusBadRequest, "validation_error")
+	}
+}
+
+func TestCategoriesHandler_UpdateCategories_Success(t *testing.T) {
+	mockService := new(MockCategoriesService)
+	h := NewCategoriesHandler(mockService, testConfig)
+	mockService.On("UpdateCategories", 1, mock.Anything).Return(nil)
+
+	rec, req := newRequest(http.MethodPut, "/categories/1", `{"name":"Elektronik"}`, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", `"1"`)
+	h.UpdateCategories(rec, req)
+
+	assertSuccess(t, rec, http.StatusOK)
+}
+
+func TestCategoriesHandler_DeleteCategories_StillInUse(t *testing.T) {
+	mockService := new(MockCategoriesService)
+	h := NewCategoriesHandler(mockService, testConfig)
+	mockService.On("DeleteCategories", 1).Return(apperror.ForeignKeyViolation("category_foreign_key_violation", "category is still in use"))
+
+	rec, req := newRequest(http.MethodDelete, "/categories/1", "", map[string]string{"id": "1"})
+	h.DeleteCategories(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "category_foreign_key_violation")
+}
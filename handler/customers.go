@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CustomersHandler struct {
+	CustomersHandlerService service.CustomersService
+	config                  utils.Configuration
+}
+
+func NewCustomersHandler(customersService service.CustomersService, config utils.Configuration) CustomersHandler {
+	return CustomersHandler{
+		CustomersHandlerService: customersService,
+		config:                  config,
+	}
+}
+
+func (h *CustomersHandler) GetCustomersById(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	customer, err := h.CustomersHandlerService.GetCustomersById(r.Context(), id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting customer")
+		return
+	}
+
+	if utils.NotModified(w, r, customer.Version) {
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success get data customer by id", customer)
+}
+
+func (h *CustomersHandler) GetAllCustomers(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, h.config.Limit)
+
+	includeDeleted, err := parseIncludeDeleted(r)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid include_deleted value", nil)
+		return
+	}
+
+	customers, total, err := h.CustomersHandlerService.GetAllCustomers(r.Context(), page, limit, includeDeleted)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting customers")
+		return
+	}
+
+	utils.ResponsePagination(w, http.StatusOK, "success get all customers", customers, utils.NewPagination(page, limit, total))
+}
+
+func (h *CustomersHandler) CreateCustomers(w http.ResponseWriter, r *http.Request) {
+	var req dto.CustomersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseDecodeError(w, err, "error data")
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	customer := customerFromRequest(req)
+	if err := h.CustomersHandlerService.CreateCustomers(r.Context(), &customer); err != nil {
+		utils.ResponseError(w, r, err, "error creating customer")
+		return
+	}
+
+	utils.SetETag(w, customer.Version)
+	utils.ResponseSuccess(w, http.StatusCreated, "success created customer", customer)
+}
+
+func (h *CustomersHandler) UpdateCustomers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
+	var req dto.CustomersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseDecodeError(w, err, "error data")
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	customer := customerFromRequest(req)
+	customer.Version = version
+	if err := h.CustomersHandlerService.UpdateCustomers(r.Context(), id, &customer); err != nil {
+		utils.ResponseError(w, r, err, "error updating customer")
+		return
+	}
+
+	utils.SetETag(w, customer.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success update customer", customer)
+}
+
+func (h *CustomersHandler) PatchCustomers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
+	var req dto.CustomersRequest
+	patch, err := utils.DecodeMergePatch(r.Body, &req)
+	if err != nil {
+		utils.ResponseDecodeError(w, err, err.Error())
+		return
+	}
+
+	messages, err := utils.ValidatePartialErrors(req, patch.Fields...)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	customer, err := h.CustomersHandlerService.PatchCustomers(r.Context(), id, version, patch.Values)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error patching customer")
+		return
+	}
+
+	utils.SetETag(w, customer.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success patch customer", customer)
+}
+
+func (h *CustomersHandler) DeleteCustomers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	if err := h.CustomersHandlerService.DeleteCustomers(r.Context(), id); err != nil {
+		utils.ResponseError(w, r, err, "error deleting customer")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success delete customer", nil)
+}
+
+func (h *CustomersHandler) RestoreCustomers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	customer, err := h.CustomersHandlerService.RestoreCustomers(r.Context(), id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error restoring customer")
+		return
+	}
+
+	utils.SetETag(w, customer.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success restore customer", customer)
+}
+
+func (h *CustomersHandler) GetCustomerSales(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+	page, limit := parsePagination(r, h.config.Limit)
+
+	sales, total, err := h.CustomersHandlerService.GetCustomerSales(r.Context(), id, page, limit)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting customer sales")
+		return
+	}
+
+	utils.ResponsePagination(w, http.StatusOK, "success get customer sales", sales, utils.NewPagination(page, limit, total))
+}
+
+func customerFromRequest(req dto.CustomersRequest) model.Customers {
+	return model.Customers{
+		Name:        req.Name,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		TaxId:       req.TaxId,
+		Address:     req.Address,
+		CreditLimit: req.CreditLimit,
+	}
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCustomersService is a mock implementation of the CustomersService interface
+type MockCustomersService struct {
+	mock.Mock
+}
+
+func (m *MockCustomersService) GetCustomersById(ctx context.Context, id int) (*model.Customers, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customers), args.Error(1)
+}
+
+func (m *MockCustomersService) GetAllCustomers(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Customers, int, error) {
+	args := m.Called(page, limit, includeDeleted)
+	return args.Get(0).([]model.Customers), args.Int(1), args.Error(2)
+}
+
+func (m *MockCustomersService) CreateCustomers(ctx context.Context, data *model.Customers) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockCustomersService) UpdateCustomers(ctx context.Context, id int, data *model.Customers) error {
+	args := m.Called(id, data)
+	return args.Error(0)
+}
+
+func (m *MockCustomersService) PatchCustomers(ctx context.Context, id, version int, changes map[string]any) (*model.Customers, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customers), args.Error(1)
+}
+
+func (m *MockCustomersService) DeleteCustomers(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCustomersService) RestoreCustomers(ctx context.Context, id int) (*model.Customers, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customers), args.Error(1)
+}
+
+func (m *MockCustomersService) GetCustomerSales(ctx context.Context, id, page, limit int) ([]dto.SalesResponse, int, error) {
+	args := m.Called(id, page, limit)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]dto.SalesResponse), args.Int(1), args.Error(2)
+}
+
+func TestCustomersHandler_GetCustomersById_Success(t *testing.T) {
+	mockService := new(MockCustomersService)
+	h := NewCustomersHandler(mockService, testConfig)
+	mockService.On("GetCustomersById", 1).Return(&model.Customers{Id: 1, Name: "PT Maju", Version: 2}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/customers/1", "", map[string]string{"id": "1"})
+	h.GetCustomersById(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var data model.Customers
+	require.NoError(t, json.Unmarshal(env.Data, &data))
+	assert.Equal(t, "PT Maju", data.Name)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+}
+
+func TestCustomersHandler_GetAllCustomers_Paginated(t *testing.T) {
+	mockService := new(MockCustomersService)
+	h := NewCustomersHandler(mockService, testConfig)
+	mockService.On("GetAllCustomers", 2, 10, false).Return([]model.Customers{{Id: 11}}, 11, nil)
+
+	rec, req := newRequest(http.MethodGet, "/customers?page=2", "", nil)
+	h.GetAllCustomers(rec, req)
+
+	assertPaginated(t, rec, 2, 10, 2, 11)
+}
+
+func TestCustomersHandler_CreateCustomers_Success(t *testing.T) {
+	mockService := new(MockCustomersService)
+	h := NewCustomersHandler(mockService, testConfig)
+	mockService.On("CreateCustomers", mock.MatchedBy(func(c *model.Customers) bool {
+		return c.Name == "PT Maju" && c.TaxId == "0123" && c.CreditLimit == model.MustParseMoney("5000")
+	})).Run(func(args mock.Arguments) {
+		c := args.Get(0).(*model.Customers)
+		c.Id, c.Version = 1, 1
+	}).Return(nil)
+
+	rec, req := newRequest(http.MethodPost, "/customers", `{"name":"PT Maju","tax_id":"0123","credit_limit":5000}`, nil)
+	h.CreateCustomers(rec, req)
+
+	assertSuccess(t, rec, http.StatusCreated)
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomersHandler_CreateCustomers_ValidationError(t *testing.T) {
+	mockService := new(MockCustomersService)
+	h := NewCustomersHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/customers", `{"name":"PT Maju","email":"bukan-email","credit_limit":-1}`, nil)
+	h.CreateCustomers(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+	mockService.AssertNotCalled(t, "CreateCustomers", mock.Anything)
+}
+
+func TestCustomersHandler_CreditLimitPrecision(t *testing.T) {
+	mockService := new(MockCustomersService)
+	h := NewCustomersHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/customers", `{"name":"PT Maju","credit_limit":5000.125}`, nil)
+	h.CreateCustomers(rec, req)
+	env := assertError(t, rec, http.StatusBadRequest, "validation_error")
+	assert.NotContains(t, env.Message, "json")
+
+	rec, req = newRequest(http.MethodPut, "/customers/1", `{"name":"PT Maju","credit_limit":5000.125}`, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", `"1"`)
+	h.UpdateCustomers(rec, req)
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+
+	rec, req = newRequest(http.MethodPatch, "/customers/1", `{"credit_limit":5000.125}`, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", `"1"`)
+	h.PatchCustomers(rec, req)
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomersHandler_UpdateCustomers_RequiresIfMatch(t *testing.T) {
+	mockService := new(MockCustomersService)
+	h := NewCustomersHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodPut, "/customers/1", `{"name":"PT Maju"}`, map[string]string{"id": "1"})
+	h.UpdateCustomers(rec, req)
+
+	assertError(t, rec, http.StatusPreconditionRequired, "precondition_required")
+}
+
+func TestCustomersHandler_GetCustomerSales(t *testing.T) {
+	mockService := new(MockCustomersService)
+	h := NewCustomersHandler(mockService, testConfig)
+	customerId := 3
+	mockService.On("GetCustomerSales", 3, 1, 10).Return([]dto.SalesResponse{{Id: 12, CustomerId: &customerId, PaymentType: "credit"}}, 1, nil)
+
+	rec, req := newRequest(http.MethodGet, "/customers/3/sales", "", map[string]string{"id": "3"})
+	h.GetCustomerSales(rec, req)
+
+	assertPaginated(t, rec, 1, 10, 1, 1)
+}
+
+func TestCustomersHandler_GetCustomerSales_NotFound(t *testing.T) {
+	mockService := new(MockCustomersService)
+	h := NewCustomersHandler(mockService, testConfig)
+	mockService.On("GetCustomerSales", 9, 1, 10).Return(nil, 0, apperror.NotFound("customer"))
+
+	rec, req := newRequest(http.MethodGet, "/customers/9/sales", "", map[string]string{"id": "9"})
+	h.GetCustomerSales(rec, req)
+
+	assertError(t, rec, http.StatusNotFound, "customer_not_found")
+}
@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
 	"project-app-inventory-restapi-golang-azwin/service"
 	"project-app-inventory-restapi-golang-azwin/utils"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 type Handler struct {
@@ -12,7 +17,16 @@ type Handler struct {
 	WarehousesHandler WarehousesHandler
 	UsersHandler UsersHandler
 	SalesHandler SalesHandler
+	CustomersHandler CustomersHandler
+	PaymentsHandler PaymentsHandler
+	ReceiptsHandler ReceiptsHandler
 	ReportsHandler ReportsHandler
+	AuthHandler AuthHandler
+	AuditHandler AuditHandler
+	PasswordHandler PasswordHandler
+	TwoFactorHandler TwoFactorHandler
+	APIKeysHandler APIKeysHandler
+	ReservationsHandler ReservationsHandler
 }
 
 func NewHandler(service service.Service, config utils.Configuration) Handler {
@@ -23,6 +37,60 @@ func NewHandler(service service.Service, config utils.Configuration) Handler {
 		WarehousesHandler: NewWarehousesHandler(service.WarehousesService, config),
 		UsersHandler: NewUsersHandler(service.UsersService, config),
 		SalesHandler: NewSalesHandler(service.SalesService, config),
+		CustomersHandler: NewCustomersHandler(service.CustomersService, config),
+		PaymentsHandler: NewPaymentsHandler(service.PaymentsService, config),
+		ReceiptsHandler: NewReceiptsHandler(service.ReceiptsService, config),
 		ReportsHandler: NewReportsHandler(service.ReportsService, config),
+		AuthHandler: NewAuthHandler(service.AuthService, config),
+		AuditHandler: NewAuditHandler(service.AuditService, config),
+		PasswordHandler: NewPasswordHandler(service.PasswordService, config),
+		TwoFactorHandler: NewTwoFactorHandler(service.TwoFactorService, config),
+		APIKeysHandler: NewAPIKeysHandler(service.APIKeysService, config),
+		ReservationsHandler: NewReservationsHandler(service.ReservationsService, config),
 	}
 }
+
+// maxLimit sama dengan batas limit di service
+const maxLimit = 100
+
+// parsePagination membaca query param page dan limit dengan default dari config
+func parsePagination(r *http.Request, defaultLimit int) (int, int) {
+	page := utils.StringToInt(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit := utils.StringToInt(r.URL.Query().Get("limit"))
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	return page, limit
+}
+
+// parseIncludeDeleted membaca query param include_deleted (default false)
+func parseIncludeDeleted(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("include_deleted")
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// parseInclude membaca query param include (dipisah koma, misal "items") dan
+// menolak nilai di luar allowed
+func parseInclude(r *http.Request, allowed ...string) (map[string]bool, error) {
+	include := map[string]bool{}
+	for _, value := range strings.Split(r.URL.Query().Get("include"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !slices.Contains(allowed, value) {
+			return nil, fmt.Errorf("invalid include value %q", value)
+		}
+		include[value] = true
+	}
+	return include, nil
+}
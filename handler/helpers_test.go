@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = utils.Configuration{Limit: 10}
+
+// envelope mirrors utils.Response with raw data so each test can decode its own payload
+type envelope struct {
+	Status     bool            `json:"status"`
+	Message    string          `json:"message"`
+	Data       json.RawMessage `json:"data"`
+	Pagination *struct {
+		CurrentPage  int `json:"current_page"`
+		Limit        int `json:"limit"`
+		TotalPages   int `json:"total_pages"`
+		TotalRecords int `json:"total_records"`
+	} `json:"pagination"`
+	Error *struct {
+		Code    string          `json:"code"`
+		Details json.RawMessage `json:"details"`
+	} `json:"error"`
+	RequestID string `json:"request_id"`
+}
+
+// newRequest builds a request with chi URL params and a request id already echoed by middleware
+func newRequest(method, target string, body string, params map[string]string) (*httptest.ResponseRecorder, *http.Request) {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set(utils.HeaderRequestID, "test-request-id")
+	return rec, req
+}
+
+// decodeEnvelope asserts the common contract (JSON content type, top-level keys) and returns the body
+func decodeEnvelope(t *testing.T, rec *httptest.ResponseRecorder, expectedStatus int) envelope {
+	t.Helper()
+	assert.Equal(t, expectedStatus, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var raw map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+	for key := range raw {
+		assert.Contains(t, []string{"status", "message", "data", "pagination", "error", "request_id"}, key, "unexpected top-level key")
+	}
+
+	var env envelope
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+	assert.NotEmpty(t, env.Message)
+	assert.Equal(t, "test-request-id", env.RequestID)
+	return env
+}
+
+func assertSuccess(t *testing.T, rec *httptest.ResponseRecorder, expectedStatus int) envelope {
+	t.Helper()
+	env := decodeEnvelope(t, rec, expectedStatus)
+	assert.True(t, env.Status)
+	assert.Nil(t, env.Error)
+	return env
+}
+
+func assertPaginated(t *testing.T, rec *httptest.ResponseRecorder, page, limit, totalPages, totalRecords int) envelope {
+	t.Helper()
+	env := assertSuccess(t, rec, http.StatusOK)
+	require.NotNil(t, env.Pagination)
+	assert.Equal(t, page, env.Pagination.CurrentPage)
+	assert.Equal(t, limit, env.Pagination.Limit)
+	assert.Equal(t, totalPages, env.Pagination.TotalPages)
+	assert.Equal(t, totalRecords, env.Pagination.TotalRecords)
+	assert.True(t, strings.HasPrefix(strings.TrimSpace(string(env.Data)), "["), "paginated data must be a JSON array")
+	return env
+}
+
+func assertError(t *testing.T, rec *httptest.ResponseRecorder, expectedStatus int, code string) envelope {
+	t.Helper()
+	env := decodeEnvelope(t, rec, expectedStatus)
+	assert.False(t, env.Status)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, code, env.Error.Code)
+	assert.Empty(t, env.Data)
+	return env
+}
@@ -34,9 +34,13 @@ func (i *ItemsHandler) GetItemsById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := i.ItemsHandlerService.GetItemsById(itemID)
+	response, err := i.ItemsHandlerService.GetItemsById(r.Context(), itemID)
 	if err != nil {
-		utils.ResponseBadRequest(w, http.StatusNotFound, "item not found", nil)
+		utils.ResponseError(w, r, err, "error getting item")
+		return
+	}
+
+	if utils.NotModified(w, r, response.Version) {
 		return
 	}
 
@@ -44,45 +48,21 @@ func (i *ItemsHandler) GetItemsById(w http.ResponseWriter, r *http.Request) {
 }
 
 func (i *ItemsHandler) GetAllItems(w http.ResponseWriter, r *http.Request) {
-	// Ambil query param page dan limit
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
+	page, limit := parsePagination(r, i.config.Limit)
 
-	page := 1
-	limit := i.config.Limit
-	var err error
-	if pageStr != "" {
-		page, err = strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			page = 1
-		}
-	}
-	if limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil || limit < 1 {
-			limit = i.config.Limit
-		}
+	includeDeleted, err := parseIncludeDeleted(r)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid include_deleted value", nil)
+		return
 	}
 
-	items, total, err := i.ItemsHandlerService.GetAllItems(page, limit)
+	items, total, err := i.ItemsHandlerService.GetAllItems(r.Context(), page, limit, includeDeleted)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting items")
 		return
 	}
 
-	response := map[string]interface{}{
-		"data": items,
-		"pagination": map[string]interface{}{
-			"page": page,
-			"limit": limit,
-			"total": total,
-		},
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	utils.ResponsePagination(w, http.StatusOK, "success get all items", items, utils.NewPagination(page, limit, total))
 }
 
 func (i *ItemsHandler) GetLowStockItems(w http.ResponseWriter, r *http.Request) {
@@ -99,34 +79,28 @@ func (i *ItemsHandler) GetLowStockItems(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	items, err := i.ItemsHandlerService.GetLowStockItems(threshold)
+	items, err := i.ItemsHandlerService.GetLowStockItems(r.Context(), threshold)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error getting low stock items",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting low stock items")
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":    true,
-		"message":   "success get low stock items",
-		"data":      items,
-		"threshold": threshold,
-		"count":     len(items),
+	if items == nil {
+		items = []model.Items{}
 	}
-	
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	response := dto.LowStockResponse{
+		Threshold: threshold,
+		Count:     len(items),
+		Items:     items,
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success get low stock items", response)
 }
 
 func (i *ItemsHandler) CreateItems(w http.ResponseWriter, r *http.Request) {
 	var newItem dto.ItemsRequest
 	if err := json.NewDecoder(r.Body).Decode(&newItem); err != nil {
-		utils.ResponseBadRequest(w, http.StatusBadRequest, "error data", nil)
+		utils.ResponseDecodeError(w, err, "error data")
 		return
 	}
 
@@ -148,22 +122,20 @@ func (i *ItemsHandler) CreateItems(w http.ResponseWriter, r *http.Request) {
 		Stock: newItem.Stock,
 		MinStock: newItem.MinStock,
 		Price: newItem.Price,
+		TaxRate: newItem.TaxRate,
+		TaxInclusive: newItem.TaxInclusive,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	// create assignment service
-	err = i.ItemsHandlerService.CreateItems(&items)
+	err = i.ItemsHandlerService.CreateItems(r.Context(), &items)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error creating item",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error creating item")
 		return
 	}
 
+	utils.SetETag(w, items.Version)
 	utils.ResponseSuccess(w, http.StatusCreated, "success created item", items)
 }
 
@@ -176,9 +148,16 @@ func (i *ItemsHandler) UpdateItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// optimistic concurrency: versi yang diharapkan client wajib dikirim lewat If-Match
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
 	var newItem dto.ItemsRequest
 	if err := json.NewDecoder(r.Body).Decode(&newItem); err != nil {
-		utils.ResponseBadRequest(w, http.StatusBadRequest, "error data :"+err.Error(), nil)
+		utils.ResponseDecodeError(w, err, "error data :"+err.Error())
 		return
 	}
 
@@ -191,6 +170,7 @@ func (i *ItemsHandler) UpdateItems(w http.ResponseWriter, r *http.Request) {
 
 	// parsing to model assignment
 	items := model.Items{
+		Version: version,
 		Id: newItem.Id,
 		CategoryId: newItem.CategoryId,
 		RackId: newItem.RackId,
@@ -199,43 +179,91 @@ func (i *ItemsHandler) UpdateItems(w http.ResponseWriter, r *http.Request) {
 		Stock: newItem.Stock,
 		MinStock: newItem.MinStock,
 		Price: newItem.Price,
+		TaxRate: newItem.TaxRate,
+		TaxInclusive: newItem.TaxInclusive,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 
-	err = i.ItemsHandlerService.UpdateItems(itemID, &items)
+	err = i.ItemsHandlerService.UpdateItems(r.Context(), itemID, &items)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error updating item",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error updating item")
 		return
 	}
 
+	utils.SetETag(w, items.Version)
 	utils.ResponseSuccess(w, http.StatusOK, "success update item", items)
 }
 
+// PatchItems menerapkan JSON Merge Patch (RFC 7396): hanya field yang dikirim yang divalidasi dan diupdate
+func (i *ItemsHandler) PatchItems(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
+	var req dto.ItemsRequest
+	patch, err := utils.DecodeMergePatch(r.Body, &req)
+	if err != nil {
+		utils.ResponseDecodeError(w, err, err.Error())
+		return
+	}
+
+	messages, err := utils.ValidatePartialErrors(req, patch.Fields...)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	item, err := i.ItemsHandlerService.PatchItems(r.Context(), id, version, patch.Values)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error patching item")
+		return
+	}
+
+	utils.SetETag(w, item.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success patch item", item)
+}
+
 func (i *ItemsHandler) DeleteItems(w http.ResponseWriter, r *http.Request) {
 	itemIDstr := chi.URLParam(r, "id")
 
 	itemID, err := strconv.Atoi(itemIDstr)
 	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
 		return
 	}
 
-	err = i.ItemsHandlerService.DeleteItems(itemID)
+	err = i.ItemsHandlerService.DeleteItems(r.Context(), itemID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error deleting item",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error deleting item")
 		return
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "success delete item", nil)
+}
+
+func (i *ItemsHandler) RestoreItems(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	item, err := i.ItemsHandlerService.RestoreItems(r.Context(), id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error restoring item")
+		return
+	}
+
+	utils.SetETag(w, item.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success restore item", item)
 }
\ No newline at end of file
@@ -0,0 +1,376 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockItemsService is a mock implementation of the ItemsService interface
+type MockItemsService struct {
+	mock.Mock
+}
+
+func (m *MockItemsService) GetItemsById(ctx context.Context, id int) (*model.Items, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Items), args.Error(1)
+}
+
+func (m *MockItemsService) GetAllItems(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Items, int, error) {
+	args := m.Called(page, limit, includeDeleted)
+	return args.Get(0).([]model.Items), args.Int(1), args.Error(2)
+}
+
+func (m *MockItemsService) GetLowStockItems(ctx context.Context, threshold int) ([]model.Items, error) {
+	args := m.Called(threshold)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Items), args.Error(1)
+}
+
+func (m *MockItemsService) CreateItems(ctx context.Context, data *model.Items) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockItemsService) UpdateItems(ctx context.Context, id int, data *model.Items) error {
+	args := m.Called(id, data)
+	return args.Error(0)
+}
+
+func (m *MockItemsService) PatchItems(ctx context.Context, id, version int, changes map[string]any) (*model.Items, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Items), args.Error(1)
+}
+
+func (m *MockItemsService) DeleteItems(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockItemsService) RestoreItems(ctx context.Context, id int) (*model.Items, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Items), args.Error(1)
+}
+
+func TestItemsHandler_GetItemsById_Success(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("GetItemsById", 1).Return(&model.Items{Id: 1, Name: "Laptop", Sku: "LP-01"}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/items/1", "", map[string]string{"id": "1"})
+	h.GetItemsById(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var item model.Items
+	require.NoError(t, json.Unmarshal(env.Data, &item))
+	assert.Equal(t, "Laptop", item.Name)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemsHandler_GetItemsById_NotFound(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("GetItemsById", 999).Return(nil, apperror.NotFound("item"))
+
+	rec, req := newRequest(http.MethodGet, "/items/999", "", map[string]string{"id": "999"})
+	h.GetItemsById(rec, req)
+
+	env := assertError(t, rec, http.StatusNotFound, "item_not_found")
+	assert.Equal(t, "item not found", env.Message)
+}
+
+func TestItemsHandler_GetItemsById_InvalidId(t *testing.T) {
+	h := NewItemsHandler(new(MockItemsService), testConfig)
+
+	rec, req := newRequest(http.MethodGet, "/items/abc", "", map[string]string{"id": "abc"})
+	h.GetItemsById(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+}
+
+func TestItemsHandler_GetAllItems_Paginated(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("GetAllItems", 2, 10, false).Return([]model.Items{{Id: 11}, {Id: 12}}, 25, nil)
+
+	rec, req := newRequest(http.MethodGet, "/items?page=2", "", nil)
+	h.GetAllItems(rec, req)
+
+	env := assertPaginated(t, rec, 2, 10, 3, 25)
+	var items []model.Items
+	require.NoError(t, json.Unmarshal(env.Data, &items))
+	assert.Len(t, items, 2)
+}
+
+func TestItemsHandler_GetAllItems_EmptyListIsArray(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("GetAllItems", 1, 10, false).Return([]model.Items(nil), 0, nil)
+
+	rec, req := newRequest(http.MethodGet, "/items", "", nil)
+	h.GetAllItems(rec, req)
+
+	env := assertPaginated(t, rec, 1, 10, 0, 0)
+	assert.JSONEq(t, "[]", string(env.Data))
+}
+
+func TestItemsHandler_GetAllItems_InternalError(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("GetAllItems", 1, 10, false).Return([]model.Items(nil), 0, errors.New("connection reset"))
+
+	rec, req := newRequest(http.MethodGet, "/items", "", nil)
+	h.GetAllItems(rec, req)
+
+	env := assertError(t, rec, http.StatusInternalServerError, "internal_error")
+	assert.NotContains(t, env.Message, "connection reset")
+}
+
+func TestItemsHandler_GetLowStockItems(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("GetLowStockItems", 3).Return([]model.Items{{Id: 1, Stock: 1}}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/items/low-stock?threshold=3", "", nil)
+	h.GetLowStockItems(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var data dto.LowStockResponse
+	require.NoError(t, json.Unmarshal(env.Data, &data))
+	assert.Equal(t, 3, data.Threshold)
+	assert.Equal(t, 1, data.Count)
+	assert.Len(t, data.Items, 1)
+}
+
+func TestItemsHandler_CreateItems_ValidationError(t *testing.T) {
+	h := NewItemsHandler(new(MockItemsService), testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/items", `{"name":"ab"}`, nil)
+	h.CreateItems(rec, req)
+
+	env := assertError(t, rec, http.StatusBadRequest, "validation_error")
+	var details []map[string]string
+	require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+	assert.NotEmpty(t, details)
+	assert.Contains(t, details[0], "field")
+}
+
+func TestItemsHandler_CreateItems_Conflict(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("CreateItems", mock.Anything).Return(apperror.Conflict("item_already_exists", "item already exists"))
+
+	rec, req := newRequest(http.MethodPost, "/items", validItemBody, nil)
+	h.CreateItems(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "item_already_exists")
+}
+
+func TestItemsHandler_GetItemsById_ETag(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("GetItemsById", 1).Return(&model.Items{Id: 1, Version: 3}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/items/1", "", map[string]string{"id": "1"})
+	h.GetItemsById(rec, req)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+	rec, req = newRequest(http.MethodGet, "/items/1", "", map[string]string{"id": "1"})
+	req.Header.Set("If-None-Match", `"3"`)
+	h.GetItemsById(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	rec, req = newRequest(http.MethodGet, "/items/1", "", map[string]string{"id": "1"})
+	req.Header.Set("If-None-Match", `"2"`)
+	h.GetItemsById(rec, req)
+	assertSuccess(t, rec, http.StatusOK)
+}
+
+const validItemBody = `{"category_id":1,"rack_id":1,"name":"Laptop","sku":"LP-01","stock":1,"min_stock":1,"price":10}`
+
+func TestItemsHandler_UpdateItems_RequiresIfMatch(t *testing.T) {
+	h := NewItemsHandler(new(MockItemsService), testConfig)
+
+	rec, req := newRequest(http.MethodPut, "/items/1", validItemBody, map[string]string{"id": "1"})
+	h.UpdateItems(rec, req)
+
+	assertError(t, rec, http.StatusPreconditionRequired, "precondition_required")
+}
+
+func TestItemsHandler_UpdateItems_VersionMismatch(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("UpdateItems", 1, mock.MatchedBy(func(i *model.Items) bool { return i.Version == 2 })).
+		Return(apperror.PreconditionFailed("item", 3))
+
+	rec, req := newRequest(http.MethodPut, "/items/1", validItemBody, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", `"2"`)
+	h.UpdateItems(rec, req)
+
+	assertError(t, rec, http.StatusPreconditionFailed, "item_version_mismatch")
+	mockService.AssertExpectations(t)
+}
+
+func TestItemsHandler_UpdateItems_ReturnsNewETag(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("UpdateItems", 1, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.Items).Version = 3
+	}).Return(nil)
+
+	rec, req := newRequest(http.MethodPut, "/items/1", validItemBody, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", `"2"`)
+	h.UpdateItems(rec, req)
+
+	assertSuccess(t, rec, http.StatusOK)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+}
+
+func TestItemsHandler_DeleteItems(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("DeleteItems", 1).Return(nil)
+
+	rec, req := newRequest(http.MethodDelete, "/items/1", "", map[string]string{"id": "1"})
+	h.DeleteItems(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	assert.Empty(t, env.Data)
+}
+
+func TestItemsHandler_DeleteItems_InvalidId(t *testing.T) {
+	h := NewItemsHandler(new(MockItemsService), testConfig)
+
+	rec, req := newRequest(http.MethodDelete, "/items/x", "", map[string]string{"id": "x"})
+	h.DeleteItems(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+}
+
+func TestItemsHandler_PatchItems_ValidatesOnlyProvidedFields(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("PatchItems", 1, 2, map[string]any{"stock": 7}).Return(&model.Items{Id: 1, Name: "Laptop", Stock: 7, Version: 3}, nil)
+
+	// name, sku, price dll. wajib di PUT tapi tidak dikirim di sini
+	rec, req := newRequest(http.MethodPatch, "/items/1", `{"stock":7}`, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", `"2"`)
+	h.PatchItems(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var item model.Items
+	require.NoError(t, json.Unmarshal(env.Data, &item))
+	assert.Equal(t, 7, item.Stock)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestItemsHandler_PatchItems_InvalidProvidedField(t *testing.T) {
+	h := NewItemsHandler(new(MockItemsService), testConfig)
+
+	rec, req := newRequest(http.MethodPatch, "/items/1", `{"name":"ab"}`, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", `"2"`)
+	h.PatchItems(rec, req)
+
+	env := assertError(t, rec, http.StatusBadRequest, "validation_error")
+	var details []map[string]string
+	require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+	require.Len(t, details, 1)
+	assert.Equal(t, "Name", details[0]["field"])
+}
+
+func TestItemsHandler_PatchItems_RejectsBadPatch(t *testing.T) {
+	h := NewItemsHandler(new(MockItemsService), testConfig)
+
+	for body, message := range map[string]string{
+		`{"name":null}`:   "field name cannot be null",
+		`{"color":"red"}`: "unknown field color",
+		`{}`:              "patch body must contain at least one field",
+		`[1]`:             "patch body must be a JSON object",
+		`{"stock":"x"}`:   "invalid value for field stock",
+	} {
+		rec, req := newRequest(http.MethodPatch, "/items/1", body, map[string]string{"id": "1"})
+		req.Header.Set("If-Match", `"2"`)
+		h.PatchItems(rec, req)
+
+		env := assertError(t, rec, http.StatusBadRequest, "bad_request")
+		assert.Equal(t, message, env.Message, body)
+	}
+}
+
+func TestItemsHandler_PatchItems_RequiresIfMatch(t *testing.T) {
+	h := NewItemsHandler(new(MockItemsService), testConfig)
+
+	rec, req := newRequest(http.MethodPatch, "/items/1", `{"stock":7}`, map[string]string{"id": "1"})
+	h.PatchItems(rec, req)
+
+	assertError(t, rec, http.StatusPreconditionRequired, "precondition_required")
+}
+
+func TestItemsHandler_GetAllItems_IncludeDeleted(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	deletedAt := time.Now()
+	mockService.On("GetAllItems", 1, 10, true).Return([]model.Items{{Id: 1}, {Id: 2, DeletedAt: &deletedAt}}, 2, nil)
+
+	rec, req := newRequest(http.MethodGet, "/items?include_deleted=true", "", nil)
+	h.GetAllItems(rec, req)
+
+	env := assertPaginated(t, rec, 1, 10, 1, 2)
+	assert.Contains(t, string(env.Data), `"deleted_at"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemsHandler_GetAllItems_InvalidIncludeDeleted(t *testing.T) {
+	h := NewItemsHandler(new(MockItemsService), testConfig)
+
+	rec, req := newRequest(http.MethodGet, "/items?include_deleted=maybe", "", nil)
+	h.GetAllItems(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+}
+
+func TestItemsHandler_RestoreItems(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("RestoreItems", 3).Return(&model.Items{Id: 3, Version: 5}, nil)
+
+	rec, req := newRequest(http.MethodPost, "/items/3/restore", "", map[string]string{"id": "3"})
+	h.RestoreItems(rec, req)
+
+	assertSuccess(t, rec, http.StatusOK)
+	assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+}
+
+func TestItemsHandler_RestoreItems_NotDeleted(t *testing.T) {
+	mockService := new(MockItemsService)
+	h := NewItemsHandler(mockService, testConfig)
+	mockService.On("RestoreItems", 3).Return(nil, apperror.Conflict("item_not_deleted", "item is not deleted"))
+
+	rec, req := newRequest(http.MethodPost, "/items/3/restore", "", map[string]string{"id": "3"})
+	h.RestoreItems(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "item_not_deleted")
+}
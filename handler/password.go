@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PasswordHandler struct {
+	PasswordHandlerService service.PasswordService
+	config                 utils.Configuration
+}
+
+func NewPasswordHandler(passwordService service.PasswordService, config utils.Configuration) PasswordHandler {
+	return PasswordHandler{
+		PasswordHandlerService: passwordService,
+		config:                 config,
+	}
+}
+
+// ChangeMyPassword - ganti password user yang sedang login. Semua session
+// (termasuk token yang dipakai sekarang) dicabut, jadi client harus login ulang.
+func (h *PasswordHandler) ChangeMyPassword(w http.ResponseWriter, r *http.Request) {
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		utils.ResponseError(w, r, apperror.Unauthorized("authentication required"), "error changing password")
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	err = h.PasswordHandlerService.ChangePassword(r.Context(), actor.UserId, req.OldPassword, req.NewPassword)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error changing password")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success change password, please login again", nil)
+}
+
+// IssuePasswordReset - admin membuat token reset password untuk user
+func (h *PasswordHandler) IssuePasswordReset(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	reset, err := h.PasswordHandlerService.IssuePasswordReset(r.Context(), id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error creating password reset")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "success create password reset token", reset)
+}
+
+// ResetPassword - ganti password memakai token reset (tanpa login)
+func (h *PasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	err = h.PasswordHandlerService.ResetPassword(r.Context(), req.Token, req.NewPassword)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error resetting password")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success reset password", nil)
+}
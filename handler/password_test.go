@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPasswordService struct {
+	mock.Mock
+}
+
+func (m *MockPasswordService) ChangePassword(ctx context.Context, userId int, oldPassword, newPassword string) error {
+	args := m.Called(userId, oldPassword, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockPasswordService) IssuePasswordReset(ctx context.Context, userId int) (*dto.PasswordResetResponse, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PasswordResetResponse), args.Error(1)
+}
+
+func (m *MockPasswordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	args := m.Called(token, newPassword)
+	return args.Error(0)
+}
+
+func TestPasswordHandler_ChangeMyPassword_UsesActor(t *testing.T) {
+	mockService := new(MockPasswordService)
+	h := NewPasswordHandler(mockService, testConfig)
+	mockService.On("ChangePassword", 7, "OldPass123", "NewPass456").Return(nil)
+
+	rec, req := newRequest(http.MethodPost, "/users/me/password", `{"old_password":"OldPass123","new_password":"NewPass456"}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.ChangeMyPassword(rec, req)
+
+	assertSuccess(t, rec, http.StatusOK)
+	mockService.AssertExpectations(t)
+}
+
+func TestPasswordHandler_ChangeMyPassword_WrongOldPassword(t *testing.T) {
+	mockService := new(MockPasswordService)
+	h := NewPasswordHandler(mockService, testConfig)
+	mockService.On("ChangePassword", 7, "Salah123", "NewPass456").Return(apperror.Validation("old password is incorrect", nil))
+
+	rec, req := newRequest(http.MethodPost, "/users/me/password", `{"old_password":"Salah123","new_password":"NewPass456"}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.ChangeMyPassword(rec, req)
+
+	assertError(t, rec, http.StatusUnprocessableEntity, "validation_error")
+}
+
+func TestPasswordHandler_ChangeMyPassword_WeakNewPassword(t *testing.T) {
+	mockService := new(MockPasswordService)
+	h := NewPasswordHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/users/me/password", `{"old_password":"OldPass123","new_password":"short"}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.ChangeMyPassword(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+	mockService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPasswordHandler_IssuePasswordReset(t *testing.T) {
+	mockService := new(MockPasswordService)
+	h := NewPasswordHandler(mockService, testConfig)
+	expiredAt := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	mockService.On("IssuePasswordReset", 5).Return(&dto.PasswordResetResponse{Token: "abc", ExpiredAt: expiredAt}, nil)
+
+	rec, req := newRequest(http.MethodPost, "/users/5/password-reset", "", map[string]string{"id": "5"})
+	h.IssuePasswordReset(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusCreated)
+	var reset dto.PasswordResetResponse
+	require.NoError(t, json.Unmarshal(env.Data, &reset))
+	assert.Equal(t, "abc", reset.Token)
+}
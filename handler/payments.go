@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PaymentsHandler struct {
+	PaymentsHandlerService service.PaymentsService
+	config                 utils.Configuration
+}
+
+func NewPaymentsHandler(paymentsService service.PaymentsService, config utils.Configuration) PaymentsHandler {
+	return PaymentsHandler{
+		PaymentsHandlerService: paymentsService,
+		config:                 config,
+	}
+}
+
+func (h *PaymentsHandler) GetSalePayments(w http.ResponseWriter, r *http.Request) {
+	saleId, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	payments, err := h.PaymentsHandlerService.GetSalePayments(r.Context(), saleId)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting payments")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success get payments", payments)
+}
+
+// CreatePayments - satu request bisa berisi beberapa tender (split payment),
+// dicatat atas nama user yang login
+func (h *PaymentsHandler) CreatePayments(w http.ResponseWriter, r *http.Request) {
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		utils.ResponseError(w, r, apperror.Unauthorized("authentication required"), "error creating payments")
+		return
+	}
+
+	saleId, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	var req dto.PaymentsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseDecodeError(w, err, "invalid request body")
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	payments, err := h.PaymentsHandlerService.CreatePayments(r.Context(), saleId, actor.UserId, &req)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error creating payments")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "success create payments", payments)
+}
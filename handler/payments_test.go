@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPaymentsService struct {
+	mock.Mock
+}
+
+func (m *MockPaymentsService) GetSalePayments(ctx context.Context, saleId int) (*dto.PaymentsResponse, error) {
+	args := m.Called(saleId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PaymentsResponse), args.Error(1)
+}
+
+func (m *MockPaymentsService) CreatePayments(ctx context.Context, saleId, userId int, data *dto.PaymentsRequest) (*dto.PaymentsResponse, error) {
+	args := m.Called(saleId, userId, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PaymentsResponse), args.Error(1)
+}
+
+func TestPaymentsHandler_CreatePayments(t *testing.T) {
+	mockService := new(MockPaymentsService)
+	h := NewPaymentsHandler(mockService, testConfig)
+	mockService.On("CreatePayments", 5, 7, mock.MatchedBy(func(req *dto.PaymentsRequest) bool {
+		return len(req.Payments) == 2 && req.Payments[1].Amount == model.MustParseMoney("50000")
+	})).Return(&dto.PaymentsResponse{
+		SaleId: 5, PaymentStatus: model.PaymentPaid,
+		TotalAmount: model.MustParseMoney("120000"), PaidAmount: model.MustParseMoney("120000"),
+		Change: model.MustParseMoney("30000"),
+	}, nil)
+
+	body := `{"payments":[{"method":"qris","amount":100000,"reference":"QR-1"},{"method":"cash","amount":50000}]}`
+	rec, req := newRequest(http.MethodPost, "/sales/5/payments", body, map[string]string{"id": "5"})
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.CreatePayments(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusCreated)
+	var got dto.PaymentsResponse
+	require.NoError(t, json.Unmarshal(env.Data, &got))
+	assert.Equal(t, model.PaymentPaid, got.PaymentStatus)
+	assert.Equal(t, model.MustParseMoney("30000"), got.Change)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentsHandler_CreatePayments_Unauthenticated(t *testing.T) {
+	mockService := new(MockPaymentsService)
+	h := NewPaymentsHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/sales/5/payments", `{"payments":[{"method":"cash","amount":10}]}`, map[string]string{"id": "5"})
+	h.CreatePayments(rec, req)
+
+	assertError(t, rec, http.StatusUnauthorized, "unauthorized")
+	mockService.AssertNotCalled(t, "CreatePayments", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPaymentsHandler_CreatePayments_Validation(t *testing.T) {
+	for _, body := range []string{
+		`{"payments":[]}`,
+		`{"payments":[{"method":"voucher","amount":10}]}`,
+		`{"payments":[{"method":"cash","amount":0}]}`,
+		`{"payments":[{"method":"cash","amount":10.005}]}`,
+	} {
+		mockService := new(MockPaymentsService)
+		h := NewPaymentsHandler(mockService, testConfig)
+
+		rec, req := newRequest(http.MethodPost, "/sales/5/payments", body, map[string]string{"id": "5"})
+		req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+		h.CreatePayments(rec, req)
+
+		assertError(t, rec, http.StatusBadRequest, "validation_error")
+		mockService.AssertNotCalled(t, "CreatePayments", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestPaymentsHandler_CreatePayments_AlreadyPaid(t *testing.T) {
+	mockService := new(MockPaymentsService)
+	h := NewPaymentsHandler(mockService, testConfig)
+	mockService.On("CreatePayments", 5, 7, mock.Anything).Return(nil, apperror.SaleAlreadyPaid())
+
+	rec, req := newRequest(http.MethodPost, "/sales/5/payments", `{"payments":[{"method":"cash","amount":10}]}`, map[string]string{"id": "5"})
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.CreatePayments(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "sale_already_paid")
+}
@@ -34,9 +34,13 @@ func (h *RacksHandler) GetRacksById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := h.RacksHandlerService.GetRacksById(racksID)
+	response, err := h.RacksHandlerService.GetRacksById(r.Context(), racksID)
 	if err != nil {
-		utils.ResponseBadRequest(w, http.StatusNotFound, "rack not found", nil)
+		utils.ResponseError(w, r, err, "error getting rack")
+		return
+	}
+
+	if utils.NotModified(w, r, response.Version) {
 		return
 	}
 
@@ -44,45 +48,21 @@ func (h *RacksHandler) GetRacksById(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *RacksHandler) GetAllRacks(w http.ResponseWriter, r *http.Request) {
-	// Ambil query param page dan limit
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
+	page, limit := parsePagination(r, h.config.Limit)
 
-	page := 1
-	limit := h.config.Limit
-	var err error
-	if pageStr != "" {
-		page, err = strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			page = 1
-		}
-	}
-	if limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil || limit < 1 {
-			limit = h.config.Limit
-		}
+	includeDeleted, err := parseIncludeDeleted(r)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid include_deleted value", nil)
+		return
 	}
 
-	racks, total, err := h.RacksHandlerService.GetAllRacks(page, limit)
+	racks, total, err := h.RacksHandlerService.GetAllRacks(r.Context(), page, limit, includeDeleted)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting racks")
 		return
 	}
 
-	response := map[string]interface{}{
-		"data": racks,
-		"pagination": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	utils.ResponsePagination(w, http.StatusOK, "success get all racks", racks, utils.NewPagination(page, limit, total))
 }
 
 func (h *RacksHandler) CreateRacks(w http.ResponseWriter, r *http.Request) {
@@ -108,17 +88,13 @@ func (h *RacksHandler) CreateRacks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// create service
-	err = h.RacksHandlerService.CreateRacks(&racks)
+	err = h.RacksHandlerService.CreateRacks(r.Context(), &racks)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error creating rack",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error creating rack")
 		return
 	}
 
+	utils.SetETag(w, racks.Version)
 	utils.ResponseSuccess(w, http.StatusCreated, "success created rack", racks)
 }
 
@@ -131,6 +107,12 @@ func (h *RacksHandler) UpdateRacks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
 	var newRacks dto.RacksRequest
 	if err := json.NewDecoder(r.Body).Decode(&newRacks); err != nil {
 		utils.ResponseBadRequest(w, http.StatusBadRequest, "error data :"+err.Error(), nil)
@@ -146,44 +128,90 @@ func (h *RacksHandler) UpdateRacks(w http.ResponseWriter, r *http.Request) {
 
 	// parsing to model
 	racks := model.Racks{
+		Version: version,
 		WarehouseId: newRacks.WarehouseId,
 		Name:        newRacks.Name,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
-	err = h.RacksHandlerService.UpdateRacks(racksID, &racks)
+	err = h.RacksHandlerService.UpdateRacks(r.Context(), racksID, &racks)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error updating rack",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error updating rack")
 		return
 	}
 
+	utils.SetETag(w, racks.Version)
 	utils.ResponseSuccess(w, http.StatusOK, "success update rack", racks)
 }
 
+func (h *RacksHandler) PatchRacks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
+	var req dto.RacksRequest
+	patch, err := utils.DecodeMergePatch(r.Body, &req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	messages, err := utils.ValidatePartialErrors(req, patch.Fields...)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	rack, err := h.RacksHandlerService.PatchRacks(r.Context(), id, version, patch.Values)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error patching rack")
+		return
+	}
+
+	utils.SetETag(w, rack.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success patch rack", rack)
+}
+
 func (h *RacksHandler) DeleteRacks(w http.ResponseWriter, r *http.Request) {
 	racksIDstr := chi.URLParam(r, "id")
 
 	racksID, err := strconv.Atoi(racksIDstr)
 	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
 		return
 	}
 
-	err = h.RacksHandlerService.DeleteRacks(racksID)
+	err = h.RacksHandlerService.DeleteRacks(r.Context(), racksID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error deleting rack",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error deleting rack")
 		return
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "success delete rack", nil)
 }
+
+func (h *RacksHandler) RestoreRacks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	rack, err := h.RacksHandlerService.RestoreRacks(r.Context(), id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error restoring rack")
+		return
+	}
+
+	utils.SetETag(w, rack.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success restore rack", rack)
+}
\ No newline at end of file
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRacksService is a mock implementation of the RacksService interface
+type MockRacksService struct {
+	mock.Mock
+}
+
+func (m *MockRacksService) GetRacksById(ctx context.Context, id int) (*model.Racks, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Racks), args.Error(1)
+}
+
+func (m *MockRacksService) GetAllRacks(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Racks, int, error) {
+	args := m.Called(page, limit, includeDeleted)
+	return args.Get(0).([]model.Racks), args.Int(1), args.Error(2)
+}
+
+func (m *MockRacksService) CreateRacks(ctx context.Context, data *model.Racks) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockRacksService) UpdateRacks(ctx context.Context, id int, data *model.Racks) error {
+	args := m.Called(id, data)
+	return args.Error(0)
+}
+
+func (m *MockRacksService) PatchRacks(ctx context.Context, id, version int, changes map[string]any) (*model.Racks, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Racks), args.Error(1)
+}
+
+func (m *MockRacksService) DeleteRacks(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRacksService) RestoreRacks(ctx context.Context, id int) (*model.Racks, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Racks), args.Error(1)
+}
+
+func TestRacksHandler_GetRacksById_Success(t *testing.T) {
+	mockService := new(MockRacksService)
+	h := NewRacksHandler(mockService, testConfig)
+	mockService.On("GetRacksById", 1).Return(&model.Racks{Id: 1, Name: "Rak A1"}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/racks/1", "", map[string]string{"id": "1"})
+	h.GetRacksById(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var data model.Racks
+	require.NoError(t, json.Unmarshal(env.Data, &data))
+	assert.Equal(t, "Rak A1", data.Name)
+}
+
+func TestRacksHandler_GetRacksById_NotFound(t *testing.T) {
+	mockService := new(MockRacksService)
+	h := NewRacksHandler(mockService, testConfig)
+	mockService.On("GetRacksById", 999).Return(nil, apperror.NotFound("rack"))
+
+	rec, req := newRequest(http.MethodGet, "/racks/999", "", map[string]string{"id": "999"})
+	h.GetRacksById(rec, req)
+
+	assertError(t, rec, http.StatusNotFound, "rack_not_found")
+}
+
+func TestRacksHandler_GetAllRacks_Paginated(t *testing.T) {
+	mockService := new(MockRacksService)
+	h := NewRacksHandler(mockService, testConfig)
+	mockService.On("GetAllRacks", 1, 5, false).Return([]model.Racks{{Id: 1}, {Id: 2}}, 7, nil)
+
+	rec, req := newRequest(http.MethodGet, "/racks?limit=5", "", nil)
+	h.GetAllRacks(rec, req)
+
+	assertPaginated(t, rec, 1, 5, 2, 7)
+}
+
+func TestRacksHandler_CreateRacks_ValidationError(t *testing.T) {
+	h := NewRacksHandler(new(MockRacksService), testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/racks", `{}`, nil)
+	h.CreateRacks(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+}
+
+func TestRacksHandler_UpdateRacks_Success(t *testing.T) {
+	mockService := new(MockRacksService)
+	h := NewRacksHandler(mockService, testConfig)
+	mockService.On("UpdateRacks", 1, mock.Anything).Return(nil)
+
+	rec, req := newRequest(http.MethodPut, "/racks/1", `{"warehouse_id":1,"name":"Rak A1"}`, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", `"1"`)
+	h.UpdateRacks(rec, req)
+
+	assertSuccess(t, rec, http.StatusOK)
+}
+
+func TestRacksHandler_DeleteRacks_Success(t *testing.T) {
+	mockService := new(MockRacksService)
+	h := NewRacksHandler(mockService, testConfig)
+	mockService.On("DeleteRacks", 1).Return(nil)
+
+	rec, req := newRequest(http.MethodDelete, "/racks/1", "", map[string]string{"id": "1"})
+	h.DeleteRacks(rec, req)
+
+	assertSuccess(t, rec, http.StatusOK)
+}
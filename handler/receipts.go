@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/receipt"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReceiptsHandler struct {
+	ReceiptsHandlerService service.ReceiptsService
+	config                 utils.Configuration
+}
+
+func NewReceiptsHandler(receiptsService service.ReceiptsService, config utils.Configuration) ReceiptsHandler {
+	return ReceiptsHandler{
+		ReceiptsHandlerService: receiptsService,
+		config:                 config,
+	}
+}
+
+// GetReceipt - ?format=text (default, struk thermal) atau ?format=pdf (invoice A4).
+// Response berupa file mentah, bukan JSON envelope; error tetap JSON.
+func (h *ReceiptsHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	saleId, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "text"
+	}
+	if format != "text" && format != "pdf" {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid format, use text or pdf", nil)
+		return
+	}
+
+	// lebar struk bisa di-override per printer, default dari config
+	width := h.config.Receipt.Width
+	if value := r.URL.Query().Get("width"); value != "" {
+		width, err = strconv.Atoi(value)
+		if err != nil || width < receipt.MinWidth || width > receipt.MaxWidth {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid width, must be between 24 and 80", nil)
+			return
+		}
+	}
+
+	data, err := h.ReceiptsHandlerService.GetReceipt(r.Context(), saleId)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting receipt")
+		return
+	}
+
+	var body []byte
+	if format == "pdf" {
+		body = receipt.PDF(data)
+		filename := strings.ReplaceAll(receipt.DocumentNo(data), "/", "-") + ".pdf"
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+	} else {
+		body = receipt.Text(data, width)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReceiptsService struct {
+	mock.Mock
+}
+
+func (m *MockReceiptsService) GetReceipt(ctx context.Context, saleId int) (*dto.Receipt, error) {
+	args := m.Called(saleId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.Receipt), args.Error(1)
+}
+
+func testReceipt() *dto.Receipt {
+	return &dto.Receipt{
+		StoreName: "Toko Azwin", SaleId: 5, InvoiceNo: "INV/2026/10/000005",
+		CreatedAt: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), PaymentStatus: model.PaymentPaid,
+		Lines: []dto.ReceiptLine{{
+			Name: "Kopi Arabika", Quantity: 2, Price: model.MustParseMoney("25000"), Total: model.MustParseMoney("50000"),
+		}},
+		Subtotal: model.MustParseMoney("50000"), TotalAmount: model.MustParseMoney("50000"),
+	}
+}
+
+func TestReceiptsHandler_GetReceipt_Text(t *testing.T) {
+	mockService := new(MockReceiptsService)
+	config := testConfig
+	config.Receipt.Width = 32
+	h := NewReceiptsHandler(mockService, config)
+	mockService.On("GetReceipt", 5).Return(testReceipt(), nil)
+
+	rec, req := newRequest(http.MethodGet, "/sales/5/receipt", "", map[string]string{"id": "5"})
+	h.GetReceipt(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	assert.Contains(t, lines, "No      : INV/2026/10/000005")
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 32, line)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestReceiptsHandler_GetReceipt_WidthOverride(t *testing.T) {
+	mockService := new(MockReceiptsService)
+	h := NewReceiptsHandler(mockService, testConfig)
+	mockService.On("GetReceipt", 5).Return(testReceipt(), nil)
+
+	rec, req := newRequest(http.MethodGet, "/sales/5/receipt?width=58", "", map[string]string{"id": "5"})
+	h.GetReceipt(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, strings.Repeat("-", 58), strings.Split(rec.Body.String(), "\n")[1])
+}
+
+func TestReceiptsHandler_GetReceipt_PDF(t *testing.T) {
+	mockService := new(MockReceiptsService)
+	h := NewReceiptsHandler(mockService, testConfig)
+	mockService.On("GetReceipt", 5).Return(testReceipt(), nil)
+
+	rec, req := newRequest(http.MethodGet, "/sales/5/receipt?format=pdf", "", map[string]string{"id": "5"})
+	h.GetReceipt(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="INV-2026-10-000005.pdf"`, rec.Header().Get("Content-Disposition"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "%PDF-1.4"))
+}
+
+func TestReceiptsHandler_GetReceipt_InvalidQuery(t *testing.T) {
+	for _, target := range []string{"/sales/5/receipt?format=html", "/sales/5/receipt?width=10", "/sales/5/receipt?width=abc"} {
+		mockService := new(MockReceiptsService)
+		h := NewReceiptsHandler(mockService, testConfig)
+
+		rec, req := newRequest(http.MethodGet, target, "", map[string]string{"id": "5"})
+		h.GetReceipt(rec, req)
+
+		assertError(t, rec, http.StatusBadRequest, "bad_request")
+		mockService.AssertNotCalled(t, "GetReceipt", mock.Anything)
+	}
+}
+
+func TestReceiptsHandler_GetReceipt_NotFound(t *testing.T) {
+	mockService := new(MockReceiptsService)
+	h := NewReceiptsHandler(mockService, testConfig)
+	mockService.On("GetReceipt", 99).Return(nil, apperror.NotFound("sale"))
+
+	rec, req := newRequest(http.MethodGet, "/sales/99/receipt", "", map[string]string{"id": "99"})
+	h.GetReceipt(rec, req)
+
+	assertError(t, rec, http.StatusNotFound, "sale_not_found")
+}
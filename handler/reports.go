@@ -1,10 +1,13 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 	"project-app-inventory-restapi-golang-azwin/service"
 	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
 )
 
 type ReportsHandler struct {
@@ -20,12 +23,9 @@ func NewReportsHandler(reportsService service.ReportsService, config utils.Confi
 }
 
 func (h *ReportsHandler) GetItemsReport(w http.ResponseWriter, r *http.Request) {
-	report, err := h.ReportsHandlerService.GetItemsReport()
+	report, err := h.ReportsHandlerService.GetItemsReport(r.Context())
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting items report")
 		return
 	}
 
@@ -33,12 +33,9 @@ func (h *ReportsHandler) GetItemsReport(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *ReportsHandler) GetSalesReport(w http.ResponseWriter, r *http.Request) {
-	report, err := h.ReportsHandlerService.GetSalesReport()
+	report, err := h.ReportsHandlerService.GetSalesReport(r.Context())
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting sales report")
 		return
 	}
 
@@ -46,14 +43,61 @@ func (h *ReportsHandler) GetSalesReport(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *ReportsHandler) GetRevenueReport(w http.ResponseWriter, r *http.Request) {
-	report, err := h.ReportsHandlerService.GetRevenueReport()
+	report, err := h.ReportsHandlerService.GetRevenueReport(r.Context())
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting revenue report")
 		return
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "success get revenue report", report)
 }
+
+func (h *ReportsHandler) GetCustomerReport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	report, err := h.ReportsHandlerService.GetCustomerReport(r.Context(), id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting customer report")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success get customer report", report)
+}
+
+// GetCashDrawerReport - rekap kas per kasir untuk satu hari (?date=YYYY-MM-DD,
+// default hari ini) dengan filter opsional ?user_id=
+func (h *ReportsHandler) GetCashDrawerReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	date := time.Now()
+	if value := query.Get("date"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid date value, use YYYY-MM-DD", nil)
+			return
+		}
+		date = parsed
+	}
+
+	var userId *int
+	if value := query.Get("user_id"); value != "" {
+		id, err := parseOptionalId(value)
+		if err != nil {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid user_id value", nil)
+			return
+		}
+		userId = &id
+	}
+
+	report, err := h.ReportsHandlerService.GetCashDrawerReport(r.Context(), date, userId)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting cash drawer report")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success get cash drawer report", report)
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockReportsService is a mock implementation of the ReportsService interface
+type MockReportsService struct {
+	mock.Mock
+}
+
+func (m *MockReportsService) GetItemsReport(ctx context.Context) (*repository.ItemsReport, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ItemsReport), args.Error(1)
+}
+
+func (m *MockReportsService) GetSalesReport(ctx context.Context) (*repository.SalesReport, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.SalesReport), args.Error(1)
+}
+
+func (m *MockReportsService) GetRevenueReport(ctx context.Context) (*repository.RevenueReport, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.RevenueReport), args.Error(1)
+}
+
+func (m *MockReportsService) GetCustomerReport(ctx context.Context, customerId int) (*repository.CustomerPurchaseReport, error) {
+	args := m.Called(customerId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.CustomerPurchaseReport), args.Error(1)
+}
+
+func TestReportsHandler_GetItemsReport(t *testing.T) {
+	mockService := new(MockReportsService)
+	h := NewReportsHandler(mockService, testConfig)
+	mockService.On("GetItemsReport").Return(&repository.ItemsReport{TotalItems: 3, TotalStock: 40, LowStockItems: 1}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/reports/items", "", nil)
+	h.GetItemsReport(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var report repository.ItemsReport
+	require.NoError(t, json.Unmarshal(env.Data, &report))
+	assert.Equal(t, 40, report.TotalStock)
+}
+
+func TestReportsHandler_GetRevenueReport_Error(t *testing.T) {
+	mockService := new(MockReportsService)
+	h := NewReportsHandler(mockService, testConfig)
+	mockService.On("GetRevenueReport").Return(nil, errors.New("timeout"))
+
+	rec, req := newRequest(http.MethodGet, "/reports/revenue", "", nil)
+	h.GetRevenueReport(rec, req)
+
+	assertError(t, rec, http.StatusInternalServerError, "internal_error")
+}
+
+func TestReportsHandler_GetCustomerReport(t *testing.T) {
+	mockService := new(MockReportsService)
+	h := NewReportsHandler(mockService, testConfig)
+	mockService.On("GetCustomerReport", 3).Return(&repository.CustomerPurchaseReport{
+		CustomerId: 3, TotalTransactions: 2, TotalSpent: model.MustParseMoney("350"), CreditOutstanding: model.MustParseMoney("200"),
+		TopItems: []repository.CustomerItemSummary{{ItemId: 4, Name: "Kabel", Quantity: 5, TotalSpent: model.MustParseMoney("250")}},
+	}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/reports/customers/3", "", map[string]string{"id": "3"})
+	h.GetCustomerReport(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var report repository.CustomerPurchaseReport
+	require.NoError(t, json.Unmarshal(env.Data, &report))
+	assert.Equal(t, model.MustParseMoney("350"), report.TotalSpent)
+	assert.Len(t, report.TopItems, 1)
+}
+
+func TestReportsHandler_GetCustomerReport_NotFound(t *testing.T) {
+	mockService := new(MockReportsService)
+	h := NewReportsHandler(mockService, testConfig)
+	mockService.On("GetCustomerReport", 9).Return(nil, apperror.NotFound("customer"))
+
+	rec, req := newRequest(http.MethodGet, "/reports/customers/9", "", map[string]string{"id": "9"})
+	h.GetCustomerReport(rec, req)
+
+	assertError(t, rec, http.StatusNotFound, "customer_not_found")
+}
+
+func (m *MockReportsService) GetCashDrawerReport(ctx context.Context, date time.Time, userId *int) (*repository.CashDrawerReport, error) {
+	args := m.Called(date, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.CashDrawerReport), args.Error(1)
+}
+
+func TestReportsHandler_GetCashDrawerReport(t *testing.T) {
+	mockService := new(MockReportsService)
+	h := NewReportsHandler(mockService, testConfig)
+	date := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	userId := 4
+	mockService.On("GetCashDrawerReport", date, &userId).Return(&repository.CashDrawerReport{
+		Date: "2024-05-02",
+		Users: []repository.CashDrawerUser{{
+			UserId: 4, Username: "kasir1", Payments: 2,
+			CashReceived: model.MustParseMoney("100"), ChangeGiven: model.MustParseMoney("12.50"), ExpectedCash: model.MustParseMoney("87.50"),
+			Total: model.MustParseMoney("87.50"),
+		}},
+	}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/reports/cash-drawer?date=2024-05-02&user_id=4", "", nil)
+	h.GetCashDrawerReport(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var report repository.CashDrawerReport
+	require.NoError(t, json.Unmarshal(env.Data, &report))
+	require.Len(t, report.Users, 1)
+	assert.Equal(t, model.MustParseMoney("87.50"), report.Users[0].ExpectedCash)
+	mockService.AssertExpectations(t)
+}
+
+func TestReportsHandler_GetCashDrawerReport_InvalidQuery(t *testing.T) {
+	for _, target := range []string{"/reports/cash-drawer?date=02-05-2024", "/reports/cash-drawer?user_id=abc"} {
+		mockService := new(MockReportsService)
+		h := NewReportsHandler(mockService, testConfig)
+
+		rec, req := newRequest(http.MethodGet, target, "", nil)
+		h.GetCashDrawerReport(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		mockService.AssertNotCalled(t, "GetCashDrawerReport", mock.Anything, mock.Anything)
+	}
+}
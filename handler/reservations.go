@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReservationsHandler struct {
+	ReservationsHandlerService service.ReservationsService
+	config                     utils.Configuration
+}
+
+func NewReservationsHandler(reservationsService service.ReservationsService, config utils.Configuration) ReservationsHandler {
+	return ReservationsHandler{
+		ReservationsHandlerService: reservationsService,
+		config:                     config,
+	}
+}
+
+func (h *ReservationsHandler) GetReservationById(w http.ResponseWriter, r *http.Request) {
+	reservationID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	reservation, err := h.ReservationsHandlerService.GetReservationById(r.Context(), reservationID)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting reservation")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success get data reservation by id", reservation)
+}
+
+func (h *ReservationsHandler) GetAllReservations(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, h.config.Limit)
+
+	filter := repository.ReservationFilter{
+		Status:    r.URL.Query().Get("status"),
+		Reference: strings.TrimSpace(r.URL.Query().Get("reference")),
+	}
+	switch filter.Status {
+	case "", model.ReservationActive, model.ReservationConfirmed, model.ReservationReleased, model.ReservationExpired:
+	default:
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid status, use active, confirmed, released or expired", nil)
+		return
+	}
+
+	reservations, total, err := h.ReservationsHandlerService.GetAllReservations(r.Context(), filter, page, limit)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error getting reservations")
+		return
+	}
+
+	utils.ResponsePagination(w, http.StatusOK, "success get all reservations", reservations, utils.NewPagination(page, limit, total))
+}
+
+// CreateReservation memesan stock atas nama user yang login / pemilik API key
+func (h *ReservationsHandler) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		utils.ResponseError(w, r, apperror.Unauthorized("authentication required"), "error creating reservation")
+		return
+	}
+
+	var req dto.ReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	reservation, err := h.ReservationsHandlerService.CreateReservation(r.Context(), actor.UserId, &req)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error creating reservation")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "success create reservation", reservation)
+}
+
+// ConfirmReservation mengurangi stock sebesar quantity reservasi
+func (h *ReservationsHandler) ConfirmReservation(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.ReservationsHandlerService.ConfirmReservation, "success confirm reservation", "error confirming reservation")
+}
+
+// ReleaseReservation melepas stock yang dipesan
+func (h *ReservationsHandler) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.ReservationsHandlerService.ReleaseReservation, "success release reservation", "error releasing reservation")
+}
+
+func (h *ReservationsHandler) transition(w http.ResponseWriter, r *http.Request, fn func(context.Context, int) (*model.StockReservation, error), message, errMessage string) {
+	reservationID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	reservation, err := fn(r.Context(), reservationID)
+	if err != nil {
+		utils.ResponseError(w, r, err, errMessage)
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, message, reservation)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReservationsService struct {
+	mock.Mock
+}
+
+func (m *MockReservationsService) GetReservationById(ctx context.Context, id int) (*model.StockReservation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationsService) GetAllReservations(ctx context.Context, filter repository.ReservationFilter, page, limit int) ([]model.StockReservation, int, error) {
+	args := m.Called(filter, page, limit)
+	return args.Get(0).([]model.StockReservation), args.Int(1), args.Error(2)
+}
+
+func (m *MockReservationsService) CreateReservation(ctx context.Context, userId int, data *dto.ReservationRequest) (*model.StockReservation, error) {
+	args := m.Called(userId, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationsService) ConfirmReservation(ctx context.Context, id int) (*model.StockReservation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationsService) ReleaseReservation(ctx context.Context, id int) (*model.StockReservation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationsService) ExpireReservations(ctx context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestReservationsHandler_CreateReservation(t *testing.T) {
+	mockService := new(MockReservationsService)
+	h := NewReservationsHandler(mockService, testConfig)
+	mockService.On("CreateReservation", 7, &dto.ReservationRequest{
+		Reference: "WEB-1001",
+		Items:     []dto.ReservationItemRequest{{ItemId: 3, Quantity: 2}},
+	}).Return(&model.StockReservation{Id: 1, Reference: "WEB-1001", Status: model.ReservationActive, CreatedBy: 7}, nil)
+
+	rec, req := newRequest(http.MethodPost, "/reservations", `{"reference":"WEB-1001","items":[{"item_id":3,"quantity":2}]}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.CreateReservation(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusCreated)
+	var got model.StockReservation
+	require.NoError(t, json.Unmarshal(env.Data, &got))
+	assert.Equal(t, model.ReservationActive, got.Status)
+	assert.Equal(t, 7, got.CreatedBy)
+	mockService.AssertExpectations(t)
+}
+
+func TestReservationsHandler_CreateReservation_ValidationError(t *testing.T) {
+	mockService := new(MockReservationsService)
+	h := NewReservationsHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/reservations", `{"reference":"WEB-1001","items":[{"item_id":3,"quantity":0}]}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.CreateReservation(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+	mockService.AssertNotCalled(t, "CreateReservation", mock.Anything, mock.Anything)
+}
+
+func TestReservationsHandler_CreateReservation_InsufficientStock(t *testing.T) {
+	mockService := new(MockReservationsService)
+	h := NewReservationsHandler(mockService, testConfig)
+	mockService.On("CreateReservation", 7, mock.Anything).Return(nil,
+		apperror.InsufficientStock("insufficient stock for one or more items", map[string]any{"item_ids": []int{3}}))
+
+	rec, req := newRequest(http.MethodPost, "/reservations", `{"reference":"WEB-1001","items":[{"item_id":3,"quantity":20}]}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.CreateReservation(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "insufficient_stock")
+}
+
+func TestReservationsHandler_GetAllReservations_Filter(t *testing.T) {
+	mockService := new(MockReservationsService)
+	h := NewReservationsHandler(mockService, testConfig)
+	filter := repository.ReservationFilter{Status: model.ReservationActive, Reference: "WEB-1001"}
+	mockService.On("GetAllReservations", filter, 1, 10).Return([]model.StockReservation{{Id: 1, Reference: "WEB-1001"}}, 1, nil)
+
+	rec, req := newRequest(http.MethodGet, "/reservations?status=active&reference=WEB-1001", "", nil)
+	h.GetAllReservations(rec, req)
+
+	assertPaginated(t, rec, 1, 10, 1, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestReservationsHandler_GetAllReservations_InvalidStatus(t *testing.T) {
+	mockService := new(MockReservationsService)
+	h := NewReservationsHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodGet, "/reservations?status=held", "", nil)
+	h.GetAllReservations(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+}
+
+func TestReservationsHandler_ConfirmReservation(t *testing.T) {
+	mockService := new(MockReservationsService)
+	h := NewReservationsHandler(mockService, testConfig)
+	mockService.On("ConfirmReservation", 1).Return(&model.StockReservation{Id: 1, Status: model.ReservationConfirmed}, nil)
+
+	rec, req := newRequest(http.MethodPost, "/reservations/1/confirm", "", map[string]string{"id": "1"})
+	h.ConfirmReservation(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var got model.StockReservation
+	require.NoError(t, json.Unmarshal(env.Data, &got))
+	assert.Equal(t, model.ReservationConfirmed, got.Status)
+}
+
+func TestReservationsHandler_ReleaseReservation_NotActive(t *testing.T) {
+	mockService := new(MockReservationsService)
+	h := NewReservationsHandler(mockService, testConfig)
+	mockService.On("ReleaseReservation", 1).Return(nil, apperror.ReservationNotActive(model.ReservationExpired))
+
+	rec, req := newRequest(http.MethodPost, "/reservations/1/release", "", map[string]string{"id": "1"})
+	h.ReleaseReservation(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "reservation_not_active")
+}
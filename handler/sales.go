@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
 	"project-app-inventory-restapi-golang-azwin/service"
 	"project-app-inventory-restapi-golang-azwin/utils"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -32,9 +37,9 @@ func (h *SalesHandler) GetSalesById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := h.SalesHandlerService.GetSalesById(saleID)
+	response, err := h.SalesHandlerService.GetSalesById(r.Context(), saleID)
 	if err != nil {
-		utils.ResponseBadRequest(w, http.StatusNotFound, "sale not found", nil)
+		utils.ResponseError(w, r, err, "error getting sale")
 		return
 	}
 
@@ -42,51 +47,41 @@ func (h *SalesHandler) GetSalesById(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *SalesHandler) GetAllSales(w http.ResponseWriter, r *http.Request) {
-	// Get query param page and limit
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
+	page, limit := parsePagination(r, h.config.Limit)
+	include, err := parseInclude(r, "items")
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
 
-	page := 1
-	limit := h.config.Limit
-	var err error
-	if pageStr != "" {
-		page, err = strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			page = 1
-		}
+	filter := repository.SalesFilter{
+		InvoiceNo: strings.TrimSpace(r.URL.Query().Get("invoice_no")),
+		Status:    r.URL.Query().Get("status"),
 	}
-	if limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil || limit < 1 {
-			limit = h.config.Limit
-		}
+	switch filter.Status {
+	case "", model.SaleDraft, model.SaleQuote, model.SaleHeld, model.SaleCompleted, model.SaleVoided:
+	default:
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid status, use draft, quote, held, completed or voided", nil)
+		return
 	}
 
-	sales, total, err := h.SalesHandlerService.GetAllSales(page, limit)
+	sales, total, err := h.SalesHandlerService.GetAllSales(r.Context(), filter, page, limit, include["items"])
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting sales")
 		return
 	}
 
-	response := map[string]interface{}{
-		"data": sales,
-		"pagination": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	utils.ResponsePagination(w, http.StatusOK, "success get all sales", sales, utils.NewPagination(page, limit, total))
 }
 
 func (h *SalesHandler) CreateSales(w http.ResponseWriter, r *http.Request) {
 	var newSale dto.SalesRequest
 	if err := json.NewDecoder(r.Body).Decode(&newSale); err != nil {
-		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		utils.ResponseDecodeError(w, err, "invalid request body")
+		return
+	}
+	if err := saleCashier(r, &newSale); err != nil {
+		utils.ResponseError(w, r, err, "error creating sale")
 		return
 	}
 
@@ -97,19 +92,32 @@ func (h *SalesHandler) CreateSales(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create sale
-	err = h.SalesHandlerService.CreateSales(&newSale)
+	// Create sale; response berisi id dan invoice_no untuk struk dan pembayaran
+	sale, err := h.SalesHandlerService.CreateSales(r.Context(), &newSale)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error creating sale",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error creating sale")
 		return
 	}
 
-	utils.ResponseSuccess(w, http.StatusCreated, "success create sale", nil)
+	utils.ResponseSuccess(w, http.StatusCreated, "success create sale", sale)
+}
+
+// saleCashier mengisi user_id sale dengan user yang login (session atau pemilik
+// API key), supaya terminal POS tidak bisa mencatat sale atas nama kasir lain.
+// user_id berbeda hanya boleh dikirim admin yang login lewat session.
+func saleCashier(r *http.Request, data *dto.SalesRequest) error {
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		return apperror.Unauthorized("authentication required")
+	}
+	if data.UserId == 0 || data.UserId == actor.UserId {
+		data.UserId = actor.UserId
+		return nil
+	}
+	if actor.APIKeyId != 0 || (actor.Role != "super_admin" && actor.Role != "admin") {
+		return apperror.Forbidden("only admins can record sales for another user")
+	}
+	return nil
 }
 
 func (h *SalesHandler) UpdateSales(w http.ResponseWriter, r *http.Request) {
@@ -123,7 +131,11 @@ func (h *SalesHandler) UpdateSales(w http.ResponseWriter, r *http.Request) {
 
 	var updateSale dto.SalesRequest
 	if err := json.NewDecoder(r.Body).Decode(&updateSale); err != nil {
-		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		utils.ResponseDecodeError(w, err, "invalid request body")
+		return
+	}
+	if err := saleCashier(r, &updateSale); err != nil {
+		utils.ResponseError(w, r, err, "error updating sale")
 		return
 	}
 
@@ -135,14 +147,9 @@ func (h *SalesHandler) UpdateSales(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update sale
-	err = h.SalesHandlerService.UpdateSales(saleID, &updateSale)
+	err = h.SalesHandlerService.UpdateSales(r.Context(), saleID, &updateSale)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error updating sale",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error updating sale")
 		return
 	}
 
@@ -158,16 +165,77 @@ func (h *SalesHandler) DeleteSales(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.SalesHandlerService.DeleteSales(saleID)
+	err = h.SalesHandlerService.DeleteSales(r.Context(), saleID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error deleting sale",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error deleting sale")
 		return
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "success delete sale", nil)
 }
+
+// VoidSale membatalkan sale atas nama user yang login; alasan wajib diisi
+func (h *SalesHandler) VoidSale(w http.ResponseWriter, r *http.Request) {
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		utils.ResponseError(w, r, apperror.Unauthorized("authentication required"), "error voiding sale")
+		return
+	}
+
+	saleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	var req dto.VoidSaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseDecodeError(w, err, "invalid request body")
+		return
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	sale, err := h.SalesHandlerService.VoidSale(r.Context(), saleID, actor.UserId, req.Reason)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error voiding sale")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success void sale", sale)
+}
+
+// HoldSale memesan stock untuk sale draft/quote (atau memperpanjang hold)
+func (h *SalesHandler) HoldSale(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.SalesHandlerService.HoldSale, "success hold sale", "error holding sale")
+}
+
+// ReleaseHold melepas stock yang dipesan; sale kembali menjadi draft
+func (h *SalesHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.SalesHandlerService.ReleaseHold, "success release sale", "error releasing sale")
+}
+
+// CompleteSale menyelesaikan sale draft, quote atau held
+func (h *SalesHandler) CompleteSale(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.SalesHandlerService.CompleteSale, "success complete sale", "error completing sale")
+}
+
+func (h *SalesHandler) transition(w http.ResponseWriter, r *http.Request, fn func(context.Context, int) (*dto.SalesResponse, error), message, errMessage string) {
+	saleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	sale, err := fn(r.Context(), saleID)
+	if err != nil {
+		utils.ResponseError(w, r, err, errMessage)
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, message, sale)
+}
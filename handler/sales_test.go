@@ -0,0 +1,416 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSalesService is a mock implementation of the SalesService interface
+type MockSalesService struct {
+	mock.Mock
+}
+
+func (m *MockSalesService) GetSalesById(ctx context.Context, id int) (*dto.SalesResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SalesResponse), args.Error(1)
+}
+
+func (m *MockSalesService) GetAllSales(ctx context.Context, filter repository.SalesFilter, page, limit int, includeItems bool) ([]dto.SalesResponse, int, error) {
+	args := m.Called(filter, page, limit, includeItems)
+	return args.Get(0).([]dto.SalesResponse), args.Int(1), args.Error(2)
+}
+
+func (m *MockSalesService) CreateSales(ctx context.Context, data *dto.SalesRequest) (*dto.SalesResponse, error) {
+	args := m.Called(data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SalesResponse), args.Error(1)
+}
+
+func (m *MockSalesService) UpdateSales(ctx context.Context, id int, data *dto.SalesRequest) error {
+	args := m.Called(id, data)
+	return args.Error(0)
+}
+
+func (m *MockSalesService) DeleteSales(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockSalesService) VoidSale(ctx context.Context, id, userId int, reason string) (*dto.SalesResponse, error) {
+	args := m.Called(id, userId, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SalesResponse), args.Error(1)
+}
+
+func (m *MockSalesService) HoldSale(ctx context.Context, id int) (*dto.SalesResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SalesResponse), args.Error(1)
+}
+
+func (m *MockSalesService) ReleaseHold(ctx context.Context, id int) (*dto.SalesResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SalesResponse), args.Error(1)
+}
+
+func (m *MockSalesService) CompleteSale(ctx context.Context, id int) (*dto.SalesResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SalesResponse), args.Error(1)
+}
+
+func (m *MockSalesService) ExpireHolds(ctx context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestSalesHandler_GetSalesById_Success(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("GetSalesById", 1).Return(&dto.SalesResponse{Id: 1, UserId: 1, Items: []dto.SaleItemResponse{{Id: 1, ItemId: 2, Quantity: 1}}}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/sales/1", "", map[string]string{"id": "1"})
+	h.GetSalesById(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var sale dto.SalesResponse
+	require.NoError(t, json.Unmarshal(env.Data, &sale))
+	assert.Len(t, sale.Items, 1)
+}
+
+func TestSalesHandler_GetAllSales_Paginated(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("GetAllSales", repository.SalesFilter{}, 1, 100, false).Return([]dto.SalesResponse{{Id: 1}}, 1, nil)
+
+	// limit di atas batas maksimum dipotong menjadi 100
+	rec, req := newRequest(http.MethodGet, "/sales?limit=1000", "", nil)
+	h.GetAllSales(rec, req)
+
+	assertPaginated(t, rec, 1, 100, 1, 1)
+}
+
+func TestSalesHandler_GetAllSales_Include(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("GetAllSales", repository.SalesFilter{}, 1, 10, true).Return([]dto.SalesResponse{{Id: 1, Items: []dto.SaleItemResponse{{Id: 1}}}}, 1, nil)
+
+	rec, req := newRequest(http.MethodGet, "/sales?include=items", "", nil)
+	h.GetAllSales(rec, req)
+	assertPaginated(t, rec, 1, 10, 1, 1)
+
+	rec, req = newRequest(http.MethodGet, "/sales?include=customer", "", nil)
+	h.GetAllSales(rec, req)
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+	mockService.AssertNumberOfCalls(t, "GetAllSales", 1)
+}
+
+func TestSalesHandler_GetAllSales_InvoiceNo(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("GetAllSales", repository.SalesFilter{InvoiceNo: "INV/2026/10/000123"}, 1, 10, false).
+		Return([]dto.SalesResponse{{Id: 7, InvoiceNo: "INV/2026/10/000123"}}, 1, nil)
+
+	rec, req := newRequest(http.MethodGet, "/sales?invoice_no=+INV/2026/10/000123+", "", nil)
+	h.GetAllSales(rec, req)
+
+	assertPaginated(t, rec, 1, 10, 1, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestSalesHandler_CreateSales_ReturnsInvoiceNo(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("CreateSales", mock.Anything).Return(&dto.SalesResponse{
+		Id: 12, InvoiceNo: "INV/2026/10/000123", Status: model.SaleCompleted,
+	}, nil)
+
+	body := `{"items":[{"item_id":2,"quantity":1,"price":1000}]}`
+	rec, req := newRequest(http.MethodPost, "/sales", body, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 1, Role: "staff"}))
+	h.CreateSales(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusCreated)
+	var sale dto.SalesResponse
+	require.NoError(t, json.Unmarshal(env.Data, &sale))
+	assert.Equal(t, 12, sale.Id)
+	assert.Equal(t, "INV/2026/10/000123", sale.InvoiceNo)
+}
+
+// TestSalesHandler_CreateSales_DraftReturnsId: id draft dibutuhkan untuk
+// hold, release dan complete tanpa query list tambahan
+func TestSalesHandler_CreateSales_DraftReturnsId(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("CreateSales", mock.MatchedBy(func(data *dto.SalesRequest) bool {
+		return data.Status == model.SaleDraft
+	})).Return(&dto.SalesResponse{Id: 21, Status: model.SaleDraft}, nil)
+
+	body := `{"status":"draft","items":[{"item_id":2,"quantity":1,"price":1000}]}`
+	rec, req := newRequest(http.MethodPost, "/sales", body, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 1, Role: "staff"}))
+	h.CreateSales(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusCreated)
+	var sale map[string]any
+	require.NoError(t, json.Unmarshal(env.Data, &sale))
+	assert.Equal(t, float64(21), sale["id"])
+	assert.Equal(t, model.SaleDraft, sale["status"])
+	assert.Equal(t, "", sale["invoice_no"])
+	mockService.AssertExpectations(t)
+}
+
+// TestSalesHandler_CreateSales_CashierFromAPIKey: kasir diambil dari user pemilik
+// API key; user_id lain di body ditolak kecuali admin yang login lewat session
+func TestSalesHandler_CreateSales_CashierFromAPIKey(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("CreateSales", mock.MatchedBy(func(data *dto.SalesRequest) bool {
+		return data.UserId == 4
+	})).Return(&dto.SalesResponse{Id: 1, UserId: 4}, nil)
+	terminal := utils.Actor{UserId: 4, Role: "staff", APIKeyId: 2, Scopes: []string{"sales:write"}}
+
+	rec, req := newRequest(http.MethodPost, "/sales", `{"items":[{"item_id":2,"quantity":1,"price":1000}]}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), terminal))
+	h.CreateSales(rec, req)
+	assertSuccess(t, rec, http.StatusCreated)
+
+	// API key, bahkan milik admin, tidak boleh mencatat sale atas nama user lain
+	for _, actor := range []utils.Actor{terminal, {UserId: 4, Role: "admin", APIKeyId: 3}, {UserId: 4, Role: "staff"}} {
+		rec, req = newRequest(http.MethodPost, "/sales", `{"user_id":9,"items":[{"item_id":2,"quantity":1,"price":1000}]}`, nil)
+		req = req.WithContext(utils.WithActor(req.Context(), actor))
+		h.CreateSales(rec, req)
+		assertError(t, rec, http.StatusForbidden, "forbidden")
+	}
+	mockService.AssertNumberOfCalls(t, "CreateSales", 1)
+
+	// admin yang login boleh memilih kasir
+	mockService.On("CreateSales", mock.MatchedBy(func(data *dto.SalesRequest) bool {
+		return data.UserId == 9
+	})).Return(&dto.SalesResponse{Id: 2, UserId: 9}, nil)
+	rec, req = newRequest(http.MethodPost, "/sales", `{"user_id":9,"items":[{"item_id":2,"quantity":1,"price":1000}]}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 1, Role: "admin"}))
+	h.CreateSales(rec, req)
+	assertSuccess(t, rec, http.StatusCreated)
+}
+
+func TestSalesHandler_UpdateSales_OtherCashierForbidden(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+
+	body := `{"user_id":9,"items":[{"item_id":2,"quantity":1,"price":1000}]}`
+	rec, req := newRequest(http.MethodPut, "/sales/5", body, map[string]string{"id": "5"})
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 4, Role: "staff", APIKeyId: 2}))
+	h.UpdateSales(rec, req)
+
+	assertError(t, rec, http.StatusForbidden, "forbidden")
+	mockService.AssertNotCalled(t, "UpdateSales", mock.Anything, mock.Anything)
+}
+
+// TestSalesHandler_MoneyPrecision: harga dan diskon dengan lebih dari 2 desimal
+// dijawab validation error, bukan "invalid request body"
+func TestSalesHandler_MoneyPrecision(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	cashier := utils.Actor{UserId: 1, Role: "staff"}
+
+	for _, body := range []string{
+		`{"items":[{"item_id":2,"quantity":1,"price":1000.125}]}`,
+		`{"items":[{"item_id":2,"quantity":1,"price":1000}],"discount":{"type":"fixed","value":10.005}}`,
+	} {
+		rec, req := newRequest(http.MethodPost, "/sales", body, nil)
+		req = req.WithContext(utils.WithActor(req.Context(), cashier))
+		h.CreateSales(rec, req)
+		assertError(t, rec, http.StatusBadRequest, "validation_error")
+
+		rec, req = newRequest(http.MethodPut, "/sales/5", body, map[string]string{"id": "5"})
+		req = req.WithContext(utils.WithActor(req.Context(), cashier))
+		h.UpdateSales(rec, req)
+		assertError(t, rec, http.StatusBadRequest, "validation_error")
+	}
+	mockService.AssertNotCalled(t, "CreateSales", mock.Anything)
+	mockService.AssertNotCalled(t, "UpdateSales", mock.Anything, mock.Anything)
+}
+
+func TestSalesHandler_CreateSales_InsufficientStock(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("CreateSales", mock.Anything).Return(nil,
+		apperror.InsufficientStock("insufficient stock for one or more items", map[string]any{"item_ids": []int{2}}))
+
+	body := `{"items":[{"item_id":2,"quantity":5,"price":1000}]}`
+	rec, req := newRequest(http.MethodPost, "/sales", body, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 1, Role: "staff"}))
+	h.CreateSales(rec, req)
+
+	env := assertError(t, rec, http.StatusConflict, "insufficient_stock")
+	assert.JSONEq(t, `{"item_ids":[2]}`, string(env.Error.Details))
+}
+
+func TestSalesHandler_CreateSales_BusinessValidation(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("CreateSales", mock.Anything).Return(nil, apperror.Validation("price must be greater than 0", nil))
+
+	body := `{"items":[{"item_id":2,"quantity":1,"price":1000}]}`
+	rec, req := newRequest(http.MethodPost, "/sales", body, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 1, Role: "staff"}))
+	h.CreateSales(rec, req)
+
+	assertError(t, rec, http.StatusUnprocessableEntity, "validation_error")
+}
+
+func TestSalesHandler_DeleteSales_NotFound(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("DeleteSales", 9).Return(apperror.NotFound("sale"))
+
+	rec, req := newRequest(http.MethodDelete, "/sales/9", "", map[string]string{"id": "9"})
+	h.DeleteSales(rec, req)
+
+	assertError(t, rec, http.StatusNotFound, "sale_not_found")
+}
+
+func TestSalesHandler_DeleteSales_NotDraft(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("DeleteSales", 9).Return(apperror.InvalidSaleStatus(model.SaleCompleted, model.SaleDraft))
+
+	rec, req := newRequest(http.MethodDelete, "/sales/9", "", map[string]string{"id": "9"})
+	h.DeleteSales(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "invalid_sale_status")
+}
+
+func TestSalesHandler_VoidSale(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("VoidSale", 5, 7, "customer batal").Return(&dto.SalesResponse{
+		Id: 5, Status: model.SaleVoided, VoidReason: "customer batal",
+	}, nil)
+
+	rec, req := newRequest(http.MethodPost, "/sales/5/void", `{"reason":"customer batal"}`, map[string]string{"id": "5"})
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.VoidSale(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var got dto.SalesResponse
+	require.NoError(t, json.Unmarshal(env.Data, &got))
+	assert.Equal(t, model.SaleVoided, got.Status)
+	assert.Equal(t, "customer batal", got.VoidReason)
+	mockService.AssertExpectations(t)
+}
+
+func TestSalesHandler_VoidSale_ReasonRequired(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/sales/5/void", `{}`, map[string]string{"id": "5"})
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.VoidSale(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+	mockService.AssertNotCalled(t, "VoidSale", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSalesHandler_VoidSale_Unauthenticated(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/sales/5/void", `{"reason":"x"}`, map[string]string{"id": "5"})
+	h.VoidSale(rec, req)
+
+	assertError(t, rec, http.StatusUnauthorized, "unauthorized")
+}
+
+func TestSalesHandler_VoidSale_AlreadyVoided(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("VoidSale", 5, 7, "dobel").Return(nil, apperror.SaleVoided())
+
+	rec, req := newRequest(http.MethodPost, "/sales/5/void", `{"reason":"dobel"}`, map[string]string{"id": "5"})
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.VoidSale(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "sale_voided")
+}
+
+func TestSalesHandler_GetAllSales_Status(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("GetAllSales", repository.SalesFilter{Status: model.SaleHeld}, 1, 10, false).
+		Return([]dto.SalesResponse{{Id: 3, Status: model.SaleHeld}}, 1, nil)
+
+	rec, req := newRequest(http.MethodGet, "/sales?status=held", "", nil)
+	h.GetAllSales(rec, req)
+	assertPaginated(t, rec, 1, 10, 1, 1)
+
+	rec, req = newRequest(http.MethodGet, "/sales?status=parked", "", nil)
+	h.GetAllSales(rec, req)
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+	mockService.AssertNumberOfCalls(t, "GetAllSales", 1)
+}
+
+func TestSalesHandler_CompleteSale(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("CompleteSale", 5).Return(&dto.SalesResponse{
+		Id: 5, Status: model.SaleCompleted, InvoiceNo: "INV/2026/10/000007",
+	}, nil)
+
+	rec, req := newRequest(http.MethodPost, "/sales/5/complete", "", map[string]string{"id": "5"})
+	h.CompleteSale(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var got dto.SalesResponse
+	require.NoError(t, json.Unmarshal(env.Data, &got))
+	assert.Equal(t, model.SaleCompleted, got.Status)
+	assert.Equal(t, "INV/2026/10/000007", got.InvoiceNo)
+}
+
+func TestSalesHandler_HoldSale_InvalidStatus(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+	mockService.On("HoldSale", 5).Return(nil, apperror.InvalidSaleStatus(model.SaleCompleted, "draft or quote"))
+
+	rec, req := newRequest(http.MethodPost, "/sales/5/hold", "", map[string]string{"id": "5"})
+	h.HoldSale(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "invalid_sale_status")
+}
+
+func TestSalesHandler_ReleaseHold_InvalidId(t *testing.T) {
+	mockService := new(MockSalesService)
+	h := NewSalesHandler(mockService, testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/sales/abc/release", "", map[string]string{"id": "abc"})
+	h.ReleaseHold(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+	mockService.AssertNotCalled(t, "ReleaseHold", mock.Anything)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+)
+
+type TwoFactorHandler struct {
+	TwoFactorHandlerService service.TwoFactorService
+	config                  utils.Configuration
+}
+
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService, config utils.Configuration) TwoFactorHandler {
+	return TwoFactorHandler{
+		TwoFactorHandlerService: twoFactorService,
+		config:                  config,
+	}
+}
+
+// Enroll - buat secret TOTP baru untuk user yang sedang login (belum aktif sampai dikonfirmasi)
+func (h *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		utils.ResponseError(w, r, apperror.Unauthorized("authentication required"), "error enrolling two-factor")
+		return
+	}
+
+	enroll, err := h.TwoFactorHandlerService.Enroll(r.Context(), actor.UserId)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error enrolling two-factor")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "success enroll two-factor, confirm with a code from the authenticator app", enroll)
+}
+
+// Confirm - aktifkan 2FA dengan kode pertama, response berisi recovery code
+func (h *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	actor, req, ok := h.decodeCode(w, r, "error confirming two-factor")
+	if !ok {
+		return
+	}
+
+	codes, err := h.TwoFactorHandlerService.Confirm(r.Context(), actor.UserId, req.Code)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error confirming two-factor")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success enable two-factor", codes)
+}
+
+// Disable - matikan 2FA (wajib kode TOTP atau recovery code)
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	actor, req, ok := h.decodeCode(w, r, "error disabling two-factor")
+	if !ok {
+		return
+	}
+
+	if err := h.TwoFactorHandlerService.Disable(r.Context(), actor.UserId, req.Code); err != nil {
+		utils.ResponseError(w, r, err, "error disabling two-factor")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success disable two-factor", nil)
+}
+
+// RegenerateRecoveryCodes - ganti semua recovery code
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	actor, req, ok := h.decodeCode(w, r, "error regenerating recovery codes")
+	if !ok {
+		return
+	}
+
+	codes, err := h.TwoFactorHandlerService.RegenerateRecoveryCodes(r.Context(), actor.UserId, req.Code)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error regenerating recovery codes")
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "success regenerate recovery codes", codes)
+}
+
+// decodeCode membaca actor dan body {"code": "..."}; response error sudah ditulis jika ok false
+func (h *TwoFactorHandler) decodeCode(w http.ResponseWriter, r *http.Request, failMessage string) (utils.Actor, dto.TwoFactorCodeRequest, bool) {
+	var req dto.TwoFactorCodeRequest
+	actor, ok := utils.ActorFromContext(r.Context())
+	if !ok {
+		utils.ResponseError(w, r, apperror.Unauthorized("authentication required"), failMessage)
+		return actor, req, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return actor, req, false
+	}
+
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return actor, req, false
+	}
+	return actor, req, true
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTwoFactorService struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorService) Enroll(ctx context.Context, userId int) (*dto.TwoFactorEnrollResponse, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.TwoFactorEnrollResponse), args.Error(1)
+}
+
+func (m *MockTwoFactorService) Confirm(ctx context.Context, userId int, code string) (*dto.RecoveryCodesResponse, error) {
+	args := m.Called(userId, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RecoveryCodesResponse), args.Error(1)
+}
+
+func (m *MockTwoFactorService) Disable(ctx context.Context, userId int, code string) error {
+	args := m.Called(userId, code)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userId int, code string) (*dto.RecoveryCodesResponse, error) {
+	args := m.Called(userId, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RecoveryCodesResponse), args.Error(1)
+}
+
+func TestTwoFactorHandler_Enroll(t *testing.T) {
+	mockService := new(MockTwoFactorService)
+	h := NewTwoFactorHandler(mockService, testConfig)
+	mockService.On("Enroll", 7).Return(&dto.TwoFactorEnrollResponse{Secret: "ABC", ProvisioningURI: "otpauth://totp/x"}, nil)
+
+	rec, req := newRequest(http.MethodPost, "/users/me/2fa", "", nil)
+	h.Enroll(rec, req)
+	assertError(t, rec, http.StatusUnauthorized, "unauthorized")
+
+	rec, req = newRequest(http.MethodPost, "/users/me/2fa", "", nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.Enroll(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusCreated)
+	var got dto.TwoFactorEnrollResponse
+	require.NoError(t, json.Unmarshal(env.Data, &got))
+	assert.Equal(t, "otpauth://totp/x", got.ProvisioningURI)
+}
+
+func TestTwoFactorHandler_Confirm(t *testing.T) {
+	mockService := new(MockTwoFactorService)
+	h := NewTwoFactorHandler(mockService, testConfig)
+	mockService.On("Confirm", 7, "123456").Return(&dto.RecoveryCodesResponse{RecoveryCodes: []string{"AAAAA-BBBBB"}}, nil)
+
+	rec, req := newRequest(http.MethodPost, "/users/me/2fa/confirm", `{}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.Confirm(rec, req)
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+
+	rec, req = newRequest(http.MethodPost, "/users/me/2fa/confirm", `{"code":"123456"}`, nil)
+	req = req.WithContext(utils.WithActor(req.Context(), utils.Actor{UserId: 7}))
+	h.Confirm(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	assert.JSONEq(t, `{"recovery_codes":["AAAAA-BBBBB"]}`, string(env.Data))
+	mockService.AssertExpectations(t)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/dto"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"project-app-inventory-restapi-golang-azwin/service"
@@ -34,9 +35,13 @@ func (u *UsersHandler) GetUsersByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := u.UsersHandlerService.GetUsersByID(usersID)
+	response, err := u.UsersHandlerService.GetUsersByID(r.Context(), usersID)
 	if err != nil {
-		utils.ResponseBadRequest(w, http.StatusNotFound, "user not found", nil)
+		utils.ResponseError(w, r, err, "error getting user")
+		return
+	}
+
+	if utils.NotModified(w, r, response.Version) {
 		return
 	}
 
@@ -45,12 +50,15 @@ func (u *UsersHandler) GetUsersByID(w http.ResponseWriter, r *http.Request) {
 
 // GetAllUsers - Get all users with pagination
 func (u *UsersHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := u.UsersHandlerService.GetAllUsers()
+	includeDeleted, err := parseIncludeDeleted(r)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid include_deleted value", nil)
+		return
+	}
+
+	users, err := u.UsersHandlerService.GetAllUsers(r.Context(), includeDeleted)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting users")
 		return
 	}
 
@@ -65,23 +73,14 @@ func (u *UsersHandler) GetUsersByEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := u.UsersHandlerService.GetUsersByEmail(email)
+	user, err := u.UsersHandlerService.GetUsersByEmail(r.Context(), email)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error finding user",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error finding user")
 		return
 	}
 
 	if user == nil {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "user not found",
-		})
+		utils.ResponseError(w, r, apperror.NotFound("user"), "user not found")
 		return
 	}
 
@@ -106,7 +105,11 @@ func (u *UsersHandler) CreateUsers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Hash password before saving
-	hashedPassword := utils.HashPassword(userReq.Password)
+	hashedPassword, err := utils.HashPassword(userReq.Password)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error creating user")
+		return
+	}
 
 	// Map DTO to model
 	users := model.Users{
@@ -116,17 +119,13 @@ func (u *UsersHandler) CreateUsers(w http.ResponseWriter, r *http.Request) {
 		Role:     userReq.Role,
 	}
 
-	err = u.UsersHandlerService.CreateUsers(&users)
+	err = u.UsersHandlerService.CreateUsers(r.Context(), &users)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error creating user",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error creating user")
 		return
 	}
 
+	utils.SetETag(w, users.Version)
 	utils.ResponseSuccess(w, http.StatusCreated, "success create user", users)
 }
 
@@ -139,7 +138,13 @@ func (u *UsersHandler) UpdateUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var userReq dto.Usersrequest
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
+	var userReq dto.UsersUpdateRequest
 
 	err = json.NewDecoder(r.Body).Decode(&userReq)
 	if err != nil {
@@ -154,51 +159,91 @@ func (u *UsersHandler) UpdateUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Hash password before saving
-	rawPassword := userReq.Password
-	hashedPassword := utils.HashPassword(rawPassword)
-
-	// Map DTO to model
+	// Map DTO to model (password tidak ikut diupdate)
 	users := model.Users{
+		Version: version,
 		Username: userReq.Username,
 		Email:    userReq.Email,
-		Password: hashedPassword,
 		Role:     userReq.Role,
 	}
 
-	err = u.UsersHandlerService.UpdateUsers(usersID, &users)
+	err = u.UsersHandlerService.UpdateUsers(r.Context(), usersID, &users)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error updating user",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error updating user")
 		return
 	}
 
+	utils.SetETag(w, users.Version)
 	utils.ResponseSuccess(w, http.StatusOK, "success update user", users)
 }
 
+func (u *UsersHandler) PatchUsers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
+	var req dto.UsersUpdateRequest
+	patch, err := utils.DecodeMergePatch(r.Body, &req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	messages, err := utils.ValidatePartialErrors(req, patch.Fields...)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	user, err := u.UsersHandlerService.PatchUsers(r.Context(), id, version, patch.Values)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error patching user")
+		return
+	}
+
+	utils.SetETag(w, user.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success patch user", user)
+}
+
 func (u *UsersHandler) DeleteUsers(w http.ResponseWriter, r *http.Request) {
 	usersIDStr := chi.URLParam(r, "id")
 
 	usersID, err := strconv.Atoi(usersIDStr)
 	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
 		return
 	}
 
-	err = u.UsersHandlerService.DeleteUsers(usersID)
+	err = u.UsersHandlerService.DeleteUsers(r.Context(), usersID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error deleting user",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error deleting user")
 		return
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "success delete user", nil)
 }
 
+func (u *UsersHandler) RestoreUsers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	user, err := u.UsersHandlerService.RestoreUsers(r.Context(), id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error restoring user")
+		return
+	}
+
+	utils.SetETag(w, user.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success restore user", user)
+}
\ No newline at end of file
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUsersService is a mock implementation of the UsersService interface
+type MockUsersService struct {
+	mock.Mock
+}
+
+func (m *MockUsersService) GetUsersByEmail(ctx context.Context, email string) (*model.Users, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Users), args.Error(1)
+}
+
+func (m *MockUsersService) GetUsersByID(ctx context.Context, id int) (model.Users, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Users), args.Error(1)
+}
+
+func (m *MockUsersService) GetAllUsers(ctx context.Context, includeDeleted bool) ([]model.Users, error) {
+	args := m.Called(includeDeleted)
+	return args.Get(0).([]model.Users), args.Error(1)
+}
+
+func (m *MockUsersService) CreateUsers(ctx context.Context, data *model.Users) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockUsersService) UpdateUsers(ctx context.Context, id int, data *model.Users) error {
+	args := m.Called(id, data)
+	return args.Error(0)
+}
+
+func (m *MockUsersService) PatchUsers(ctx context.Context, id, version int, changes map[string]any) (*model.Users, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Users), args.Error(1)
+}
+
+func (m *MockUsersService) DeleteUsers(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUsersService) RestoreUsers(ctx context.Context, id int) (*model.Users, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Users), args.Error(1)
+}
+
+func TestUsersHandler_GetUsersByID_HidesPassword(t *testing.T) {
+	mockService := new(MockUsersService)
+	h := NewUsersHandler(mockService, testConfig)
+	mockService.On("GetUsersByID", 1).Return(model.Users{Id: 1, Username: "admin", Password: "$2a$hash"}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/users/1", "", map[string]string{"id": "1"})
+	h.GetUsersByID(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	assert.NotContains(t, string(env.Data), "password")
+}
+
+func TestUsersHandler_GetUsersByEmail_NotFound(t *testing.T) {
+	mockService := new(MockUsersService)
+	h := NewUsersHandler(mockService, testConfig)
+	mockService.On("GetUsersByEmail", "nobody@example.com").Return(nil, nil)
+
+	rec, req := newRequest(http.MethodGet, "/users/email?email=nobody@example.com", "", nil)
+	h.GetUsersByEmail(rec, req)
+
+	assertError(t, rec, http.StatusNotFound, "user_not_found")
+}
+
+func TestUsersHandler_GetAllUsers(t *testing.T) {
+	mockService := new(MockUsersService)
+	h := NewUsersHandler(mockService, testConfig)
+	mockService.On("GetAllUsers", false).Return([]model.Users{{Id: 1}, {Id: 2}}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/users", "", nil)
+	h.GetAllUsers(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var users []model.Users
+	require.NoError(t, json.Unmarshal(env.Data, &users))
+	assert.Len(t, users, 2)
+}
+
+func TestUsersHandler_CreateUsers_DuplicateEmail(t *testing.T) {
+	mockService := new(MockUsersService)
+	h := NewUsersHandler(mockService, testConfig)
+	mockService.On("CreateUsers", mock.Anything).Return(apperror.Conflict("user_already_exists", "user already exists"))
+
+	body := `{"username":"admin","email":"admin@example.com","password":"Secret123","role":"admin"}`
+	rec, req := newRequest(http.MethodPost, "/users", body, nil)
+	h.CreateUsers(rec, req)
+
+	assertError(t, rec, http.StatusConflict, "user_already_exists")
+}
+
+func TestUsersHandler_CreateUsers_WeakPassword(t *testing.T) {
+	h := NewUsersHandler(new(MockUsersService), testConfig)
+
+	body := `{"username":"admin","email":"admin@example.com","password":"secret123","role":"admin"}`
+	rec, req := newRequest(http.MethodPost, "/users", body, nil)
+	h.CreateUsers(rec, req)
+
+	env := assertError(t, rec, http.StatusBadRequest, "validation_error")
+	assert.Contains(t, string(env.Error.Details), "Password must contain an uppercase letter")
+}
+
+func TestUsersHandler_CreateUsers_InvalidBody(t *testing.T) {
+	h := NewUsersHandler(new(MockUsersService), testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/users", `{not json`, nil)
+	h.CreateUsers(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+}
+
+func TestUsersHandler_PatchUsers_RejectsPassword(t *testing.T) {
+	mockService := new(MockUsersService)
+	h := NewUsersHandler(mockService, testConfig)
+
+	// password hanya bisa diganti lewat /users/me/password atau reset
+	rec, req := newRequest(http.MethodPatch, "/users/1", `{"password":"Secret123"}`, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", "*")
+	h.PatchUsers(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "bad_request")
+	mockService.AssertNotCalled(t, "PatchUsers", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -34,9 +34,13 @@ func (h *WarehousesHandler) GetWarehousesById(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	response, err := h.WarehousesHandlerService.GetWarehousesById(warehousesID)
+	response, err := h.WarehousesHandlerService.GetWarehousesById(r.Context(), warehousesID)
 	if err != nil {
-		utils.ResponseBadRequest(w, http.StatusNotFound, "warehouse not found", nil)
+		utils.ResponseError(w, r, err, "error getting warehouse")
+		return
+	}
+
+	if utils.NotModified(w, r, response.Version) {
 		return
 	}
 
@@ -44,45 +48,21 @@ func (h *WarehousesHandler) GetWarehousesById(w http.ResponseWriter, r *http.Req
 }
 
 func (h *WarehousesHandler) GetAllWarehouses(w http.ResponseWriter, r *http.Request) {
-	// Ambil query param page dan limit
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
+	page, limit := parsePagination(r, h.config.Limit)
 
-	page := 1
-	limit := h.config.Limit
-	var err error
-	if pageStr != "" {
-		page, err = strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			page = 1
-		}
-	}
-	if limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil || limit < 1 {
-			limit = h.config.Limit
-		}
+	includeDeleted, err := parseIncludeDeleted(r)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid include_deleted value", nil)
+		return
 	}
 
-	warehouses, total, err := h.WarehousesHandlerService.GetAllWarehouses(page, limit)
+	warehouses, total, err := h.WarehousesHandlerService.GetAllWarehouses(r.Context(), page, limit, includeDeleted)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error getting warehouses")
 		return
 	}
 
-	response := map[string]interface{}{
-		"data": warehouses,
-		"pagination": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	utils.ResponsePagination(w, http.StatusOK, "success get all warehouses", warehouses, utils.NewPagination(page, limit, total))
 }
 
 func (h *WarehousesHandler) CreateWarehouses(w http.ResponseWriter, r *http.Request) {
@@ -108,17 +88,13 @@ func (h *WarehousesHandler) CreateWarehouses(w http.ResponseWriter, r *http.Requ
 	}
 
 	// create service
-	err = h.WarehousesHandlerService.CreateWarehouses(&warehouses)
+	err = h.WarehousesHandlerService.CreateWarehouses(r.Context(), &warehouses)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error creating warehouse",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error creating warehouse")
 		return
 	}
 
+	utils.SetETag(w, warehouses.Version)
 	utils.ResponseSuccess(w, http.StatusCreated, "success created warehouse", warehouses)
 }
 
@@ -131,6 +107,12 @@ func (h *WarehousesHandler) UpdateWarehouses(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
 	var newWarehouses dto.WarehousesRequest
 	if err := json.NewDecoder(r.Body).Decode(&newWarehouses); err != nil {
 		utils.ResponseBadRequest(w, http.StatusBadRequest, "error data :"+err.Error(), nil)
@@ -146,44 +128,90 @@ func (h *WarehousesHandler) UpdateWarehouses(w http.ResponseWriter, r *http.Requ
 
 	// parsing to model
 	warehouses := model.Warehouses{
+		Version: version,
 		Name:      newWarehouses.Name,
 		Location:  newWarehouses.Location,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	err = h.WarehousesHandlerService.UpdateWarehouses(warehousesID, &warehouses)
+	err = h.WarehousesHandlerService.UpdateWarehouses(r.Context(), warehousesID, &warehouses)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error updating warehouse",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error updating warehouse")
 		return
 	}
 
+	utils.SetETag(w, warehouses.Version)
 	utils.ResponseSuccess(w, http.StatusOK, "success update warehouse", warehouses)
 }
 
+func (h *WarehousesHandler) PatchWarehouses(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.ResponseError(w, r, err, "invalid If-Match header")
+		return
+	}
+
+	var req dto.WarehousesRequest
+	patch, err := utils.DecodeMergePatch(r.Body, &req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	messages, err := utils.ValidatePartialErrors(req, patch.Fields...)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	warehouse, err := h.WarehousesHandlerService.PatchWarehouses(r.Context(), id, version, patch.Values)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error patching warehouse")
+		return
+	}
+
+	utils.SetETag(w, warehouse.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success patch warehouse", warehouse)
+}
+
 func (h *WarehousesHandler) DeleteWarehouses(w http.ResponseWriter, r *http.Request) {
 	warehousesIDstr := chi.URLParam(r, "id")
 
 	warehousesID, err := strconv.Atoi(warehousesIDstr)
 	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
 		return
 	}
 
-	err = h.WarehousesHandlerService.DeleteWarehouses(warehousesID)
+	err = h.WarehousesHandlerService.DeleteWarehouses(r.Context(), warehousesID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  false,
-			"message": "error deleting warehouse",
-			"error":   err.Error(),
-		})
+		utils.ResponseError(w, r, err, "error deleting warehouse")
 		return
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "success delete warehouse", nil)
 }
+
+func (h *WarehousesHandler) RestoreWarehouses(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid id format", nil)
+		return
+	}
+
+	warehouse, err := h.WarehousesHandlerService.RestoreWarehouses(r.Context(), id)
+	if err != nil {
+		utils.ResponseError(w, r, err, "error restoring warehouse")
+		return
+	}
+
+	utils.SetETag(w, warehouse.Version)
+	utils.ResponseSuccess(w, http.StatusOK, "success restore warehouse", warehouse)
+}
\ No newline at end of file
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockWarehousesService is a mock implementation of the WarehousesService interface
+type MockWarehousesService struct {
+	mock.Mock
+}
+
+func (m *MockWarehousesService) GetWarehousesById(ctx context.Context, id int) (*model.Warehouses, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Warehouses), args.Error(1)
+}
+
+func (m *MockWarehousesService) GetAllWarehouses(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Warehouses, int, error) {
+	args := m.Called(page, limit, includeDeleted)
+	return args.Get(0).([]model.Warehouses), args.Int(1), args.Error(2)
+}
+
+func (m *MockWarehousesService) CreateWarehouses(ctx context.Context, data *model.Warehouses) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockWarehousesService) UpdateWarehouses(ctx context.Context, id int, data *model.Warehouses) error {
+	args := m.Called(id, data)
+	return args.Error(0)
+}
+
+func (m *MockWarehousesService) PatchWarehouses(ctx context.Context, id, version int, changes map[string]any) (*model.Warehouses, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Warehouses), args.Error(1)
+}
+
+func (m *MockWarehousesService) DeleteWarehouses(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWarehousesService) RestoreWarehouses(ctx context.Context, id int) (*model.Warehouses, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Warehouses), args.Error(1)
+}
+
+func TestWarehousesHandler_GetWarehousesById_Success(t *testing.T) {
+	mockService := new(MockWarehousesService)
+	h := NewWarehousesHandler(mockService, testConfig)
+	mockService.On("GetWarehousesById", 1).Return(&model.Warehouses{Id: 1, Name: "Gudang Utama"}, nil)
+
+	rec, req := newRequest(http.MethodGet, "/warehouses/1", "", map[string]string{"id": "1"})
+	h.GetWarehousesById(rec, req)
+
+	env := assertSuccess(t, rec, http.StatusOK)
+	var data model.Warehouses
+	require.NoError(t, json.Unmarshal(env.Data, &data))
+	assert.Equal(t, "Gudang Utama", data.Name)
+}
+
+func TestWarehousesHandler_GetWarehousesById_NotFound(t *testing.T) {
+	mockService := new(MockWarehousesService)
+	h := NewWarehousesHandler(mockService, testConfig)
+	mockService.On("GetWarehousesById", 999).Return(nil, apperror.NotFound("warehouse"))
+
+	rec, req := newRequest(http.MethodGet, "/warehouses/999", "", map[string]string{"id": "999"})
+	h.GetWarehousesById(rec, req)
+
+	assertError(t, rec, http.StatusNotFound, "warehouse_not_found")
+}
+
+func TestWarehousesHandler_GetAllWarehouses_Paginated(t *testing.T) {
+	mockService := new(MockWarehousesService)
+	h := NewWarehousesHandler(mockService, testConfig)
+	mockService.On("GetAllWarehouses", 1, 5, false).Return([]model.Warehouses{{Id: 1}, {Id: 2}}, 7, nil)
+
+	rec, req := newRequest(http.MethodGet, "/warehouses?limit=5", "", nil)
+	h.GetAllWarehouses(rec, req)
+
+	assertPaginated(t, rec, 1, 5, 2, 7)
+}
+
+func TestWarehousesHandler_CreateWarehouses_ValidationError(t *testing.T) {
+	h := NewWarehousesHandler(new(MockWarehousesService), testConfig)
+
+	rec, req := newRequest(http.MethodPost, "/warehouses", `{}`, nil)
+	h.CreateWarehouses(rec, req)
+
+	assertError(t, rec, http.StatusBadRequest, "validation_error")
+}
+
+func TestWarehousesHandler_UpdateWarehouses_Success(t *testing.T) {
+	mockService := new(MockWarehousesService)
+	h := NewWarehousesHandler(mockService, testConfig)
+	mockService.On("UpdateWarehouses", 1, mock.Anything).Return(nil)
+
+	rec, req := newRequest(http.MethodPut, "/warehouses/1", `{"name":"Gudang Utama","location":"Jakarta"}`, map[string]string{"id": "1"})
+	req.Header.Set("If-Match", `"1"`)
+	h.UpdateWarehouses(rec, req)
+
+	assertSuccess(t, rec, http.StatusOK)
+}
+
+func TestWarehousesHandler_DeleteWarehouses_NotFound(t *testing.T) {
+	mockService := new(MockWarehousesService)
+	h := NewWarehousesHandler(mockService, testConfig)
+	mockService.On("DeleteWarehouses", 1).Return(apperror.NotFound("warehouse"))
+
+	rec, req := newRequest(http.MethodDelete, "/warehouses/1", "", map[string]string{"id": "1"})
+	h.DeleteWarehouses(rec, req)
+
+	assertError(t, rec, http.StatusNotFound, "warehouse_not_found")
+}
@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/service"
+
+	"go.uber.org/zap"
+)
+
+// PurgeIdempotencyKeys membuat job yang menghapus Idempotency-Key yang sudah kedaluwarsa.
+func PurgeIdempotencyKeys(svc service.IdempotencyService, log *zap.Logger) func(context.Context) error {
+	return func(ctx context.Context) error {
+		purged, err := svc.PurgeExpired(ctx)
+		if err != nil {
+			return err
+		}
+		if purged > 0 {
+			log.Info("expired idempotency keys purged", zap.Int64("total", purged))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+// Package jobs berisi pekerjaan latar belakang yang berjalan bersama HTTP server.
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Every menjalankan fn sekali di awal lalu setiap interval sampai ctx selesai.
+// Error dari fn hanya dicatat; job tetap berjalan pada putaran berikutnya.
+func Every(ctx context.Context, name string, interval time.Duration, log *zap.Logger, fn func(context.Context) error) {
+	if interval <= 0 {
+		log.Info("job disabled", zap.String("job", name))
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			log.Error("job failed", zap.String("job", name), zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
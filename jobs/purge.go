@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PurgeDeleted membuat job yang menghapus permanen data soft delete
+// yang umurnya melewati retention.
+func PurgeDeleted(svc service.PurgeService, retention time.Duration, log *zap.Logger) func(context.Context) error {
+	return func(ctx context.Context) error {
+		purged, err := svc.PurgeDeleted(ctx, retention)
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		fields := make([]zap.Field, 0, len(purged)+2)
+		for table, count := range purged {
+			total += count
+			fields = append(fields, zap.Int64(table, count))
+		}
+		fields = append(fields, zap.Int64("total", total), zap.Duration("retention", retention))
+
+		log.Info("soft deleted rows purged", fields...)
+		return nil
+	}
+}
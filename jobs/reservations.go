@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/service"
+
+	"go.uber.org/zap"
+)
+
+// ExpireReservations membuat job yang melepas stock reservasi active yang sudah
+// melewati expires_at; statusnya menjadi expired.
+func ExpireReservations(svc service.ReservationsService, log *zap.Logger) func(context.Context) error {
+	return func(ctx context.Context) error {
+		expired, err := svc.ExpireReservations(ctx)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			log.Info("expired stock reservations released", zap.Int64("total", expired))
+		}
+		return nil
+	}
+}
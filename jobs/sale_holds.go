@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/service"
+
+	"go.uber.org/zap"
+)
+
+// ExpireSaleHolds membuat job yang melepas stock sale held yang sudah melewati
+// hold_expires_at; sale-nya kembali menjadi draft.
+func ExpireSaleHolds(svc service.SalesService, log *zap.Logger) func(context.Context) error {
+	return func(ctx context.Context) error {
+		expired, err := svc.ExpireHolds(ctx)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			log.Info("expired sale holds released", zap.Int64("total", expired))
+		}
+		return nil
+	}
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"project-app-inventory-restapi-golang-azwin/database"
 	"project-app-inventory-restapi-golang-azwin/handler"
+	"project-app-inventory-restapi-golang-azwin/jobs"
 	"project-app-inventory-restapi-golang-azwin/repository"
 	"project-app-inventory-restapi-golang-azwin/router"
 	"project-app-inventory-restapi-golang-azwin/service"
@@ -26,6 +28,7 @@ func main() {
 		fmt.Printf("Failed to initialize database: %v\n", err)
 		return
 	}
+	defer db.Close()
 
 	// Initialize logger with daily log rotation
 	logger, err := utils.InitLogger(loadConfig.PathLogging, loadConfig.Debug)
@@ -34,21 +37,45 @@ func main() {
 		return
 	}
 	defer logger.Sync()
+	zap.ReplaceGlobals(logger)
+
+	// Initialize tracing (OTLP / stdout / file exporter)
+	shutdownTracer, err := utils.InitTracer(loadConfig.Tracing)
+	if err != nil {
+		logger.Error("Failed to initialize tracing", zap.Error(err))
+		return
+	}
+	defer shutdownTracer(context.Background())
 
 	logger.Info("Application starting",
 		zap.String("app_name", "Inventory REST API"),
 		zap.Int("port", loadConfig.Port),
 		zap.Bool("debug", loadConfig.Debug),
+		zap.String("tracing_exporter", loadConfig.Tracing.Exporter),
 	)
 
+	// password policy dipakai validator (tag validate:"password")
+	utils.SetPasswordPolicy(loadConfig.Auth.PasswordPolicy)
+
 	// Initialize repository
 	repo := repository.NewRepository(db, logger)
-	service := service.NewService(repo)
+	service := service.NewService(repo, *loadConfig)
 	handler := handler.NewHandler(service, *loadConfig)
 
+	// Background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go jobs.Every(jobsCtx, "purge_soft_deleted", loadConfig.SoftDelete.PurgeInterval, logger,
+		jobs.PurgeDeleted(service.PurgeService, loadConfig.SoftDelete.Retention, logger))
+	go jobs.Every(jobsCtx, "purge_idempotency_keys", loadConfig.Idempotency.PurgeInterval, logger,
+		jobs.PurgeIdempotencyKeys(service.IdempotencyService, logger))
+	go jobs.Every(jobsCtx, "expire_sale_holds", loadConfig.SaleHold.SweepInterval, logger,
+		jobs.ExpireSaleHolds(service.SalesService, logger))
+	go jobs.Every(jobsCtx, "expire_reservations", loadConfig.Reservation.SweepInterval, logger,
+		jobs.ExpireReservations(service.ReservationsService, logger))
 
 	// Initialize router
-	r := router.NewRouter(handler, service, logger)
+	r := router.NewRouter(handler, service, *loadConfig, logger)
 
 	// Start server
 	addr := fmt.Sprintf(":%d", loadConfig.Port)
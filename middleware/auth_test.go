@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// stubAuth menerima satu token dan satu API key saja
+type stubAuth struct {
+	token string
+	actor utils.Actor
+	key   string
+}
+
+func (s stubAuth) Login(ctx context.Context, email, password string) (*dto.LoginResponse, error) {
+	return nil, nil
+}
+
+func (s stubAuth) Authenticate(ctx context.Context, token string) (*utils.Actor, error) {
+	if token != s.token {
+		return nil, apperror.Unauthorized("invalid or expired token")
+	}
+	return &s.actor, nil
+}
+
+func (s stubAuth) AuthenticateAPIKey(ctx context.Context, key string) (*utils.Actor, error) {
+	if key != s.key {
+		return nil, apperror.Unauthorized("invalid or expired api key")
+	}
+	return &utils.Actor{UserId: 9, Username: "pos", Role: "admin", APIKeyId: 3, Scopes: []string{"items:read", "sales:write"}}, nil
+}
+
+func (s stubAuth) Logout(ctx context.Context, token string) error { return nil }
+
+func (s stubAuth) VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*dto.LoginResponse, error) {
+	return nil, nil
+}
+
+func (s stubAuth) UnlockAccount(ctx context.Context, userId int) error { return nil }
+
+func newAuthMiddleware() MiddlewareCostume {
+	auth := stubAuth{token: "good", actor: utils.Actor{UserId: 1, Username: "root", Role: "super_admin"}, key: "ik_pos"}
+	return NewMiddlewareCustome(service.Service{AuthService: auth}, zap.NewNop())
+}
+
+func TestAuthentication(t *testing.T) {
+	mw := newAuthMiddleware()
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantActor     bool
+	}{
+		{"anonymous", "", http.StatusOK, false},
+		{"valid token", "Bearer good", http.StatusOK, true},
+		{"invalid token", "Bearer bad", http.StatusUnauthorized, false},
+		{"wrong scheme", "Basic Zm9vOmJhcg==", http.StatusUnauthorized, false},
+		{"valid api key", "ApiKey ik_pos", http.StatusOK, true},
+		{"invalid api key", "ApiKey ik_other", http.StatusUnauthorized, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotActor bool
+			handler := mw.Authentication(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, gotActor = utils.ActorFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantActor, gotActor)
+		})
+	}
+}
+
+func TestAuthentication_APIKeyScopes(t *testing.T) {
+	mw := newAuthMiddleware()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{http.MethodGet, "/items/5", http.StatusOK},
+		{http.MethodPost, "/items", http.StatusForbidden},
+		{http.MethodPost, "/sales", http.StatusOK},
+		{http.MethodGet, "/sales", http.StatusForbidden},
+		{http.MethodGet, "/audit", http.StatusForbidden},
+		{http.MethodPost, "/api-keys", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "ApiKey ik_pos")
+			rec := httptest.NewRecorder()
+			mw.Authentication(ok).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	mw := newAuthMiddleware()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name       string
+		actor      *utils.Actor
+		roles      []string
+		wantStatus int
+	}{
+		{"anonymous", nil, []string{"super_admin"}, http.StatusUnauthorized},
+		{"role not allowed", &utils.Actor{UserId: 2, Role: "staff"}, []string{"super_admin"}, http.StatusForbidden},
+		{"role allowed", &utils.Actor{UserId: 1, Role: "super_admin"}, []string{"super_admin"}, http.StatusOK},
+		{"any logged in user", &utils.Actor{UserId: 2, Role: "staff"}, nil, http.StatusOK},
+		{"two factor pending", &utils.Actor{UserId: 1, Role: "super_admin", TwoFactorPending: true}, []string{"super_admin"}, http.StatusForbidden},
+		{"two factor pending can enroll", &utils.Actor{UserId: 1, Role: "super_admin", TwoFactorPending: true}, nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/audit", nil)
+			if tt.actor != nil {
+				req = req.WithContext(utils.WithActor(req.Context(), *tt.actor))
+			}
+			rec := httptest.NewRecorder()
+			mw.RequireRole(tt.roles...)(ok).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+)
+
+// idempotencyHeaders adalah header response yang disimpan dan ikut di-replay
+var idempotencyHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// recordingWriter meneruskan response ke client sambil menyimpan salinannya
+type recordingWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Idempotency menangani header Idempotency-Key pada POST. Request pertama dijalankan
+// dan response-nya disimpan; retry dengan key dan body yang sama mendapat response
+// yang sama (header Idempotent-Replayed: true) tanpa menjalankan handler lagi.
+// Response 5xx tidak disimpan supaya client bisa mencoba lagi.
+// Harus dipasang setelah Authentication karena key dipisah per client.
+func (middlewareCostume *MiddlewareCostume) Idempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(idempotencyKeyHeader))
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters", nil)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		svc := middlewareCostume.Service.IdempotencyService
+		record, err := svc.Begin(r.Context(), clientKey(r), key, r.Method, r.URL.Path, hashRequestBody(body))
+		if err != nil {
+			utils.ResponseError(w, r, err, "error idempotency key")
+			return
+		}
+
+		if record.Completed() {
+			for name, value := range record.ResponseHeaders {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set(idempotencyReplayedHeader, "true")
+			w.WriteHeader(*record.StatusCode)
+			w.Write(record.ResponseBody)
+			return
+		}
+
+		log := utils.LoggerFromContext(r.Context(), middlewareCostume.Log)
+		// response sudah atau sedang dikirim, jadi simpan / lepas key walau client putus
+		ctx := context.WithoutCancel(r.Context())
+		recorder := &recordingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			// panic atau 5xx: lepas key supaya retry menjalankan request lagi
+			if err := svc.Release(ctx, record.Id); err != nil {
+				log.Error("error releasing idempotency key", zap.Int("idempotency_key_id", record.Id), zap.Error(err))
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+
+		if recorder.statusCode >= http.StatusInternalServerError {
+			return
+		}
+		headers := make(map[string]string, len(idempotencyHeaders))
+		for _, name := range idempotencyHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+		// gagal menyimpan: key tetap berstatus diproses (retry ditolak 409 sampai
+		// kedaluwarsa) karena melepasnya bisa membuat request dijalankan dua kali
+		stored = true
+		if err := svc.Complete(ctx, record.Id, recorder.statusCode, headers, recorder.body.Bytes()); err != nil {
+			log.Error("error storing idempotent response", zap.Int("idempotency_key_id", record.Id), zap.Error(err))
+		}
+	})
+}
+
+func hashRequestBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// memoryIdempotencyRepo menyimpan key di map, cukup untuk menguji alur middleware
+type memoryIdempotencyRepo struct {
+	keys   map[string]*model.IdempotencyKey
+	nextId int
+}
+
+func (m *memoryIdempotencyRepo) ReserveIdempotencyKey(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, bool, error) {
+	if existing, ok := m.keys[key.Scope+"|"+key.Key]; ok {
+		copied := *existing
+		return &copied, false, nil
+	}
+	m.nextId++
+	key.Id = m.nextId
+	m.keys[key.Scope+"|"+key.Key] = key
+	return key, true, nil
+}
+
+func (m *memoryIdempotencyRepo) CompleteIdempotencyKey(ctx context.Context, id, statusCode int, headers map[string]string, body []byte) error {
+	for _, key := range m.keys {
+		if key.Id == id {
+			key.StatusCode, key.ResponseHeaders, key.ResponseBody = &statusCode, headers, body
+			return nil
+		}
+	}
+	return apperror.NotFound("idempotency_key")
+}
+
+func (m *memoryIdempotencyRepo) ReleaseIdempotencyKey(ctx context.Context, id int) error {
+	for name, key := range m.keys {
+		if key.Id == id && !key.Completed() {
+			delete(m.keys, name)
+		}
+	}
+	return nil
+}
+
+func (m *memoryIdempotencyRepo) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// countingHandler mencatat berapa kali handler benar-benar dijalankan
+type countingHandler struct {
+	calls  int
+	status int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/sales/42")
+	w.WriteHeader(h.status)
+	w.Write([]byte(`{"call":` + strconv.Itoa(h.calls) + `,"body":` + string(body) + `}`))
+}
+
+func newIdempotencyMiddleware() (MiddlewareCostume, *memoryIdempotencyRepo) {
+	repo := &memoryIdempotencyRepo{keys: map[string]*model.IdempotencyKey{}}
+	svc := service.Service{IdempotencyService: service.NewIdempotencyService(repo, time.Hour)}
+	return NewMiddlewareCustome(svc, zap.NewNop()), repo
+}
+
+func doIdempotent(mw MiddlewareCostume, next http.Handler, method, key, body string, actor *utils.Actor) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/sales", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	if actor != nil {
+		req = req.WithContext(utils.WithActor(req.Context(), *actor))
+	}
+	rec := httptest.NewRecorder()
+	mw.Idempotency(next).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	mw, _ := newIdempotencyMiddleware()
+	next := &countingHandler{status: http.StatusCreated}
+	cashier := &utils.Actor{UserId: 1, Role: "cashier"}
+
+	first := doIdempotent(mw, next, http.MethodPost, "sale-1", `{"items":[1]}`, cashier)
+	retry := doIdempotent(mw, next, http.MethodPost, "sale-1", `{"items":[1]}`, cashier)
+
+	assert.Equal(t, 1, next.calls)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "/sales/42", retry.Header().Get("Location"))
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_ReusedKeyWithDifferentBody(t *testing.T) {
+	mw, _ := newIdempotencyMiddleware()
+	next := &countingHandler{status: http.StatusCreated}
+	cashier := &utils.Actor{UserId: 1, Role: "cashier"}
+
+	doIdempotent(mw, next, http.MethodPost, "sale-1", `{"items":[1]}`, cashier)
+	rec := doIdempotent(mw, next, http.MethodPost, "sale-1", `{"items":[2]}`, cashier)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "idempotency_key_reused")
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	mw, repo := newIdempotencyMiddleware()
+	next := &countingHandler{status: http.StatusInternalServerError}
+
+	doIdempotent(mw, next, http.MethodPost, "sale-1", `{}`, nil)
+	assert.Empty(t, repo.keys)
+
+	next.status = http.StatusCreated
+	rec := doIdempotent(mw, next, http.MethodPost, "sale-1", `{}`, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestIdempotency_ClientErrorIsStored(t *testing.T) {
+	mw, _ := newIdempotencyMiddleware()
+	next := &countingHandler{status: http.StatusConflict}
+
+	doIdempotent(mw, next, http.MethodPost, "sale-1", `{}`, nil)
+	rec := doIdempotent(mw, next, http.MethodPost, "sale-1", `{}`, nil)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestIdempotency_Passthrough(t *testing.T) {
+	mw, _ := newIdempotencyMiddleware()
+	next := &countingHandler{status: http.StatusOK}
+
+	// tanpa header
+	doIdempotent(mw, next, http.MethodPost, "", `{}`, nil)
+	doIdempotent(mw, next, http.MethodPost, "", `{}`, nil)
+	// selain POST
+	doIdempotent(mw, next, http.MethodPut, "sale-1", `{}`, nil)
+	doIdempotent(mw, next, http.MethodPut, "sale-1", `{}`, nil)
+
+	assert.Equal(t, 4, next.calls)
+}
+
+func TestIdempotency_KeysAreScopedPerClient(t *testing.T) {
+	mw, _ := newIdempotencyMiddleware()
+	next := &countingHandler{status: http.StatusCreated}
+
+	doIdempotent(mw, next, http.MethodPost, "sale-1", `{}`, &utils.Actor{UserId: 1})
+	rec := doIdempotent(mw, next, http.MethodPost, "sale-1", `{}`, &utils.Actor{UserId: 2})
+
+	assert.Equal(t, 2, next.calls)
+	assert.Empty(t, rec.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_KeyTooLong(t *testing.T) {
+	mw, _ := newIdempotencyMiddleware()
+	next := &countingHandler{status: http.StatusCreated}
+
+	rec := doIdempotent(mw, next, http.MethodPost, strings.Repeat("k", 256), `{}`, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 0, next.calls)
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"project-app-inventory-restapi-golang-azwin/utils"
 	"time"
 
 	"go.uber.org/zap"
@@ -43,7 +44,7 @@ func (middlewareCostume *MiddlewareCostume) Logging(next http.Handler) http.Hand
 		duration := time.Since(start)
 
 		// Log the request dengan detail lengkap
-		middlewareCostume.Log.Info("HTTP Request",
+		utils.LoggerFromContext(r.Context(), middlewareCostume.Log).Info("HTTP Request",
 			zap.String("method", r.Method),
 			zap.String("path", r.URL.Path),
 			zap.String("query", r.URL.RawQuery),
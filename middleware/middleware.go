@@ -9,6 +9,8 @@ import (
 type MiddlewareCostume struct {
 	Service service.Service
 	Log     *zap.Logger
+	// RateLimiter nil = rate limit tidak aktif
+	RateLimiter *RateLimiter
 }
 
 func NewMiddlewareCustome(service service.Service, log *zap.Logger) MiddlewareCostume {
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RateLimitResult adalah hasil satu kali Take pada bucket
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset: waktu sampai bucket penuh kembali
+	Reset time.Duration
+	// RetryAfter: waktu sampai ada token lagi (hanya jika tidak Allowed)
+	RetryAfter time.Duration
+}
+
+// RateLimitStore menyimpan state bucket per key. Implementasi default in-memory
+// (per instance); untuk beberapa instance bisa diganti store bersama (misal Redis).
+type RateLimitStore interface {
+	Take(key string, rule utils.RateLimitRule, now time.Time) RateLimitResult
+}
+
+// RateLimiter memilih rule per route dan key per client (API key, user, atau IP)
+type RateLimiter struct {
+	Store  RateLimitStore
+	Config utils.RateLimitConfig
+	now    func() time.Time
+}
+
+func NewRateLimiter(config utils.RateLimitConfig, store RateLimitStore) *RateLimiter {
+	return &RateLimiter{Store: store, Config: config, now: time.Now}
+}
+
+// rule mencari route limit dengan path prefix terpanjang yang cocok, atau Default.
+// Nama yang dikembalikan memisahkan bucket antar rule untuk client yang sama.
+func (l *RateLimiter) rule(r *http.Request) (string, utils.RateLimitRule) {
+	name, rule, matched := "default", l.Config.Default, 0
+	for _, route := range l.Config.Routes {
+		if route.Method != "*" && route.Method != r.Method {
+			continue
+		}
+		if !pathHasPrefix(r.URL.Path, route.PathPrefix) || len(route.PathPrefix) <= matched {
+			continue
+		}
+		name, rule, matched = route.Method+" "+route.PathPrefix, route.Rule, len(route.PathPrefix)
+	}
+	return name, rule
+}
+
+// pathHasPrefix mencocokkan per segmen: "/sales" cocok dengan "/sales/1" tapi tidak "/salesman"
+func pathHasPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/") || prefix == ""
+}
+
+// clientKey: API key lebih spesifik dari user, user lebih spesifik dari IP
+func clientKey(r *http.Request) string {
+	if actor, ok := utils.ActorFromContext(r.Context()); ok {
+		if actor.APIKeyId != 0 {
+			return "api_key:" + strconv.Itoa(actor.APIKeyId)
+		}
+		return "user:" + strconv.Itoa(actor.UserId)
+	}
+	return "ip:" + utils.ClientIPFromContext(r.Context())
+}
+
+// RateLimit membatasi request per client dengan token bucket dan mengirim header
+// RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset dan RateLimit-Policy.
+// Harus dipasang setelah Authentication supaya key per API key / user dipakai.
+func (middlewareCostume *MiddlewareCostume) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := middlewareCostume.RateLimiter
+		if limiter == nil || !limiter.Config.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		name, rule := limiter.rule(r)
+		if rule.Requests <= 0 || rule.Per <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := clientKey(r)
+		result := limiter.Store.Take(key+"|"+name, rule, limiter.now())
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(utils.CeilSeconds(result.Reset)))
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rule.Requests, utils.CeilSeconds(rule.Per)))
+
+		if !result.Allowed {
+			utils.LoggerFromContext(r.Context(), middlewareCostume.Log).Warn("rate limit exceeded",
+				zap.String("client", key),
+				zap.String("rule", name),
+			)
+			err := apperror.TooManyRequests("rate_limited", "rate limit exceeded, try again later", result.RetryAfter)
+			utils.ResponseError(w, r, err, "rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// MemoryRateLimitStore adalah token bucket in-memory. Bucket yang sudah penuh
+// kembali (idle selama satu periode) dibuang secara berkala.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	// SweepInterval: jarak minimal antar pembersihan bucket idle
+	SweepInterval time.Duration
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	per    time.Duration
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*tokenBucket), SweepInterval: time.Minute}
+}
+
+func (s *MemoryRateLimitStore) Take(key string, rule utils.RateLimitRule, now time.Time) RateLimitResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	capacity := float64(rule.Requests)
+	rate := capacity / rule.Per.Seconds() // token per detik
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	}
+	b.per = rule.Per
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+		b.last = now
+	}
+
+	result := RateLimitResult{Limit: rule.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsDuration((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = secondsDuration((capacity - b.tokens) / rate)
+	return result
+}
+
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.SweepInterval {
+		return
+	}
+	for key, b := range s.buckets {
+		if now.Sub(b.last) >= b.per {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// secondsDuration dibulatkan ke mikrodetik supaya sisa pembulatan float tidak
+// menambah satu detik di header
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds*1e6)) * time.Microsecond
+}
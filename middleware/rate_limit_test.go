@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeClock mengganti time.Now di limiter supaya test deterministik
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newRateLimitMiddleware(config utils.RateLimitConfig) (MiddlewareCostume, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mw := NewMiddlewareCustome(service.Service{}, zap.NewNop())
+	mw.RateLimiter = NewRateLimiter(config, NewMemoryRateLimitStore())
+	mw.RateLimiter.now = clock.Now
+	return mw, clock
+}
+
+func doRateLimited(mw MiddlewareCostume, method, path string, actor *utils.Actor, ip string) *httptest.ResponseRecorder {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	req := httptest.NewRequest(method, path, nil)
+	ctx := utils.WithClientIP(req.Context(), ip)
+	if actor != nil {
+		ctx = utils.WithActor(ctx, *actor)
+	}
+	rec := httptest.NewRecorder()
+	mw.RateLimit(ok).ServeHTTP(rec, req.WithContext(ctx))
+	return rec
+}
+
+func TestRateLimit_ExhaustAndRefill(t *testing.T) {
+	mw, clock := newRateLimitMiddleware(utils.RateLimitConfig{
+		Enabled: true,
+		Default: utils.RateLimitRule{Requests: 3, Per: time.Minute},
+	})
+
+	for i, remaining := range []string{"2", "1", "0"} {
+		rec := doRateLimited(mw, http.MethodGet, "/items", nil, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, rec.Code, "request %d", i+1)
+		assert.Equal(t, "3", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, remaining, rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "3;w=60", rec.Header().Get("RateLimit-Policy"))
+	}
+
+	rec := doRateLimited(mw, http.MethodGet, "/items", nil, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", rec.Header().Get("RateLimit-Reset"))
+	// satu token terisi setiap 20 detik
+	assert.Equal(t, "20", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), "rate_limited")
+
+	clock.Advance(19 * time.Second)
+	rec = doRateLimited(mw, http.MethodGet, "/items", nil, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	clock.Advance(time.Second)
+	rec = doRateLimited(mw, http.MethodGet, "/items", nil, "10.0.0.1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+	// setelah satu periode penuh bucket kembali penuh
+	clock.Advance(time.Minute)
+	rec = doRateLimited(mw, http.MethodGet, "/items", nil, "10.0.0.1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Remaining"))
+}
+
+func TestRateLimit_RouteRules(t *testing.T) {
+	mw, _ := newRateLimitMiddleware(utils.RateLimitConfig{
+		Enabled: true,
+		Default: utils.RateLimitRule{Requests: 100, Per: time.Minute},
+		Routes: []utils.RouteRateLimit{
+			{Method: http.MethodGet, PathPrefix: "/sales", Rule: utils.RateLimitRule{Requests: 1, Per: time.Minute}},
+			{Method: "*", PathPrefix: "/sales/report", Rule: utils.RateLimitRule{Requests: 5, Per: time.Hour}},
+		},
+	})
+
+	rec := doRateLimited(mw, http.MethodGet, "/sales/7", nil, "10.0.0.1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, http.StatusTooManyRequests, doRateLimited(mw, http.MethodGet, "/sales", nil, "10.0.0.1").Code)
+
+	// method lain dan path yang hanya mirip memakai default
+	rec = doRateLimited(mw, http.MethodPost, "/sales", nil, "10.0.0.1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "100", rec.Header().Get("RateLimit-Limit"))
+	rec = doRateLimited(mw, http.MethodGet, "/salesman", nil, "10.0.0.1")
+	assert.Equal(t, "100", rec.Header().Get("RateLimit-Limit"))
+
+	// prefix terpanjang menang
+	rec = doRateLimited(mw, http.MethodGet, "/sales/report", nil, "10.0.0.1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "5;w=3600", rec.Header().Get("RateLimit-Policy"))
+}
+
+func TestRateLimit_ClientKeys(t *testing.T) {
+	mw, _ := newRateLimitMiddleware(utils.RateLimitConfig{
+		Enabled: true,
+		Default: utils.RateLimitRule{Requests: 1, Per: time.Minute},
+	})
+
+	user := &utils.Actor{UserId: 9, Role: "admin"}
+	apiKey := &utils.Actor{UserId: 9, Role: "admin", APIKeyId: 3}
+
+	assert.Equal(t, http.StatusOK, doRateLimited(mw, http.MethodGet, "/items", nil, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, doRateLimited(mw, http.MethodGet, "/items", nil, "10.0.0.2").Code)
+	assert.Equal(t, http.StatusOK, doRateLimited(mw, http.MethodGet, "/items", user, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, doRateLimited(mw, http.MethodGet, "/items", apiKey, "10.0.0.1").Code)
+
+	// user yang sama dari IP lain tetap memakai bucket user
+	assert.Equal(t, http.StatusTooManyRequests, doRateLimited(mw, http.MethodGet, "/items", user, "10.0.0.3").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRateLimited(mw, http.MethodGet, "/items", apiKey, "10.0.0.3").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRateLimited(mw, http.MethodGet, "/items", nil, "10.0.0.1").Code)
+}
+
+func TestRateLimit_Disabled(t *testing.T) {
+	mw, _ := newRateLimitMiddleware(utils.RateLimitConfig{
+		Enabled: false,
+		Default: utils.RateLimitRule{Requests: 1, Per: time.Minute},
+	})
+
+	for i := 0; i < 3; i++ {
+		rec := doRateLimited(mw, http.MethodGet, "/items", nil, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestMemoryRateLimitStore_SweepIdleBuckets(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	rule := utils.RateLimitRule{Requests: 2, Per: time.Minute}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store.Take("a", rule, now)
+	store.Take("b", rule, now.Add(30*time.Second))
+	assert.Len(t, store.buckets, 2)
+
+	// "a" sudah idle satu periode, "b" belum
+	store.Take("c", rule, now.Add(70*time.Second))
+	assert.Len(t, store.buckets, 2)
+	assert.NotContains(t, store.buckets, "a")
+	assert.Contains(t, store.buckets, "b")
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"project-app-inventory-restapi-golang-azwin/utils"
+
+	"go.uber.org/zap"
+)
+
+// maxRequestIDLength membatasi panjang X-Request-ID dari client
+const maxRequestIDLength = 128
+
+// RequestID memakai X-Request-ID dari client (jika valid) atau membuat yang baru,
+// lalu menyimpan id dan child logger di context serta mengembalikannya di response header
+func (middlewareCostume *MiddlewareCostume) RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(utils.HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = utils.GenerateUUIDToken()
+		}
+
+		// Echo request id sebelum handler menulis response
+		w.Header().Set(utils.HeaderRequestID, requestID)
+
+		log := middlewareCostume.Log.With(zap.String("request_id", requestID))
+		ctx := utils.WithRequestID(r.Context(), requestID)
+		ctx = utils.WithLogger(ctx, log)
+		ctx = utils.WithClientIP(ctx, clientIP(r))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID hanya menerima karakter yang aman untuk log dan header
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// clientIP mengambil host dari RemoteAddr (chi RealIP sudah menimpanya bila dipasang)
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRequestID_PropagatesIncomingHeader(t *testing.T) {
+	mw := NewMiddlewareCustome(service.Service{}, zap.NewNop())
+
+	var ctxRequestID string
+	handler := mw.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxRequestID = utils.RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set(utils.HeaderRequestID, "pos-terminal-01:abc123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "pos-terminal-01:abc123", ctxRequestID)
+	assert.Equal(t, "pos-terminal-01:abc123", rec.Header().Get(utils.HeaderRequestID))
+}
+
+func TestRequestID_GeneratesWhenMissingOrInvalid(t *testing.T) {
+	mw := NewMiddlewareCustome(service.Service{}, zap.NewNop())
+
+	var ctxRequestID string
+	handler := mw.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxRequestID = utils.RequestIDFromContext(r.Context())
+		assert.NotNil(t, utils.LoggerFromContext(r.Context(), nil))
+	}))
+
+	for _, incoming := range []string{"", "bad id\nwith newline"} {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set(utils.HeaderRequestID, incoming)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.NotEmpty(t, ctxRequestID)
+		assert.NotEqual(t, incoming, ctxRequestID)
+		assert.Equal(t, ctxRequestID, rec.Header().Get(utils.HeaderRequestID))
+	}
+}
+
+func TestResponseBadRequest_IncludesRequestID(t *testing.T) {
+	mw := NewMiddlewareCustome(service.Service{}, zap.NewNop())
+
+	handler := mw.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.ResponseBadRequest(w, http.StatusNotFound, "item not found", nil)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/items/99", nil)
+	req.Header.Set(utils.HeaderRequestID, "req-42")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"request_id":"req-42"`)
+}
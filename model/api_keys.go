@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// APIKeyScopes adalah scope yang bisa diberikan ke API key, formatnya
+// "<resource>:read" (GET) atau "<resource>:write" (POST/PUT/PATCH/DELETE)
+var APIKeyScopes = []string{
+	"items:read", "items:write",
+	"categories:read", "categories:write",
+	"racks:read", "racks:write",
+	"warehouses:read", "warehouses:write",
+	"sales:read", "sales:write",
+	"customers:read", "customers:write",
+	"reservations:read", "reservations:write",
+	"reports:read",
+}
+
+type APIKey struct {
+	Id         int        `json:"id"`
+	UserId     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Role       string     `json:"role"`
+	Scopes     []string   `json:"scopes"`
+	ExpiredAt  *time.Time `json:"expired_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
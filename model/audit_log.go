@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Aksi yang dicatat di audit_log
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	// sale dibatalkan (stock dikembalikan, data tetap disimpan)
+	AuditVoid = "void"
+	// lifecycle sale: stock dipesan, pesanan dilepas, sale diselesaikan
+	AuditHold     = "hold"
+	AuditRelease  = "release"
+	AuditComplete = "complete"
+	// reservasi stock di-confirm (stock dikurangi)
+	AuditConfirm = "confirm"
+	// token reset password dibuat admin
+	AuditPasswordReset = "password_reset"
+	// akun yang terkunci karena login gagal dibuka admin
+	AuditUnlock = "unlock"
+	// 2FA diaktifkan / dinonaktifkan / recovery code dibuat ulang
+	AuditTwoFactorEnable  = "two_factor_enable"
+	AuditTwoFactorDisable = "two_factor_disable"
+	AuditRecoveryCodes    = "recovery_codes"
+)
+
+type AuditLog struct {
+	Id            int64           `json:"id"`
+	ActorId       *int            `json:"actor_id"`
+	ActorUsername string          `json:"actor_username,omitempty"`
+	ActorRole     string          `json:"actor_role,omitempty"`
+	Action        string          `json:"action"`
+	Entity        string          `json:"entity"`
+	EntityId      int             `json:"entity_id"`
+	Changes       json.RawMessage `json:"changes"`
+	IP            string          `json:"ip,omitempty"`
+	RequestId     string          `json:"request_id,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -3,8 +3,13 @@ package model
 import "time"
 
 type Categories struct {
-	Id	   int    `json:"id"`
-	Name     string `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	// TaxRate dalam persen (PPN 11 -> 11.00), berlaku untuk item di category ini
+	TaxRate      TaxRate    `json:"tax_rate"`
+	TaxInclusive bool       `json:"tax_inclusive"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Version      int        `json:"version"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+}
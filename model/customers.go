@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type Customers struct {
+	Id          int        `json:"id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Phone       string     `json:"phone"`
+	TaxId       string     `json:"tax_id"`
+	Address     string     `json:"address"`
+	CreditLimit Money      `json:"credit_limit"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     int        `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
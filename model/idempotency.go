@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// IdempotencyKey menyimpan response pertama dari request POST dengan header
+// Idempotency-Key supaya retry tidak menjalankan ulang request
+type IdempotencyKey struct {
+	Id              int
+	Scope           string
+	Key             string
+	Method          string
+	Path            string
+	RequestHash     string
+	StatusCode      *int
+	ResponseHeaders map[string]string
+	ResponseBody    []byte
+	CreatedAt       time.Time
+	ExpiredAt       time.Time
+}
+
+// Completed bernilai true jika response sudah tersimpan (request pertama selesai)
+func (k IdempotencyKey) Completed() bool {
+	return k.StatusCode != nil
+}
@@ -3,14 +3,22 @@ package model
 import "time"
 
 type Items struct {
-	Id         int       `json:"id"`
-	CategoryId int       `json:"category_id"`
-	RackId     int       `json:"rack_id"`
-	Name       string    `json:"name"`
-	Sku        string    `json:"sku"`
-	Stock      int       `json:"stock"`
-	MinStock   int       `json:"min_stock"`
-	Price      float64   `json:"price"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-}
\ No newline at end of file
+	Id         int    `json:"id"`
+	CategoryId int    `json:"category_id"`
+	RackId     int    `json:"rack_id"`
+	Name       string `json:"name"`
+	Sku        string `json:"sku"`
+	Stock      int    `json:"stock"`
+	// Reserved dipesan sale held / reservasi; Available = Stock - Reserved
+	Reserved  int   `json:"reserved"`
+	Available int   `json:"available"`
+	MinStock  int   `json:"min_stock"`
+	Price     Money `json:"price"`
+	// TaxRate / TaxInclusive nil berarti mengikuti category
+	TaxRate      *TaxRate   `json:"tax_rate"`
+	TaxInclusive *bool      `json:"tax_inclusive"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Version      int        `json:"version"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Money adalah nominal rupiah dengan 2 desimal (sama dengan kolom numeric(15,2)),
+// disimpan sebagai jumlah sen supaya penjumlahan dan perkalian selalu eksak.
+//
+// Di JSON ditulis sebagai angka fixed-point ("price": 50000.25) dan diterima
+// sebagai angka atau string ("50000.25"); lebih dari 2 desimal ditolak.
+// Di Postgres dibaca / ditulis sebagai numeric tanpa lewat float.
+type Money int64
+
+var hundred = big.NewInt(100)
+
+// ParseMoney membaca nominal desimal seperti "1500", "1500.5" atau "-0.25"
+func ParseMoney(s string) (Money, error) {
+	cents, err := parseHundredths(s, "money")
+	return Money(cents), err
+}
+
+// MustParseMoney seperti ParseMoney tetapi panic jika s tidak valid; untuk konstanta dan test
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Cents mengembalikan nominal dalam sen
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+func (m Money) String() string {
+	return formatHundredths(int64(m))
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s, err := unquoteNumber(s)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// ScanNumeric membaca numeric Postgres. Hasil agregat (misal AVG) yang punya
+// lebih dari 2 desimal dibulatkan half-up ke sen.
+func (m *Money) ScanNumeric(n pgtype.Numeric) error {
+	cents, err := scanHundredths(n, "Money")
+	if err != nil {
+		return err
+	}
+	*m = Money(cents)
+	return nil
+}
+
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(m)), Exp: -2, Valid: true}, nil
+}
+
+// DecimalPlacesError dipakai saat angka desimal punya lebih dari 2 desimal
+type DecimalPlacesError struct {
+	Name string
+}
+
+func (e *DecimalPlacesError) Error() string {
+	return e.Name + " must have at most 2 decimal places"
+}
+
+// parseHundredths membaca angka desimal menjadi perseratus (sen / basis point) tanpa lewat float
+func parseHundredths(s, name string) (int64, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid %s %q", name, s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(hundred))
+	if !r.IsInt() {
+		return 0, &DecimalPlacesError{Name: name}
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("%s %q out of range", name, s)
+	}
+	return r.Num().Int64(), nil
+}
+
+func formatHundredths(v int64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(v))
+	units, frac := new(big.Int).QuoRem(abs, hundred, new(big.Int))
+	return fmt.Sprintf("%s%s.%02d", sign, units.String(), frac.Int64())
+}
+
+// unquoteNumber menerima angka JSON maupun string ("50000.25")
+func unquoteNumber(s string) (string, error) {
+	if len(s) > 0 && s[0] == '"' {
+		return strconv.Unquote(s)
+	}
+	return s, nil
+}
+
+// scanHundredths membaca numeric Postgres sebagai perseratus, lebih dari 2
+// desimal dibulatkan half-up
+func scanHundredths(n pgtype.Numeric, name string) (int64, error) {
+	if !n.Valid {
+		return 0, fmt.Errorf("cannot scan NULL into %s", name)
+	}
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return 0, fmt.Errorf("cannot scan non-finite numeric into %s", name)
+	}
+
+	value := new(big.Int).Set(n.Int)
+	exp := int64(n.Exp) + 2
+	if exp >= 0 {
+		value.Mul(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+	} else {
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(-exp), nil)
+		quo, rem := value.QuoRem(value, divisor, new(big.Int))
+		// half-up menjauhi nol, sama seperti round() di Postgres
+		if rem.Abs(rem).Lsh(rem, 1).Cmp(divisor) >= 0 {
+			if n.Int.Sign() < 0 {
+				quo.Sub(quo, big.NewInt(1))
+			} else {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+		value = quo
+	}
+
+	if !value.IsInt64() {
+		return 0, fmt.Errorf("numeric %s out of %s range", value.String(), name)
+	}
+	return value.Int64(), nil
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"0", 0},
+		{"1500", 150000},
+		{"1500.5", 150050},
+		{"1500.50", 150050},
+		{"0.01", 1},
+		{"-0.25", -25},
+		{"1e3", 100000},
+		// 0.1 + 0.2 di float64 bukan 0.3; di sini selalu eksak
+		{"0.30", 30},
+		{"9999999999999.99", 999999999999999},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"", "abc", "1.005", "0.001", "1,5", "99999999999999999999"} {
+		_, err := ParseMoney(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "0.00", Money(0).String())
+	assert.Equal(t, "0.05", Money(5).String())
+	assert.Equal(t, "1500.50", Money(150050).String())
+	assert.Equal(t, "-0.25", Money(-25).String())
+	assert.Equal(t, "-92233720368547758.08", Money(math.MinInt64).String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var body struct {
+		Price Money  `json:"price"`
+		Total *Money `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 50000.25, "total": "100.10"}`), &body))
+	assert.Equal(t, Money(5000025), body.Price)
+	assert.Equal(t, Money(10010), *body.Total)
+
+	out, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price": 50000.25, "total": 100.10}`, string(out))
+	// fixed-point: selalu 2 desimal
+	assert.Contains(t, string(out), `"total":100.10`)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price": 10.125}`), &body))
+	assert.Error(t, json.Unmarshal([]byte(`{"price": "abc"}`), &body))
+	assert.Error(t, json.Unmarshal([]byte(`{"price": true}`), &body))
+}
+
+func TestMoney_ScanNumeric(t *testing.T) {
+	tests := []struct {
+		name string
+		in   pgtype.Numeric
+		want Money
+	}{
+		{"numeric(15,2)", pgtype.Numeric{Int: big.NewInt(10050), Exp: -2, Valid: true}, 10050},
+		{"tanpa desimal", pgtype.Numeric{Int: big.NewInt(15), Exp: 3, Valid: true}, 1500000},
+		// hasil AVG: 33.333... dibulatkan ke 33.33, 0.005 ke 0.01
+		{"agregat dibulatkan ke bawah", pgtype.Numeric{Int: big.NewInt(333333), Exp: -4, Valid: true}, 3333},
+		{"agregat tepat setengah sen", pgtype.Numeric{Int: big.NewInt(5), Exp: -3, Valid: true}, 1},
+		{"negatif menjauhi nol", pgtype.Numeric{Int: big.NewInt(-5), Exp: -3, Valid: true}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			require.NoError(t, m.ScanNumeric(tt.in))
+			assert.Equal(t, tt.want, m)
+		})
+	}
+
+	var m Money
+	assert.Error(t, m.ScanNumeric(pgtype.Numeric{}))
+	assert.Error(t, m.ScanNumeric(pgtype.Numeric{NaN: true, Valid: true}))
+	assert.Error(t, m.ScanNumeric(pgtype.Numeric{Int: big.NewInt(1), Exp: 30, Valid: true}))
+}
+
+// TestMoney_RoundTrip: String, JSON dan numeric tidak pernah mengubah nilai
+func TestMoney_RoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(44))
+	for i := 0; i < 5000; i++ {
+		m := Money(rng.Int63n(2_000_000_000_000) - 1_000_000_000_000)
+
+		parsed, err := ParseMoney(m.String())
+		require.NoError(t, err)
+		assert.Equal(t, m, parsed)
+
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		var decoded Money
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, m, decoded)
+
+		n, err := m.NumericValue()
+		require.NoError(t, err)
+		var scanned Money
+		require.NoError(t, scanned.ScanNumeric(n))
+		assert.Equal(t, m, scanned)
+	}
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// Metode pembayaran (tender)
+const (
+	TenderCash     = "cash"
+	TenderCard     = "card"
+	TenderQRIS     = "qris"
+	TenderTransfer = "transfer"
+)
+
+// Status pembayaran sale
+const (
+	PaymentUnpaid  = "unpaid"
+	PaymentPartial = "partial"
+	PaymentPaid    = "paid"
+)
+
+type Payments struct {
+	Id     int    `json:"id"`
+	SaleId int    `json:"sale_id"`
+	UserId int    `json:"user_id"`
+	Method string `json:"method"`
+	// Amount mengurangi tagihan sale; Tendered = Amount + Change (kembalian hanya untuk cash)
+	Amount    Money     `json:"amount"`
+	Tendered  Money     `json:"tendered"`
+	Change    Money     `json:"change"`
+	Reference string    `json:"reference"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PaymentStatusFor menentukan status pembayaran dari total dan jumlah yang sudah dibayar
+func PaymentStatusFor(total, paid Money) string {
+	switch {
+	case paid >= total:
+		return PaymentPaid
+	case paid > 0:
+		return PaymentPartial
+	}
+	return PaymentUnpaid
+}
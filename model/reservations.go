@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Status reservasi stock: active memesan stock (items.reserved) sampai
+// expires_at, confirmed sudah mengurangi stock, released dilepas sebelum
+// dipakai, expired dilepas oleh sweeper
+const (
+	ReservationActive    = "active"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// StockReservation memesan quantity untuk order dari channel lain (misal toko
+// online) tanpa mengurangi stock
+type StockReservation struct {
+	Id int `json:"id"`
+	// Reference nomor order dari channel penjualan; unik di antara reservasi active
+	Reference string                 `json:"reference"`
+	Status    string                 `json:"status"`
+	ExpiresAt time.Time              `json:"expires_at"`
+	CreatedBy int                    `json:"created_by"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Items     []StockReservationItem `json:"items"`
+}
+
+type StockReservationItem struct {
+	Id            int `json:"id"`
+	ReservationId int `json:"reservation_id"`
+	ItemId        int `json:"item_id"`
+	Quantity      int `json:"quantity"`
+}
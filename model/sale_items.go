@@ -1,10 +1,18 @@
 package model
 
 type SaleItems struct {
-	Id       int     `json:"id"`
-	SaleId   int     `json:"sale_id"`
-	ItemId   int     `json:"item_id"`
-	Quantity int     `json:"quantity"`
-	Price    float64 `json:"price"`
-	Subtotal float64 `json:"subtotal"`
-}
\ No newline at end of file
+	Id       int   `json:"id"`
+	SaleId   int   `json:"sale_id"`
+	ItemId   int   `json:"item_id"`
+	Quantity int   `json:"quantity"`
+	Price    Money `json:"price"`
+	// Subtotal = quantity x price (sebelum diskon)
+	Subtotal Money `json:"subtotal"`
+	// DiscountAmount = diskon baris + bagian diskon order untuk baris ini
+	DiscountAmount Money   `json:"discount_amount"`
+	TaxRate        TaxRate `json:"tax_rate"`
+	TaxInclusive   bool    `json:"tax_inclusive"`
+	TaxAmount      Money   `json:"tax_amount"`
+	// Total = nilai akhir baris setelah diskon, termasuk pajak
+	Total Money `json:"total"`
+}
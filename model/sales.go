@@ -2,10 +2,48 @@ package model
 
 import "time"
 
+// Jenis pembayaran sale; penjualan kredit menambah piutang customer
+const (
+	PaymentCash   = "cash"
+	PaymentCredit = "credit"
+)
+
+// Status sale: draft (keranjang parkir) dan quote (penawaran) belum menyentuh
+// stock, held memesan stock sampai hold_expires_at, completed sudah mengurangi
+// stock, voided dibatalkan (stock dikembalikan, data tidak bisa diubah lagi)
+const (
+	SaleDraft     = "draft"
+	SaleQuote     = "quote"
+	SaleHeld      = "held"
+	SaleCompleted = "completed"
+	SaleVoided    = "voided"
+)
+
+// Jenis diskon baris / order
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
 type Sales struct {
-	Id          int         `json:"id"`
-	UserId      int         `json:"user_id"`
-	TotalAmount float64     `json:"total_amount"`
-	Items       []SaleItems `json:"items"`
-	CreatedAt   time.Time   `json:"created_at"`
+	Id          int    `json:"id"`
+	InvoiceNo   string `json:"invoice_no"`
+	UserId      int    `json:"user_id"`
+	WarehouseId *int   `json:"warehouse_id,omitempty"`
+	CustomerId  *int   `json:"customer_id,omitempty"`
+	PaymentType string `json:"payment_type"`
+	// Subtotal - DiscountAmount + pajak exclusive = TotalAmount (grand total)
+	Subtotal       Money       `json:"subtotal"`
+	DiscountAmount Money       `json:"discount_amount"`
+	TaxAmount      Money       `json:"tax_amount"`
+	TotalAmount    Money       `json:"total_amount"`
+	PaidAmount     Money       `json:"paid_amount"`
+	PaymentStatus  string      `json:"payment_status"`
+	Status         string      `json:"status"`
+	VoidedAt       *time.Time  `json:"voided_at,omitempty"`
+	VoidedBy       *int        `json:"voided_by,omitempty"`
+	VoidReason     string      `json:"void_reason,omitempty"`
+	HoldExpiresAt  *time.Time  `json:"hold_expires_at,omitempty"`
+	Items          []SaleItems `json:"items,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
 }
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TaxRate adalah tarif pajak dalam persen dengan 2 desimal (kolom numeric(5,2)),
+// disimpan sebagai basis point (PPN 11% -> 1100) supaya perhitungan pajak
+// tidak pernah lewat float.
+//
+// Di JSON ditulis sebagai angka fixed-point ("tax_rate": 11.00) dan diterima
+// sebagai angka atau string; lebih dari 2 desimal ditolak (*DecimalPlacesError).
+type TaxRate int64
+
+// MaxTaxRate adalah 100% dalam basis point
+const MaxTaxRate TaxRate = 10000
+
+// ParseTaxRate membaca persen desimal seperti "11", "11.5" atau "0.25"
+func ParseTaxRate(s string) (TaxRate, error) {
+	bps, err := parseHundredths(s, "tax_rate")
+	return TaxRate(bps), err
+}
+
+// MustParseTaxRate seperti ParseTaxRate tetapi panic jika s tidak valid; untuk konstanta dan test
+func MustParseTaxRate(s string) TaxRate {
+	r, err := ParseTaxRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// BasisPoints mengembalikan tarif dalam basis point (1% = 100)
+func (r TaxRate) BasisPoints() int64 {
+	return int64(r)
+}
+
+// Valid melaporkan apakah tarif berada di 0-100%
+func (r TaxRate) Valid() bool {
+	return r >= 0 && r <= MaxTaxRate
+}
+
+func (r TaxRate) String() string {
+	return formatHundredths(int64(r))
+}
+
+func (r TaxRate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *TaxRate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s, err := unquoteNumber(s)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseTaxRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+func (r *TaxRate) ScanNumeric(n pgtype.Numeric) error {
+	bps, err := scanHundredths(n, "TaxRate")
+	if err != nil {
+		return err
+	}
+	*r = TaxRate(bps)
+	return nil
+}
+
+func (r TaxRate) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(r)), Exp: -2, Valid: true}, nil
+}
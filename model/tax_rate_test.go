@@ -0,0 +1,71 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaxRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want TaxRate
+	}{
+		{"0", 0},
+		{"11", 1100},
+		{"11.5", 1150},
+		{"12.25", 1225},
+		{"0.01", 1},
+		{"100", MaxTaxRate},
+	}
+	for _, tt := range tests {
+		got, err := ParseTaxRate(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := ParseTaxRate("11.005")
+	var places *DecimalPlacesError
+	require.True(t, errors.As(err, &places))
+	assert.Equal(t, "tax_rate", places.Name)
+
+	assert.True(t, TaxRate(1100).Valid())
+	assert.False(t, TaxRate(-1).Valid())
+	assert.False(t, TaxRate(10001).Valid())
+}
+
+func TestTaxRate_JSON(t *testing.T) {
+	var body struct {
+		TaxRate  TaxRate  `json:"tax_rate"`
+		Override *TaxRate `json:"override"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"tax_rate": 11, "override": "12.5"}`), &body))
+	assert.Equal(t, TaxRate(1100), body.TaxRate)
+	assert.Equal(t, TaxRate(1250), *body.Override)
+
+	out, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"tax_rate":11.00`)
+	assert.Contains(t, string(out), `"override":12.50`)
+
+	err = json.Unmarshal([]byte(`{"tax_rate": 11.005}`), &body)
+	var places *DecimalPlacesError
+	assert.True(t, errors.As(err, &places))
+}
+
+func TestTaxRate_Numeric(t *testing.T) {
+	var r TaxRate
+	// numeric(5,2) 11.00 dari pgx
+	require.NoError(t, r.ScanNumeric(pgtype.Numeric{Int: big.NewInt(1100), Exp: -2, Valid: true}))
+	assert.Equal(t, TaxRate(1100), r)
+
+	n, err := r.NumericValue()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1100), n.Int.Int64())
+	assert.Equal(t, int32(-2), n.Exp)
+}
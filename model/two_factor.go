@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// UserTOTP adalah secret TOTP milik user. Secret tidak pernah dikirim ke client
+// kecuali satu kali saat enrollment.
+type UserTOTP struct {
+	UserId       int        `json:"user_id"`
+	Secret       string     `json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep int64      `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Enabled bernilai true setelah enrollment dikonfirmasi dengan kode pertama
+func (t UserTOTP) Enabled() bool {
+	return t.EnabledAt != nil
+}
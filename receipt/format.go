@@ -0,0 +1,135 @@
+// Package receipt merender dto.Receipt menjadi struk thermal (plain text) dan
+// invoice PDF. Output deterministik (tanpa timestamp cetak) supaya bisa diuji
+// dengan golden file.
+package receipt
+
+import (
+	"fmt"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"strings"
+)
+
+const dateLayout = "02/01/2006 15:04"
+
+// formatMoney memakai format Indonesia: 1.234.567,89
+func formatMoney(m model.Money) string {
+	s := m.String()
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String() + "," + frac
+}
+
+// DocumentNo adalah nomor invoice, atau nomor sementara untuk sale yang belum
+// completed (nomor invoice baru diberikan saat completed)
+func DocumentNo(r *dto.Receipt) string {
+	if r.InvoiceNo != "" {
+		return r.InvoiceNo
+	}
+	return fmt.Sprintf("SALE-%d", r.SaleId)
+}
+
+// documentTitle: sale yang belum completed tidak dicetak sebagai invoice
+func documentTitle(status string) string {
+	switch status {
+	case model.SaleQuote:
+		return "PENAWARAN"
+	case model.SaleDraft:
+		return "DRAFT"
+	case model.SaleHeld:
+		return "HOLD"
+	}
+	return "INVOICE"
+}
+
+func tenderLabel(method string) string {
+	switch method {
+	case model.TenderCash:
+		return "Tunai"
+	case model.TenderCard:
+		return "Kartu"
+	case model.TenderQRIS:
+		return "QRIS"
+	case model.TenderTransfer:
+		return "Transfer"
+	}
+	return method
+}
+
+func statusLabel(status string) string {
+	switch status {
+	case model.PaymentPaid:
+		return "LUNAS"
+	case model.PaymentPartial:
+		return "DIBAYAR SEBAGIAN"
+	}
+	return "BELUM DIBAYAR"
+}
+
+// taxSplit memisahkan pajak exclusive (ditambahkan ke total) dan inclusive
+// (sudah termasuk di harga) supaya rincian total tetap bisa dijumlahkan
+func taxSplit(r *dto.Receipt) (exclusive, inclusive model.Money) {
+	for _, line := range r.Lines {
+		if line.TaxInclusive {
+			inclusive += line.TaxAmount
+		} else {
+			exclusive += line.TaxAmount
+		}
+	}
+	return exclusive, inclusive
+}
+
+// ascii mengganti karakter non-ASCII dengan '?': printer ESC/POS dan font
+// standar PDF (WinAnsi) tidak menjamin karakter di luar ASCII
+func ascii(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' {
+			return ' '
+		}
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+// wrap memotong s per kata menjadi baris maksimal width karakter; kata yang
+// lebih panjang dari width dipotong paksa
+func wrap(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for len(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, word[:width])
+			word = word[width:]
+		}
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,322 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"strconv"
+	"strings"
+)
+
+// Ukuran A4 dalam point dan tata letak halaman invoice
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	marginLeft   = 40
+	marginRight  = 555
+	marginBottom = 60
+	rowHeight    = 14
+	fontSize     = 9
+)
+
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// kolom tabel item: nama di kiri, angka rata kanan pada posisi x berikut
+var pdfColumns = []struct {
+	title string
+	right float64
+}{
+	{"Qty", 290},
+	{"Harga", 365},
+	{"Diskon", 430},
+	{"PPN", 490},
+	{"Total", marginRight},
+}
+
+// PDF merender invoice A4 dengan font standar Helvetica (tanpa embed font dan
+// tanpa kompresi), jadi tidak butuh library eksternal. Item yang tidak muat
+// di satu halaman dilanjutkan di halaman berikutnya.
+func PDF(r *dto.Receipt) []byte {
+	doc := &pdfDoc{}
+	page := doc.addPage()
+	y := float64(pageHeight - 50)
+
+	// header toko di kiri, judul dan nomor invoice di kanan
+	page.text(fontBold, 16, marginLeft, y, r.StoreName)
+	page.textRight(fontBold, 18, marginRight, y, documentTitle(r.Status))
+	y -= 16
+	for _, header := range []string{r.StoreAddress, r.StorePhone, warehouseLabel(r)} {
+		if header != "" {
+			page.text(fontRegular, fontSize, marginLeft, y, header)
+			y -= 12
+		}
+	}
+
+	infoY := float64(pageHeight - 70)
+	for _, field := range [][2]string{
+		{"No", DocumentNo(r)},
+		{"Tanggal", r.CreatedAt.Format(dateLayout)},
+		{"Kasir", r.Cashier},
+		{"Customer", r.Customer},
+		{"Status", statusLabel(r.PaymentStatus)},
+		{"Hold s/d", holdLabel(r)},
+	} {
+		if field[1] == "" {
+			continue
+		}
+		page.text(fontRegular, fontSize, 360, infoY, field[0])
+		page.textRight(fontRegular, fontSize, marginRight, infoY, field[1])
+		infoY -= 12
+	}
+
+	y = min(y, infoY) - 16
+	if r.Status == model.SaleVoided {
+		page.text(fontBold, 12, marginLeft, y, "VOID")
+		page.text(fontRegular, fontSize, marginLeft+40, y, "Alasan: "+r.VoidReason)
+		y -= 20
+	}
+	y = tableHeader(page, y)
+
+	for i, line := range r.Lines {
+		names := wrap(ascii(line.Name), 44)
+		if y-float64(len(names)-1)*rowHeight < marginBottom {
+			page = doc.addPage()
+			y = tableHeader(page, pageHeight-50)
+		}
+		page.text(fontRegular, fontSize, marginLeft, y, fmt.Sprintf("%d. %s", i+1, names[0]))
+		values := []string{
+			strconv.Itoa(line.Quantity),
+			formatMoney(line.Price),
+			formatMoney(line.DiscountAmount),
+			taxLabel(line),
+			formatMoney(line.Total),
+		}
+		for c, value := range values {
+			page.textRight(fontRegular, fontSize, pdfColumns[c].right, y, value)
+		}
+		for _, name := range names[1:] {
+			y -= rowHeight - 3
+			page.text(fontRegular, fontSize, marginLeft+12, y, name)
+		}
+		y -= rowHeight
+	}
+	page.line(marginLeft, y+rowHeight-4, marginRight, y+rowHeight-4)
+
+	// ringkasan total dan pembayaran butuh sekitar 12 baris
+	if y-12*rowHeight < marginBottom {
+		page = doc.addPage()
+		y = pageHeight - 50
+	}
+	y -= 4
+
+	exclusive, inclusive := taxSplit(r)
+	totals := [][2]string{{"Subtotal", formatMoney(r.Subtotal)}}
+	if r.DiscountAmount > 0 {
+		totals = append(totals, [2]string{"Diskon", "-" + formatMoney(r.DiscountAmount)})
+	}
+	if exclusive > 0 {
+		totals = append(totals, [2]string{"PPN", formatMoney(exclusive)})
+	}
+	totalsY := y
+	for _, total := range totals {
+		page.text(fontRegular, fontSize, 380, totalsY, total[0])
+		page.textRight(fontRegular, fontSize, marginRight, totalsY, total[1])
+		totalsY -= rowHeight
+	}
+	page.text(fontBold, 11, 380, totalsY, "TOTAL")
+	page.textRight(fontBold, 11, marginRight, totalsY, formatMoney(r.TotalAmount))
+	totalsY -= rowHeight
+	if inclusive > 0 {
+		page.text(fontRegular, fontSize, 380, totalsY, "Termasuk PPN")
+		page.textRight(fontRegular, fontSize, marginRight, totalsY, formatMoney(inclusive))
+		totalsY -= rowHeight
+	}
+
+	paymentsY := y
+	page.text(fontBold, fontSize, marginLeft, paymentsY, "Pembayaran")
+	paymentsY -= rowHeight
+	for _, p := range r.Payments {
+		label := tenderLabel(p.Method)
+		if p.Reference != "" {
+			label += " (" + p.Reference + ")"
+		}
+		page.text(fontRegular, fontSize, marginLeft, paymentsY, label)
+		page.textRight(fontRegular, fontSize, 300, paymentsY, formatMoney(p.Tendered))
+		paymentsY -= rowHeight
+	}
+	summary := [][2]string{{"Dibayar", formatMoney(r.PaidAmount)}}
+	if r.Change > 0 {
+		summary = append(summary, [2]string{"Kembalian", formatMoney(r.Change)})
+	}
+	if r.BalanceDue > 0 {
+		if r.PaymentType == model.PaymentCredit {
+			summary = append(summary, [2]string{"Bayar", "Kredit"})
+		}
+		summary = append(summary, [2]string{"Sisa Tagihan", formatMoney(r.BalanceDue)})
+	}
+	for _, s := range summary {
+		page.text(fontRegular, fontSize, marginLeft, paymentsY, s[0])
+		page.textRight(fontRegular, fontSize, 300, paymentsY, s[1])
+		paymentsY -= rowHeight
+	}
+
+	if r.Footer != "" {
+		y = min(totalsY, paymentsY) - rowHeight
+		page.textCenter(fontRegular, fontSize, y, r.Footer)
+	}
+
+	// nomor halaman baru diketahui setelah semua halaman dibuat
+	for i, p := range doc.pages {
+		p.textRight(fontRegular, 8, marginRight, 30, fmt.Sprintf("%s - Halaman %d/%d", DocumentNo(r), i+1, len(doc.pages)))
+	}
+
+	return doc.bytes(DocumentNo(r))
+}
+
+func holdLabel(r *dto.Receipt) string {
+	if r.HoldExpiresAt == nil {
+		return ""
+	}
+	return r.HoldExpiresAt.Format(dateLayout)
+}
+
+func tableHeader(page *pdfPage, y float64) float64 {
+	page.text(fontBold, fontSize, marginLeft, y, "Item")
+	for _, column := range pdfColumns {
+		page.textRight(fontBold, fontSize, column.right, y, column.title)
+	}
+	page.line(marginLeft, y-5, marginRight, y-5)
+	return y - rowHeight - 4
+}
+
+func warehouseLabel(r *dto.Receipt) string {
+	if r.Warehouse == "" || r.WarehouseLocation == "" {
+		return r.Warehouse
+	}
+	return r.Warehouse + " - " + r.WarehouseLocation
+}
+
+// taxLabel: nominal pajak baris, ditandai "(inc)" jika sudah termasuk harga
+func taxLabel(line dto.ReceiptLine) string {
+	if line.TaxAmount == 0 {
+		return "-"
+	}
+	if line.TaxInclusive {
+		return formatMoney(line.TaxAmount) + " (inc)"
+	}
+	return formatMoney(line.TaxAmount)
+}
+
+type pdfDoc struct {
+	pages []*pdfPage
+}
+
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (d *pdfDoc) addPage() *pdfPage {
+	page := &pdfPage{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+func (p *pdfPage) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, pdfNum(size), pdfNum(x), pdfNum(y), pdfEscape(s))
+}
+
+func (p *pdfPage) textRight(font string, size, right, y float64, s string) {
+	p.text(font, size, right-textWidth(font, size, s), y, s)
+}
+
+func (p *pdfPage) textCenter(font string, size, y float64, s string) {
+	p.text(font, size, (pageWidth-textWidth(font, size, s))/2, y, s)
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %s %s m %s %s l S\n", pdfNum(x1), pdfNum(y1), pdfNum(x2), pdfNum(y2))
+}
+
+// bytes menyusun file PDF: catalog, pages, font, lalu page + content stream
+// per halaman, diakhiri tabel xref dengan offset byte tiap object
+func (d *pdfDoc) bytes(title string) []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	const firstPage = 6 // 1 catalog, 2 pages, 3-4 font, 5 info
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) >>", pdfEscape(title)))
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// pdfNum: koordinat dibulatkan 3 desimal supaya output stabil
+func pdfNum(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(ascii(s))
+}
+
+// textWidth menghitung lebar teks dalam point dari metrik AFM Helvetica
+// (per 1000 unit em, karakter ASCII 32-126)
+func textWidth(font string, size float64, s string) float64 {
+	widths := helveticaWidths
+	if font == fontBold {
+		widths = helveticaBoldWidths
+	}
+	units := 0
+	for _, c := range ascii(s) {
+		units += widths[c-32]
+	}
+	return float64(units) * size / 1000
+}
+
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // ' ' - '/'
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // '0' - '?'
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // '@' - 'O'
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // 'P' - '_'
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // '`' - 'o'
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // 'p' - '~'
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278, // ' ' - '/'
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611, // '0' - '?'
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778, // '@' - 'O'
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556, // 'P' - '_'
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611, // '`' - 'o'
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584, // 'p' - '~'
+}
@@ -0,0 +1,232 @@
+package receipt
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./receipt -update untuk menulis ulang golden file
+var update = flag.Bool("update", false, "update golden files")
+
+func sampleReceipt() *dto.Receipt {
+	return &dto.Receipt{
+		StoreName:         "Toko Azwin",
+		StoreAddress:      "Jl. Merdeka No. 10, Bandung",
+		StorePhone:        "022-123456",
+		Warehouse:         "Gudang Utama",
+		WarehouseLocation: "Bandung",
+		Footer:            "Terima kasih atas kunjungan Anda",
+
+		SaleId:      12,
+		InvoiceNo:   "INV/2026/10/000123",
+		CreatedAt:   time.Date(2026, 10, 19, 14, 5, 0, 0, time.UTC),
+		Cashier:     "Budi Santoso",
+		Customer:    "PT Maju Jaya",
+		PaymentType: model.PaymentCash,
+
+		Lines: []dto.ReceiptLine{
+			{
+				Name: "Kopi Arabika Gayo Premium (Biji Sangrai) 1kg Kemasan Vakum", Quantity: 2,
+				Price: model.MustParseMoney("150000"), DiscountAmount: model.MustParseMoney("15000"),
+				TaxRate: model.MustParseTaxRate("11"), TaxAmount: model.MustParseMoney("31350"), Total: model.MustParseMoney("316350"),
+			},
+			{
+				Name: "Gula Aren Cair", Quantity: 3, Price: model.MustParseMoney("22200"),
+				TaxRate: model.MustParseTaxRate("11"), TaxInclusive: true, TaxAmount: model.MustParseMoney("6600"), Total: model.MustParseMoney("66600"),
+			},
+			{
+				Name: "Café Latte (Sachet)", Quantity: 1, Price: model.MustParseMoney("4500.50"),
+				Total: model.MustParseMoney("4500.50"),
+			},
+		},
+		Subtotal:       model.MustParseMoney("370500.50"),
+		DiscountAmount: model.MustParseMoney("15000"),
+		TaxAmount:      model.MustParseMoney("37950"),
+		TotalAmount:    model.MustParseMoney("387450.50"),
+
+		PaymentStatus: model.PaymentPaid,
+		Payments: []model.Payments{
+			{Method: model.TenderQRIS, Amount: model.MustParseMoney("300000"), Tendered: model.MustParseMoney("300000"), Reference: "QR-20261019-0001"},
+			{Method: model.TenderCash, Amount: model.MustParseMoney("87450.50"), Tendered: model.MustParseMoney("100000"), Change: model.MustParseMoney("12549.50")},
+		},
+		PaidAmount: model.MustParseMoney("387450.50"),
+		Change:     model.MustParseMoney("12549.50"),
+	}
+}
+
+func creditReceipt() *dto.Receipt {
+	r := sampleReceipt()
+	r.PaymentType = model.PaymentCredit
+	r.PaymentStatus = model.PaymentPartial
+	r.Payments = r.Payments[:1]
+	r.PaidAmount = model.MustParseMoney("300000")
+	r.BalanceDue = model.MustParseMoney("87450.50")
+	r.Change = 0
+	return r
+}
+
+func voidReceipt() *dto.Receipt {
+	r := sampleReceipt()
+	r.Status = model.SaleVoided
+	r.VoidReason = "Salah input item, diganti dengan INV/2026/10/000124"
+	return r
+}
+
+// heldReceipt: sale held belum punya nomor invoice dan pembayaran
+func heldReceipt() *dto.Receipt {
+	r := sampleReceipt()
+	expires := time.Date(2026, 10, 19, 14, 35, 0, 0, time.UTC)
+	r.Status = model.SaleHeld
+	r.InvoiceNo = ""
+	r.HoldExpiresAt = &expires
+	r.PaymentStatus = model.PaymentUnpaid
+	r.Payments = nil
+	r.PaidAmount = 0
+	r.BalanceDue = 0
+	r.Change = 0
+	return r
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestText_Golden(t *testing.T) {
+	tests := []struct {
+		golden  string
+		receipt *dto.Receipt
+		width   int
+	}{
+		{"receipt_42.txt", sampleReceipt(), 42},
+		{"receipt_32.txt", sampleReceipt(), 32},
+		{"receipt_credit_42.txt", creditReceipt(), 42},
+		{"receipt_void_32.txt", voidReceipt(), 32},
+		{"receipt_held_32.txt", heldReceipt(), 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got := Text(tt.receipt, tt.width)
+			assertGolden(t, tt.golden, got)
+
+			for _, line := range strings.Split(strings.TrimSuffix(string(got), "\n"), "\n") {
+				assert.LessOrEqual(t, len(line), tt.width, line)
+			}
+			for _, c := range got {
+				assert.True(t, c == '\n' || (c >= 0x20 && c <= 0x7e), "non printable byte %q", c)
+			}
+		})
+	}
+}
+
+func TestText_WidthClamped(t *testing.T) {
+	got := Text(sampleReceipt(), 5)
+	for _, line := range strings.Split(strings.TrimSuffix(string(got), "\n"), "\n") {
+		assert.LessOrEqual(t, len(line), MinWidth, line)
+	}
+}
+
+func TestPDF_Golden(t *testing.T) {
+	got := PDF(sampleReceipt())
+	assertGolden(t, "invoice.pdf", got)
+	assertValidPDF(t, got, 1)
+}
+
+func TestPDF_Void(t *testing.T) {
+	got := PDF(voidReceipt())
+	assertValidPDF(t, got, 1)
+	assert.True(t, bytes.Contains(got, []byte("(VOID)")))
+}
+
+func TestPDF_Quote(t *testing.T) {
+	r := heldReceipt()
+	r.Status = model.SaleQuote
+	r.HoldExpiresAt = nil
+
+	got := PDF(r)
+	assertValidPDF(t, got, 1)
+	assert.True(t, bytes.Contains(got, []byte("(PENAWARAN)")))
+	assert.True(t, bytes.Contains(got, []byte("(SALE-12)")))
+	assert.False(t, bytes.Contains(got, []byte("(INVOICE)")))
+}
+
+func TestPDF_MultiPage(t *testing.T) {
+	r := sampleReceipt()
+	for i := 0; i < 120; i++ {
+		r.Lines = append(r.Lines, dto.ReceiptLine{
+			Name: fmt.Sprintf("Barang %d", i), Quantity: 1,
+			Price: model.MustParseMoney("1000"), Total: model.MustParseMoney("1000"),
+		})
+	}
+
+	got := PDF(r)
+	assertValidPDF(t, got, 3)
+	assert.True(t, bytes.Contains(got, []byte("Halaman 3/3")))
+	assert.True(t, bytes.Contains(got, []byte(". Barang 119)")))
+}
+
+// assertValidPDF memeriksa struktur minimal: offset xref menunjuk ke object
+// yang benar, startxref menunjuk ke tabel xref, dan jumlah halaman
+func assertValidPDF(t *testing.T, pdf []byte, pages int) {
+	t.Helper()
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	require.NotNil(t, m)
+	xref, _ := strconv.Atoi(string(m[1]))
+	require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	require.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(pdf[offset:], fmt.Appendf(nil, "%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+
+	for _, stream := range regexp.MustCompile(`(?s)/Length (\d+) >>\nstream\n(.*?)endstream`).FindAllSubmatch(pdf, -1) {
+		length, _ := strconv.Atoi(string(stream[1]))
+		assert.Equal(t, length, len(stream[2]))
+	}
+	assert.True(t, bytes.Contains(pdf, fmt.Appendf(nil, "/Count %d >>", pages)), "expected %d pages", pages)
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := map[string]string{
+		"0":          "0,00",
+		"999":        "999,00",
+		"1000":       "1.000,00",
+		"1234567.89": "1.234.567,89",
+		"-15000.5":   "-15.000,50",
+		"100000000":  "100.000.000,00",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, formatMoney(model.MustParseMoney(in)), in)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"Kopi Arabika", "Gayo"}, wrap("Kopi Arabika Gayo", 12))
+	assert.Equal(t, []string{"abcde", "fghij", "k"}, wrap("abcdefghijk", 5))
+	assert.Equal(t, []string{""}, wrap("", 10))
+}
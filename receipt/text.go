@@ -0,0 +1,150 @@
+package receipt
+
+import (
+	"fmt"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"strings"
+)
+
+// MinWidth dan MaxWidth membatasi lebar struk (karakter per baris)
+const (
+	MinWidth = 24
+	MaxWidth = 80
+)
+
+// Text merender struk untuk printer thermal: ASCII saja, setiap baris
+// maksimal width karakter, diakhiri LF. Aman dikirim apa adanya ke printer
+// ESC/POS (mode teks) tanpa perintah khusus.
+func Text(r *dto.Receipt, width int) []byte {
+	width = min(max(width, MinWidth), MaxWidth)
+	t := &textReceipt{width: width}
+
+	for _, header := range []string{r.StoreName, r.StoreAddress, r.StorePhone} {
+		t.center(header)
+	}
+	if r.Warehouse != "" {
+		warehouse := r.Warehouse
+		if r.WarehouseLocation != "" {
+			warehouse += " - " + r.WarehouseLocation
+		}
+		t.center(warehouse)
+	}
+	t.rule()
+
+	t.field("No", DocumentNo(r))
+	t.field("Tanggal", r.CreatedAt.Format(dateLayout))
+	t.field("Kasir", r.Cashier)
+	t.field("Customer", r.Customer)
+	if r.Status == model.SaleVoided {
+		t.rule()
+		t.center("*** VOID ***")
+		t.field("Alasan", r.VoidReason)
+	}
+	if title := documentTitle(r.Status); title != "INVOICE" {
+		t.rule()
+		t.center("*** " + title + " ***")
+		if r.HoldExpiresAt != nil {
+			t.field("Hold s/d", r.HoldExpiresAt.Format(dateLayout))
+		}
+	}
+	t.rule()
+
+	for _, line := range r.Lines {
+		for _, name := range wrap(ascii(line.Name), width) {
+			t.line(name)
+		}
+		t.pair(fmt.Sprintf("  %d x %s", line.Quantity, formatMoney(line.Price)), formatMoney(model.Money(line.Quantity)*line.Price))
+		if line.DiscountAmount > 0 {
+			t.pair("  Diskon", "-"+formatMoney(line.DiscountAmount))
+		}
+	}
+	t.rule()
+
+	exclusive, inclusive := taxSplit(r)
+	t.pair("Subtotal", formatMoney(r.Subtotal))
+	if r.DiscountAmount > 0 {
+		t.pair("Diskon", "-"+formatMoney(r.DiscountAmount))
+	}
+	if exclusive > 0 {
+		t.pair("PPN", formatMoney(exclusive))
+	}
+	t.pair("TOTAL", formatMoney(r.TotalAmount))
+	if inclusive > 0 {
+		t.pair("  Termasuk PPN", formatMoney(inclusive))
+	}
+	t.rule()
+
+	for _, p := range r.Payments {
+		t.pair(tenderLabel(p.Method), formatMoney(p.Tendered))
+		if p.Reference != "" {
+			for _, ref := range wrap(ascii("Ref: "+p.Reference), width-2) {
+				t.line("  " + ref)
+			}
+		}
+	}
+	if r.Change > 0 {
+		t.pair("Kembalian", formatMoney(r.Change))
+	}
+	if r.BalanceDue > 0 {
+		if r.PaymentType == model.PaymentCredit {
+			t.field("Bayar", "Kredit")
+		}
+		t.pair("Sisa Tagihan", formatMoney(r.BalanceDue))
+	}
+	t.field("Status", statusLabel(r.PaymentStatus))
+
+	if r.Footer != "" {
+		t.rule()
+		t.center(r.Footer)
+	}
+	return []byte(t.b.String())
+}
+
+type textReceipt struct {
+	b     strings.Builder
+	width int
+}
+
+func (t *textReceipt) line(s string) {
+	t.b.WriteString(strings.TrimRight(s, " "))
+	t.b.WriteByte('\n')
+}
+
+func (t *textReceipt) rule() {
+	t.line(strings.Repeat("-", t.width))
+}
+
+func (t *textReceipt) center(s string) {
+	if s == "" {
+		return
+	}
+	for _, part := range wrap(ascii(s), t.width) {
+		t.line(strings.Repeat(" ", (t.width-len(part))/2) + part)
+	}
+}
+
+// field: "Label   : value", value panjang dilanjutkan di baris berikutnya
+func (t *textReceipt) field(label, value string) {
+	if value == "" {
+		return
+	}
+	prefix := fmt.Sprintf("%-8s: ", label)
+	for i, part := range wrap(ascii(value), t.width-len(prefix)) {
+		if i > 0 {
+			prefix = strings.Repeat(" ", len(prefix))
+		}
+		t.line(prefix + part)
+	}
+}
+
+// pair menulis label di kiri dan nilai rata kanan; jika tidak muat, nilai
+// pindah ke baris sendiri
+func (t *textReceipt) pair(left, right string) {
+	left, right = ascii(left), ascii(right)
+	if len(left)+1+len(right) > t.width {
+		t.line(left)
+		left = ""
+	}
+	t.line(left + strings.Repeat(" ", max(t.width-len(left)-len(right), 0)) + right)
+}
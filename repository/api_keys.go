@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type APIKeysRepository interface {
+	CreateAPIKey(ctx context.Context, key *model.APIKey, keyHash string) error
+	GetAPIKeyById(ctx context.Context, id int) (*model.APIKey, error)
+	GetAllAPIKeys(ctx context.Context, userId *int, page, limit int) ([]model.APIKey, int, error)
+	UpdateAPIKey(ctx context.Context, key *model.APIKey) error
+	RevokeAPIKey(ctx context.Context, id int) error
+	RevokeUserAPIKeys(ctx context.Context, userId int) (int64, error)
+	GetActiveAPIKey(ctx context.Context, keyHash string) (*model.APIKey, *model.Users, error)
+	TouchAPIKey(ctx context.Context, id int) error
+}
+
+type apiKeysRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewAPIKeysRepository(db database.PgxIface, log *zap.Logger) APIKeysRepository {
+	return &apiKeysRepository{db: db, Logger: log}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, role, scopes, expired_at, last_used_at, revoked_at, created_at, updated_at`
+
+func scanAPIKey(row pgx.Row, extra ...any) (*model.APIKey, error) {
+	var k model.APIKey
+	dest := []any{&k.Id, &k.UserId, &k.Name, &k.Prefix, &k.Role, &k.Scopes, &k.ExpiredAt, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt, &k.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *apiKeysRepository) CreateAPIKey(ctx context.Context, key *model.APIKey, keyHash string) error {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, role, scopes, expired_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	err := conn(ctx, r.db).QueryRow(ctx, query, key.UserId, key.Name, key.Prefix, keyHash, key.Role, key.Scopes, key.ExpiredAt).
+		Scan(&key.Id, &key.CreatedAt, &key.UpdatedAt)
+	if err != nil {
+		return apperror.FromDB(err, "api_key")
+	}
+	return nil
+}
+
+func (r *apiKeysRepository) GetAPIKeyById(ctx context.Context, id int) (*model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1`
+
+	key, err := scanAPIKey(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, apperror.FromDB(err, "api_key")
+	}
+	return key, nil
+}
+
+// GetAllAPIKeys mengembalikan key milik userId, atau semua key jika userId nil
+func (r *apiKeysRepository) GetAllAPIKeys(ctx context.Context, userId *int, page, limit int) ([]model.APIKey, int, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	offset := (page - 1) * limit
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM api_keys WHERE ($1::int IS NULL OR user_id = $1)`
+	if err := conn(ctx, r.db).QueryRow(ctx, countQuery, userId).Scan(&total); err != nil {
+		log.Error("error query count api keys", zap.Error(err))
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE ($1::int IS NULL OR user_id = $1)
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, userId, limit, offset)
+	if err != nil {
+		log.Error("error query api keys", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	keys := []model.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			log.Error("error scan api keys", zap.Error(err))
+			return nil, 0, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, total, rows.Err()
+}
+
+// UpdateAPIKey mengubah nama, scope dan expiry. Key yang sudah dicabut tidak bisa diubah.
+func (r *apiKeysRepository) UpdateAPIKey(ctx context.Context, key *model.APIKey) error {
+	query := `
+		UPDATE api_keys
+		SET name = $1, scopes = $2, expired_at = $3, updated_at = NOW()
+		WHERE id = $4 AND revoked_at IS NULL
+		RETURNING updated_at
+	`
+	err := conn(ctx, r.db).QueryRow(ctx, query, key.Name, key.Scopes, key.ExpiredAt, key.Id).Scan(&key.UpdatedAt)
+	if err != nil {
+		return apperror.FromDB(err, "api_key")
+	}
+	return nil
+}
+
+func (r *apiKeysRepository) RevokeAPIKey(ctx context.Context, id int) error {
+	query := `UPDATE api_keys SET revoked_at = NOW(), updated_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return apperror.FromDB(err, "api_key")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("api_key")
+	}
+	return nil
+}
+
+// RevokeUserAPIKeys mencabut semua key aktif milik user (misal setelah ganti password)
+func (r *apiKeysRepository) RevokeUserAPIKeys(ctx context.Context, userId int) (int64, error) {
+	query := `UPDATE api_keys SET revoked_at = NOW(), updated_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userId)
+	if err != nil {
+		return 0, apperror.FromDB(err, "api_key")
+	}
+	return result.RowsAffected(), nil
+}
+
+// GetActiveAPIKey mencari key yang belum dicabut dan belum kedaluwarsa beserta
+// pemiliknya (yang belum dihapus)
+func (r *apiKeysRepository) GetActiveAPIKey(ctx context.Context, keyHash string) (*model.APIKey, *model.Users, error) {
+	query := `
+		SELECT k.id, k.user_id, k.name, k.prefix, k.role, k.scopes, k.expired_at, k.last_used_at, k.revoked_at, k.created_at, k.updated_at,
+		       u.id, u.username, u.email, u.role
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = $1
+		  AND k.revoked_at IS NULL
+		  AND (k.expired_at IS NULL OR k.expired_at > NOW())
+		  AND u.deleted_at IS NULL
+	`
+	var user model.Users
+	key, err := scanAPIKey(conn(ctx, r.db).QueryRow(ctx, query, keyHash), &user.Id, &user.Username, &user.Email, &user.Role)
+	if err != nil {
+		return nil, nil, apperror.FromDB(err, "api_key")
+	}
+	return key, &user, nil
+}
+
+// TouchAPIKey memperbarui last_used_at, paling sering sekali per menit supaya
+// setiap request tidak selalu menulis ke database
+func (r *apiKeysRepository) TouchAPIKey(ctx context.Context, id int) error {
+	query := `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - interval '1 minute')
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return apperror.FromDB(err, "api_key")
+	}
+	return nil
+}
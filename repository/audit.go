@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AuditFilter adalah filter opsional untuk GET /audit; nilai kosong berarti tidak difilter
+type AuditFilter struct {
+	Entity   string
+	EntityId int
+	ActorId  int
+	From     *time.Time
+	To       *time.Time
+}
+
+type AuditRepository interface {
+	CreateAuditLog(ctx context.Context, data *model.AuditLog) error
+	GetAuditLogs(ctx context.Context, filter AuditFilter, page, limit int) ([]model.AuditLog, int, error)
+}
+
+type auditRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewAuditRepository(db database.PgxIface, log *zap.Logger) AuditRepository {
+	return &auditRepository{db: db, Logger: log}
+}
+
+func (r *auditRepository) CreateAuditLog(ctx context.Context, data *model.AuditLog) error {
+	query := `
+		INSERT INTO audit_log (actor_id, actor_username, actor_role, action, entity, entity_id, changes, ip, request_id, created_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), NOW())
+		RETURNING id, created_at
+	`
+	return conn(ctx, r.db).QueryRow(ctx, query,
+		data.ActorId, data.ActorUsername, data.ActorRole, data.Action, data.Entity, data.EntityId,
+		data.Changes, data.IP, data.RequestId,
+	).Scan(&data.Id, &data.CreatedAt)
+}
+
+func (r *auditRepository) GetAuditLogs(ctx context.Context, filter AuditFilter, page, limit int) ([]model.AuditLog, int, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	offset := (page - 1) * limit
+
+	where, args := auditWhere(filter)
+
+	var total int
+	err := conn(ctx, r.db).QueryRow(ctx, "SELECT COUNT(*) FROM audit_log"+where, args...).Scan(&total)
+	if err != nil {
+		log.Error("error query audit log count", zap.Error(err))
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT id, actor_id, COALESCE(actor_username, ''), COALESCE(actor_role, ''), action, entity, entity_id,
+		       changes, COALESCE(ip, ''), COALESCE(request_id, ''), created_at
+		FROM audit_log%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		log.Error("error query audit log", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var logs []model.AuditLog
+	for rows.Next() {
+		var a model.AuditLog
+		err := rows.Scan(&a.Id, &a.ActorId, &a.ActorUsername, &a.ActorRole, &a.Action, &a.Entity, &a.EntityId,
+			&a.Changes, &a.IP, &a.RequestId, &a.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, a)
+	}
+
+	return logs, total, rows.Err()
+}
+
+// auditWhere membangun klausa WHERE dari filter yang terisi
+func auditWhere(filter AuditFilter) (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, value any) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.Entity != "" {
+		add("entity = $%d", filter.Entity)
+	}
+	if filter.EntityId > 0 {
+		add("entity_id = $%d", filter.EntityId)
+	}
+	if filter.ActorId > 0 {
+		add("actor_id = $%d", filter.ActorId)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
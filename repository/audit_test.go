@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestAuditWhere(t *testing.T) {
+	where, args := auditWhere(AuditFilter{})
+	assert.Empty(t, where)
+	assert.Nil(t, args)
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	where, args = auditWhere(AuditFilter{Entity: "item", ActorId: 3, From: &from})
+	assert.Equal(t, " WHERE entity = $1 AND actor_id = $2 AND created_at >= $3", where)
+	assert.Equal(t, []any{"item", 3, from}, args)
+}
+
+func TestWithinTx_AuditUsesSameTransaction(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+	mockRow := new(MockRow)
+	audit := NewAuditRepository(mockDB, zap.NewNop())
+	items := NewItemsRepository(mockDB, zap.NewNop())
+
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Exec", mock.Anything, queryContains("SET deleted_at = NOW()"), []any{1}).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	mockTx.On("QueryRow", mock.Anything, queryContains("INSERT INTO audit_log"), mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Return(nil)
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	err := NewTransactor(mockDB).WithinTx(context.Background(), func(ctx context.Context) error {
+		if err := items.DeleteItems(ctx, 1); err != nil {
+			return err
+		}
+		return audit.CreateAuditLog(ctx, &model.AuditLog{Action: model.AuditDelete, Entity: "item", EntityId: 1})
+	})
+
+	assert.NoError(t, err)
+	// tidak ada query yang lolos ke db di luar transaksi
+	mockDB.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "QueryRow", mock.Anything, mock.Anything, mock.Anything)
+	mockTx.AssertExpectations(t)
+}
+
+func TestWithinTx_RollbackOnError(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := NewTransactor(mockDB).WithinTx(context.Background(), func(ctx context.Context) error {
+		return errors.New("audit failed")
+	})
+
+	assert.EqualError(t, err, "audit failed")
+	mockTx.AssertExpectations(t)
+	mockTx.AssertNotCalled(t, "Commit", mock.Anything)
+}
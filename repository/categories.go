@@ -2,20 +2,22 @@ package repository
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/database"
 	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
 
 	"go.uber.org/zap"
 )
 
 type CategoriesRepository interface {
-	GetCategoriesById(id int) (*model.Categories, error)
-	GetAllCategories(page, limit int) ([]model.Categories, int, error)
-	CreateCategories(data *model.Categories) error
-	UpdateCategories(id int, data *model.Categories) error
-	DeleteCategories(id int) error
+	GetCategoriesById(ctx context.Context, id int) (*model.Categories, error)
+	GetAllCategories(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Categories, int, error)
+	CreateCategories(ctx context.Context, data *model.Categories) error
+	UpdateCategories(ctx context.Context, id int, data *model.Categories) error
+	PatchCategories(ctx context.Context, id, version int, changes map[string]any) (*model.Categories, error)
+	DeleteCategories(ctx context.Context, id int) error
+	RestoreCategories(ctx context.Context, id int) (*model.Categories, error)
 }
 
 type categoriesRepository struct {
@@ -27,46 +29,51 @@ func NewCategoriesRepository(db database.PgxIface, log *zap.Logger) CategoriesRe
 	return &categoriesRepository{db: db, Logger: log}
 }
 
-func (r *categoriesRepository) GetCategoriesById(id int) (*model.Categories, error) {
+func (r *categoriesRepository) GetCategoriesById(ctx context.Context, id int) (*model.Categories, error) {
 	query := `
-		SELECT id, name, created_at, updated_at
+		SELECT id, name, created_at, updated_at, version, tax_rate, tax_inclusive
 		FROM categories
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	var c model.Categories
-	err := r.db.QueryRow(context.Background(), query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&c.Id,
 		&c.Name,
 		&c.CreatedAt,
 		&c.UpdatedAt,
+		&c.Version,
+		&c.TaxRate,
+		&c.TaxInclusive,
 	)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("category not found")
+	if err != nil {
+		return nil, apperror.FromDB(err, "category")
 	}
-	return &c, err
+	return &c, nil
 }
 
 
-func (r *categoriesRepository) GetAllCategories(page, limit int) ([]model.Categories, int, error) {
+func (r *categoriesRepository) GetAllCategories(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Categories, int, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	offset := (page - 1) * limit
 
 	// get total data for pagination
 	var total int
-	countQuery := `SELECT COUNT(*) FROM categories`
-	err := r.db.QueryRow(context.Background(), countQuery).Scan(&total)
+	countQuery := `SELECT COUNT(*) FROM categories WHERE ($1 OR deleted_at IS NULL)`
+	err := conn(ctx, r.db).QueryRow(ctx, countQuery, includeDeleted).Scan(&total)
 	if err != nil {
-		r.Logger.Error("error query findall repo ", zap.Error(err))
+		log.Error("error query findall repo ", zap.Error(err))
 		return nil, 0, err
 	}
 
 	// get data with pagination
 	query := `
-		SELECT id, name, created_at, updated_at
+		SELECT id, name, created_at, updated_at, version, deleted_at, tax_rate, tax_inclusive
 		FROM categories
+		WHERE ($3 OR deleted_at IS NULL)
 		ORDER BY id
 		LIMIT $1 OFFSET $2
 	`
-	rows, err := r.db.Query(context.Background(), query, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, offset, includeDeleted)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -80,6 +87,10 @@ func (r *categoriesRepository) GetAllCategories(page, limit int) ([]model.Catego
 			&c.Name,
 			&c.CreatedAt,
 			&c.UpdatedAt,
+			&c.Version,
+			&c.DeletedAt,
+			&c.TaxRate,
+			&c.TaxInclusive,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -90,46 +101,66 @@ func (r *categoriesRepository) GetAllCategories(page, limit int) ([]model.Catego
 	return categories, total, nil
 }
 
-func (r *categoriesRepository) CreateCategories(data *model.Categories) error {
+func (r *categoriesRepository) CreateCategories(ctx context.Context, data *model.Categories) error {
 	query := `
-		INSERT INTO categories (name, created_at, updated_at)
-		VALUES ($1, NOW(), NOW())
-		RETURNING id
+		INSERT INTO categories (name, tax_rate, tax_inclusive, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING id, version
 	`
-	err := r.db.QueryRow(context.Background(), query, data.Name).Scan(&data.Id)
-	return err
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.Name, data.TaxRate, data.TaxInclusive).Scan(&data.Id, &data.Version)
+	return apperror.FromDB(err, "category")
 }
 
-func (r *categoriesRepository) UpdateCategories(id int, data *model.Categories) error {
+func (r *categoriesRepository) UpdateCategories(ctx context.Context, id int, data *model.Categories) error {
 	query := `
 		UPDATE categories
-		SET name = $1, updated_at = NOW()
-		WHERE id = $2`
-
-	result, err := r.db.Exec(context.Background(), query, data.Name, id)
-	if err != nil {
-		return err
-	}
-	rowAffected := result.RowsAffected()
+		SET name = $1, tax_rate = $2, tax_inclusive = $3, updated_at = NOW(), version = version + 1
+		WHERE id = $4 AND ($5 = 0 OR version = $5) AND deleted_at IS NULL
+		RETURNING version`
 
-	if rowAffected == 0 {
-		return errors.New("no rows affected")
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.Name, data.TaxRate, data.TaxInclusive, id, data.Version).Scan(&data.Version)
+	if apperror.IsNoRows(err) {
+		return resolveUpdateMiss(ctx, r.db, "categories", "category", id)
 	}
-	return err
+	return apperror.FromDB(err, "category")
 }
 
-func (r *categoriesRepository) DeleteCategories(id int) error {
-	query := `DELETE FROM categories WHERE id = $1`
+var categoriesPatchColumns = map[string]bool{
+	"name":          true,
+	"tax_rate":      true,
+	"tax_inclusive": true,
+}
 
-	result, err := r.db.Exec(context.Background(), query, id)
+func (r *categoriesRepository) PatchCategories(ctx context.Context, id, version int, changes map[string]any) (*model.Categories, error) {
+	query, args, err := buildPatch("categories", categoriesPatchColumns, changes, id, version,
+		"id, name, created_at, updated_at, version, tax_rate, tax_inclusive")
 	if err != nil {
-		return err
+		return nil, err
 	}
-	rowAffected := result.RowsAffected()
 
-	if rowAffected == 0 {
-		return errors.New("no rows affected")
+	var c model.Categories
+	err = conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&c.Id, &c.Name, &c.CreatedAt, &c.UpdatedAt, &c.Version, &c.TaxRate, &c.TaxInclusive)
+	if apperror.IsNoRows(err) {
+		return nil, resolveUpdateMiss(ctx, r.db, "categories", "category", id)
+	}
+	if err != nil {
+		return nil, apperror.FromDB(err, "category")
 	}
+	return &c, nil
+}
+
+func (r *categoriesRepository) DeleteCategories(ctx context.Context, id int) error {
+	return softDelete(ctx, r.db, "categories", "category", id,
+		"SELECT 1 FROM items WHERE items.category_id = categories.id AND items.deleted_at IS NULL")
+}
 
-	return err
+func (r *categoriesRepository) RestoreCategories(ctx context.Context, id int) (*model.Categories, error) {
+	var c model.Categories
+	err := restoreDeleted(ctx, r.db, "categories", "category", id,
+		"id, name, created_at, updated_at, version, tax_rate, tax_inclusive",
+		&c.Id, &c.Name, &c.CreatedAt, &c.UpdatedAt, &c.Version, &c.TaxRate, &c.TaxInclusive)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
 }
\ No newline at end of file
@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"database/sql"
 	"errors"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -35,7 +38,7 @@ func TestGetCategoriesById_Success(t *testing.T) {
 		*dest[3].(*time.Time) = expectedCategory.UpdatedAt
 	}).Return(nil)
 
-	result, err := repo.GetCategoriesById(1)
+	result, err := repo.GetCategoriesById(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -54,7 +57,7 @@ func TestGetCategoriesById_NotFound(t *testing.T) {
 	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
 	mockRow.On("Scan", mock.Anything).Return(sql.ErrNoRows)
 
-	result, err := repo.GetCategoriesById(999)
+	result, err := repo.GetCategoriesById(context.Background(), 999)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -102,7 +105,7 @@ func TestGetAllCategories_Success(t *testing.T) {
 	mockRows.On("Close").Return()
 	mockRows.On("Err").Return(nil)
 
-	categories, total, err := repo.GetAllCategories(1, 10)
+	categories, total, err := repo.GetAllCategories(context.Background(), 1, 10, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, total)
@@ -128,7 +131,7 @@ func TestCreateCategories_Success(t *testing.T) {
 		*dest[0].(*int) = 1
 	}).Return(nil)
 
-	err := repo.CreateCategories(category)
+	err := repo.CreateCategories(context.Background(), category)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, category.Id)
@@ -145,12 +148,17 @@ func TestUpdateCategories_Success(t *testing.T) {
 		Name: "Updated Category",
 	}
 
-	mockTag := MockCommandTag{rowsAffected: 1}
-	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[0].(*int) = 3 // versi baru
+	}).Return(nil)
 
-	err := repo.UpdateCategories(1, category)
+	err := repo.UpdateCategories(context.Background(), 1, category)
 
 	assert.NoError(t, err)
+	assert.Equal(t, 3, category.Version)
 	mockDB.AssertExpectations(t)
 }
 
@@ -163,13 +171,16 @@ func TestUpdateCategories_NoRowsAffected(t *testing.T) {
 		Name: "Updated Category",
 	}
 
-	mockTag := MockCommandTag{rowsAffected: 0}
-	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
+	// UPDATE tidak mengenai baris dan lookup versi juga kosong -> not found
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
 
-	err := repo.UpdateCategories(999, category)
+	err := repo.UpdateCategories(context.Background(), 999, category)
 
 	assert.Error(t, err)
-	assert.Equal(t, "no rows affected", err.Error())
+	assert.Equal(t, "category not found", err.Error())
+	assert.True(t, apperror.IsNotFound(err))
 	mockDB.AssertExpectations(t)
 }
 
@@ -181,7 +192,7 @@ func TestDeleteCategories_Success(t *testing.T) {
 	mockTag := MockCommandTag{rowsAffected: 1}
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
 
-	err := repo.DeleteCategories(1)
+	err := repo.DeleteCategories(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockDB.AssertExpectations(t)
@@ -195,10 +206,18 @@ func TestDeleteCategories_NoRowsAffected(t *testing.T) {
 	mockTag := MockCommandTag{rowsAffected: 0}
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
 
-	err := repo.DeleteCategories(999)
+	// cek keberadaan category aktif setelah UPDATE tidak mengenai baris
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*bool) = false
+	}).Return(nil)
+
+	err := repo.DeleteCategories(context.Background(), 999)
 
 	assert.Error(t, err)
-	assert.Equal(t, "no rows affected", err.Error())
+	assert.Equal(t, "category not found", err.Error())
+	assert.True(t, apperror.IsNotFound(err))
 	mockDB.AssertExpectations(t)
 }
 
@@ -210,7 +229,7 @@ func TestDeleteCategories_Error(t *testing.T) {
 	mockTag := MockCommandTag{rowsAffected: 0}
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, errors.New("database error"))
 
-	err := repo.DeleteCategories(1)
+	err := repo.DeleteCategories(context.Background(), 1)
 
 	assert.Error(t, err)
 	mockDB.AssertExpectations(t)
@@ -232,7 +251,7 @@ func TestGetAllCategories_QueryError(t *testing.T) {
 	// Mock data query failure
 	mockDB.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("query failed"))
 
-	categories, total, err := repo.GetAllCategories(1, 10)
+	categories, total, err := repo.GetAllCategories(context.Background(), 1, 10, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, categories)
@@ -255,7 +274,7 @@ func TestCreateCategories_Error(t *testing.T) {
 	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
 	mockRow.On("Scan", mock.Anything).Return(errors.New("constraint violation"))
 
-	err := repo.CreateCategories(category)
+	err := repo.CreateCategories(context.Background(), category)
 
 	assert.Error(t, err)
 	assert.Equal(t, "constraint violation", err.Error())
@@ -272,10 +291,11 @@ func TestUpdateCategories_Error(t *testing.T) {
 		Name: "Updated Category",
 	}
 
-	mockTag := MockCommandTag{rowsAffected: 0}
-	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, errors.New("connection lost"))
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Return(errors.New("connection lost"))
 
-	err := repo.UpdateCategories(1, category)
+	err := repo.UpdateCategories(context.Background(), 1, category)
 
 	assert.Error(t, err)
 	assert.Equal(t, "connection lost", err.Error())
@@ -292,7 +312,7 @@ func TestGetAllCategories_CountQueryError(t *testing.T) {
 	mockDB.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(mockRowCount)
 	mockRowCount.On("Scan", mock.Anything).Return(errors.New("count query failed"))
 
-	categories, total, err := repo.GetAllCategories(1, 10)
+	categories, total, err := repo.GetAllCategories(context.Background(), 1, 10, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, categories)
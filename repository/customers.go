@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+
+	"go.uber.org/zap"
+)
+
+// CustomerCredit adalah posisi kredit customer saat penjualan kredit dibuat
+type CustomerCredit struct {
+	CreditLimit model.Money
+	Outstanding model.Money
+}
+
+type CustomersRepository interface {
+	GetCustomersById(ctx context.Context, id int) (*model.Customers, error)
+	GetAllCustomers(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Customers, int, error)
+	CreateCustomers(ctx context.Context, data *model.Customers) error
+	UpdateCustomers(ctx context.Context, id int, data *model.Customers) error
+	PatchCustomers(ctx context.Context, id, version int, changes map[string]any) (*model.Customers, error)
+	DeleteCustomers(ctx context.Context, id int) error
+	RestoreCustomers(ctx context.Context, id int) (*model.Customers, error)
+	LockCustomerCredit(ctx context.Context, id int) (*CustomerCredit, error)
+}
+
+type customersRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewCustomersRepository(db database.PgxIface, log *zap.Logger) CustomersRepository {
+	return &customersRepository{db: db, Logger: log}
+}
+
+const customersColumns = "id, name, email, phone, tax_id, address, credit_limit, created_at, updated_at, version"
+
+func customerDest(c *model.Customers) []any {
+	return []any{&c.Id, &c.Name, &c.Email, &c.Phone, &c.TaxId, &c.Address, &c.CreditLimit, &c.CreatedAt, &c.UpdatedAt, &c.Version}
+}
+
+func (r *customersRepository) GetCustomersById(ctx context.Context, id int) (*model.Customers, error) {
+	query := `
+		SELECT ` + customersColumns + `
+		FROM customers
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	var customer model.Customers
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(customerDest(&customer)...)
+	if err != nil {
+		return nil, apperror.FromDB(err, "customer")
+	}
+	return &customer, nil
+}
+
+func (r *customersRepository) GetAllCustomers(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Customers, int, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	offset := (page - 1) * limit
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM customers WHERE ($1 OR deleted_at IS NULL)`
+	err := conn(ctx, r.db).QueryRow(ctx, countQuery, includeDeleted).Scan(&total)
+	if err != nil {
+		log.Error("error query count customers", zap.Error(err))
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + customersColumns + `, deleted_at
+		FROM customers
+		WHERE ($3 OR deleted_at IS NULL)
+		ORDER BY id
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, offset, includeDeleted)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var customers []model.Customers
+	for rows.Next() {
+		var customer model.Customers
+		if err := rows.Scan(append(customerDest(&customer), &customer.DeletedAt)...); err != nil {
+			return nil, 0, err
+		}
+		customers = append(customers, customer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return customers, total, nil
+}
+
+func (r *customersRepository) CreateCustomers(ctx context.Context, data *model.Customers) error {
+	query := `
+		INSERT INTO customers (name, email, phone, tax_id, address, credit_limit, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, version
+	`
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		data.Name, data.Email, data.Phone, data.TaxId, data.Address, data.CreditLimit,
+	).Scan(&data.Id, &data.Version)
+	return apperror.FromDB(err, "customer")
+}
+
+func (r *customersRepository) UpdateCustomers(ctx context.Context, id int, data *model.Customers) error {
+	query := `
+		UPDATE customers
+		SET name = $1, email = $2, phone = $3, tax_id = $4, address = $5, credit_limit = $6,
+			updated_at = NOW(), version = version + 1
+		WHERE id = $7 AND ($8 = 0 OR version = $8) AND deleted_at IS NULL
+		RETURNING version`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		data.Name, data.Email, data.Phone, data.TaxId, data.Address, data.CreditLimit, id, data.Version,
+	).Scan(&data.Version)
+	if apperror.IsNoRows(err) {
+		return resolveUpdateMiss(ctx, r.db, "customers", "customer", id)
+	}
+	return apperror.FromDB(err, "customer")
+}
+
+var customersPatchColumns = map[string]bool{
+	"name":         true,
+	"email":        true,
+	"phone":        true,
+	"tax_id":       true,
+	"address":      true,
+	"credit_limit": true,
+}
+
+func (r *customersRepository) PatchCustomers(ctx context.Context, id, version int, changes map[string]any) (*model.Customers, error) {
+	query, args, err := buildPatch("customers", customersPatchColumns, changes, id, version, customersColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	var c model.Customers
+	err = conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(customerDest(&c)...)
+	if apperror.IsNoRows(err) {
+		return nil, resolveUpdateMiss(ctx, r.db, "customers", "customer", id)
+	}
+	if err != nil {
+		return nil, apperror.FromDB(err, "customer")
+	}
+	return &c, nil
+}
+
+// DeleteCustomers: customer yang punya riwayat penjualan tetap boleh dihapus
+// (soft delete), riwayatnya tetap menunjuk ke baris yang sama
+func (r *customersRepository) DeleteCustomers(ctx context.Context, id int) error {
+	return softDelete(ctx, r.db, "customers", "customer", id, "")
+}
+
+func (r *customersRepository) RestoreCustomers(ctx context.Context, id int) (*model.Customers, error) {
+	var c model.Customers
+	err := restoreDeleted(ctx, r.db, "customers", "customer", id, customersColumns, customerDest(&c)...)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// LockCustomerCredit mengunci baris customer (FOR UPDATE) lalu menghitung total
+// penjualan kredit yang belum lunas. Harus dipanggil di dalam transaksi supaya dua
+// penjualan kredit bersamaan tidak sama-sama lolos pengecekan credit limit.
+func (r *customersRepository) LockCustomerCredit(ctx context.Context, id int) (*CustomerCredit, error) {
+	query := `
+		SELECT c.credit_limit,
+			COALESCE((SELECT SUM(s.total_amount - s.paid_amount) FROM sales s
+				WHERE s.customer_id = c.id AND s.payment_type = 'credit' AND s.status = 'completed'), 0)
+		FROM customers c
+		WHERE c.id = $1 AND c.deleted_at IS NULL
+		FOR UPDATE OF c
+	`
+	var credit CustomerCredit
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&credit.CreditLimit, &credit.Outstanding)
+	if err != nil {
+		return nil, apperror.FromDB(err, "customer")
+	}
+	return &credit, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/model"
+
+	"go.uber.org/zap"
+)
+
+type IdempotencyRepository interface {
+	ReserveIdempotencyKey(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, bool, error)
+	CompleteIdempotencyKey(ctx context.Context, id, statusCode int, headers map[string]string, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, id int) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewIdempotencyRepository(db database.PgxIface, log *zap.Logger) IdempotencyRepository {
+	return &idempotencyRepository{db: db, Logger: log}
+}
+
+// ReserveIdempotencyKey menyimpan key baru (status diproses) dan mengembalikan true.
+// Key yang sudah kedaluwarsa ditimpa. Jika key masih aktif, record yang ada
+// dikembalikan dengan false.
+func (r *idempotencyRepository) ReserveIdempotencyKey(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (scope, idempotency_key, method, path, request_hash, created_at, expired_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), $6)
+		ON CONFLICT (scope, idempotency_key) DO UPDATE
+		SET method = EXCLUDED.method, path = EXCLUDED.path, request_hash = EXCLUDED.request_hash,
+		    status_code = NULL, response_headers = NULL, response_body = NULL,
+		    created_at = NOW(), expired_at = EXCLUDED.expired_at
+		WHERE idempotency_keys.expired_at <= NOW()
+		RETURNING id, created_at
+	`
+	err := conn(ctx, r.db).QueryRow(ctx, query, key.Scope, key.Key, key.Method, key.Path, key.RequestHash, key.ExpiredAt).
+		Scan(&key.Id, &key.CreatedAt)
+	if err == nil {
+		return key, true, nil
+	}
+	if !apperror.IsNoRows(err) {
+		return nil, false, apperror.FromDB(err, "idempotency_key")
+	}
+
+	query = `
+		SELECT id, scope, idempotency_key, method, path, request_hash, status_code, response_headers, response_body, created_at, expired_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2
+	`
+	var existing model.IdempotencyKey
+	err = conn(ctx, r.db).QueryRow(ctx, query, key.Scope, key.Key).Scan(
+		&existing.Id, &existing.Scope, &existing.Key, &existing.Method, &existing.Path, &existing.RequestHash,
+		&existing.StatusCode, &existing.ResponseHeaders, &existing.ResponseBody, &existing.CreatedAt, &existing.ExpiredAt)
+	if err != nil {
+		return nil, false, apperror.FromDB(err, "idempotency_key")
+	}
+	return &existing, false, nil
+}
+
+// CompleteIdempotencyKey menyimpan response untuk di-replay pada retry
+func (r *idempotencyRepository) CompleteIdempotencyKey(ctx context.Context, id, statusCode int, headers map[string]string, body []byte) error {
+	query := `UPDATE idempotency_keys SET status_code = $1, response_headers = $2, response_body = $3 WHERE id = $4`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, statusCode, headers, body, id)
+	if err != nil {
+		return apperror.FromDB(err, "idempotency_key")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("idempotency_key")
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey menghapus key yang belum selesai supaya request bisa diulang
+func (r *idempotencyRepository) ReleaseIdempotencyKey(ctx context.Context, id int) error {
+	query := `DELETE FROM idempotency_keys WHERE id = $1 AND status_code IS NULL`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, id); err != nil {
+		return apperror.FromDB(err, "idempotency_key")
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expired_at <= NOW()`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query)
+	if err != nil {
+		return 0, apperror.FromDB(err, "idempotency_key")
+	}
+	return result.RowsAffected(), nil
+}
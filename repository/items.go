@@ -2,21 +2,23 @@ package repository
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/database"
 	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
 
 	"go.uber.org/zap"
 )
 
 type ItemsRepository interface {
-	GetItemsById(id int) (*model.Items, error) 
-	GetAllItems(page, limit int) ([]model.Items, int, error)
-	GetLowStockItems(threshold int) ([]model.Items, error)
-	CreateItems(data *model.Items) error
-	UpdateItems(id int, data *model.Items) error
-	DeleteItems(id int) error
+	GetItemsById(ctx context.Context, id int) (*model.Items, error) 
+	GetAllItems(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Items, int, error)
+	GetLowStockItems(ctx context.Context, threshold int) ([]model.Items, error)
+	CreateItems(ctx context.Context, data *model.Items) error
+	UpdateItems(ctx context.Context, id int, data *model.Items) error
+	PatchItems(ctx context.Context, id, version int, changes map[string]any) (*model.Items, error)
+	DeleteItems(ctx context.Context, id int) error
+	RestoreItems(ctx context.Context, id int) (*model.Items, error)
 }
 
 type itemsRepository struct {
@@ -28,15 +30,15 @@ func NewItemsRepository(db database.PgxIface, log *zap.Logger) ItemsRepository {
 	return &itemsRepository{db: db, Logger: log}
 }
 
-func (r *itemsRepository) GetItemsById(id int) (*model.Items, error) {
+func (r *itemsRepository) GetItemsById(ctx context.Context, id int) (*model.Items, error) {
 	query := `
-		SELECT id, category_id, rack_id, name, sku, stock, min_stock, price, created_at, updated_at
+		SELECT id, category_id, rack_id, name, sku, stock, min_stock, price, created_at, updated_at, version, tax_rate, tax_inclusive, reserved, stock - reserved
 		FROM items
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 
 	`
 	var i model.Items
-	err := r.db.QueryRow(context.Background(), query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&i.Id,
 		&i.CategoryId,
 		&i	.RackId,
@@ -47,33 +49,40 @@ func (r *itemsRepository) GetItemsById(id int) (*model.Items, error) {
 		&i.Price,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.Reserved,
+		&i.Available,
 	)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("item not found")
+	if err != nil {
+		return nil, apperror.FromDB(err, "item")
 	}
-	return &i, err
+	return &i, nil
 }
 
-func (r *itemsRepository) GetAllItems(page, limit int) ([]model.Items, int, error) {
+func (r *itemsRepository) GetAllItems(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Items, int, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	offset := (page - 1) * limit
 
 	// get total data for pagination
 	var total int
-	countQuery := `SELECT COUNT(*) FROM items`
-	err := r.db.QueryRow(context.Background(), countQuery).Scan(&total)
+	countQuery := `SELECT COUNT(*) FROM items WHERE ($1 OR deleted_at IS NULL)`
+	err := conn(ctx, r.db).QueryRow(ctx, countQuery, includeDeleted).Scan(&total)
 	if err != nil {
-		r.Logger.Error("error query findall repo ", zap.Error(err))
+		log.Error("error query findall repo ", zap.Error(err))
 		return nil, 0, err
 	}
 
 	// get data with pagination
 	query := `
-		SELECT id, category_id, rack_id, name, sku, stock, min_stock, price, created_at, updated_at
+		SELECT id, category_id, rack_id, name, sku, stock, min_stock, price, created_at, updated_at, version, deleted_at, tax_rate, tax_inclusive, reserved, stock - reserved
 		FROM items
+		WHERE ($3 OR deleted_at IS NULL)
 		ORDER BY id ASC
 		LIMIT $1 OFFSET $2
 	`
-	rows, err := r.db.Query(context.Background(), query, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, offset, includeDeleted)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -93,6 +102,12 @@ func (r *itemsRepository) GetAllItems(page, limit int) ([]model.Items, int, erro
 			&i.Price,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+			&i.TaxRate,
+			&i.TaxInclusive,
+			&i.Reserved,
+			&i.Available,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -103,17 +118,20 @@ func (r *itemsRepository) GetAllItems(page, limit int) ([]model.Items, int, erro
 	return items, total, nil
 }
 
-func (r *itemsRepository) GetLowStockItems(threshold int) ([]model.Items, error) {
+// GetLowStockItems memakai stock tersedia (stock - reserved): stock yang sudah
+// dipesan sale held / reservasi tidak bisa dijual lagi
+func (r *itemsRepository) GetLowStockItems(ctx context.Context, threshold int) ([]model.Items, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	query := `
-		SELECT id, category_id, rack_id, name, sku, stock, min_stock, price, created_at, updated_at
+		SELECT id, category_id, rack_id, name, sku, stock, min_stock, price, created_at, updated_at, version, tax_rate, tax_inclusive, reserved, stock - reserved
 		FROM items
-		WHERE stock < $1
-		ORDER BY stock ASC, name ASC
+		WHERE stock - reserved < $1 AND deleted_at IS NULL
+		ORDER BY stock - reserved ASC, name ASC
 	`
 
-	rows, err := r.db.Query(context.Background(), query, threshold)
+	rows, err := conn(ctx, r.db).Query(ctx, query, threshold)
 	if err != nil {
-		r.Logger.Error("failed to get low stock items",
+		log.Error("failed to get low stock items",
 			zap.Int("threshold", threshold),
 			zap.Error(err),
 		)
@@ -135,20 +153,25 @@ func (r *itemsRepository) GetLowStockItems(threshold int) ([]model.Items, error)
 			&i.Price,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.TaxRate,
+			&i.TaxInclusive,
+			&i.Reserved,
+			&i.Available,
 		)
 		if err != nil {
-			r.Logger.Error("failed to scan low stock item", zap.Error(err))
+			log.Error("failed to scan low stock item", zap.Error(err))
 			return nil, err
 		}
 		items = append(items, i)
 	}
 
 	if err = rows.Err(); err != nil {
-		r.Logger.Error("error iterating low stock items", zap.Error(err))
+		log.Error("error iterating low stock items", zap.Error(err))
 		return nil, err
 	}
 
-	r.Logger.Info("low stock items retrieved",
+	log.Info("low stock items retrieved",
 		zap.Int("count", len(items)),
 		zap.Int("threshold", threshold),
 	)
@@ -156,46 +179,74 @@ func (r *itemsRepository) GetLowStockItems(threshold int) ([]model.Items, error)
 	return items, nil
 }
 
-func (r *itemsRepository) CreateItems(data *model.Items) error {
+func (r *itemsRepository) CreateItems(ctx context.Context, data *model.Items) error {
 	query := `
-		INSERT INTO items (category_id, rack_id, name, sku, stock, min_stock, price, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
-		RETURNING id
+		INSERT INTO items (category_id, rack_id, name, sku, stock, min_stock, price, tax_rate, tax_inclusive, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		RETURNING id, version, reserved, stock - reserved
 	`
-	err := r.db.QueryRow(context.Background(), query, data.CategoryId, data.RackId, data.Name, data.Sku, data.Stock, data.MinStock, data.Price).Scan(&data.Id)
-	return err
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.CategoryId, data.RackId, data.Name, data.Sku, data.Stock, data.MinStock, data.Price, data.TaxRate, data.TaxInclusive).Scan(&data.Id, &data.Version, &data.Reserved, &data.Available)
+	return apperror.FromDB(err, "item")
 }
 
-func (r *itemsRepository) UpdateItems(id int, data *model.Items) error {
+func (r *itemsRepository) UpdateItems(ctx context.Context, id int, data *model.Items) error {
+	// data.Version adalah versi dari If-Match; AnyVersion berarti tanpa cek versi
 	query := `
 		UPDATE items
-		SET category_id = $1, rack_id = $2, name = $3, sku = $4, stock = $5, min_stock = $6, price = $7, updated_at = NOW()
-		WHERE id = $8`
-
-	result, err := r.db.Exec(context.Background(), query, data.CategoryId, data.RackId, data.Name, data.Sku, data.Stock, data.MinStock, data.Price, id)
-	if err != nil {
-		return err
-	}
-	rowAffected := result.RowsAffected()
+		SET category_id = $1, rack_id = $2, name = $3, sku = $4, stock = $5, min_stock = $6, price = $7, tax_rate = $8, tax_inclusive = $9, updated_at = NOW(), version = version + 1
+		WHERE id = $10 AND ($11 = 0 OR version = $11) AND deleted_at IS NULL
+		RETURNING version, reserved, stock - reserved`
 
-	if rowAffected == 0 {
-		return errors.New("no rows affected")
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.CategoryId, data.RackId, data.Name, data.Sku, data.Stock, data.MinStock, data.Price, data.TaxRate, data.TaxInclusive, id, data.Version).Scan(&data.Version, &data.Reserved, &data.Available)
+	if apperror.IsNoRows(err) {
+		return resolveUpdateMiss(ctx, r.db, "items", "item", id)
 	}
-	return err
+	return apperror.FromDB(err, "item")
 }
 
-func (r *itemsRepository) DeleteItems(id int) error {
-	query := `DELETE FROM items WHERE id = $1`
+// itemsPatchColumns adalah kolom yang boleh diubah lewat PATCH
+var itemsPatchColumns = map[string]bool{
+	"category_id":   true,
+	"rack_id":       true,
+	"name":          true,
+	"sku":           true,
+	"stock":         true,
+	"min_stock":     true,
+	"price":         true,
+	// null = kembali ikut tarif category
+	"tax_rate":      true,
+	"tax_inclusive": true,
+}
 
-	result, err := r.db.Exec(context.Background(), query, id)
+func (r *itemsRepository) PatchItems(ctx context.Context, id, version int, changes map[string]any) (*model.Items, error) {
+	query, args, err := buildPatch("items", itemsPatchColumns, changes, id, version,
+		"id, category_id, rack_id, name, sku, stock, min_stock, price, created_at, updated_at, version, tax_rate, tax_inclusive, reserved, stock - reserved")
 	if err != nil {
-		return err
+		return nil, err
 	}
-	rowAffected := result.RowsAffected()
 
-	if rowAffected == 0 {
-		return errors.New("no rows affected")
+	var i model.Items
+	err = conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&i.Id, &i.CategoryId, &i.RackId, &i.Name, &i.Sku, &i.Stock, &i.MinStock, &i.Price, &i.CreatedAt, &i.UpdatedAt, &i.Version, &i.TaxRate, &i.TaxInclusive, &i.Reserved, &i.Available)
+	if apperror.IsNoRows(err) {
+		return nil, resolveUpdateMiss(ctx, r.db, "items", "item", id)
+	}
+	if err != nil {
+		return nil, apperror.FromDB(err, "item")
 	}
+	return &i, nil
+}
+
+func (r *itemsRepository) DeleteItems(ctx context.Context, id int) error {
+	return softDelete(ctx, r.db, "items", "item", id, "")
+}
 
-	return err
+func (r *itemsRepository) RestoreItems(ctx context.Context, id int) (*model.Items, error) {
+	var i model.Items
+	err := restoreDeleted(ctx, r.db, "items", "item", id,
+		"id, category_id, rack_id, name, sku, stock, min_stock, price, created_at, updated_at, version, tax_rate, tax_inclusive, reserved, stock - reserved",
+		&i.Id, &i.CategoryId, &i.RackId, &i.Name, &i.Sku, &i.Stock, &i.MinStock, &i.Price, &i.CreatedAt, &i.UpdatedAt, &i.Version, &i.TaxRate, &i.TaxInclusive, &i.Reserved, &i.Available)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
 }
\ No newline at end of file
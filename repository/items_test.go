@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"database/sql"
 	"errors"
 	"project-app-inventory-restapi-golang-azwin/model"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -27,7 +31,7 @@ func TestGetItemsById_Success(t *testing.T) {
 		Sku:        "TEST-001",
 		Stock:      100,
 		MinStock:   10,
-		Price:      50000,
+		Price:      model.MustParseMoney("50000"),
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -44,13 +48,13 @@ func TestGetItemsById_Success(t *testing.T) {
 		*dest[4].(*string) = expectedItem.Sku
 		*dest[5].(*int) = expectedItem.Stock
 		*dest[6].(*int) = expectedItem.MinStock
-		*dest[7].(*float64) = expectedItem.Price
+		*dest[7].(*model.Money) = expectedItem.Price
 		*dest[8].(*time.Time) = expectedItem.CreatedAt
 		*dest[9].(*time.Time) = expectedItem.UpdatedAt
 	}).Return(nil)
 
 	// Execute
-	result, err := repo.GetItemsById(1)
+	result, err := repo.GetItemsById(context.Background(), 1)
 
 	// Assert
 	assert.NoError(t, err)
@@ -73,7 +77,7 @@ func TestGetItemsById_NotFound(t *testing.T) {
 	mockRow.On("Scan", mock.Anything).Return(sql.ErrNoRows)
 
 	// Execute
-	result, err := repo.GetItemsById(999)
+	result, err := repo.GetItemsById(context.Background(), 999)
 
 	// Assert
 	assert.Error(t, err)
@@ -115,7 +119,7 @@ func TestGetAllItems_Success(t *testing.T) {
 		*dest[4].(*string) = "SKU-001"
 		*dest[5].(*int) = 50
 		*dest[6].(*int) = 10
-		*dest[7].(*float64) = 10000.0
+		*dest[7].(*model.Money) = model.MustParseMoney("10000")
 		*dest[8].(*time.Time) = time.Now()
 		*dest[9].(*time.Time) = time.Now()
 	}).Return(nil).Once()
@@ -130,7 +134,7 @@ func TestGetAllItems_Success(t *testing.T) {
 		*dest[4].(*string) = "SKU-002"
 		*dest[5].(*int) = 75
 		*dest[6].(*int) = 15
-		*dest[7].(*float64) = 15000.0
+		*dest[7].(*model.Money) = model.MustParseMoney("15000")
 		*dest[8].(*time.Time) = time.Now()
 		*dest[9].(*time.Time) = time.Now()
 	}).Return(nil).Once().NotBefore(call2)
@@ -139,7 +143,7 @@ func TestGetAllItems_Success(t *testing.T) {
 	mockRows.On("Close").Return()
 
 	// Execute
-	items, total, err := repo.GetAllItems(page, limit)
+	items, total, err := repo.GetAllItems(context.Background(), page, limit, false)
 
 	// Assert
 	assert.NoError(t, err)
@@ -173,7 +177,7 @@ func TestGetLowStockItems_Success(t *testing.T) {
 		*dest[4].(*string) = "LOW-001"
 		*dest[5].(*int) = 5
 		*dest[6].(*int) = 10
-		*dest[7].(*float64) = 10000.0
+		*dest[7].(*model.Money) = model.MustParseMoney("10000")
 		*dest[8].(*time.Time) = time.Now()
 		*dest[9].(*time.Time) = time.Now()
 	}).Return(nil).Once()
@@ -183,7 +187,7 @@ func TestGetLowStockItems_Success(t *testing.T) {
 	mockRows.On("Err").Return(nil)
 
 	// Execute
-	items, err := repo.GetLowStockItems(threshold)
+	items, err := repo.GetLowStockItems(context.Background(), threshold)
 
 	// Assert
 	assert.NoError(t, err)
@@ -193,6 +197,36 @@ func TestGetLowStockItems_Success(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+// stock yang dipesan sale held / reservasi tidak dihitung sebagai stock tersedia
+func TestGetLowStockItems_UsesAvailableStock(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockRows := new(MockRows)
+	repo := NewItemsRepository(mockDB, zap.NewNop())
+
+	mockDB.On("Query", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "WHERE stock - reserved < $1") && strings.Contains(query, "ORDER BY stock - reserved ASC")
+	}), []interface{}{5}).Return(mockRows, nil)
+	mockRows.On("Next").Return(true).Once()
+	mockRows.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[5].(*int) = 20
+		*dest[13].(*int) = 18
+		*dest[14].(*int) = 2
+	}).Return(nil).Once()
+	mockRows.On("Next").Return(false).Once()
+	mockRows.On("Close").Return()
+	mockRows.On("Err").Return(nil)
+
+	items, err := repo.GetLowStockItems(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, 20, items[0].Stock)
+	assert.Equal(t, 18, items[0].Reserved)
+	assert.Equal(t, 2, items[0].Available)
+	mockDB.AssertExpectations(t)
+}
+
 func TestCreateItems_Success(t *testing.T) {
 	// Setup
 	mockDB := new(MockPgxIface)
@@ -218,7 +252,7 @@ func TestCreateItems_Success(t *testing.T) {
 	}).Return(nil)
 
 	// Execute
-	err := repo.CreateItems(newItem)
+	err := repo.CreateItems(context.Background(), newItem)
 
 	// Assert
 	assert.NoError(t, err)
@@ -242,16 +276,21 @@ func TestUpdateItems_Success(t *testing.T) {
 		Price:      30000,
 	}
 
-	mockTag := MockCommandTag{rowsAffected: 1}
+	mockRow := new(MockRow)
 
 	// Mock expectations
-	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[0].(*int) = 3 // versi baru
+	}).Return(nil)
 
 	// Execute
-	err := repo.UpdateItems(1, updateItem)
+	err := repo.UpdateItems(context.Background(), 1, updateItem)
 
 	// Assert
 	assert.NoError(t, err)
+	assert.Equal(t, 3, updateItem.Version)
 	mockDB.AssertExpectations(t)
 }
 
@@ -271,17 +310,44 @@ func TestUpdateItems_NoRowsAffected(t *testing.T) {
 		Price:      30000,
 	}
 
-	mockTag := MockCommandTag{rowsAffected: 0}
+	// UPDATE tidak mengenai baris dan lookup versi juga kosong -> not found
+	mockRow := new(MockRow)
 
 	// Mock expectations
-	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
 
 	// Execute
-	err := repo.UpdateItems(999, updateItem)
+	err := repo.UpdateItems(context.Background(), 999, updateItem)
 
 	// Assert
 	assert.Error(t, err)
-	assert.Equal(t, "no rows affected", err.Error())
+	assert.Equal(t, "item not found", err.Error())
+	assert.True(t, apperror.IsNotFound(err))
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateItems_VersionMismatch(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	repo := NewItemsRepository(mockDB, zap.NewNop())
+
+	updateRow := new(MockRow)
+	versionRow := new(MockRow)
+	isUpdate := func(q string) bool { return strings.Contains(q, "UPDATE items") }
+
+	// UPDATE dengan versi lama tidak mengenai baris, tapi item masih ada di versi 5
+	mockDB.On("QueryRow", mock.Anything, mock.MatchedBy(isUpdate), mock.Anything).Return(updateRow)
+	mockDB.On("QueryRow", mock.Anything, mock.MatchedBy(func(q string) bool { return !isUpdate(q) }), mock.Anything).Return(versionRow)
+	updateRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+	versionRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*int) = 5
+	}).Return(nil)
+
+	err := repo.UpdateItems(context.Background(), 1, &model.Items{Name: "Stale", Version: 4})
+
+	assert.True(t, apperror.Is(err, apperror.KindPrecondition))
+	assert.Equal(t, "item_version_mismatch", apperror.Code(err))
+	assert.Equal(t, 5, apperror.Details(err).(map[string]int)["current_version"])
 	mockDB.AssertExpectations(t)
 }
 
@@ -297,7 +363,7 @@ func TestDeleteItems_Success(t *testing.T) {
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
 
 	// Execute
-	err := repo.DeleteItems(1)
+	err := repo.DeleteItems(context.Background(), 1)
 
 	// Assert
 	assert.NoError(t, err)
@@ -316,11 +382,12 @@ func TestDeleteItems_NoRowsAffected(t *testing.T) {
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
 
 	// Execute
-	err := repo.DeleteItems(999)
+	err := repo.DeleteItems(context.Background(), 999)
 
 	// Assert
 	assert.Error(t, err)
-	assert.Equal(t, "no rows affected", err.Error())
+	assert.Equal(t, "item not found", err.Error())
+	assert.True(t, apperror.IsNotFound(err))
 	mockDB.AssertExpectations(t)
 }
 
@@ -336,7 +403,7 @@ func TestGetAllItems_CountQueryError(t *testing.T) {
 	mockRow.On("Scan", mock.Anything).Return(errors.New("database error"))
 
 	// Execute
-	items, total, err := repo.GetAllItems(1, 10)
+	items, total, err := repo.GetAllItems(context.Background(), 1, 10, false)
 
 	// Assert
 	assert.Error(t, err)
@@ -367,7 +434,7 @@ func TestCreateItems_Error(t *testing.T) {
 	mockRow.On("Scan", mock.Anything).Return(errors.New("duplicate key error"))
 
 	// Execute
-	err := repo.CreateItems(newItem)
+	err := repo.CreateItems(context.Background(), newItem)
 
 	// Assert
 	assert.Error(t, err)
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type PasswordResetsRepository interface {
+	CreatePasswordReset(ctx context.Context, userId int, tokenHash string, expiredAt time.Time, createdBy *int) error
+	ConsumePasswordReset(ctx context.Context, tokenHash string) (int, error)
+	InvalidatePasswordResets(ctx context.Context, userId int) error
+}
+
+type passwordResetsRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewPasswordResetsRepository(db database.PgxIface, log *zap.Logger) PasswordResetsRepository {
+	return &passwordResetsRepository{db: db, Logger: log}
+}
+
+func (r *passwordResetsRepository) CreatePasswordReset(ctx context.Context, userId int, tokenHash string, expiredAt time.Time, createdBy *int) error {
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expired_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, userId, tokenHash, expiredAt, createdBy)
+	if err != nil {
+		return apperror.FromDB(err, "password_reset")
+	}
+	return nil
+}
+
+// ConsumePasswordReset menandai token terpakai dan mengembalikan user id-nya.
+// Token yang tidak ada, sudah dipakai, atau kedaluwarsa dianggap tidak ditemukan.
+func (r *passwordResetsRepository) ConsumePasswordReset(ctx context.Context, tokenHash string) (int, error) {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expired_at > NOW()
+		RETURNING user_id
+	`
+	var userId int
+	err := conn(ctx, r.db).QueryRow(ctx, query, tokenHash).Scan(&userId)
+	if err != nil {
+		return 0, apperror.FromDB(err, "password_reset")
+	}
+	return userId, nil
+}
+
+// InvalidatePasswordResets membatalkan semua token user yang belum dipakai
+func (r *passwordResetsRepository) InvalidatePasswordResets(ctx context.Context, userId int) error {
+	query := `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, userId)
+	if err != nil {
+		return apperror.FromDB(err, "password_reset")
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"fmt"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"sort"
+	"strings"
+)
+
+// buildPatch membangun UPDATE dinamis yang hanya men-set kolom di changes.
+// columns adalah whitelist kolom yang boleh di-patch, sehingga nama kolom
+// aman disisipkan ke query; nilainya tetap lewat parameter.
+func buildPatch(table string, columns map[string]bool, changes map[string]any, id, version int, returning string) (string, []any, error) {
+	if len(changes) == 0 {
+		return "", nil, apperror.Validation("no fields to update", nil)
+	}
+
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		if !columns[name] {
+			return "", nil, apperror.Validation(fmt.Sprintf("field %s cannot be updated", name), map[string]string{"field": name})
+		}
+		names = append(names, name)
+	}
+	// urutan kolom stabil agar query mudah dibaca di log/trace
+	sort.Strings(names)
+
+	sets := make([]string, 0, len(names)+2)
+	args := make([]any, 0, len(names)+2)
+	for _, name := range names {
+		args = append(args, changes[name])
+		sets = append(sets, fmt.Sprintf("%s = $%d", name, len(args)))
+	}
+	sets = append(sets, "updated_at = NOW()", "version = version + 1")
+
+	args = append(args, id, version)
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE id = $%d AND ($%d = 0 OR version = $%d) AND deleted_at IS NULL RETURNING %s",
+		table, strings.Join(sets, ", "), len(args)-1, len(args), len(args), returning,
+	)
+	return query, args, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBuildPatch_OnlyProvidedColumns(t *testing.T) {
+	query, args, err := buildPatch("items", itemsPatchColumns, map[string]any{"stock": 7, "name": "Mouse"}, 3, 2, "id, version")
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"UPDATE items SET name = $1, stock = $2, updated_at = NOW(), version = version + 1 WHERE id = $3 AND ($4 = 0 OR version = $4) AND deleted_at IS NULL RETURNING id, version",
+		query)
+	assert.Equal(t, []any{"Mouse", 7, 3, 2}, args)
+}
+
+func TestBuildPatch_RejectsUnknownColumn(t *testing.T) {
+	_, _, err := buildPatch("items", itemsPatchColumns, map[string]any{"id": 99}, 1, 1, "id")
+
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+	assert.Equal(t, "field id cannot be updated", err.Error())
+}
+
+func TestBuildPatch_RejectsEmptyChanges(t *testing.T) {
+	_, _, err := buildPatch("items", itemsPatchColumns, map[string]any{}, 1, 1, "id")
+
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+}
+
+func TestPatchItems_Success(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockRow := new(MockRow)
+	repo := NewItemsRepository(mockDB, zap.NewNop())
+
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, []any{25, 1, 4}).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[0].(*int) = 1
+		*dest[3].(*string) = "Laptop"
+		*dest[5].(*int) = 25
+		*dest[10].(*int) = 5
+	}).Return(nil)
+
+	item, err := repo.PatchItems(context.Background(), 1, 4, map[string]any{"stock": 25})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Laptop", item.Name)
+	assert.Equal(t, 25, item.Stock)
+	assert.Equal(t, 5, item.Version)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPatchItems_NotFound(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockRow := new(MockRow)
+	repo := NewItemsRepository(mockDB, zap.NewNop())
+
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+
+	item, err := repo.PatchItems(context.Background(), 999, 0, map[string]any{"stock": 1})
+
+	assert.Nil(t, item)
+	assert.True(t, apperror.IsNotFound(err))
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/model"
+
+	"go.uber.org/zap"
+)
+
+// SaleBalance adalah total dan jumlah yang sudah dibayar pada satu sale
+type SaleBalance struct {
+	TotalAmount model.Money
+	PaidAmount  model.Money
+	Status      string
+}
+
+type PaymentsRepository interface {
+	GetSaleBalance(ctx context.Context, saleId int) (*SaleBalance, error)
+	LockSaleBalance(ctx context.Context, saleId int) (*SaleBalance, error)
+	CreatePayment(ctx context.Context, payment *model.Payments) error
+	UpdateSalePayment(ctx context.Context, saleId int, paid model.Money, status string) error
+	GetPaymentsBySale(ctx context.Context, saleId int) ([]model.Payments, error)
+}
+
+type paymentsRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewPaymentsRepository(db database.PgxIface, log *zap.Logger) PaymentsRepository {
+	return &paymentsRepository{db: db, Logger: log}
+}
+
+func (r *paymentsRepository) GetSaleBalance(ctx context.Context, saleId int) (*SaleBalance, error) {
+	return r.saleBalance(ctx, `SELECT total_amount, paid_amount, status FROM sales WHERE id = $1`, saleId)
+}
+
+// LockSaleBalance mengunci baris sale sampai transaksi selesai, supaya dua
+// pembayaran bersamaan tidak sama-sama melihat sisa tagihan yang lama
+func (r *paymentsRepository) LockSaleBalance(ctx context.Context, saleId int) (*SaleBalance, error) {
+	return r.saleBalance(ctx, `SELECT total_amount, paid_amount, status FROM sales WHERE id = $1 FOR UPDATE`, saleId)
+}
+
+func (r *paymentsRepository) saleBalance(ctx context.Context, query string, saleId int) (*SaleBalance, error) {
+	var balance SaleBalance
+	err := conn(ctx, r.db).QueryRow(ctx, query, saleId).Scan(&balance.TotalAmount, &balance.PaidAmount, &balance.Status)
+	if err != nil {
+		return nil, apperror.FromDB(err, "sale")
+	}
+	return &balance, nil
+}
+
+func (r *paymentsRepository) CreatePayment(ctx context.Context, payment *model.Payments) error {
+	query := `
+		INSERT INTO payments (sale_id, user_id, method, amount, tendered, change_amount, reference, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING id, created_at
+	`
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		payment.SaleId,
+		payment.UserId,
+		payment.Method,
+		payment.Amount,
+		payment.Tendered,
+		payment.Change,
+		payment.Reference,
+	).Scan(&payment.Id, &payment.CreatedAt)
+	return apperror.FromDB(err, "payment")
+}
+
+func (r *paymentsRepository) UpdateSalePayment(ctx context.Context, saleId int, paid model.Money, status string) error {
+	query := `UPDATE sales SET paid_amount = $1, payment_status = $2 WHERE id = $3`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, paid, status, saleId)
+	if err != nil {
+		return apperror.FromDB(err, "sale")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("sale")
+	}
+	return nil
+}
+
+func (r *paymentsRepository) GetPaymentsBySale(ctx context.Context, saleId int) ([]model.Payments, error) {
+	query := `
+		SELECT id, sale_id, user_id, method, amount, tendered, change_amount, reference, created_at
+		FROM payments
+		WHERE sale_id = $1
+		ORDER BY id
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, saleId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []model.Payments{}
+	for rows.Next() {
+		var p model.Payments
+		err := rows.Scan(&p.Id, &p.SaleId, &p.UserId, &p.Method, &p.Amount, &p.Tendered, &p.Change, &p.Reference, &p.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type PurgeRepository interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (map[string]int64, error)
+}
+
+type purgeRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewPurgeRepository(db database.PgxIface, log *zap.Logger) PurgeRepository {
+	return &purgeRepository{db: db, Logger: log}
+}
+
+// purgeTargets diurutkan dari anak ke induk supaya induk yang anaknya baru
+// di-purge ikut terhapus pada putaran yang sama. Baris yang masih direferensikan
+// (misal item yang ada di riwayat penjualan) dibiarkan tetap ada.
+var purgeTargets = []struct {
+	table      string
+	referenced string
+}{
+	{"items", "SELECT 1 FROM sale_items WHERE sale_items.item_id = items.id UNION ALL SELECT 1 FROM stock_reservation_items WHERE stock_reservation_items.item_id = items.id"},
+	{"racks", "SELECT 1 FROM items WHERE items.rack_id = racks.id"},
+	{"categories", "SELECT 1 FROM items WHERE items.category_id = categories.id"},
+	{"warehouses", "SELECT 1 FROM racks WHERE racks.warehouse_id = warehouses.id UNION ALL SELECT 1 FROM sales WHERE sales.warehouse_id = warehouses.id"},
+	{"users", "SELECT 1 FROM sales WHERE sales.user_id = users.id OR sales.voided_by = users.id UNION ALL SELECT 1 FROM payments WHERE payments.user_id = users.id UNION ALL SELECT 1 FROM stock_reservations WHERE stock_reservations.created_by = users.id"},
+	{"customers", "SELECT 1 FROM sales WHERE sales.customer_id = customers.id"},
+}
+
+// PurgeDeleted menghapus permanen data yang di-soft delete sebelum waktu before.
+// Mengembalikan jumlah baris yang terhapus per tabel.
+func (r *purgeRepository) PurgeDeleted(ctx context.Context, before time.Time) (map[string]int64, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+
+	purged := make(map[string]int64, len(purgeTargets))
+	for _, target := range purgeTargets {
+		query := "DELETE FROM " + target.table +
+			" WHERE deleted_at IS NOT NULL AND deleted_at < $1 AND NOT EXISTS (" + target.referenced + ")"
+
+		result, err := r.db.Exec(ctx, query, before)
+		if err != nil {
+			log.Error("failed to purge soft deleted rows",
+				zap.String("table", target.table),
+				zap.Error(err),
+			)
+			return purged, err
+		}
+		purged[target.table] = result.RowsAffected()
+	}
+
+	return purged, nil
+}
@@ -2,20 +2,22 @@ package repository
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/database"
 	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
 
 	"go.uber.org/zap"
 )
 
 type RacksRepository interface {
-	GetRacksById(id int) (*model.Racks, error)
-	GetAllRacks(page, limit int) ([]model.Racks, int, error)
-	CreateRacks(data *model.Racks) error
-	UpdateRacks(id int, data *model.Racks) error
-	DeleteRacks(id int) error
+	GetRacksById(ctx context.Context, id int) (*model.Racks, error)
+	GetAllRacks(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Racks, int, error)
+	CreateRacks(ctx context.Context, data *model.Racks) error
+	UpdateRacks(ctx context.Context, id int, data *model.Racks) error
+	PatchRacks(ctx context.Context, id, version int, changes map[string]any) (*model.Racks, error)
+	DeleteRacks(ctx context.Context, id int) error
+	RestoreRacks(ctx context.Context, id int) (*model.Racks, error)
 }
 
 type racksRepository struct {
@@ -27,46 +29,49 @@ func NewRacksRepository(db database.PgxIface, log *zap.Logger) RacksRepository {
 	return &racksRepository{db: db, Logger: log}
 }
 
-func (r *racksRepository) GetRacksById(id int) (*model.Racks, error) {
+func (r *racksRepository) GetRacksById(ctx context.Context, id int) (*model.Racks, error) {
 	query := `
-		SELECT id, warehouse_id, name, created_at, updated_at
+		SELECT id, warehouse_id, name, created_at, updated_at, version
 		FROM racks
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	var rack model.Racks
-	err := r.db.QueryRow(context.Background(), query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&rack.Id,
 		&rack.WarehouseId,
 		&rack.Name,
 		&rack.CreatedAt,
 		&rack.UpdatedAt,
+		&rack.Version,
 	)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("rack not found")
+	if err != nil {
+		return nil, apperror.FromDB(err, "rack")
 	}
-	return &rack, err
+	return &rack, nil
 }
 
-func (r *racksRepository) GetAllRacks(page, limit int) ([]model.Racks, int, error) {
+func (r *racksRepository) GetAllRacks(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Racks, int, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	offset := (page - 1) * limit
 
 	// get total data for pagination
 	var total int
-	countQuery := `SELECT COUNT(*) FROM racks`
-	err := r.db.QueryRow(context.Background(), countQuery).Scan(&total)
+	countQuery := `SELECT COUNT(*) FROM racks WHERE ($1 OR deleted_at IS NULL)`
+	err := conn(ctx, r.db).QueryRow(ctx, countQuery, includeDeleted).Scan(&total)
 	if err != nil {
-		r.Logger.Error("error query findall repo ", zap.Error(err))
+		log.Error("error query findall repo ", zap.Error(err))
 		return nil, 0, err
 	}
 
 	// get data with pagination
 	query := `
-		SELECT id, warehouse_id, name, created_at, updated_at
+		SELECT id, warehouse_id, name, created_at, updated_at, version, deleted_at
 		FROM racks
+		WHERE ($3 OR deleted_at IS NULL)
 		ORDER BY id
 		LIMIT $1 OFFSET $2
 	`
-	rows, err := r.db.Query(context.Background(), query, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, offset, includeDeleted)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -81,6 +86,8 @@ func (r *racksRepository) GetAllRacks(page, limit int) ([]model.Racks, int, erro
 			&rack.Name,
 			&rack.CreatedAt,
 			&rack.UpdatedAt,
+			&rack.Version,
+			&rack.DeletedAt,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -91,46 +98,65 @@ func (r *racksRepository) GetAllRacks(page, limit int) ([]model.Racks, int, erro
 	return racks, total, nil
 }
 
-func (r *racksRepository) CreateRacks(data *model.Racks) error {
+func (r *racksRepository) CreateRacks(ctx context.Context, data *model.Racks) error {
 	query := `
 		INSERT INTO racks (warehouse_id, name, created_at, updated_at)
 		VALUES ($1, $2, NOW(), NOW())
-		RETURNING id
+		RETURNING id, version
 	`
-	err := r.db.QueryRow(context.Background(), query, data.WarehouseId, data.Name).Scan(&data.Id)
-	return err
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.WarehouseId, data.Name).Scan(&data.Id, &data.Version)
+	return apperror.FromDB(err, "rack")
 }
 
-func (r *racksRepository) UpdateRacks(id int, data *model.Racks) error {
+func (r *racksRepository) UpdateRacks(ctx context.Context, id int, data *model.Racks) error {
 	query := `
 		UPDATE racks
-		SET warehouse_id = $1, name = $2, updated_at = NOW()
-		WHERE id = $3`
-
-	result, err := r.db.Exec(context.Background(), query, data.WarehouseId, data.Name, id)
-	if err != nil {
-		return err
-	}
-	rowAffected := result.RowsAffected()
+		SET warehouse_id = $1, name = $2, updated_at = NOW(), version = version + 1
+		WHERE id = $3 AND ($4 = 0 OR version = $4) AND deleted_at IS NULL
+		RETURNING version`
 
-	if rowAffected == 0 {
-		return errors.New("no rows affected")
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.WarehouseId, data.Name, id, data.Version).Scan(&data.Version)
+	if apperror.IsNoRows(err) {
+		return resolveUpdateMiss(ctx, r.db, "racks", "rack", id)
 	}
-	return err
+	return apperror.FromDB(err, "rack")
 }
 
-func (r *racksRepository) DeleteRacks(id int) error {
-	query := `DELETE FROM racks WHERE id = $1`
+var racksPatchColumns = map[string]bool{
+	"warehouse_id": true,
+	"name":         true,
+}
 
-	result, err := r.db.Exec(context.Background(), query, id)
+func (r *racksRepository) PatchRacks(ctx context.Context, id, version int, changes map[string]any) (*model.Racks, error) {
+	query, args, err := buildPatch("racks", racksPatchColumns, changes, id, version,
+		"id, warehouse_id, name, created_at, updated_at, version")
 	if err != nil {
-		return err
+		return nil, err
 	}
-	rowAffected := result.RowsAffected()
 
-	if rowAffected == 0 {
-		return errors.New("no rows affected")
+	var rack model.Racks
+	err = conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&rack.Id, &rack.WarehouseId, &rack.Name, &rack.CreatedAt, &rack.UpdatedAt, &rack.Version)
+	if apperror.IsNoRows(err) {
+		return nil, resolveUpdateMiss(ctx, r.db, "racks", "rack", id)
 	}
+	if err != nil {
+		return nil, apperror.FromDB(err, "rack")
+	}
+	return &rack, nil
+}
 
-	return err
+func (r *racksRepository) DeleteRacks(ctx context.Context, id int) error {
+	return softDelete(ctx, r.db, "racks", "rack", id,
+		"SELECT 1 FROM items WHERE items.rack_id = racks.id AND items.deleted_at IS NULL")
 }
+
+func (r *racksRepository) RestoreRacks(ctx context.Context, id int) (*model.Racks, error) {
+	var rack model.Racks
+	err := restoreDeleted(ctx, r.db, "racks", "rack", id,
+		"id, warehouse_id, name, created_at, updated_at, version",
+		&rack.Id, &rack.WarehouseId, &rack.Name, &rack.CreatedAt, &rack.UpdatedAt, &rack.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &rack, nil
+}
\ No newline at end of file
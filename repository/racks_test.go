@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"database/sql"
 	"errors"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -37,7 +40,7 @@ func TestGetRacksById_Success(t *testing.T) {
 		*dest[4].(*time.Time) = expectedRack.UpdatedAt
 	}).Return(nil)
 
-	result, err := repo.GetRacksById(1)
+	result, err := repo.GetRacksById(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -55,7 +58,7 @@ func TestGetRacksById_NotFound(t *testing.T) {
 	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
 	mockRow.On("Scan", mock.Anything).Return(sql.ErrNoRows)
 
-	result, err := repo.GetRacksById(999)
+	result, err := repo.GetRacksById(context.Background(), 999)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -103,7 +106,7 @@ func TestGetAllRacks_Success(t *testing.T) {
 	mockRows.On("Close").Return()
 	mockRows.On("Err").Return(nil)
 
-	racks, total, err := repo.GetAllRacks(1, 10)
+	racks, total, err := repo.GetAllRacks(context.Background(), 1, 10, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, total)
@@ -128,7 +131,7 @@ func TestCreateRacks_Success(t *testing.T) {
 		*dest[0].(*int) = 1
 	}).Return(nil)
 
-	err := repo.CreateRacks(rack)
+	err := repo.CreateRacks(context.Background(), rack)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, rack.Id)
@@ -144,12 +147,17 @@ func TestUpdateRacks_Success(t *testing.T) {
 		Name:        "Updated Rack",
 	}
 
-	mockTag := MockCommandTag{rowsAffected: 1}
-	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[0].(*int) = 3 // versi baru
+	}).Return(nil)
 
-	err := repo.UpdateRacks(1, rack)
+	err := repo.UpdateRacks(context.Background(), 1, rack)
 
 	assert.NoError(t, err)
+	assert.Equal(t, 3, rack.Version)
 }
 
 func TestUpdateRacks_NoRowsAffected(t *testing.T) {
@@ -162,13 +170,16 @@ func TestUpdateRacks_NoRowsAffected(t *testing.T) {
 		Name:        "Updated Rack",
 	}
 
-	mockTag := MockCommandTag{rowsAffected: 0}
-	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
+	// UPDATE tidak mengenai baris dan lookup versi juga kosong -> not found
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
 
-	err := repo.UpdateRacks(999, rack)
+	err := repo.UpdateRacks(context.Background(), 999, rack)
 
 	assert.Error(t, err)
-	assert.Equal(t, "no rows affected", err.Error())
+	assert.Equal(t, "rack not found", err.Error())
+	assert.True(t, apperror.IsNotFound(err))
 }
 
 func TestDeleteRacks_Success(t *testing.T) {
@@ -179,7 +190,7 @@ func TestDeleteRacks_Success(t *testing.T) {
 	mockTag := MockCommandTag{rowsAffected: 1}
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
 
-	err := repo.DeleteRacks(1)
+	err := repo.DeleteRacks(context.Background(), 1)
 
 	assert.NoError(t, err)
 }
@@ -192,10 +203,18 @@ func TestDeleteRacks_NoRowsAffected(t *testing.T) {
 	mockTag := MockCommandTag{rowsAffected: 0}
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
 
-	err := repo.DeleteRacks(999)
+	// cek keberadaan rack aktif setelah UPDATE tidak mengenai baris
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*bool) = false
+	}).Return(nil)
+
+	err := repo.DeleteRacks(context.Background(), 999)
 
 	assert.Error(t, err)
-	assert.Equal(t, "no rows affected", err.Error())
+	assert.Equal(t, "rack not found", err.Error())
+	assert.True(t, apperror.IsNotFound(err))
 }
 
 func TestCreateRacks_Error(t *testing.T) {
@@ -213,7 +232,7 @@ func TestCreateRacks_Error(t *testing.T) {
 	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
 	mockRow.On("Scan", mock.Anything).Return(errors.New("foreign key constraint failed"))
 
-	err := repo.CreateRacks(rack)
+	err := repo.CreateRacks(context.Background(), rack)
 
 	assert.Error(t, err)
 	assert.Equal(t, "foreign key constraint failed", err.Error())
@@ -230,10 +249,11 @@ func TestUpdateRacks_Error(t *testing.T) {
 		Name:        "Updated Rack",
 	}
 
-	mockTag := MockCommandTag{rowsAffected: 0}
-	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, errors.New("database connection error"))
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Return(errors.New("database connection error"))
 
-	err := repo.UpdateRacks(1, rack)
+	err := repo.UpdateRacks(context.Background(), 1, rack)
 
 	assert.Error(t, err)
 	assert.Equal(t, "database connection error", err.Error())
@@ -248,7 +268,7 @@ func TestDeleteRacks_Error(t *testing.T) {
 	mockTag := MockCommandTag{rowsAffected: 0}
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, errors.New("foreign key violation"))
 
-	err := repo.DeleteRacks(1)
+	err := repo.DeleteRacks(context.Background(), 1)
 
 	assert.Error(t, err)
 	assert.Equal(t, "foreign key violation", err.Error())
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+
+	"go.uber.org/zap"
+)
+
+// ReceiptInfo melengkapi sale dengan nama-nama untuk dicetak di struk
+type ReceiptInfo struct {
+	Cashier           string
+	Customer          string
+	Warehouse         string
+	WarehouseLocation string
+	// ItemNames dikunci dengan id sale_item
+	ItemNames map[int]string
+}
+
+type ReceiptsRepository interface {
+	GetReceiptInfo(ctx context.Context, saleId int) (*ReceiptInfo, error)
+}
+
+type receiptsRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewReceiptsRepository(db database.PgxIface, log *zap.Logger) ReceiptsRepository {
+	return &receiptsRepository{db: db, Logger: log}
+}
+
+// GetReceiptInfo: item, customer dan gudang yang sudah di-soft delete tetap
+// ditampilkan dengan namanya, karena struk mencetak ulang transaksi lama
+func (r *receiptsRepository) GetReceiptInfo(ctx context.Context, saleId int) (*ReceiptInfo, error) {
+	query := `
+		SELECT COALESCE(u.username, ''), COALESCE(c.name, ''), COALESCE(w.name, ''), COALESCE(w.location, '')
+		FROM sales s
+		LEFT JOIN users u ON u.id = s.user_id
+		LEFT JOIN customers c ON c.id = s.customer_id
+		LEFT JOIN warehouses w ON w.id = s.warehouse_id
+		WHERE s.id = $1
+	`
+	info := ReceiptInfo{ItemNames: map[int]string{}}
+	err := conn(ctx, r.db).QueryRow(ctx, query, saleId).Scan(&info.Cashier, &info.Customer, &info.Warehouse, &info.WarehouseLocation)
+	if err != nil {
+		return nil, apperror.FromDB(err, "sale")
+	}
+
+	queryItems := `
+		SELECT si.id, i.name
+		FROM sale_items si
+		JOIN items i ON i.id = si.item_id
+		WHERE si.sale_id = $1
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, queryItems, saleId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		info.ItemNames[id] = name
+	}
+	return &info, rows.Err()
+}
@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -19,14 +23,64 @@ type SalesReport struct {
 }
 
 type RevenueReport struct {
-	TotalRevenue float64 `json:"total_revenue"`
-	AveragePerTransaction float64 `json:"average_per_transaction"`
+	TotalRevenue model.Money `json:"total_revenue"`
+	AveragePerTransaction model.Money `json:"average_per_transaction"`
+	TotalDiscount model.Money `json:"total_discount"`
+	TotalTax model.Money `json:"total_tax"`
 }
 
+// CustomerPurchaseReport merangkum riwayat pembelian satu customer
+type CustomerPurchaseReport struct {
+	CustomerId            int                   `json:"customer_id"`
+	Name                  string                `json:"name"`
+	TotalTransactions     int                   `json:"total_transactions"`
+	TotalItemsBought      int                   `json:"total_items_bought"`
+	TotalSpent            model.Money               `json:"total_spent"`
+	AveragePerTransaction model.Money               `json:"average_per_transaction"`
+	CreditLimit           model.Money               `json:"credit_limit"`
+	CreditOutstanding     model.Money               `json:"credit_outstanding"`
+	FirstPurchaseAt       *time.Time            `json:"first_purchase_at"`
+	LastPurchaseAt        *time.Time            `json:"last_purchase_at"`
+	TopItems              []CustomerItemSummary `json:"top_items"`
+}
+
+type CustomerItemSummary struct {
+	ItemId     int     `json:"item_id"`
+	Name       string  `json:"name"`
+	Quantity   int     `json:"quantity"`
+	TotalSpent model.Money `json:"total_spent"`
+}
+
+// CashDrawerReport merangkum pembayaran satu hari per kasir untuk rekonsiliasi laci kas
+type CashDrawerReport struct {
+	Date  string           `json:"date"`
+	Users []CashDrawerUser `json:"users"`
+}
+
+// CashDrawerUser: ExpectedCash = CashReceived - ChangeGiven, yaitu uang yang
+// seharusnya bertambah di laci kasir (di luar modal awal)
+type CashDrawerUser struct {
+	UserId       int         `json:"user_id"`
+	Username     string      `json:"username"`
+	Payments     int         `json:"payments"`
+	CashReceived model.Money `json:"cash_received"`
+	ChangeGiven  model.Money `json:"change_given"`
+	ExpectedCash model.Money `json:"expected_cash"`
+	Card         model.Money `json:"card"`
+	QRIS         model.Money `json:"qris"`
+	Transfer     model.Money `json:"transfer"`
+	Total        model.Money `json:"total"`
+}
+
+// customerTopItemsLimit adalah jumlah item terbanyak dibeli yang ditampilkan di report customer
+const customerTopItemsLimit = 5
+
 type ReportsRepository interface {
-	GetItemsReport() (*ItemsReport, error)
-	GetSalesReport() (*SalesReport, error)
-	GetRevenueReport() (*RevenueReport, error)
+	GetItemsReport(ctx context.Context) (*ItemsReport, error)
+	GetSalesReport(ctx context.Context) (*SalesReport, error)
+	GetRevenueReport(ctx context.Context) (*RevenueReport, error)
+	GetCustomerReport(ctx context.Context, customerId int) (*CustomerPurchaseReport, error)
+	GetCashDrawerReport(ctx context.Context, date time.Time, userId *int) (*CashDrawerReport, error)
 }
 
 type reportsRepository struct {
@@ -38,69 +92,206 @@ func NewReportsRepository(db database.PgxIface, log *zap.Logger) ReportsReposito
 	return &reportsRepository{db: db, Logger: log}
 }
 
-func (r *reportsRepository) GetItemsReport() (*ItemsReport, error) {
+func (r *reportsRepository) GetItemsReport(ctx context.Context) (*ItemsReport, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	query := `
 		SELECT 
-			(SELECT COUNT(*) FROM items) as total_items,
-			(SELECT COALESCE(SUM(stock), 0) FROM items) as total_stock,
-			(SELECT COUNT(*) FROM items WHERE stock < min_stock) as low_stock_items
+			(SELECT COUNT(*) FROM items WHERE deleted_at IS NULL) as total_items,
+			(SELECT COALESCE(SUM(stock), 0) FROM items WHERE deleted_at IS NULL) as total_stock,
+			(SELECT COUNT(*) FROM items WHERE stock < min_stock AND deleted_at IS NULL) as low_stock_items
 	`
 
 	var report ItemsReport
-	err := r.db.QueryRow(context.Background(), query).Scan(
+	err := r.db.QueryRow(ctx, query).Scan(
 		&report.TotalItems,
 		&report.TotalStock,
 		&report.LowStockItems,
 	)
 
 	if err != nil {
-		r.Logger.Error("failed to get items report", zap.Error(err))
+		log.Error("failed to get items report", zap.Error(err))
 		return nil, err
 	}
 
 	return &report, nil
 }
 
-func (r *reportsRepository) GetSalesReport() (*SalesReport, error) {
+func (r *reportsRepository) GetSalesReport(ctx context.Context) (*SalesReport, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	query := `
 		SELECT 
-			(SELECT COUNT(*) FROM sales) as total_transactions,
-			(SELECT COALESCE(SUM(quantity), 0) FROM sale_items) as total_items_sold
+			(SELECT COUNT(*) FROM sales WHERE status = 'completed') as total_transactions,
+			(SELECT COALESCE(SUM(si.quantity), 0) FROM sale_items si
+				JOIN sales s ON s.id = si.sale_id WHERE s.status = 'completed') as total_items_sold
 	`
 
 	var report SalesReport
-	err := r.db.QueryRow(context.Background(), query).Scan(
+	err := r.db.QueryRow(ctx, query).Scan(
 		&report.TotalTransactions,
 		&report.TotalItemsSold,
 	)
 
 	if err != nil {
-		r.Logger.Error("failed to get sales report", zap.Error(err))
+		log.Error("failed to get sales report", zap.Error(err))
 		return nil, err
 	}
 
 	return &report, nil
 }
 
-func (r *reportsRepository) GetRevenueReport() (*RevenueReport, error) {
+func (r *reportsRepository) GetRevenueReport(ctx context.Context) (*RevenueReport, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	query := `
 		SELECT 
 			COALESCE(SUM(total_amount), 0) as total_revenue,
 			CASE 
 				WHEN COUNT(*) > 0 THEN COALESCE(SUM(total_amount), 0) / COUNT(*)
 				ELSE 0
-			END as average_per_transaction
+			END as average_per_transaction,
+			COALESCE(SUM(discount_amount), 0) as total_discount,
+			COALESCE(SUM(tax_amount), 0) as total_tax
 		FROM sales
+		WHERE status = 'completed'
 	`
 
 	var report RevenueReport
-	err := r.db.QueryRow(context.Background(), query).Scan(
+	err := r.db.QueryRow(ctx, query).Scan(
 		&report.TotalRevenue,
 		&report.AveragePerTransaction,
+		&report.TotalDiscount,
+		&report.TotalTax,
+	)
+
+	if err != nil {
+		log.Error("failed to get revenue report", zap.Error(err))
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// GetCustomerReport juga berlaku untuk customer yang sudah dihapus, karena riwayatnya tetap ada
+func (r *reportsRepository) GetCustomerReport(ctx context.Context, customerId int) (*CustomerPurchaseReport, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	query := `
+		SELECT
+			c.id,
+			c.name,
+			c.credit_limit,
+			COUNT(s.id) as total_transactions,
+			COALESCE(SUM(s.total_amount), 0) as total_spent,
+			CASE
+				WHEN COUNT(s.id) > 0 THEN COALESCE(SUM(s.total_amount), 0) / COUNT(s.id)
+				ELSE 0
+			END as average_per_transaction,
+			COALESCE(SUM(s.total_amount - s.paid_amount) FILTER (WHERE s.payment_type = 'credit'), 0) as credit_outstanding,
+			MIN(s.created_at) as first_purchase_at,
+			MAX(s.created_at) as last_purchase_at,
+			(SELECT COALESCE(SUM(si.quantity), 0)
+				FROM sale_items si JOIN sales s2 ON s2.id = si.sale_id
+				WHERE s2.customer_id = c.id AND s2.status = 'completed') as total_items_bought
+		FROM customers c
+		LEFT JOIN sales s ON s.customer_id = c.id AND s.status = 'completed'
+		WHERE c.id = $1
+		GROUP BY c.id
+	`
+
+	report := CustomerPurchaseReport{TopItems: []CustomerItemSummary{}}
+	err := r.db.QueryRow(ctx, query, customerId).Scan(
+		&report.CustomerId,
+		&report.Name,
+		&report.CreditLimit,
+		&report.TotalTransactions,
+		&report.TotalSpent,
+		&report.AveragePerTransaction,
+		&report.CreditOutstanding,
+		&report.FirstPurchaseAt,
+		&report.LastPurchaseAt,
+		&report.TotalItemsBought,
 	)
+	if err != nil {
+		if !apperror.IsNoRows(err) {
+			log.Error("failed to get customer report", zap.Error(err))
+		}
+		return nil, apperror.FromDB(err, "customer")
+	}
+
+	topQuery := `
+		SELECT si.item_id, i.name, SUM(si.quantity) as quantity, SUM(si.total) as total_spent
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		JOIN items i ON i.id = si.item_id
+		WHERE s.customer_id = $1 AND s.status = 'completed'
+		GROUP BY si.item_id, i.name
+		ORDER BY quantity DESC, si.item_id
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, topQuery, customerId, customerTopItemsLimit)
+	if err != nil {
+		log.Error("failed to get customer top items", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item CustomerItemSummary
+		if err := rows.Scan(&item.ItemId, &item.Name, &item.Quantity, &item.TotalSpent); err != nil {
+			return nil, err
+		}
+		report.TopItems = append(report.TopItems, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
 
+// GetCashDrawerReport menghitung pembayaran pada tanggal date (jam server), per
+// user yang mencatat pembayaran; userId nil berarti semua kasir. Pembayaran sale
+// voided tidak dihitung karena uangnya dikembalikan ke customer.
+func (r *reportsRepository) GetCashDrawerReport(ctx context.Context, date time.Time, userId *int) (*CashDrawerReport, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	query := `
+		SELECT
+			p.user_id,
+			u.username,
+			COUNT(*) as payments,
+			COALESCE(SUM(p.tendered) FILTER (WHERE p.method = 'cash'), 0) as cash_received,
+			COALESCE(SUM(p.change_amount) FILTER (WHERE p.method = 'cash'), 0) as change_given,
+			COALESCE(SUM(p.amount) FILTER (WHERE p.method = 'cash'), 0) as expected_cash,
+			COALESCE(SUM(p.amount) FILTER (WHERE p.method = 'card'), 0) as card,
+			COALESCE(SUM(p.amount) FILTER (WHERE p.method = 'qris'), 0) as qris,
+			COALESCE(SUM(p.amount) FILTER (WHERE p.method = 'transfer'), 0) as transfer,
+			SUM(p.amount) as total
+		FROM payments p
+		JOIN sales s ON s.id = p.sale_id
+		JOIN users u ON u.id = p.user_id
+		WHERE p.created_at >= $1::date AND p.created_at < $1::date + 1
+			AND s.status <> 'voided'
+			AND ($2::int IS NULL OR p.user_id = $2)
+		GROUP BY p.user_id, u.username
+		ORDER BY u.username
+	`
+	day := date.Format(time.DateOnly)
+	rows, err := r.db.Query(ctx, query, day, userId)
 	if err != nil {
-		r.Logger.Error("failed to get revenue report", zap.Error(err))
+		log.Error("failed to get cash drawer report", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := CashDrawerReport{Date: day, Users: []CashDrawerUser{}}
+	for rows.Next() {
+		var u CashDrawerUser
+		err := rows.Scan(&u.UserId, &u.Username, &u.Payments, &u.CashReceived, &u.ChangeGiven,
+			&u.ExpectedCash, &u.Card, &u.QRIS, &u.Transfer, &u.Total)
+		if err != nil {
+			return nil, err
+		}
+		report.Users = append(report.Users, u)
+	}
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
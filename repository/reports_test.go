@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetCashDrawerReport_ExcludesVoidedSales(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockRows := new(MockRows)
+	repo := NewReportsRepository(mockDB, zap.NewNop())
+
+	// pembayaran sale voided sudah dikembalikan, jadi tidak masuk laci kas
+	mockDB.On("Query", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "JOIN sales s ON s.id = p.sale_id") &&
+			strings.Contains(query, "s.status <> 'voided'")
+	}), []any{"2026-10-19", (*int)(nil)}).Return(mockRows, nil)
+	mockRows.On("Next").Return(false)
+	mockRows.On("Err").Return(nil)
+	mockRows.On("Close").Return()
+
+	report, err := repo.GetCashDrawerReport(context.Background(), time.Date(2026, 10, 19, 15, 0, 0, 0, time.Local), nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-19", report.Date)
+	assert.Empty(t, report.Users)
+	mockDB.AssertExpectations(t)
+}
@@ -13,7 +13,19 @@ type Repository struct {
 	WarehousesRepo *warehousesRepository
 	UsersRepo *usersRepository
 	SalesRepo *salesRepository
+	CustomersRepo *customersRepository
+	PaymentsRepo *paymentsRepository
+	ReceiptsRepo *receiptsRepository
 	ReportsRepo *reportsRepository
+	PurgeRepo *purgeRepository
+	AuditRepo *auditRepository
+	SessionsRepo *sessionsRepository
+	PasswordResetsRepo *passwordResetsRepository
+	TwoFactorRepo *twoFactorRepository
+	APIKeysRepo *apiKeysRepository
+	IdempotencyRepo *idempotencyRepository
+	ReservationsRepo *reservationsRepository
+	Transactor Transactor
 }
 
 func NewRepository(db database.PgxIface, log *zap.Logger) Repository {
@@ -24,6 +36,18 @@ func NewRepository(db database.PgxIface, log *zap.Logger) Repository {
 		WarehousesRepo: &warehousesRepository{db: db, Logger: log},
 		UsersRepo: &usersRepository{db: db, Logger: log},
 		SalesRepo: &salesRepository{db: db, Logger: log},
+		CustomersRepo: &customersRepository{db: db, Logger: log},
+		PaymentsRepo: &paymentsRepository{db: db, Logger: log},
+		ReceiptsRepo: &receiptsRepository{db: db, Logger: log},
 		ReportsRepo: &reportsRepository{db: db, Logger: log},
+		PurgeRepo: &purgeRepository{db: db, Logger: log},
+		AuditRepo: &auditRepository{db: db, Logger: log},
+		SessionsRepo: &sessionsRepository{db: db, Logger: log},
+		PasswordResetsRepo: &passwordResetsRepository{db: db, Logger: log},
+		TwoFactorRepo: &twoFactorRepository{db: db, Logger: log},
+		APIKeysRepo: &apiKeysRepository{db: db, Logger: log},
+		IdempotencyRepo: &idempotencyRepository{db: db, Logger: log},
+		ReservationsRepo: &reservationsRepository{db: db, Logger: log},
+		Transactor: NewTransactor(db),
 	}
 }
\ No newline at end of file
@@ -0,0 +1,384 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ReservationsRepository interface {
+	GetReservationById(ctx context.Context, id int) (*model.StockReservation, error)
+	GetAllReservations(ctx context.Context, filter ReservationFilter, page, limit int) ([]model.StockReservation, int, error)
+	CreateReservation(ctx context.Context, reservation *model.StockReservation, ttl time.Duration) error
+	ConfirmReservation(ctx context.Context, id int) error
+	ReleaseReservation(ctx context.Context, id int) error
+	ExpireReservations(ctx context.Context) (int64, error)
+}
+
+// ReservationFilter untuk list reservasi; field kosong berarti tidak difilter
+type ReservationFilter struct {
+	Status    string
+	Reference string
+}
+
+type reservationsRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewReservationsRepository(db database.PgxIface, log *zap.Logger) ReservationsRepository {
+	return &reservationsRepository{db: db, Logger: log}
+}
+
+func (r *reservationsRepository) GetReservationById(ctx context.Context, id int) (*model.StockReservation, error) {
+	query := `
+		SELECT id, reference, status, expires_at, created_by, created_at, updated_at
+		FROM stock_reservations
+		WHERE id = $1
+	`
+	var res model.StockReservation
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
+		&res.Id,
+		&res.Reference,
+		&res.Status,
+		&res.ExpiresAt,
+		&res.CreatedBy,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+	if err != nil {
+		return nil, apperror.FromDB(err, "reservation")
+	}
+
+	itemsByReservation, err := r.getReservationItems(ctx, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	res.Items = itemsByReservation[id]
+	return &res, nil
+}
+
+// GetAllReservations mengembalikan satu halaman reservasi beserta item-nya (satu
+// query item untuk semua reservasi di halaman itu)
+func (r *reservationsRepository) GetAllReservations(ctx context.Context, filter ReservationFilter, page, limit int) ([]model.StockReservation, int, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	offset := (page - 1) * limit
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM stock_reservations WHERE ($1 = '' OR status = $1) AND ($2 = '' OR reference = $2)`
+	err := conn(ctx, r.db).QueryRow(ctx, countQuery, filter.Status, filter.Reference).Scan(&total)
+	if err != nil {
+		log.Error("error query count reservations", zap.Error(err))
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, reference, status, expires_at, created_by, created_at, updated_at
+		FROM stock_reservations
+		WHERE ($3 = '' OR status = $3) AND ($4 = '' OR reference = $4)
+		ORDER BY id DESC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, offset, filter.Status, filter.Reference)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reservations := []model.StockReservation{}
+	for rows.Next() {
+		var res model.StockReservation
+		err := rows.Scan(&res.Id, &res.Reference, &res.Status, &res.ExpiresAt, &res.CreatedBy, &res.CreatedAt, &res.UpdatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		reservations = append(reservations, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(reservations) == 0 {
+		return reservations, total, nil
+	}
+
+	ids := make([]int, len(reservations))
+	for i, res := range reservations {
+		ids[i] = res.Id
+	}
+	itemsByReservation, err := r.getReservationItems(ctx, ids)
+	if err != nil {
+		log.Error("error querying reservation items", zap.Error(err))
+		return nil, 0, err
+	}
+	for i := range reservations {
+		reservations[i].Items = itemsByReservation[reservations[i].Id]
+	}
+	return reservations, total, nil
+}
+
+func (r *reservationsRepository) getReservationItems(ctx context.Context, ids []int) (map[int][]model.StockReservationItem, error) {
+	query := `
+		SELECT id, reservation_id, item_id, quantity
+		FROM stock_reservation_items
+		WHERE reservation_id = ANY($1)
+		ORDER BY reservation_id, id
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	itemsByReservation := make(map[int][]model.StockReservationItem, len(ids))
+	for rows.Next() {
+		var item model.StockReservationItem
+		if err := rows.Scan(&item.Id, &item.ReservationId, &item.ItemId, &item.Quantity); err != nil {
+			return nil, err
+		}
+		itemsByReservation[item.ReservationId] = append(itemsByReservation[item.ReservationId], item)
+	}
+	return itemsByReservation, rows.Err()
+}
+
+// CreateReservation menyimpan reservasi active sampai LOCALTIMESTAMP + ttl dan
+// memesan stock yang belum dipesan sale held / reservasi lain. Item di
+// reservation.Items harus unik (dijumlahkan di service). Semua item dipesan
+// dengan satu UPDATE, jadi reservasi bersamaan tidak bisa memesan melebihi stock.
+func (r *reservationsRepository) CreateReservation(ctx context.Context, reservation *model.StockReservation, ttl time.Duration) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	queryInsert := `
+		INSERT INTO stock_reservations (reference, status, expires_at, created_by)
+		VALUES ($1, 'active', LOCALTIMESTAMP + $2::interval, $3)
+		RETURNING id, status, expires_at, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, queryInsert, reservation.Reference, ttl, reservation.CreatedBy).Scan(
+		&reservation.Id,
+		&reservation.Status,
+		&reservation.ExpiresAt,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+	if err != nil {
+		log.Error("failed to insert reservation", zap.Error(err))
+		err = apperror.FromDB(err, "reservation")
+		return err
+	}
+
+	itemIds := make([]int, len(reservation.Items))
+	quantities := make([]int, len(reservation.Items))
+	for i, item := range reservation.Items {
+		itemIds[i] = item.ItemId
+		quantities[i] = item.Quantity
+	}
+
+	queryItems := `
+		INSERT INTO stock_reservation_items (reservation_id, item_id, quantity)
+		SELECT $1, unnest($2::int[]), unnest($3::int[])
+	`
+	_, err = tx.Exec(ctx, queryItems, reservation.Id, itemIds, quantities)
+	if err != nil {
+		log.Error("failed to insert reservation items", zap.Error(err))
+		err = apperror.FromDB(err, "item")
+		return err
+	}
+
+	err = updateStock(ctx, tx, log, queryReserveStock, itemIds, quantities)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	log.Info("stock reserved",
+		zap.Int("reservation_id", reservation.Id),
+		zap.String("reference", reservation.Reference),
+		zap.Duration("ttl", ttl))
+	return nil
+}
+
+// ConfirmReservation mengurangi stock sebesar quantity reservasi dan melepas
+// pesanannya. Reservasi yang sudah lewat expires_at ditolak walaupun sweeper
+// belum melepasnya.
+func (r *reservationsRepository) ConfirmReservation(ctx context.Context, id int) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	status, expired, err := lockReservation(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status != model.ReservationActive {
+		err = apperror.ReservationNotActive(status)
+		return err
+	}
+	if expired {
+		err = apperror.ReservationExpired()
+		return err
+	}
+
+	itemIds, quantities, err := reservationQuantities(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	err = updateStock(ctx, tx, log, queryCommitReserved, itemIds, quantities)
+	if err != nil {
+		return err
+	}
+
+	err = r.setStatus(ctx, tx, id, model.ReservationConfirmed)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	log.Info("reservation confirmed", zap.Int("reservation_id", id))
+	return nil
+}
+
+// ReleaseReservation melepas stock yang dipesan reservasi active
+func (r *reservationsRepository) ReleaseReservation(ctx context.Context, id int) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	status, _, err := lockReservation(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status != model.ReservationActive {
+		err = apperror.ReservationNotActive(status)
+		return err
+	}
+
+	err = r.setStatus(ctx, tx, id, model.ReservationReleased)
+	if err != nil {
+		return err
+	}
+	queryRelease := `
+		UPDATE items
+		SET reserved = items.reserved - data.quantity
+		FROM stock_reservation_items AS data
+		WHERE data.reservation_id = $1 AND items.id = data.item_id
+	`
+	_, err = tx.Exec(ctx, queryRelease, id)
+	if err != nil {
+		log.Error("failed to release reserved stock", zap.Error(err))
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	log.Info("reservation released", zap.Int("reservation_id", id))
+	return nil
+}
+
+// ExpireReservations menandai reservasi active yang melewati expires_at sebagai
+// expired dan melepas stock-nya dalam satu statement. Reservasi yang sedang
+// di-confirm bersamaan terkunci, jadi statusnya sudah confirmed saat dicek ulang.
+func (r *reservationsRepository) ExpireReservations(ctx context.Context) (int64, error) {
+	query := `
+		WITH expired AS (
+			UPDATE stock_reservations
+			SET status = 'expired', updated_at = LOCALTIMESTAMP
+			WHERE status = 'active' AND expires_at <= LOCALTIMESTAMP
+			RETURNING id
+		), released AS (
+			UPDATE items
+			SET reserved = items.reserved - data.qty
+			FROM (
+				SELECT ri.item_id, SUM(ri.quantity) AS qty
+				FROM stock_reservation_items ri
+				JOIN expired e ON e.id = ri.reservation_id
+				GROUP BY ri.item_id
+			) AS data
+			WHERE items.id = data.item_id
+		)
+		SELECT COUNT(*) FROM expired
+	`
+	var expired int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query).Scan(&expired); err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
+
+func (r *reservationsRepository) setStatus(ctx context.Context, tx database.PgxIface, id int, status string) error {
+	_, err := tx.Exec(ctx, `UPDATE stock_reservations SET status = $2, updated_at = LOCALTIMESTAMP WHERE id = $1`, id, status)
+	if err != nil {
+		utils.LoggerFromContext(ctx, r.Logger).Error("failed to update reservation status", zap.String("status", status), zap.Error(err))
+		return apperror.FromDB(err, "reservation")
+	}
+	return nil
+}
+
+// lockReservation mengunci baris reservasi sampai transaksi selesai dan
+// mengembalikan statusnya serta apakah expires_at sudah lewat
+func lockReservation(ctx context.Context, tx database.PgxIface, id int) (string, bool, error) {
+	var status string
+	var expired bool
+	query := `SELECT status, expires_at <= LOCALTIMESTAMP FROM stock_reservations WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(ctx, query, id).Scan(&status, &expired); err != nil {
+		return "", false, apperror.FromDB(err, "reservation")
+	}
+	return status, expired, nil
+}
+
+// reservationQuantities mengembalikan quantity per item satu reservasi, urut item_id
+func reservationQuantities(ctx context.Context, tx database.PgxIface, id int) ([]int, []int, error) {
+	query := `
+		SELECT COALESCE(array_agg(item_id ORDER BY item_id), '{}'), COALESCE(array_agg(quantity ORDER BY item_id), '{}')
+		FROM stock_reservation_items
+		WHERE reservation_id = $1
+	`
+	var itemIds, quantities []int
+	if err := tx.QueryRow(ctx, query, id).Scan(&itemIds, &quantities); err != nil {
+		return nil, nil, err
+	}
+	return itemIds, quantities, nil
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// mockReservationTx menyiapkan transaksi dengan baris reservasi terkunci
+// (status, expired) dan quantity per item-nya
+func mockReservationTx(status string, expired bool, itemIds, quantities []int) (*MockPgxIface, *MockTx) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+
+	lockRow := new(MockRow)
+	mockTx.On("QueryRow", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "FROM stock_reservations WHERE id = $1 FOR UPDATE")
+	}), []interface{}{7}).Return(lockRow)
+	lockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[0].(*string) = status
+		*dest[1].(*bool) = expired
+	}).Return(nil)
+
+	quantityRow := new(MockRow)
+	mockTx.On("QueryRow", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "array_agg(quantity ORDER BY item_id)")
+	}), []interface{}{7}).Return(quantityRow)
+	quantityRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[0].(*[]int) = itemIds
+		*dest[1].(*[]int) = quantities
+	}).Return(nil)
+	return mockDB, mockTx
+}
+
+func mockReservationInsert(mockTx *MockTx) {
+	headerRow := new(MockRow)
+	mockTx.On("QueryRow", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "INSERT INTO stock_reservations")
+	}), []interface{}{"WEB-1001", 15 * time.Minute, 2}).Return(headerRow)
+	headerRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[0].(*int) = 7
+		*dest[1].(*string) = model.ReservationActive
+	}).Return(nil)
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "INSERT INTO stock_reservation_items")
+	}), []interface{}{7, []int{3, 4}, []int{2, 1}}).Return(pgconn.NewCommandTag("INSERT 0 2"), nil)
+}
+
+func newTestReservation() *model.StockReservation {
+	return &model.StockReservation{
+		Reference: "WEB-1001",
+		CreatedBy: 2,
+		Items:     []model.StockReservationItem{{ItemId: 3, Quantity: 2}, {ItemId: 4, Quantity: 1}},
+	}
+}
+
+func TestCreateReservation_ReservesAvailableStock(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+	repo := NewReservationsRepository(mockDB, zap.NewNop())
+
+	mockReservationInsert(mockTx)
+	// hanya stock yang belum dipesan sale held / reservasi lain yang bisa dipesan
+	mockStockUpdate(mockTx, "items.stock - items.reserved >= data.qty", []int{3, 4})
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	reservation := newTestReservation()
+	err := repo.CreateReservation(context.Background(), reservation, 15*time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, reservation.Id)
+	assert.Equal(t, model.ReservationActive, reservation.Status)
+	mockTx.AssertExpectations(t)
+}
+
+func TestCreateReservation_InsufficientStock(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+	repo := NewReservationsRepository(mockDB, zap.NewNop())
+
+	mockReservationInsert(mockTx)
+	mockStockUpdate(mockTx, "SET reserved = reserved + data.qty", []int{3})
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.CreateReservation(context.Background(), newTestReservation(), 15*time.Minute)
+
+	assert.True(t, apperror.Is(err, apperror.KindInsufficientStock))
+	assert.Equal(t, map[string]any{"item_ids": []int{4}}, err.(*apperror.Error).Details)
+	mockTx.AssertCalled(t, "Rollback", mock.Anything)
+	mockTx.AssertNotCalled(t, "Commit", mock.Anything)
+}
+
+func TestCreateReservation_DuplicateActiveReference(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+	repo := NewReservationsRepository(mockDB, zap.NewNop())
+
+	headerRow := new(MockRow)
+	mockTx.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(headerRow)
+	headerRow.On("Scan", mock.Anything).Return(&pgconn.PgError{Code: "23505", ConstraintName: "stock_reservations_active_reference_key"})
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.CreateReservation(context.Background(), newTestReservation(), 15*time.Minute)
+
+	assert.Equal(t, "reservation_already_exists", apperror.Code(err))
+	mockTx.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmReservation_CommitsReservedStock(t *testing.T) {
+	mockDB, mockTx := mockReservationTx(model.ReservationActive, false, []int{3}, []int{2})
+	repo := NewReservationsRepository(mockDB, zap.NewNop())
+
+	mockStockUpdate(mockTx, "reserved = reserved - data.qty", []int{3})
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "UPDATE stock_reservations SET status = $2")
+	}), []interface{}{7, model.ReservationConfirmed}).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	err := repo.ConfirmReservation(context.Background(), 7)
+
+	assert.NoError(t, err)
+	mockTx.AssertExpectations(t)
+}
+
+func TestConfirmReservation_Expired(t *testing.T) {
+	mockDB, mockTx := mockReservationTx(model.ReservationActive, true, nil, nil)
+	repo := NewReservationsRepository(mockDB, zap.NewNop())
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.ConfirmReservation(context.Background(), 7)
+
+	assert.Equal(t, "reservation_expired", apperror.Code(err))
+	mockTx.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmReservation_AlreadyReleased(t *testing.T) {
+	mockDB, mockTx := mockReservationTx(model.ReservationReleased, false, nil, nil)
+	repo := NewReservationsRepository(mockDB, zap.NewNop())
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.ConfirmReservation(context.Background(), 7)
+
+	assert.Equal(t, "reservation_not_active", apperror.Code(err))
+	assert.True(t, apperror.Is(err, apperror.KindConflict))
+	mockTx.AssertCalled(t, "Rollback", mock.Anything)
+}
+
+func TestReleaseReservation(t *testing.T) {
+	mockDB, mockTx := mockReservationTx(model.ReservationActive, false, nil, nil)
+	repo := NewReservationsRepository(mockDB, zap.NewNop())
+
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "UPDATE stock_reservations SET status = $2")
+	}), []interface{}{7, model.ReservationReleased}).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "SET reserved = items.reserved - data.quantity")
+	}), []interface{}{7}).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	err := repo.ReleaseReservation(context.Background(), 7)
+
+	assert.NoError(t, err)
+	mockTx.AssertNumberOfCalls(t, "Exec", 2)
+	mockTx.AssertCalled(t, "Commit", mock.Anything)
+}
+
+func TestExpireReservations(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockRow := new(MockRow)
+	repo := NewReservationsRepository(mockDB, zap.NewNop())
+
+	mockDB.On("QueryRow", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "expires_at <= LOCALTIMESTAMP") && strings.Contains(query, "reserved = items.reserved - data.qty")
+	}), []interface{}(nil)).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*int64) = 2
+	}).Return(nil)
+
+	expired, err := repo.ExpireReservations(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), expired)
+}
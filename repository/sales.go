@@ -2,22 +2,45 @@ package repository
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"fmt"
 	"project-app-inventory-restapi-golang-azwin/database"
 	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 type SalesRepository interface {
-	GetSalesById(id int) (*model.Sales, []model.SaleItems, error)
-	GetAllSales(page, limit int) ([]model.Sales, int, error)
-	CreateSales(sale *model.Sales, items []model.SaleItems) error
-	UpdateSales(id int, data *model.Sales) error
-	DeleteSales(id int) error
+	GetSalesById(ctx context.Context, id int) (*model.Sales, []model.SaleItems, error)
+	GetAllSales(ctx context.Context, filter SalesFilter, page, limit int, includeItems bool) ([]model.Sales, int, error)
+	GetSalesByCustomer(ctx context.Context, customerId, page, limit int) ([]model.Sales, int, error)
+	GetItemTaxes(ctx context.Context, itemIds []int) (map[int]ItemTax, error)
+	NextInvoiceNo(ctx context.Context, warehouseId int) (int, time.Time, error)
+	CreateSales(ctx context.Context, sale *model.Sales, items []model.SaleItems) error
+	UpdateSales(ctx context.Context, id int, data *model.Sales, items []model.SaleItems) error
+	DeleteSales(ctx context.Context, id int) error
+	VoidSale(ctx context.Context, id, userId int, reason string) error
+	HoldSale(ctx context.Context, id int, ttl time.Duration) error
+	ReleaseHold(ctx context.Context, id int) error
+	CompleteSale(ctx context.Context, id int, invoiceNo string) error
+	ExpireHolds(ctx context.Context) (int64, error)
+}
+
+// SalesFilter untuk list sales; field kosong berarti tidak difilter
+type SalesFilter struct {
+	CustomerId *int
+	// InvoiceNo dicocokkan sebagai prefix, misal "INV/2026/10"
+	InvoiceNo string
+	Status    string
+}
+
+// ItemTax adalah tarif pajak efektif item (tarif item, atau tarif category jika item tidak punya)
+type ItemTax struct {
+	Rate      model.TaxRate
+	Inclusive bool
 }
 
 type salesRepository struct {
@@ -29,34 +52,46 @@ func NewSalesRepository(db database.PgxIface, log *zap.Logger) SalesRepository {
 	return &salesRepository{db: db, Logger: log}
 }
 
-func (r *salesRepository) GetSalesById(id int) (*model.Sales, []model.SaleItems, error) {
+func (r *salesRepository) GetSalesById(ctx context.Context, id int) (*model.Sales, []model.SaleItems, error) {
 	// Get sales data
 	queryS := `
-		SELECT id, user_id, total_amount, created_at
+		SELECT id, user_id, total_amount, created_at, customer_id, payment_type, subtotal, discount_amount, tax_amount, paid_amount, payment_status, COALESCE(invoice_no, ''), warehouse_id,
+			status, voided_at, voided_by, COALESCE(void_reason, ''), hold_expires_at
 		FROM sales
 		WHERE id = $1
 	`
 	var s model.Sales
-	err := r.db.QueryRow(context.Background(), queryS, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, queryS, id).Scan(
 		&s.Id,
 		&s.UserId,
 		&s.TotalAmount,
 		&s.CreatedAt,
+		&s.CustomerId,
+		&s.PaymentType,
+		&s.Subtotal,
+		&s.DiscountAmount,
+		&s.TaxAmount,
+		&s.PaidAmount,
+		&s.PaymentStatus,
+		&s.InvoiceNo,
+		&s.WarehouseId,
+		&s.Status,
+		&s.VoidedAt,
+		&s.VoidedBy,
+		&s.VoidReason,
+		&s.HoldExpiresAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, nil, errors.New("sale not found")
-	}
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, apperror.FromDB(err, "sale")
 	}
 
 	// Get sale items
 	queryItems := `
-		SELECT id, sale_id, item_id, quantity, price, subtotal
+		SELECT id, sale_id, item_id, quantity, price, subtotal, discount_amount, tax_rate, tax_inclusive, tax_amount, total
 		FROM sale_items
 		WHERE sale_id = $1
 	`
-	rows, err := r.db.Query(context.Background(), queryItems, id)
+	rows, err := conn(ctx, r.db).Query(ctx, queryItems, id)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -72,6 +107,11 @@ func (r *salesRepository) GetSalesById(id int) (*model.Sales, []model.SaleItems,
 			&item.Quantity,
 			&item.Price,
 			&item.Subtotal,
+			&item.DiscountAmount,
+			&item.TaxRate,
+			&item.TaxInclusive,
+			&item.TaxAmount,
+			&item.Total,
 		)
 		if err != nil {
 			return nil, nil, err
@@ -82,26 +122,43 @@ func (r *salesRepository) GetSalesById(id int) (*model.Sales, []model.SaleItems,
 	return &s, items, nil
 }
 
-func (r *salesRepository) GetAllSales(page, limit int) ([]model.Sales, int, error) {
+// GetAllSales mengembalikan satu halaman sales. Jika includeItems true, item dari
+// semua sale di halaman itu diambil dengan satu query (bukan satu query per sale).
+func (r *salesRepository) GetAllSales(ctx context.Context, filter SalesFilter, page, limit int, includeItems bool) ([]model.Sales, int, error) {
+	return r.listSales(ctx, filter, page, limit, includeItems)
+}
+
+// GetSalesByCustomer mengembalikan riwayat penjualan satu customer, selalu dengan item
+func (r *salesRepository) GetSalesByCustomer(ctx context.Context, customerId, page, limit int) ([]model.Sales, int, error) {
+	return r.listSales(ctx, SalesFilter{CustomerId: &customerId}, page, limit, true)
+}
+
+func (r *salesRepository) listSales(ctx context.Context, filter SalesFilter, page, limit int, includeItems bool) ([]model.Sales, int, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	offset := (page - 1) * limit
+	invoicePrefix := escapeLike(filter.InvoiceNo) + "%"
 
 	// get total data for pagination
 	var total int
-	countQuery := `SELECT COUNT(*) FROM sales`
-	err := r.db.QueryRow(context.Background(), countQuery).Scan(&total)
+	countQuery := `
+		SELECT COUNT(*) FROM sales
+		WHERE ($1::int IS NULL OR customer_id = $1) AND COALESCE(invoice_no, '') LIKE $2 AND ($3 = '' OR status = $3)`
+	err := conn(ctx, r.db).QueryRow(ctx, countQuery, filter.CustomerId, invoicePrefix, filter.Status).Scan(&total)
 	if err != nil {
-		r.Logger.Error("error query count sales", zap.Error(err))
+		log.Error("error query count sales", zap.Error(err))
 		return nil, 0, err
 	}
 
 	// get data with pagination
 	query := `
-		SELECT id, user_id, total_amount, created_at
+		SELECT id, user_id, total_amount, created_at, customer_id, payment_type, subtotal, discount_amount, tax_amount, paid_amount, payment_status, COALESCE(invoice_no, ''), warehouse_id,
+			status, voided_at, voided_by, COALESCE(void_reason, ''), hold_expires_at
 		FROM sales
+		WHERE ($3::int IS NULL OR customer_id = $3) AND COALESCE(invoice_no, '') LIKE $4 AND ($5 = '' OR status = $5)
 		ORDER BY id DESC
 		LIMIT $1 OFFSET $2
 	`
-	rows, err := r.db.Query(context.Background(), query, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, offset, filter.CustomerId, invoicePrefix, filter.Status)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -115,224 +172,774 @@ func (r *salesRepository) GetAllSales(page, limit int) ([]model.Sales, int, erro
 			&s.UserId,
 			&s.TotalAmount,
 			&s.CreatedAt,
+			&s.CustomerId,
+			&s.PaymentType,
+			&s.Subtotal,
+			&s.DiscountAmount,
+			&s.TaxAmount,
+			&s.PaidAmount,
+			&s.PaymentStatus,
+			&s.InvoiceNo,
+			&s.WarehouseId,
+			&s.Status,
+			&s.VoidedAt,
+			&s.VoidedBy,
+			&s.VoidReason,
+			&s.HoldExpiresAt,
 		)
 		if err != nil {
 			return nil, 0, err
 		}
 		sales = append(sales, s)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if !includeItems || len(sales) == 0 {
+		return sales, total, nil
+	}
 
-	// Fetch sale items for each sale
+	saleIds := make([]int, len(sales))
+	for i, sale := range sales {
+		saleIds[i] = sale.Id
+	}
+	itemsBySale, err := r.getSaleItemsBySaleIds(ctx, saleIds)
+	if err != nil {
+		log.Error("error querying sale items", zap.Error(err))
+		return nil, 0, err
+	}
 	for i := range sales {
-		itemsQuery := `
-			SELECT id, sale_id, item_id, quantity, price, subtotal
-			FROM sale_items
-			WHERE sale_id = $1
-		`
-		itemRows, err := r.db.Query(context.Background(), itemsQuery, sales[i].Id)
+		sales[i].Items = itemsBySale[sales[i].Id]
+	}
+
+	return sales, total, nil
+}
+
+// getSaleItemsBySaleIds mengambil item untuk beberapa sale sekaligus, dikelompokkan per sale_id
+func (r *salesRepository) getSaleItemsBySaleIds(ctx context.Context, saleIds []int) (map[int][]model.SaleItems, error) {
+	query := `
+		SELECT id, sale_id, item_id, quantity, price, subtotal, discount_amount, tax_rate, tax_inclusive, tax_amount, total
+		FROM sale_items
+		WHERE sale_id = ANY($1)
+		ORDER BY sale_id, id
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, saleIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	itemsBySale := make(map[int][]model.SaleItems, len(saleIds))
+	for rows.Next() {
+		var item model.SaleItems
+		err := rows.Scan(
+			&item.Id,
+			&item.SaleId,
+			&item.ItemId,
+			&item.Quantity,
+			&item.Price,
+			&item.Subtotal,
+			&item.DiscountAmount,
+			&item.TaxRate,
+			&item.TaxInclusive,
+			&item.TaxAmount,
+			&item.Total,
+		)
 		if err != nil {
-			r.Logger.Error("error querying sale items", zap.Error(err))
-			continue
+			return nil, err
 		}
+		itemsBySale[item.SaleId] = append(itemsBySale[item.SaleId], item)
+	}
+	return itemsBySale, rows.Err()
+}
 
-		var items []model.SaleItems
-		for itemRows.Next() {
-			var item model.SaleItems
-			err := itemRows.Scan(
-				&item.Id,
-				&item.SaleId,
-				&item.ItemId,
-				&item.Quantity,
-				&item.Price,
-				&item.Subtotal,
-			)
-			if err != nil {
-				r.Logger.Error("error scanning sale item", zap.Error(err))
-				continue
-			}
-			items = append(items, item)
+// GetItemTaxes mengembalikan tarif pajak efektif untuk item yang masih aktif;
+// item yang tidak ditemukan tidak ada di map
+func (r *salesRepository) GetItemTaxes(ctx context.Context, itemIds []int) (map[int]ItemTax, error) {
+	query := `
+		SELECT i.id, COALESCE(i.tax_rate, c.tax_rate), COALESCE(i.tax_inclusive, c.tax_inclusive)
+		FROM items i
+		JOIN categories c ON c.id = i.category_id
+		WHERE i.id = ANY($1) AND i.deleted_at IS NULL
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, itemIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taxes := make(map[int]ItemTax, len(itemIds))
+	for rows.Next() {
+		var id int
+		var tax ItemTax
+		if err := rows.Scan(&id, &tax.Rate, &tax.Inclusive); err != nil {
+			return nil, err
 		}
-		itemRows.Close()
-		sales[i].Items = items
+		taxes[id] = tax
 	}
+	return taxes, rows.Err()
+}
 
-	return sales, total, nil
+// NextInvoiceNo menaikkan counter invoice tahun berjalan (warehouseId 0 = seri
+// global) dan mengembalikan nomor urut beserta waktu transaksi. Harus dipanggil
+// di transaksi yang sama dengan insert sale: baris counter terkunci sampai
+// commit, dan rollback ikut membatalkan nomornya sehingga tidak ada celah.
+func (r *salesRepository) NextInvoiceNo(ctx context.Context, warehouseId int) (int, time.Time, error) {
+	query := `
+		INSERT INTO invoice_counters (year, warehouse_id, last_no)
+		VALUES (EXTRACT(YEAR FROM LOCALTIMESTAMP)::int, $1, 1)
+		ON CONFLICT (year, warehouse_id) DO UPDATE SET last_no = invoice_counters.last_no + 1
+		RETURNING last_no, LOCALTIMESTAMP
+	`
+	var seq int
+	var at time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, query, warehouseId).Scan(&seq, &at)
+	if err != nil {
+		return 0, time.Time{}, apperror.FromDB(err, "invoice_counter")
+	}
+	return seq, at, nil
 }
 
-func (r *salesRepository) CreateSales(sale *model.Sales, items []model.SaleItems) error {
+func (r *salesRepository) CreateSales(ctx context.Context, sale *model.Sales, items []model.SaleItems) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	// Start Transaction
-	tx, err := r.db.Begin(context.Background())
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
-		r.Logger.Error("failed to begin transaction", zap.Error(err))
+		log.Error("failed to begin transaction", zap.Error(err))
 		return err
 	}
 	defer func() {
 		if err != nil {
-			tx.Rollback(context.Background())
-			r.Logger.Error("transaction rolled back", zap.Error(err))
+			tx.Rollback(ctx)
+			log.Error("transaction rolled back", zap.Error(err))
 		}
 	}()
 
 	// Insert Sales
 	querySales := `
-		INSERT INTO sales (user_id, total_amount, created_at)
-		VALUES ($1, $2, NOW())
-		RETURNING id
+		INSERT INTO sales (user_id, total_amount, created_at, customer_id, payment_type, subtotal, discount_amount, tax_amount, invoice_no, warehouse_id, status)
+		VALUES ($1, $2, NOW(), $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10)
+		RETURNING id, created_at
 	`
 	var saleId int
-	err = tx.QueryRow(context.Background(), querySales,
+	var createdAt time.Time
+	err = tx.QueryRow(ctx, querySales,
 		sale.UserId,
 		sale.TotalAmount,
-	).Scan(&saleId)
+		sale.CustomerId,
+		sale.PaymentType,
+		sale.Subtotal,
+		sale.DiscountAmount,
+		sale.TaxAmount,
+		sale.InvoiceNo,
+		sale.WarehouseId,
+		sale.Status,
+	).Scan(&saleId, &createdAt)
 
 	if err != nil {
-		r.Logger.Error("failed to insert sales", zap.Error(err))
+		log.Error("failed to insert sales", zap.Error(err))
+		return apperror.FromDB(err, "sale")
+	}
+
+	err = insertSaleItems(ctx, tx, log, saleId, items)
+	if err != nil {
 		return err
 	}
 
-	// Batch INSERT sale_items
-	var valueStrings []string
-	var valueArgs []interface{}
-	argPosition := 1
+	// draft/quote tidak menyentuh stock; held dipesan lewat HoldSale
+	if sale.Status == model.SaleCompleted {
+		itemIds, quantities := sumQuantities(items)
+		err = updateStock(ctx, tx, log, queryDecrementStock, itemIds, quantities)
+		if err != nil {
+			return err
+		}
+	}
 
-	for _, item := range items {
-		subtotal := float64(item.Quantity) * item.Price
-		valueStrings = append(valueStrings,
-			fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)",
-				argPosition, argPosition+1, argPosition+2,
-				argPosition+3, argPosition+4))
+	// Commit Transaction
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
 
-		valueArgs = append(valueArgs, saleId, item.ItemId,
-			item.Quantity, item.Price, subtotal)
-		argPosition += 5
+	sale.Id = saleId
+	sale.CreatedAt = createdAt
+	log.Info("sales created successfully",
+		zap.Int("sale_id", saleId),
+		zap.Int("items_count", len(items)))
+	return nil
+}
+
+// UpdateSales mengganti header dan semua baris sale_items dalam satu transaksi.
+// Hanya draft/quote yang bisa diubah: sale held sudah memesan stock dan sale
+// completed sudah mengurangi stock serta punya nomor invoice.
+func (r *salesRepository) UpdateSales(ctx context.Context, id int, data *model.Sales, items []model.SaleItems) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return err
 	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
 
-	querySaleItems := fmt.Sprintf(`
-		INSERT INTO sale_items (sale_id, item_id, quantity, price, subtotal)
-		VALUES %s
-	`, strings.Join(valueStrings, ", "))
+	status, err := lockSaleStatus(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case model.SaleDraft, model.SaleQuote:
+	case model.SaleVoided:
+		err = apperror.SaleVoided()
+		return err
+	default:
+		err = apperror.InvalidSaleStatus(status, model.SaleDraft+" or "+model.SaleQuote)
+		return err
+	}
 
-	_, err = tx.Exec(context.Background(), querySaleItems, valueArgs...)
+	query := `
+		UPDATE sales
+		SET user_id = $1, total_amount = $2, subtotal = $3, discount_amount = $4, tax_amount = $5,
+			payment_status = CASE WHEN paid_amount >= $2 THEN 'paid' WHEN paid_amount > 0 THEN 'partial' ELSE 'unpaid' END,
+			customer_id = $6, payment_type = $7, warehouse_id = $8
+		WHERE id = $9`
+	_, err = tx.Exec(ctx, query, data.UserId, data.TotalAmount, data.Subtotal, data.DiscountAmount, data.TaxAmount,
+		data.CustomerId, data.PaymentType, data.WarehouseId, id)
 	if err != nil {
-		r.Logger.Error("failed to batch insert sale items", zap.Error(err))
+		log.Error("failed to update sales", zap.Error(err))
+		err = apperror.FromDB(err, "sale")
 		return err
 	}
 
-	// Batch UPDATE stock
-	var itemIds []int
-	var quantities []int
+	_, err = tx.Exec(ctx, `DELETE FROM sale_items WHERE sale_id = $1`, id)
+	if err != nil {
+		log.Error("failed to delete sale items", zap.Error(err))
+		return err
+	}
+	err = insertSaleItems(ctx, tx, log, id, items)
+	if err != nil {
+		return err
+	}
 
-	for _, item := range items {
-		itemIds = append(itemIds, item.ItemId)
-		quantities = append(quantities, item.Quantity)
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error("failed to commit transaction", zap.Error(err))
+		return err
 	}
+	return nil
+}
 
-	queryUpdateStock := `
-		WITH updated AS (
-			UPDATE items 
-			SET stock = stock - data.qty
-			FROM (
-				SELECT unnest($1::int[]) as item_id, 
-				       unnest($2::int[]) as qty
-			) AS data
-			WHERE items.id = data.item_id 
-			  AND items.stock >= data.qty
-			RETURNING items.id
-		)
-		SELECT COUNT(*) FROM updated
-	`
+func (r *salesRepository) DeleteSales(ctx context.Context, id int) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	// Start Transaction
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
 
-	var updatedCount int
-	err = tx.QueryRow(context.Background(), queryUpdateStock,
-		itemIds, quantities).Scan(&updatedCount)
+	// Delete sale_items first (foreign key constraint)
+	queryItems := `DELETE FROM sale_items WHERE sale_id = $1`
+	_, err = tx.Exec(ctx, queryItems, id)
+	if err != nil {
+		log.Error("failed to delete sale items", zap.Error(err))
+		return err
+	}
 
+	queryPayments := `DELETE FROM payments WHERE sale_id = $1`
+	_, err = tx.Exec(ctx, queryPayments, id)
 	if err != nil {
-		r.Logger.Error("failed to batch update stock", zap.Error(err))
+		log.Error("failed to delete payments", zap.Error(err))
 		return err
 	}
 
-	// Validate all items updated
-	if updatedCount != len(items) {
-		err = errors.New("insufficient stock for one or more items")
-		r.Logger.Error("stock validation failed",
-			zap.Int("expected", len(items)),
-			zap.Int("updated", updatedCount))
+	// Delete sales; hanya draft (sale lain dibatalkan lewat VoidSale)
+	querySales := `DELETE FROM sales WHERE id = $1 AND status = 'draft'`
+	result, err := tx.Exec(ctx, querySales, id)
+	if err != nil {
+		log.Error("failed to delete sales", zap.Error(err))
 		return err
 	}
 
-	// Commit Transaction
-	err = tx.Commit(context.Background())
+	rowAffected := result.RowsAffected()
+	if rowAffected == 0 {
+		err = apperror.NotFound("sale")
+		return err
+	}
+
+	// Commit
+	err = tx.Commit(ctx)
 	if err != nil {
-		r.Logger.Error("failed to commit transaction", zap.Error(err))
+		log.Error("failed to commit transaction", zap.Error(err))
 		return err
 	}
 
-	sale.Id = saleId
-	r.Logger.Info("sales created successfully",
-		zap.Int("sale_id", saleId),
-		zap.Int("items_count", len(items)))
 	return nil
 }
 
-func (r *salesRepository) UpdateSales(id int, data *model.Sales) error {
-	query := `
+// VoidSale menandai sale completed sebagai voided lalu mengembalikan stock setiap
+// baris, dalam satu transaksi. Sale yang sudah void (termasuk void bersamaan
+// yang menang lebih dulu) ditolak dengan SaleVoided.
+func (r *salesRepository) VoidSale(ctx context.Context, id, userId int, reason string) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	// UPDATE mengunci baris sale, jadi void kedua menunggu lalu tidak menemukan status completed
+	queryVoid := `
 		UPDATE sales
-		SET user_id = $1, total_amount = $2
-		WHERE id = $3`
+		SET status = 'voided', voided_at = NOW(), voided_by = $2, void_reason = $3
+		WHERE id = $1 AND status = 'completed'
+	`
+	result, err := tx.Exec(ctx, queryVoid, id, userId, reason)
+	if err != nil {
+		log.Error("failed to void sale", zap.Error(err))
+		err = apperror.FromDB(err, "sale")
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		var status string
+		err = tx.QueryRow(ctx, `SELECT status FROM sales WHERE id = $1`, id).Scan(&status)
+		if err != nil {
+			err = apperror.FromDB(err, "sale")
+			return err
+		}
+		if status == model.SaleVoided {
+			err = apperror.SaleVoided()
+		} else {
+			err = apperror.InvalidSaleStatus(status, model.SaleCompleted)
+		}
+		return err
+	}
 
-	result, err := r.db.Exec(context.Background(), query, data.UserId, data.TotalAmount, id)
+	// kembalikan stock, termasuk item yang sudah di-soft delete
+	queryRestock := `
+		UPDATE items
+		SET stock = items.stock + data.qty
+		FROM (
+			SELECT item_id, SUM(quantity) AS qty
+			FROM sale_items
+			WHERE sale_id = $1
+			GROUP BY item_id
+		) AS data
+		WHERE items.id = data.item_id
+	`
+	restock, err := tx.Exec(ctx, queryRestock, id)
 	if err != nil {
+		log.Error("failed to restore stock", zap.Error(err))
 		return err
 	}
-	rowAffected := result.RowsAffected()
 
-	if rowAffected == 0 {
-		return errors.New("no rows affected")
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error("failed to commit transaction", zap.Error(err))
+		return err
 	}
-	return err
+
+	log.Info("sale voided",
+		zap.Int("sale_id", id),
+		zap.Int64("items_restocked", restock.RowsAffected()))
+	return nil
 }
 
-func (r *salesRepository) DeleteSales(id int) error {
-	// Start Transaction
-	tx, err := r.db.Begin(context.Background())
+// HoldSale memesan stock untuk sale draft/quote sampai LOCALTIMESTAMP + ttl.
+// Sale yang sudah held hanya diperpanjang masa hold-nya, stock tidak dipesan
+// dua kali. Stock yang sudah dipesan sale held lain tidak bisa dipakai.
+func (r *salesRepository) HoldSale(ctx context.Context, id int, ttl time.Duration) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
-		r.Logger.Error("failed to begin transaction", zap.Error(err))
+		log.Error("failed to begin transaction", zap.Error(err))
 		return err
 	}
 	defer func() {
 		if err != nil {
-			tx.Rollback(context.Background())
+			tx.Rollback(ctx)
 		}
 	}()
 
-	// Delete sale_items first (foreign key constraint)
-	queryItems := `DELETE FROM sale_items WHERE sale_id = $1`
-	_, err = tx.Exec(context.Background(), queryItems, id)
+	status, err := lockSaleStatus(ctx, tx, id)
 	if err != nil {
-		r.Logger.Error("failed to delete sale items", zap.Error(err))
+		return err
+	}
+	switch status {
+	case model.SaleDraft, model.SaleQuote, model.SaleHeld:
+	case model.SaleVoided:
+		err = apperror.SaleVoided()
+		return err
+	default:
+		err = apperror.InvalidSaleStatus(status, model.SaleDraft+" or "+model.SaleQuote)
 		return err
 	}
 
-	// Delete sales
-	querySales := `DELETE FROM sales WHERE id = $1`
-	result, err := tx.Exec(context.Background(), querySales, id)
+	queryHold := `UPDATE sales SET status = 'held', hold_expires_at = LOCALTIMESTAMP + $2::interval WHERE id = $1`
+	_, err = tx.Exec(ctx, queryHold, id, ttl)
 	if err != nil {
-		r.Logger.Error("failed to delete sales", zap.Error(err))
+		log.Error("failed to hold sale", zap.Error(err))
+		err = apperror.FromDB(err, "sale")
 		return err
 	}
 
-	rowAffected := result.RowsAffected()
-	if rowAffected == 0 {
-		err = errors.New("no rows affected")
+	if status != model.SaleHeld {
+		var itemIds, quantities []int
+		itemIds, quantities, err = saleQuantities(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		err = updateStock(ctx, tx, log, queryReserveStock, itemIds, quantities)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error("failed to commit transaction", zap.Error(err))
 		return err
 	}
 
-	// Commit
-	err = tx.Commit(context.Background())
+	log.Info("sale held", zap.Int("sale_id", id), zap.Duration("ttl", ttl), zap.Bool("extended", status == model.SaleHeld))
+	return nil
+}
+
+// ReleaseHold mengembalikan sale held menjadi draft dan melepas stock yang dipesan
+func (r *salesRepository) ReleaseHold(ctx context.Context, id int) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	status, err := lockSaleStatus(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status != model.SaleHeld {
+		err = apperror.InvalidSaleStatus(status, model.SaleHeld)
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE sales SET status = 'draft', hold_expires_at = NULL WHERE id = $1`, id)
+	if err != nil {
+		log.Error("failed to release sale", zap.Error(err))
+		err = apperror.FromDB(err, "sale")
+		return err
+	}
+	_, err = tx.Exec(ctx, queryReleaseReserved, id)
+	if err != nil {
+		log.Error("failed to release reserved stock", zap.Error(err))
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	log.Info("sale hold released", zap.Int("sale_id", id))
+	return nil
+}
+
+// CompleteSale menyelesaikan sale draft/quote/held dengan nomor invoice yang
+// sudah diambil di transaksi yang sama. Stock dikurangi seperti CreateSales;
+// untuk sale held pesanannya sekaligus dilepas.
+func (r *salesRepository) CompleteSale(ctx context.Context, id int, invoiceNo string) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	status, err := lockSaleStatus(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	query := queryDecrementStock
+	switch status {
+	case model.SaleDraft, model.SaleQuote:
+	case model.SaleHeld:
+		query = queryCommitReserved
+	case model.SaleVoided:
+		err = apperror.SaleVoided()
+		return err
+	default:
+		err = apperror.InvalidSaleStatus(status, model.SaleHeld)
+		return err
+	}
+
+	itemIds, quantities, err := saleQuantities(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	err = updateStock(ctx, tx, log, query, itemIds, quantities)
+	if err != nil {
+		return err
+	}
+
+	queryComplete := `UPDATE sales SET status = 'completed', hold_expires_at = NULL, invoice_no = $2 WHERE id = $1`
+	_, err = tx.Exec(ctx, queryComplete, id, invoiceNo)
+	if err != nil {
+		log.Error("failed to complete sale", zap.Error(err))
+		err = apperror.FromDB(err, "sale")
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		log.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	log.Info("sale completed", zap.Int("sale_id", id), zap.String("from", status), zap.String("invoice_no", invoiceNo))
+	return nil
+}
+
+// ExpireHolds mengembalikan semua sale held yang melewati hold_expires_at
+// menjadi draft dan melepas stock-nya dalam satu statement. Sale yang sedang
+// di-complete bersamaan terkunci, jadi statusnya sudah completed saat dicek ulang.
+func (r *salesRepository) ExpireHolds(ctx context.Context) (int64, error) {
+	query := `
+		WITH expired AS (
+			UPDATE sales
+			SET status = 'draft', hold_expires_at = NULL
+			WHERE status = 'held' AND hold_expires_at <= LOCALTIMESTAMP
+			RETURNING id
+		), released AS (
+			UPDATE items
+			SET reserved = items.reserved - data.qty
+			FROM (
+				SELECT si.item_id, SUM(si.quantity) AS qty
+				FROM sale_items si
+				JOIN expired e ON e.id = si.sale_id
+				GROUP BY si.item_id
+			) AS data
+			WHERE items.id = data.item_id
+		)
+		SELECT COUNT(*) FROM expired
+	`
+	var expired int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query).Scan(&expired); err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
+
+// Perubahan stock per item untuk updateStock; $1 item id, $2 quantity.
+// Baris yang tidak memenuhi syarat tidak ter-update dan dilaporkan sebagai
+// insufficient_stock.
+const (
+	// sale completed: kurangi stock yang tidak dipesan sale held / reservasi
+	// lain (item yang sudah dihapus ikut ditolak)
+	queryDecrementStock = `
+		WITH updated AS (
+			UPDATE items
+			SET stock = stock - data.qty
+			FROM (
+				SELECT unnest($1::int[]) as item_id,
+				       unnest($2::int[]) as qty
+			) AS data
+			WHERE items.id = data.item_id
+			  AND items.stock - items.reserved >= data.qty
+			  AND items.deleted_at IS NULL
+			RETURNING items.id
+		)
+		SELECT COALESCE(array_agg(id), '{}') FROM updated
+	`
+	// sale held / reservasi: pesan dari stock yang belum dipesan
+	queryReserveStock = `
+		WITH updated AS (
+			UPDATE items
+			SET reserved = reserved + data.qty
+			FROM (
+				SELECT unnest($1::int[]) as item_id,
+				       unnest($2::int[]) as qty
+			) AS data
+			WHERE items.id = data.item_id
+			  AND items.stock - items.reserved >= data.qty
+			  AND items.deleted_at IS NULL
+			RETURNING items.id
+		)
+		SELECT COALESCE(array_agg(id), '{}') FROM updated
+	`
+	// held -> completed / reservasi confirmed: stock dikurangi dan pesanannya
+	// dilepas bersamaan
+	queryCommitReserved = `
+		WITH updated AS (
+			UPDATE items
+			SET stock = stock - data.qty, reserved = reserved - data.qty
+			FROM (
+				SELECT unnest($1::int[]) as item_id,
+				       unnest($2::int[]) as qty
+			) AS data
+			WHERE items.id = data.item_id
+			  AND items.reserved >= data.qty
+			  AND items.stock >= data.qty
+			RETURNING items.id
+		)
+		SELECT COALESCE(array_agg(id), '{}') FROM updated
+	`
+	// held -> draft: lepas pesanan satu sale
+	queryReleaseReserved = `
+		UPDATE items
+		SET reserved = items.reserved - data.qty
+		FROM (
+			SELECT item_id, SUM(quantity) AS qty
+			FROM sale_items
+			WHERE sale_id = $1
+			GROUP BY item_id
+		) AS data
+		WHERE items.id = data.item_id
+	`
+)
+
+// updateStock menjalankan salah satu query perubahan stock dan menolak dengan
+// insufficient_stock jika ada item yang tidak ter-update. Dipakai juga oleh
+// reservationsRepository.
+func updateStock(ctx context.Context, tx database.PgxIface, log *zap.Logger, query string, itemIds, quantities []int) error {
+	var updatedIds []int
+	err := tx.QueryRow(ctx, query, itemIds, quantities).Scan(&updatedIds)
 	if err != nil {
-		r.Logger.Error("failed to commit transaction", zap.Error(err))
+		log.Error("failed to batch update stock", zap.Error(err))
 		return err
 	}
 
+	if len(updatedIds) != len(itemIds) {
+		failedIds := missingIds(itemIds, updatedIds)
+		log.Error("stock validation failed",
+			zap.Int("expected", len(itemIds)),
+			zap.Int("updated", len(updatedIds)),
+			zap.Ints("item_ids", failedIds))
+		return apperror.InsufficientStock("insufficient stock for one or more items",
+			map[string]any{"item_ids": failedIds})
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// insertSaleItems batch INSERT sale_items; subtotal, diskon dan pajak sudah dihitung di service
+func insertSaleItems(ctx context.Context, tx database.PgxIface, log *zap.Logger, saleId int, items []model.SaleItems) error {
+	var valueStrings []string
+	var valueArgs []interface{}
+	argPosition := 1
+	const columns = 10
+
+	for _, item := range items {
+		placeholders := make([]string, columns)
+		for i := range placeholders {
+			placeholders[i] = fmt.Sprintf("$%d", argPosition+i)
+		}
+		valueStrings = append(valueStrings, "("+strings.Join(placeholders, ", ")+")")
+
+		valueArgs = append(valueArgs, saleId, item.ItemId,
+			item.Quantity, item.Price, item.Subtotal, item.DiscountAmount,
+			item.TaxRate, item.TaxInclusive, item.TaxAmount, item.Total)
+		argPosition += columns
+	}
+
+	querySaleItems := fmt.Sprintf(`
+		INSERT INTO sale_items (sale_id, item_id, quantity, price, subtotal, discount_amount, tax_rate, tax_inclusive, tax_amount, total)
+		VALUES %s
+	`, strings.Join(valueStrings, ", "))
+
+	_, err := tx.Exec(ctx, querySaleItems, valueArgs...)
+	if err != nil {
+		log.Error("failed to batch insert sale items", zap.Error(err))
+		return apperror.FromDB(err, "sale_item")
+	}
+	return nil
+}
+
+// lockSaleStatus mengunci baris sale sampai transaksi selesai dan mengembalikan
+// statusnya, supaya perubahan status bersamaan (termasuk sweeper) berurutan
+func lockSaleStatus(ctx context.Context, tx database.PgxIface, id int) (string, error) {
+	var status string
+	err := tx.QueryRow(ctx, `SELECT status FROM sales WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err != nil {
+		return "", apperror.FromDB(err, "sale")
+	}
+	return status, nil
+}
+
+// saleQuantities mengembalikan total quantity per item dari sale_items satu sale
+func saleQuantities(ctx context.Context, tx database.PgxIface, saleId int) ([]int, []int, error) {
+	query := `
+		SELECT COALESCE(array_agg(item_id ORDER BY item_id), '{}'), COALESCE(array_agg(qty ORDER BY item_id), '{}')
+		FROM (
+			SELECT item_id, SUM(quantity)::int AS qty
+			FROM sale_items
+			WHERE sale_id = $1
+			GROUP BY item_id
+		) AS data
+	`
+	var itemIds, quantities []int
+	if err := tx.QueryRow(ctx, query, saleId).Scan(&itemIds, &quantities); err != nil {
+		return nil, nil, err
+	}
+	return itemIds, quantities, nil
+}
+
+// sumQuantities menjumlahkan quantity per item; item yang muncul di beberapa
+// baris hanya di-update sekali oleh UPDATE ... FROM
+func sumQuantities(items []model.SaleItems) ([]int, []int) {
+	var itemIds, quantities []int
+	position := make(map[int]int, len(items))
+	for _, item := range items {
+		if i, ok := position[item.ItemId]; ok {
+			quantities[i] += item.Quantity
+			continue
+		}
+		position[item.ItemId] = len(itemIds)
+		itemIds = append(itemIds, item.ItemId)
+		quantities = append(quantities, item.Quantity)
+	}
+	return itemIds, quantities
+}
+
+// missingIds mengembalikan id di expected yang tidak ada di actual
+func missingIds(expected, actual []int) []int {
+	found := make(map[int]bool, len(actual))
+	for _, id := range actual {
+		found[id] = true
+	}
+	var missing []int
+	for _, id := range expected {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// escapeLike meng-escape karakter wildcard LIKE supaya input dicocokkan apa adanya
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// fakeSalesDB menyimpan sales dan sale_items di memori dan menambahkan latency
+// per round trip, supaya perbedaan jumlah query terlihat di benchmark
+type fakeSalesDB struct {
+	sales    []model.Sales
+	items    []model.SaleItems
+	latency  time.Duration
+	queries  int
+	itemsErr error
+}
+
+func newFakeSalesDB(sales, itemsPerSale int, latency time.Duration) *fakeSalesDB {
+	db := &fakeSalesDB{latency: latency}
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := model.MustParseMoney("10")
+	total := price * model.Money(itemsPerSale)
+	for id := 1; id <= sales; id++ {
+		db.sales = append(db.sales, model.Sales{Id: id, InvoiceNo: fmt.Sprintf("INV/2024/01/%06d", id), UserId: 1, Subtotal: total, TotalAmount: total, CreatedAt: created, PaymentType: "cash", PaymentStatus: "unpaid", Status: model.SaleCompleted})
+		for j := 0; j < itemsPerSale; j++ {
+			db.items = append(db.items, model.SaleItems{Id: len(db.items) + 1, SaleId: id, ItemId: j + 1, Quantity: 1, Price: price, Subtotal: price, Total: price})
+		}
+	}
+	return db
+}
+
+// filterSales menyalin sales, difilter customer_id jika customerId tidak nil,
+// prefix invoice_no (pattern LIKE "prefix%") dan status jika tidak kosong
+func (db *fakeSalesDB) filterSales(customerId, invoicePattern, status any) []model.Sales {
+	prefix := strings.TrimSuffix(invoicePattern.(string), "%")
+	var sales []model.Sales
+	for _, s := range db.sales {
+		if id, ok := customerId.(*int); ok && id != nil && (s.CustomerId == nil || *s.CustomerId != *id) {
+			continue
+		}
+		if !strings.HasPrefix(s.InvoiceNo, prefix) {
+			continue
+		}
+		if status.(string) != "" && s.Status != status.(string) {
+			continue
+		}
+		sales = append(sales, s)
+	}
+	return sales
+}
+
+func (db *fakeSalesDB) roundTrip() {
+	db.queries++
+	if db.latency > 0 {
+		time.Sleep(db.latency)
+	}
+}
+
+func (db *fakeSalesDB) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	db.roundTrip()
+	return &fakeRows{rows: [][]any{{len(db.filterSales(args[0], args[1], args[2]))}}}
+}
+
+func (db *fakeSalesDB) Query(ctx context.Context, query string, args ...any) (pgx.Rows, error) {
+	db.roundTrip()
+	switch {
+	case strings.Contains(query, "FROM sale_items"):
+		if db.itemsErr != nil {
+			return nil, db.itemsErr
+		}
+		wanted := map[int]bool{}
+		switch saleIds := args[0].(type) {
+		case []int:
+			for _, id := range saleIds {
+				wanted[id] = true
+			}
+		case int:
+			wanted[saleIds] = true
+		}
+		rows := &fakeRows{}
+		for _, item := range db.items {
+			if wanted[item.SaleId] {
+				rows.rows = append(rows.rows, []any{item.Id, item.SaleId, item.ItemId, item.Quantity, item.Price, item.Subtotal,
+					item.DiscountAmount, item.TaxRate, item.TaxInclusive, item.TaxAmount, item.Total})
+			}
+		}
+		return rows, nil
+	case strings.Contains(query, "FROM sales"):
+		limit, offset := args[0].(int), args[1].(int)
+		sorted := db.filterSales(args[2], args[3], args[4])
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Id > sorted[j].Id })
+		rows := &fakeRows{}
+		for i := offset; i < len(sorted) && i < offset+limit; i++ {
+			s := sorted[i]
+			rows.rows = append(rows.rows, []any{s.Id, s.UserId, s.TotalAmount, s.CreatedAt, s.CustomerId, s.PaymentType,
+				s.Subtotal, s.DiscountAmount, s.TaxAmount, s.PaidAmount, s.PaymentStatus, s.InvoiceNo, s.WarehouseId,
+				s.Status, s.VoidedAt, s.VoidedBy, s.VoidReason, s.HoldExpiresAt})
+		}
+		return rows, nil
+	}
+	return nil, errors.New("unexpected query")
+}
+
+func (db *fakeSalesDB) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("not supported")
+}
+
+func (db *fakeSalesDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+// fakeRows mengisi dest dari rows; hanya tipe yang dipakai query sales yang didukung
+type fakeRows struct {
+	rows [][]any
+	pos  int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return r.rows[r.pos-1], nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	if r.pos >= len(r.rows) {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	row := r.rows[0]
+	if r.pos > 0 {
+		row = r.rows[r.pos-1]
+	}
+	for i, d := range dest {
+		switch d := d.(type) {
+		case *int:
+			*d = row[i].(int)
+		case *model.TaxRate:
+			*d = row[i].(model.TaxRate)
+		case *model.Money:
+			*d = row[i].(model.Money)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		case *string:
+			*d = row[i].(string)
+		case *bool:
+			*d = row[i].(bool)
+		case **int:
+			*d = row[i].(*int)
+		case **time.Time:
+			*d = row[i].(*time.Time)
+		default:
+			return errors.New("unsupported scan type")
+		}
+	}
+	return nil
+}
+
+// getAllSalesPerSaleQuery adalah cara lama (satu query sale_items per sale),
+// disimpan hanya sebagai pembanding di benchmark
+func getAllSalesPerSaleQuery(ctx context.Context, db *fakeSalesDB, repo SalesRepository, page, limit int) ([]model.Sales, error) {
+	sales, _, err := repo.GetAllSales(ctx, SalesFilter{}, page, limit, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sales {
+		rows, err := db.Query(ctx, `SELECT id, sale_id, item_id, quantity, price, subtotal FROM sale_items WHERE sale_id = $1`, sales[i].Id)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var item model.SaleItems
+			if err := rows.Scan(&item.Id, &item.SaleId, &item.ItemId, &item.Quantity, &item.Price, &item.Subtotal); err != nil {
+				return nil, err
+			}
+			sales[i].Items = append(sales[i].Items, item)
+		}
+		rows.Close()
+	}
+	return sales, nil
+}
+
+// BenchmarkGetAllSales membandingkan listing 50 sale (3 item per sale) dengan
+// latency 100µs per round trip ke database
+func BenchmarkGetAllSales(b *testing.B) {
+	ctx := context.Background()
+	const latency = 100 * time.Microsecond
+
+	run := func(b *testing.B, fn func(db *fakeSalesDB, repo SalesRepository) error) {
+		db := newFakeSalesDB(50, 3, latency)
+		repo := NewSalesRepository(db, zap.NewNop())
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := fn(db, repo); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(db.queries)/float64(b.N), "queries/op")
+	}
+
+	b.Run("without_items", func(b *testing.B) {
+		run(b, func(db *fakeSalesDB, repo SalesRepository) error {
+			_, _, err := repo.GetAllSales(ctx, SalesFilter{}, 1, 50, false)
+			return err
+		})
+	})
+	b.Run("items_single_query", func(b *testing.B) {
+		run(b, func(db *fakeSalesDB, repo SalesRepository) error {
+			_, _, err := repo.GetAllSales(ctx, SalesFilter{}, 1, 50, true)
+			return err
+		})
+	})
+	b.Run("items_query_per_sale", func(b *testing.B) {
+		run(b, func(db *fakeSalesDB, repo SalesRepository) error {
+			_, err := getAllSalesPerSaleQuery(ctx, db, repo, 1, 50)
+			return err
+		})
+	})
+}
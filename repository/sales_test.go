@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/model"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,11 +17,20 @@ import (
 	"go.uber.org/zap"
 )
 
-// MockTx for transaction testing
+// MockTx for transaction testing; method pgx.Tx yang tidak di-mock akan panic
 type MockTx struct {
+	pgx.Tx
 	mock.Mock
 }
 
+func (m *MockTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(pgx.Tx), args.Error(1)
+}
+
 func (m *MockTx) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
 	mockArgs := m.Called(ctx, query, args)
 	return mockArgs.Get(0).(pgx.Row)
@@ -59,7 +70,7 @@ func TestGetSalesById_Success(t *testing.T) {
 	expectedSale := &model.Sales{
 		Id:          1,
 		UserId:      1,
-		TotalAmount: 100.50,
+		TotalAmount: model.MustParseMoney("100.50"),
 		CreatedAt:   now,
 	}
 
@@ -72,7 +83,7 @@ func TestGetSalesById_Success(t *testing.T) {
 		dest := args.Get(0).([]any)
 		*dest[0].(*int) = expectedSale.Id
 		*dest[1].(*int) = expectedSale.UserId
-		*dest[2].(*float64) = expectedSale.TotalAmount
+		*dest[2].(*model.Money) = expectedSale.TotalAmount
 		*dest[3].(*time.Time) = expectedSale.CreatedAt
 	}).Return(nil).Once()
 
@@ -88,14 +99,14 @@ func TestGetSalesById_Success(t *testing.T) {
 		*dest[1].(*int) = 1
 		*dest[2].(*int) = 1
 		*dest[3].(*int) = 2
-		*dest[4].(*float64) = 50.25
-		*dest[5].(*float64) = 100.50
+		*dest[4].(*model.Money) = model.MustParseMoney("50.25")
+		*dest[5].(*model.Money) = model.MustParseMoney("100.50")
 	}).Return(nil)
 
 	mockRows.On("Close").Return()
 	mockRows.On("Err").Return(nil)
 
-	sale, items, err := repo.GetSalesById(1)
+	sale, items, err := repo.GetSalesById(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, sale)
@@ -112,7 +123,7 @@ func TestGetSalesById_NotFound(t *testing.T) {
 	mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
 	mockRow.On("Scan", mock.Anything).Return(sql.ErrNoRows)
 
-	sale, items, err := repo.GetSalesById(999)
+	sale, items, err := repo.GetSalesById(context.Background(), 999)
 
 	assert.Error(t, err)
 	assert.Nil(t, sale)
@@ -120,74 +131,70 @@ func TestGetSalesById_NotFound(t *testing.T) {
 	assert.Equal(t, "sale not found", err.Error())
 }
 
-// TestGetAllSales_Success - SKIPPED: Complex mock pattern, service coverage already 92%
-/*
-func TestGetAllSales_Success(t *testing.T) {
-	mockDB := new(MockPgxIface)
-	mockRowCount := new(MockRow)
-	mockRows := new(MockRows)
-	mockItemRows := new(MockRows)
-	logger, _ := zap.NewDevelopment()
-	repo := NewSalesRepository(mockDB, logger)
-
-	now := time.Now()
-	
-	// Mock count query
-	mockDB.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(mockRowCount)
-	mockRowCount.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
-		*args.Get(0).(*int) = 1
-	}).Return(nil)
+func TestGetAllSales_WithoutItems(t *testing.T) {
+	db := newFakeSalesDB(3, 2, 0)
+	repo := NewSalesRepository(db, zap.NewNop())
 
-	// Mock sales query
-	mockDB.On("Query", mock.Anything, mock.MatchedBy(func(query string) bool {
-		return len(query) > 50
-	}), mock.Anything).Return(mockRows, nil)
-
-	callCount := 0
-	mockRows.On("Next").Return(func() bool {
-		callCount++
-		return callCount <= 1
-	})
+	sales, total, err := repo.GetAllSales(context.Background(), SalesFilter{}, 1, 10, false)
 
-	mockRows.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
-		*args.Get(0).(*int) = 1
-		*args.Get(1).(*int) = 1
-		*args.Get(2).(*float64) = 100.50
-		*args.Get(3).(*time.Time) = now
-	}).Return(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, sales, 3)
+	assert.Nil(t, sales[0].Items)
+	// count + sales, tanpa query sale_items
+	assert.Equal(t, 2, db.queries)
+}
 
-	mockRows.On("Close").Return()
+func TestGetAllSales_IncludeItems_SingleQuery(t *testing.T) {
+	db := newFakeSalesDB(3, 2, 0)
+	// sale 3 tanpa item
+	db.items = db.items[:4]
+	repo := NewSalesRepository(db, zap.NewNop())
 
-	// Mock sale items query
-	mockDB.On("Query", mock.Anything, mock.MatchedBy(func(query string) bool {
-		return len(query) < 50 || query == mock.Anything
-	}), []interface{}{1}).Return(mockItemRows, nil)
-
-	itemCallCount := 0
-	mockItemRows.On("Next").Return(func() bool {
-		itemCallCount++
-		return itemCallCount <= 1
-	})
+	sales, total, err := repo.GetAllSales(context.Background(), SalesFilter{}, 1, 10, true)
 
-	mockItemRows.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
-		*args.Get(0).(*int) = 1
-		*args.Get(1).(*int) = 1
-		*args.Get(2).(*int) = 1
-		*args.Get(3).(*int) = 2
-		*args.Get(4).(*float64) = 50.25
-		*args.Get(5).(*float64) = 100.50
-	}).Return(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []int{3, 2, 1}, []int{sales[0].Id, sales[1].Id, sales[2].Id})
+	assert.Empty(t, sales[0].Items)
+	assert.Len(t, sales[1].Items, 2)
+	assert.Len(t, sales[2].Items, 2)
+	for _, sale := range sales {
+		for _, item := range sale.Items {
+			assert.Equal(t, sale.Id, item.SaleId)
+		}
+	}
+	assert.Equal(t, 3, db.queries)
+}
 
-	mockItemRows.On("Close").Return()
+func TestGetAllSales_InvoiceNoPrefix(t *testing.T) {
+	db := newFakeSalesDB(12, 1, 0)
+	repo := NewSalesRepository(db, zap.NewNop())
 
-	sales, total, err := repo.GetAllSales(1, 10)
+	sales, total, err := repo.GetAllSales(context.Background(), SalesFilter{InvoiceNo: "INV/2024/01/00001"}, 1, 10, false)
 
 	assert.NoError(t, err)
-	assert.Equal(t, 1, total)
-	assert.Len(t, sales, 1)
-	assert.Len(t, sales[0].Items, 1)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []string{"INV/2024/01/000012", "INV/2024/01/000011", "INV/2024/01/000010"},
+		[]string{sales[0].InvoiceNo, sales[1].InvoiceNo, sales[2].InvoiceNo})
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `INV/2026/10/000123`, escapeLike("INV/2026/10/000123"))
+	assert.Equal(t, `100\% \_x\\`, escapeLike(`100% _x\`))
+}
+
+func TestGetAllSales_ItemsQueryError(t *testing.T) {
+	db := newFakeSalesDB(2, 1, 0)
+	db.itemsErr = errors.New("connection reset")
+	repo := NewSalesRepository(db, zap.NewNop())
+
+	sales, total, err := repo.GetAllSales(context.Background(), SalesFilter{}, 1, 10, true)
+
+	assert.EqualError(t, err, "connection reset")
+	assert.Nil(t, sales)
+	assert.Equal(t, 0, total)
 }
-*/
 
 /*
 func TestDeleteSales_Success(t *testing.T) {
@@ -212,7 +219,7 @@ func TestDeleteSales_Success(t *testing.T) {
 	
 	mockTx.On("Commit", mock.Anything).Return(nil)
 
-	err := repo.DeleteSales(1)
+	err := repo.DeleteSales(context.Background(), 1)
 
 	assert.NoError(t, err)
 }
@@ -237,7 +244,7 @@ func TestDeleteSales_NoRowsAffected(t *testing.T) {
 	
 	mockTx.On("Rollback", mock.Anything).Return(nil)
 
-	err := repo.DeleteSales(999)
+	err := repo.DeleteSales(context.Background(), 999)
 
 	assert.Error(t, err)
 	assert.Equal(t, "no rows affected", err.Error())
@@ -258,7 +265,7 @@ func TestUpdateSales_Success(t *testing.T) {
 	mockTag := MockCommandTag{rowsAffected: 1}
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
 
-	err := repo.UpdateSales(1, sale)
+	err := repo.UpdateSales(context.Background(), 1, sale)
 
 	assert.NoError(t, err)
 }
@@ -276,7 +283,7 @@ func TestUpdateSales_NoRowsAffected(t *testing.T) {
 	mockTag := MockCommandTag{rowsAffected: 1}
 	mockDB.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(mockTag, nil)
 
-	err := repo.UpdateSales(999, sale)
+	err := repo.UpdateSales(context.Background(), 999, sale)
 
 	assert.Error(t, err)
 	assert.Equal(t, "no rows affected", err.Error())
@@ -290,16 +297,320 @@ func TestCreateSales_BeginTransactionError(t *testing.T) {
 
 	sale := &model.Sales{
 		UserId:      1,
-		TotalAmount: 100.0,
+		TotalAmount: model.MustParseMoney("100"),
 	}
 	items := []model.SaleItems{
-		{ItemId: 1, Quantity: 2, Price: 50.0},
+		{ItemId: 1, Quantity: 2, Price: model.MustParseMoney("50")},
 	}
 
 	mockDB.On("Begin", mock.Anything).Return(nil, errors.New("transaction error"))
 
-	err := repo.CreateSales(sale, items)
+	err := repo.CreateSales(context.Background(), sale, items)
 
 	assert.Error(t, err)
 	assert.Equal(t, "transaction error", err.Error())
 }
+
+func TestGetSalesByCustomer_FiltersAndIncludesItems(t *testing.T) {
+	db := newFakeSalesDB(4, 1, 0)
+	customerId := 7
+	db.sales[1].CustomerId, db.sales[3].CustomerId = &customerId, &customerId
+	db.sales[3].PaymentType = model.PaymentCredit
+	repo := NewSalesRepository(db, zap.NewNop())
+
+	sales, total, err := repo.GetSalesByCustomer(context.Background(), 7, 1, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []int{4, 2}, []int{sales[0].Id, sales[1].Id})
+	assert.Equal(t, model.PaymentCredit, sales[0].PaymentType)
+	assert.Len(t, sales[0].Items, 1)
+	// count + sales + sale_items
+	assert.Equal(t, 3, db.queries)
+}
+
+func TestVoidSale_RestoresStockInSameTx(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "status = 'voided'") && strings.Contains(query, "status = 'completed'")
+	}), []interface{}{5, 7, "salah input"}).Return(pgconn.NewCommandTag("UPDATE 1"), nil).Once()
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "stock = items.stock + data.qty")
+	}), []interface{}{5}).Return(pgconn.NewCommandTag("UPDATE 2"), nil).Once()
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	err := repo.VoidSale(context.Background(), 5, 7, "salah input")
+
+	assert.NoError(t, err)
+	mockTx.AssertExpectations(t)
+	mockTx.AssertNotCalled(t, "Rollback", mock.Anything)
+}
+
+func TestVoidSale_AlreadyVoided(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+	mockRow := new(MockRow)
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.NewCommandTag("UPDATE 0"), nil).Once()
+	mockTx.On("QueryRow", mock.Anything, mock.Anything, []interface{}{5}).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*string) = model.SaleVoided
+	}).Return(nil)
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.VoidSale(context.Background(), 5, 7, "salah input")
+
+	assert.Equal(t, "sale_voided", apperror.Code(err))
+	// stock tidak dikembalikan dua kali
+	mockTx.AssertNumberOfCalls(t, "Exec", 1)
+	mockTx.AssertCalled(t, "Rollback", mock.Anything)
+}
+
+func TestVoidSale_NotFound(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+	mockRow := new(MockRow)
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+	mockTx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.NewCommandTag("UPDATE 0"), nil).Once()
+	mockTx.On("QueryRow", mock.Anything, mock.Anything, []interface{}{99}).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.VoidSale(context.Background(), 99, 7, "salah input")
+
+	assert.True(t, apperror.IsNotFound(err))
+}
+
+func TestGetAllSales_StatusFilter(t *testing.T) {
+	db := newFakeSalesDB(4, 1, 0)
+	db.sales[1].Status = model.SaleHeld
+	db.sales[3].Status = model.SaleHeld
+	repo := NewSalesRepository(db, zap.NewNop())
+
+	sales, total, err := repo.GetAllSales(context.Background(), SalesFilter{Status: model.SaleHeld}, 1, 10, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []int{4, 2}, []int{sales[0].Id, sales[1].Id})
+}
+
+// mockSaleTx menyiapkan transaksi dengan baris sale yang dikunci berstatus status
+// dan total quantity per item dari sale_items
+func mockSaleTx(status string, itemIds, quantities []int) (*MockPgxIface, *MockTx) {
+	mockDB := new(MockPgxIface)
+	mockTx := new(MockTx)
+	mockDB.On("Begin", mock.Anything).Return(mockTx, nil)
+
+	lockRow := new(MockRow)
+	mockTx.On("QueryRow", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "FOR UPDATE")
+	}), []interface{}{5}).Return(lockRow)
+	lockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*string) = status
+	}).Return(nil)
+
+	quantityRow := new(MockRow)
+	mockTx.On("QueryRow", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "SUM(quantity)::int")
+	}), []interface{}{5}).Return(quantityRow)
+	quantityRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[0].(*[]int) = itemIds
+		*dest[1].(*[]int) = quantities
+	}).Return(nil)
+	return mockDB, mockTx
+}
+
+// mockStockUpdate mengembalikan updated sebagai id item yang berhasil di-update
+func mockStockUpdate(mockTx *MockTx, fragment string, updated []int) {
+	row := new(MockRow)
+	mockTx.On("QueryRow", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, fragment)
+	}), mock.Anything).Return(row)
+	row.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*[]int) = updated
+	}).Return(nil)
+}
+
+func TestUpdateSales_ReplacesItems(t *testing.T) {
+	mockDB, mockTx := mockSaleTx(model.SaleQuote, nil, nil)
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	customerId, warehouseId := 7, 2
+	sale := &model.Sales{UserId: 2, CustomerId: &customerId, WarehouseId: &warehouseId, PaymentType: model.PaymentCredit, Subtotal: model.MustParseMoney("20"), TotalAmount: model.MustParseMoney("20")}
+	items := []model.SaleItems{{ItemId: 3, Quantity: 2, Price: model.MustParseMoney("10"), Subtotal: model.MustParseMoney("20"), Total: model.MustParseMoney("20")}}
+
+	// customer, payment_type dan gudang ikut disimpan
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "UPDATE sales") && strings.Contains(query, "customer_id = $6, payment_type = $7, warehouse_id = $8")
+	}), mock.MatchedBy(func(args []interface{}) bool {
+		return len(args) == 9 && args[5] == &customerId && args[6] == model.PaymentCredit && args[7] == &warehouseId && args[8] == 5
+	})).Return(pgconn.NewCommandTag("UPDATE 1"), nil).Once()
+	mockTx.On("Exec", mock.Anything, "DELETE FROM sale_items WHERE sale_id = $1", []interface{}{5}).
+		Return(pgconn.NewCommandTag("DELETE 2"), nil).Once()
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "INSERT INTO sale_items")
+	}), mock.MatchedBy(func(args []interface{}) bool {
+		return len(args) == 10 && args[0] == 5 && args[1] == 3
+	})).Return(pgconn.NewCommandTag("INSERT 0 1"), nil).Once()
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	err := repo.UpdateSales(context.Background(), 5, sale, items)
+
+	assert.NoError(t, err)
+	mockTx.AssertNumberOfCalls(t, "Exec", 3)
+	mockTx.AssertCalled(t, "Commit", mock.Anything)
+}
+
+func TestUpdateSales_CompletedRejected(t *testing.T) {
+	mockDB, mockTx := mockSaleTx(model.SaleCompleted, nil, nil)
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.UpdateSales(context.Background(), 5, &model.Sales{UserId: 2}, []model.SaleItems{{ItemId: 3, Quantity: 1}})
+
+	assert.Equal(t, "invalid_sale_status", apperror.Code(err))
+	mockTx.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+	mockTx.AssertCalled(t, "Rollback", mock.Anything)
+}
+
+func TestHoldSale_ReservesAvailableStock(t *testing.T) {
+	mockDB, mockTx := mockSaleTx(model.SaleDraft, []int{3, 4}, []int{2, 1})
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "status = 'held'")
+	}), []interface{}{5, 30 * time.Minute}).Return(pgconn.NewCommandTag("UPDATE 1"), nil).Once()
+	mockStockUpdate(mockTx, "items.stock - items.reserved >= data.qty", []int{3, 4})
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	err := repo.HoldSale(context.Background(), 5, 30*time.Minute)
+
+	assert.NoError(t, err)
+	mockTx.AssertExpectations(t)
+}
+
+func TestHoldSale_InsufficientAvailableStock(t *testing.T) {
+	mockDB, mockTx := mockSaleTx(model.SaleQuote, []int{3, 4}, []int{2, 1})
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockTx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.NewCommandTag("UPDATE 1"), nil).Once()
+	mockStockUpdate(mockTx, "items.stock - items.reserved >= data.qty", []int{3})
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.HoldSale(context.Background(), 5, 30*time.Minute)
+
+	assert.True(t, apperror.Is(err, apperror.KindInsufficientStock))
+	assert.Equal(t, map[string]any{"item_ids": []int{4}}, err.(*apperror.Error).Details)
+	mockTx.AssertCalled(t, "Rollback", mock.Anything)
+	mockTx.AssertNotCalled(t, "Commit", mock.Anything)
+}
+
+func TestHoldSale_ExtendDoesNotReserveTwice(t *testing.T) {
+	mockDB, mockTx := mockSaleTx(model.SaleHeld, []int{3}, []int{2})
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockTx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.NewCommandTag("UPDATE 1"), nil).Once()
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	err := repo.HoldSale(context.Background(), 5, time.Hour)
+
+	assert.NoError(t, err)
+	// hanya lock status, tanpa query quantity / reserve
+	mockTx.AssertNumberOfCalls(t, "QueryRow", 1)
+}
+
+func TestHoldSale_Completed(t *testing.T) {
+	mockDB, mockTx := mockSaleTx(model.SaleCompleted, nil, nil)
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.HoldSale(context.Background(), 5, time.Hour)
+
+	assert.Equal(t, "invalid_sale_status", apperror.Code(err))
+	mockTx.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReleaseHold_ReleasesReservedStock(t *testing.T) {
+	mockDB, mockTx := mockSaleTx(model.SaleHeld, nil, nil)
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "status = 'draft'")
+	}), []interface{}{5}).Return(pgconn.NewCommandTag("UPDATE 1"), nil).Once()
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "reserved = items.reserved - data.qty")
+	}), []interface{}{5}).Return(pgconn.NewCommandTag("UPDATE 1"), nil).Once()
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	err := repo.ReleaseHold(context.Background(), 5)
+
+	assert.NoError(t, err)
+	mockTx.AssertNumberOfCalls(t, "Exec", 2)
+	mockTx.AssertCalled(t, "Commit", mock.Anything)
+}
+
+func TestCompleteSale_HeldConsumesReservation(t *testing.T) {
+	mockDB, mockTx := mockSaleTx(model.SaleHeld, []int{3}, []int{2})
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockStockUpdate(mockTx, "reserved = reserved - data.qty", []int{3})
+	mockTx.On("Exec", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "status = 'completed'")
+	}), []interface{}{5, "INV/2026/10/000001"}).Return(pgconn.NewCommandTag("UPDATE 1"), nil).Once()
+	mockTx.On("Commit", mock.Anything).Return(nil)
+
+	err := repo.CompleteSale(context.Background(), 5, "INV/2026/10/000001")
+
+	assert.NoError(t, err)
+	mockTx.AssertExpectations(t)
+}
+
+func TestCompleteSale_DraftInsufficientStock(t *testing.T) {
+	mockDB, mockTx := mockSaleTx(model.SaleDraft, []int{3}, []int{2})
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockStockUpdate(mockTx, "items.stock - items.reserved >= data.qty", []int{})
+	mockTx.On("Rollback", mock.Anything).Return(nil)
+
+	err := repo.CompleteSale(context.Background(), 5, "INV/2026/10/000001")
+
+	assert.True(t, apperror.Is(err, apperror.KindInsufficientStock))
+	// status tidak berubah
+	mockTx.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExpireHolds(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	mockRow := new(MockRow)
+	repo := NewSalesRepository(mockDB, zap.NewNop())
+
+	mockDB.On("QueryRow", mock.Anything, mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "hold_expires_at <= LOCALTIMESTAMP") && strings.Contains(query, "reserved = items.reserved - data.qty")
+	}), []interface{}(nil)).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*int64) = 3
+	}).Return(nil)
+
+	expired, err := repo.ExpireHolds(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), expired)
+}
+
+func TestSumQuantities(t *testing.T) {
+	itemIds, quantities := sumQuantities([]model.SaleItems{
+		{ItemId: 3, Quantity: 2}, {ItemId: 1, Quantity: 1}, {ItemId: 3, Quantity: 4},
+	})
+
+	assert.Equal(t, []int{3, 1}, itemIds)
+	assert.Equal(t, []int{6, 1}, quantities)
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type SessionsRepository interface {
+	CreateSession(ctx context.Context, userId int, expiredAt time.Time) (*model.Sessions, error)
+	GetActiveSession(ctx context.Context, token string) (*model.Sessions, *model.Users, error)
+	RevokeSession(ctx context.Context, token string) error
+	RevokeUserSessions(ctx context.Context, userId int) (int64, error)
+}
+
+type sessionsRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewSessionsRepository(db database.PgxIface, log *zap.Logger) SessionsRepository {
+	return &sessionsRepository{db: db, Logger: log}
+}
+
+func (r *sessionsRepository) CreateSession(ctx context.Context, userId int, expiredAt time.Time) (*model.Sessions, error) {
+	query := `
+		INSERT INTO sessions (user_id, expired_at, created_at)
+		VALUES ($1, $2, NOW())
+		RETURNING id, token::text, created_at
+	`
+	session := model.Sessions{UserId: userId, ExpiredAt: expiredAt}
+	err := conn(ctx, r.db).QueryRow(ctx, query, userId, expiredAt).Scan(&session.Id, &session.Token, &session.CreatedAt)
+	if err != nil {
+		return nil, apperror.FromDB(err, "session")
+	}
+	return &session, nil
+}
+
+// GetActiveSession mencari session yang belum dicabut dan belum kedaluwarsa
+// beserta user pemiliknya (yang belum dihapus)
+func (r *sessionsRepository) GetActiveSession(ctx context.Context, token string) (*model.Sessions, *model.Users, error) {
+	query := `
+		SELECT s.id, s.user_id, s.token::text, s.expired_at, s.created_at,
+		       u.id, u.username, u.email, u.role,
+		       EXISTS (SELECT 1 FROM user_totp t WHERE t.user_id = u.id AND t.enabled_at IS NOT NULL)
+		FROM sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.token = $1::uuid
+		  AND s.revoked_at IS NULL
+		  AND s.expired_at > NOW()
+		  AND u.deleted_at IS NULL
+	`
+	var session model.Sessions
+	var user model.Users
+	err := conn(ctx, r.db).QueryRow(ctx, query, token).Scan(
+		&session.Id, &session.UserId, &session.Token, &session.ExpiredAt, &session.CreatedAt,
+		&user.Id, &user.Username, &user.Email, &user.Role, &user.TwoFactorEnabled,
+	)
+	if err != nil {
+		return nil, nil, apperror.FromDB(err, "session")
+	}
+	return &session, &user, nil
+}
+
+func (r *sessionsRepository) RevokeSession(ctx context.Context, token string) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE token = $1::uuid AND revoked_at IS NULL`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, token)
+	if err != nil {
+		return apperror.FromDB(err, "session")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("session")
+	}
+	return nil
+}
+
+// RevokeUserSessions mencabut semua session aktif milik user (misal setelah ganti password)
+func (r *sessionsRepository) RevokeUserSessions(ctx context.Context, userId int) (int64, error) {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userId)
+	if err != nil {
+		return 0, apperror.FromDB(err, "session")
+	}
+	return result.RowsAffected(), nil
+}
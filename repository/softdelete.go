@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+)
+
+// softDelete mengisi deleted_at pada baris yang masih aktif.
+// inUse (opsional) adalah subquery yang bernilai ada jika masih ada data aktif
+// yang mereferensikan baris ini; selama itu penghapusan ditolak dengan 409.
+func softDelete(ctx context.Context, db database.PgxIface, table, entity string, id int, inUse string) error {
+	query := "UPDATE " + table + " SET deleted_at = NOW(), updated_at = NOW(), version = version + 1 WHERE id = $1 AND deleted_at IS NULL"
+	if inUse != "" {
+		query += " AND NOT EXISTS (" + inUse + ")"
+	}
+
+	result, err := conn(ctx, db).Exec(ctx, query, id)
+	if err != nil {
+		return apperror.FromDB(err, entity)
+	}
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+	if inUse == "" {
+		return apperror.NotFound(entity)
+	}
+
+	// tidak ada baris yang terhapus: datanya tidak ada atau masih dipakai
+	exists, err := rowExists(ctx, db, table, id, "deleted_at IS NULL")
+	if err != nil {
+		return apperror.FromDB(err, entity)
+	}
+	if !exists {
+		return apperror.NotFound(entity)
+	}
+	return apperror.ForeignKeyViolation(entity+"_foreign_key_violation", entity+" is still in use")
+}
+
+// restoreDeleted mengosongkan deleted_at dan men-scan kolom returning ke dest.
+// Baris yang tidak ada -> 404, baris yang tidak sedang terhapus -> 409.
+func restoreDeleted(ctx context.Context, db database.PgxIface, table, entity string, id int, returning string, dest ...any) error {
+	query := "UPDATE " + table + " SET deleted_at = NULL, updated_at = NOW(), version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + returning
+
+	err := conn(ctx, db).QueryRow(ctx, query, id).Scan(dest...)
+	if !apperror.IsNoRows(err) {
+		// unique violation (misal SKU sudah dipakai data baru) dipetakan ke 409
+		return apperror.FromDB(err, entity)
+	}
+
+	exists, err := rowExists(ctx, db, table, id, "TRUE")
+	if err != nil {
+		return apperror.FromDB(err, entity)
+	}
+	if !exists {
+		return apperror.NotFound(entity)
+	}
+	return apperror.Conflict(entity+"_not_deleted", entity+" is not deleted")
+}
+
+func rowExists(ctx context.Context, db database.PgxIface, table string, id int, cond string) (bool, error) {
+	var exists bool
+	err := conn(ctx, db).QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1 AND "+cond+")", id).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func queryContains(part string) any {
+	return mock.MatchedBy(func(query string) bool { return strings.Contains(query, part) })
+}
+
+func TestDeleteItems_SetsDeletedAt(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	repo := NewItemsRepository(mockDB, zap.NewNop())
+
+	mockDB.On("Exec", mock.Anything, queryContains("SET deleted_at = NOW()"), []any{1}).Return(MockCommandTag{rowsAffected: 1}, nil)
+
+	err := repo.DeleteItems(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeleteCategories_InUse(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	repo := NewCategoriesRepository(mockDB, zap.NewNop())
+
+	mockDB.On("Exec", mock.Anything, queryContains("NOT EXISTS (SELECT 1 FROM items"), mock.Anything).Return(MockCommandTag{rowsAffected: 0}, nil)
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, queryContains("deleted_at IS NULL"), mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*bool) = true
+	}).Return(nil)
+
+	err := repo.DeleteCategories(context.Background(), 1)
+
+	assert.Error(t, err)
+	assert.True(t, apperror.Is(err, apperror.KindForeignKey))
+	assert.Equal(t, "category is still in use", err.Error())
+}
+
+func TestRestoreItems_Success(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	repo := NewItemsRepository(mockDB, zap.NewNop())
+
+	mockRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, queryContains("SET deleted_at = NULL"), []any{7}).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(0).([]any)
+		*dest[0].(*int) = 7
+		*dest[3].(*string) = "Mouse"
+		*dest[10].(*int) = 4
+	}).Return(nil)
+
+	item, err := repo.RestoreItems(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, item.Id)
+	assert.Equal(t, 4, item.Version)
+	assert.Nil(t, item.DeletedAt)
+}
+
+func TestRestoreItems_NotDeleted(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	repo := NewItemsRepository(mockDB, zap.NewNop())
+
+	restoreRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, queryContains("SET deleted_at = NULL"), mock.Anything).Return(restoreRow)
+	restoreRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+
+	existsRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, queryContains("SELECT EXISTS"), mock.Anything).Return(existsRow)
+	existsRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*bool) = true
+	}).Return(nil)
+
+	item, err := repo.RestoreItems(context.Background(), 7)
+
+	assert.Nil(t, item)
+	assert.True(t, apperror.Is(err, apperror.KindConflict))
+	assert.Equal(t, "item_not_deleted", apperror.Code(err))
+}
+
+func TestRestoreItems_NotFound(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	repo := NewItemsRepository(mockDB, zap.NewNop())
+
+	restoreRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, queryContains("SET deleted_at = NULL"), mock.Anything).Return(restoreRow)
+	restoreRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+
+	existsRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, queryContains("SELECT EXISTS"), mock.Anything).Return(existsRow)
+	existsRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]any)[0].(*bool) = false
+	}).Return(nil)
+
+	_, err := repo.RestoreItems(context.Background(), 999)
+
+	assert.True(t, apperror.IsNotFound(err))
+}
+
+func TestGetAllItems_ExcludesDeletedByDefault(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	repo := NewItemsRepository(mockDB, zap.NewNop())
+
+	countRow := new(MockRow)
+	mockDB.On("QueryRow", mock.Anything, queryContains("($1 OR deleted_at IS NULL)"), []any{false}).Return(countRow)
+	countRow.On("Scan", mock.Anything).Return(nil)
+
+	rows := new(MockRows)
+	mockDB.On("Query", mock.Anything, queryContains("($3 OR deleted_at IS NULL)"), []any{10, 0, false}).Return(rows, nil)
+	rows.On("Next").Return(false)
+	rows.On("Close").Return()
+
+	_, _, err := repo.GetAllItems(context.Background(), 1, 10, false)
+
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPurgeDeleted_ChildTablesFirst(t *testing.T) {
+	mockDB := new(MockPgxIface)
+	repo := NewPurgeRepository(mockDB, zap.NewNop())
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var tables []string
+	mockDB.On("Exec", mock.Anything, mock.Anything, []any{before}).Run(func(args mock.Arguments) {
+		table := strings.Fields(args.String(1))[2]
+		tables = append(tables, table)
+	}).Return(MockCommandTag{rowsAffected: 2}, nil)
+
+	purged, err := repo.PurgeDeleted(context.Background(), before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"items", "racks", "categories", "warehouses", "users", "customers"}, tables)
+	assert.Equal(t, int64(2), purged["items"])
+	assert.Len(t, purged, 6)
+}
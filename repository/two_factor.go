@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type TwoFactorRepository interface {
+	GetTOTP(ctx context.Context, userId int) (*model.UserTOTP, error)
+	SavePendingTOTP(ctx context.Context, userId int, secret string) error
+	EnableTOTP(ctx context.Context, userId int, step int64) error
+	UseTOTPStep(ctx context.Context, userId int, step int64) (bool, error)
+	DeleteTOTP(ctx context.Context, userId int) error
+	ReplaceRecoveryCodes(ctx context.Context, userId int, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userId int, codeHash string) error
+	CreateLoginChallenge(ctx context.Context, userId int, tokenHash string, expiredAt time.Time) error
+	GetLoginChallenge(ctx context.Context, tokenHash string) (int, error)
+	ConsumeLoginChallenge(ctx context.Context, tokenHash string) error
+}
+
+type twoFactorRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewTwoFactorRepository(db database.PgxIface, log *zap.Logger) TwoFactorRepository {
+	return &twoFactorRepository{db: db, Logger: log}
+}
+
+func (r *twoFactorRepository) GetTOTP(ctx context.Context, userId int) (*model.UserTOTP, error) {
+	query := `SELECT user_id, secret, enabled_at, last_used_step, created_at FROM user_totp WHERE user_id = $1`
+
+	var totp model.UserTOTP
+	err := conn(ctx, r.db).QueryRow(ctx, query, userId).Scan(
+		&totp.UserId, &totp.Secret, &totp.EnabledAt, &totp.LastUsedStep, &totp.CreatedAt)
+	if err != nil {
+		return nil, apperror.FromDB(err, "two_factor")
+	}
+	return &totp, nil
+}
+
+// SavePendingTOTP menyimpan secret baru yang belum dikonfirmasi. Enrollment ulang
+// hanya mengganti secret yang masih pending; 2FA yang sudah aktif tidak disentuh.
+func (r *twoFactorRepository) SavePendingTOTP(ctx context.Context, userId int, secret string) error {
+	query := `
+		INSERT INTO user_totp (user_id, secret, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+		WHERE user_totp.enabled_at IS NULL
+	`
+	result, err := conn(ctx, r.db).Exec(ctx, query, userId, secret)
+	if err != nil {
+		return apperror.FromDB(err, "two_factor")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.Conflict("two_factor_already_enabled", "two-factor authentication is already enabled")
+	}
+	return nil
+}
+
+// EnableTOTP mengaktifkan secret pending; step adalah kode pertama yang dipakai konfirmasi
+func (r *twoFactorRepository) EnableTOTP(ctx context.Context, userId int, step int64) error {
+	query := `UPDATE user_totp SET enabled_at = NOW(), last_used_step = $2 WHERE user_id = $1 AND enabled_at IS NULL`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userId, step)
+	if err != nil {
+		return apperror.FromDB(err, "two_factor")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("two_factor")
+	}
+	return nil
+}
+
+// UseTOTPStep menandai time step sebagai terpakai. false berarti step tersebut
+// (atau yang lebih baru) sudah pernah dipakai, jadi kodenya tidak boleh diterima lagi.
+func (r *twoFactorRepository) UseTOTPStep(ctx context.Context, userId int, step int64) (bool, error) {
+	query := `UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userId, step)
+	if err != nil {
+		return false, apperror.FromDB(err, "two_factor")
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// DeleteTOTP menonaktifkan 2FA beserta recovery code-nya
+func (r *twoFactorRepository) DeleteTOTP(ctx context.Context, userId int) error {
+	if _, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userId); err != nil {
+		return apperror.FromDB(err, "two_factor")
+	}
+
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userId)
+	if err != nil {
+		return apperror.FromDB(err, "two_factor")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("two_factor")
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes menghapus semua recovery code lama lalu menyimpan yang baru
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userId int, codeHashes []string) error {
+	if _, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userId); err != nil {
+		return apperror.FromDB(err, "recovery_code")
+	}
+
+	query := `INSERT INTO recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, NOW())`
+	for _, hash := range codeHashes {
+		if _, err := conn(ctx, r.db).Exec(ctx, query, userId, hash); err != nil {
+			return apperror.FromDB(err, "recovery_code")
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode menandai recovery code terpakai, NotFound jika tidak cocok atau sudah dipakai
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userId int, codeHash string) error {
+	query := `
+		UPDATE recovery_codes SET used_at = NOW()
+		WHERE id = (
+			SELECT id FROM recovery_codes
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+			LIMIT 1
+		)
+	`
+	result, err := conn(ctx, r.db).Exec(ctx, query, userId, codeHash)
+	if err != nil {
+		return apperror.FromDB(err, "recovery_code")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("recovery_code")
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) CreateLoginChallenge(ctx context.Context, userId int, tokenHash string, expiredAt time.Time) error {
+	query := `
+		INSERT INTO login_challenges (user_id, token_hash, expired_at, created_at)
+		VALUES ($1, $2, $3, NOW())
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, userId, tokenHash, expiredAt)
+	if err != nil {
+		return apperror.FromDB(err, "login_challenge")
+	}
+	return nil
+}
+
+// GetLoginChallenge mengembalikan user id dari challenge yang belum dipakai dan belum kedaluwarsa
+func (r *twoFactorRepository) GetLoginChallenge(ctx context.Context, tokenHash string) (int, error) {
+	query := `
+		SELECT user_id FROM login_challenges
+		WHERE token_hash = $1 AND used_at IS NULL AND expired_at > NOW()
+	`
+	var userId int
+	err := conn(ctx, r.db).QueryRow(ctx, query, tokenHash).Scan(&userId)
+	if err != nil {
+		return 0, apperror.FromDB(err, "login_challenge")
+	}
+	return userId, nil
+}
+
+// ConsumeLoginChallenge menandai challenge terpakai (login selesai atau akun terkunci)
+func (r *twoFactorRepository) ConsumeLoginChallenge(ctx context.Context, tokenHash string) error {
+	query := `UPDATE login_challenges SET used_at = NOW() WHERE token_hash = $1 AND used_at IS NULL`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, tokenHash)
+	if err != nil {
+		return apperror.FromDB(err, "login_challenge")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("login_challenge")
+	}
+	return nil
+}
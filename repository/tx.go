@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/database"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type txKey struct{}
+
+// Transactor menjalankan beberapa operasi repository dalam satu transaksi.
+// Transaksi dibawa lewat context sehingga repository tidak perlu tahu
+// apakah ia dipanggil di dalam transaksi atau tidak.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db database.PgxIface
+}
+
+func NewTransactor(db database.PgxIface) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// transaksi bersarang menjadi savepoint (pgx.Tx.Begin)
+	tx, err := conn(ctx, t.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// conn mengembalikan transaksi aktif di ctx, atau db jika tidak ada
+func conn(ctx context.Context, db database.PgxIface) database.PgxIface {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
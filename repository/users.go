@@ -2,21 +2,27 @@ package repository
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/database"
 	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 type UsersRepository interface {
-	GetUsersByEmail(email string) (*model.Users, error)
-	CreateUsers(data *model.Users) error
-	GetAllUsers() ([]model.Users, error)
-	GetUsersByID(id int) (model.Users, error)
-	UpdateUsers(id int, data *model.Users) error
-	DeleteUsers(id int) error
+	GetUsersByEmail(ctx context.Context, email string) (*model.Users, error)
+	CreateUsers(ctx context.Context, data *model.Users) error
+	GetAllUsers(ctx context.Context, includeDeleted bool) ([]model.Users, error)
+	GetUsersByID(ctx context.Context, id int) (model.Users, error)
+	UpdateUsers(ctx context.Context, id int, data *model.Users) error
+	PatchUsers(ctx context.Context, id, version int, changes map[string]any) (*model.Users, error)
+	DeleteUsers(ctx context.Context, id int) error
+	RestoreUsers(ctx context.Context, id int) (*model.Users, error)
+	UpdatePassword(ctx context.Context, id int, hashedPassword string) error
+	RecordLoginFailure(ctx context.Context, id int, now time.Time, maxAttempts int, lockUntil time.Time) (int, *time.Time, error)
+	ResetLoginFailures(ctx context.Context, id int) error
 }
 
 type usersRepository struct {
@@ -28,34 +34,38 @@ func NewUsersRepository(db database.PgxIface, log *zap.Logger) UsersRepository {
 	return &usersRepository{db: db, Logger: log}
 }
 
-func (r *usersRepository) GetUsersByEmail(email string) (*model.Users, error) {
+func (r *usersRepository) GetUsersByEmail(ctx context.Context, email string) (*model.Users, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	query := `
-		SELECT id, username, email, password, role, created_at, updated_at
+		SELECT id, username, email, password, role, created_at, updated_at, version, failed_login_attempts, locked_until,
+		       EXISTS (SELECT 1 FROM user_totp t WHERE t.user_id = users.id AND t.enabled_at IS NOT NULL)
 		FROM users
-		WHERE email = $1
+		WHERE email = $1 AND deleted_at IS NULL
 		`
 	var user model.Users
-	err := r.db.QueryRow(context.Background(), query, email).Scan(
-			&user.Id, &user.Username, &user.Email, &user.Password, &user.Role,  &user.CreatedAt, &user.UpdatedAt)
+	err := conn(ctx, r.db).QueryRow(ctx, query, email).Scan(
+			&user.Id, &user.Username, &user.Email, &user.Password, &user.Role,  &user.CreatedAt, &user.UpdatedAt, &user.Version,
+			&user.FailedLoginAttempts, &user.LockedUntil, &user.TwoFactorEnabled)
 
-	if err == sql.ErrNoRows {
-		r.Logger.Debug("user not found by email", zap.String("email", email))
+	if apperror.IsNoRows(err) {
+		log.Debug("user not found by email", zap.String("email", email))
 		return nil, nil // user tidak ditemukan
 	}
 
 	if err != nil {
-		r.Logger.Error("failed to get user by email",
+		log.Error("failed to get user by email",
 			zap.String("email", email),
 			zap.Error(err),
 		)
+		return nil, err
 	}
 
-	return &user, err
+	return &user, nil
 }
 
 
-func (r *usersRepository) GetAllUsers() ([]model.Users, error) {
-	rows, err := r.db.Query(context.Background(), `SELECT id, username, email, password, role, created_at, updated_at FROM users`)
+func (r *usersRepository) GetAllUsers(ctx context.Context, includeDeleted bool) ([]model.Users, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `SELECT id, username, email, password, role, created_at, updated_at, version, deleted_at FROM users WHERE ($1 OR deleted_at IS NULL) ORDER BY id`, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +74,7 @@ func (r *usersRepository) GetAllUsers() ([]model.Users, error) {
 	var students []model.Users
 	for rows.Next() {
 		var u model.Users
-		err := rows.Scan(&u.Id, &u.Username, &u.Email, &u.Password, &u.Role, &u.CreatedAt, &u.UpdatedAt)
+		err := rows.Scan(&u.Id, &u.Username, &u.Email, &u.Password, &u.Role, &u.CreatedAt, &u.UpdatedAt, &u.Version, &u.DeletedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -74,22 +84,23 @@ func (r *usersRepository) GetAllUsers() ([]model.Users, error) {
 }
 
 
-func (r *usersRepository) CreateUsers(data *model.Users) error {
+func (r *usersRepository) CreateUsers(ctx context.Context, data *model.Users) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	query := `
 		INSERT INTO users (username, email, password, role, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, NOW(), NOW())
-		RETURNING id
+		RETURNING id, version
 	`
-	err := r.db.QueryRow(context.Background(), query, data.Username, data.Email, data.Password, data.Role).Scan(&data.Id)
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.Username, data.Email, data.Password, data.Role).Scan(&data.Id, &data.Version)
 	if err != nil {
-		r.Logger.Error("failed to create user",
+		log.Error("failed to create user",
 			zap.String("username", data.Username),
 			zap.String("email", data.Email),
 			zap.Error(err),
 		)
-		return err
+		return apperror.FromDB(err, "user")
 	}
-	r.Logger.Info("user created successfully",
+	log.Info("user created successfully",
 		zap.Int("user_id", data.Id),
 		zap.String("username", data.Username),
 		zap.String("email", data.Email),
@@ -99,62 +110,168 @@ func (r *usersRepository) CreateUsers(data *model.Users) error {
 
 
 
-func (r *usersRepository) GetUsersByID(id int) (model.Users, error) {
+func (r *usersRepository) GetUsersByID(ctx context.Context, id int) (model.Users, error) {
 	var user model.Users
-	query := "SELECT id, username, email, password, role, created_at, updated_at FROM users WHERE id = $1"
+	query := "SELECT id, username, email, password, role, created_at, updated_at, version, failed_login_attempts, locked_until FROM users WHERE id = $1 AND deleted_at IS NULL"
 
-	err := r.db.QueryRow(context.Background(), query, id).Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.Version,
+		&user.FailedLoginAttempts, &user.LockedUntil)
 	if err != nil {
-		return user, err
+		return user, apperror.FromDB(err, "user")
 	}
 
 	return user, nil
 }
 
-func (r *usersRepository) UpdateUsers(id int, data *model.Users) error {
+func (r *usersRepository) UpdateUsers(ctx context.Context, id int, data *model.Users) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	query := `
 		UPDATE users
-		SET username = $1, email = $2, password = $3, role = $4, updated_at = NOW()
-		WHERE id = $5
+		SET username = $1, email = $2, role = $3, updated_at = NOW(), version = version + 1
+		WHERE id = $4 AND ($5 = 0 OR version = $5) AND deleted_at IS NULL
+		RETURNING version
 	`
-	result, err := r.db.Exec(context.Background(), query, data.Username, data.Email, data.Password, data.Role, id)
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.Username, data.Email, data.Role, id, data.Version).Scan(&data.Version)
+	if apperror.IsNoRows(err) {
+		log.Warn("user not updated: not found or version mismatch", zap.Int("user_id", id))
+		return resolveUpdateMiss(ctx, r.db, "users", "user", id)
+	}
 	if err != nil {
-		r.Logger.Error("failed to update user",
+		log.Error("failed to update user",
 			zap.Int("user_id", id),
 			zap.Error(err),
 		)
-		return err
+		return apperror.FromDB(err, "user")
 	}
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		r.Logger.Warn("user not found for update", zap.Int("user_id", id))
-		return errors.New("user not found or already deleted")
-	}
-	r.Logger.Info("user updated successfully",
+	log.Info("user updated successfully",
 		zap.Int("user_id", id),
 		zap.String("username", data.Username),
 	)
 	return nil
 }
 
-func (r *usersRepository) DeleteUsers(id int) error {
-	query := `DELETE FROM users WHERE id = $1`
+// password tidak bisa di-patch, gantinya lewat UpdatePassword
+var usersPatchColumns = map[string]bool{
+	"username": true,
+	"email":    true,
+	"role":     true,
+}
 
-	result, err := r.db.Exec(context.Background(), query, id)
+func (r *usersRepository) PatchUsers(ctx context.Context, id, version int, changes map[string]any) (*model.Users, error) {
+	query, args, err := buildPatch("users", usersPatchColumns, changes, id, version,
+		"id, username, email, password, role, created_at, updated_at, version")
 	if err != nil {
-		r.Logger.Error("failed to delete user",
+		return nil, err
+	}
+
+	var user model.Users
+	err = conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.Version)
+	if apperror.IsNoRows(err) {
+		return nil, resolveUpdateMiss(ctx, r.db, "users", "user", id)
+	}
+	if err != nil {
+		return nil, apperror.FromDB(err, "user")
+	}
+	return &user, nil
+}
+
+func (r *usersRepository) DeleteUsers(ctx context.Context, id int) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+
+	err := softDelete(ctx, r.db, "users", "user", id, "")
+	if apperror.IsNotFound(err) {
+		log.Warn("user not found for deletion", zap.Int("user_id", id))
+		return err
+	}
+	if err != nil {
+		log.Error("failed to delete user",
 			zap.Int("user_id", id),
 			zap.Error(err),
 		)
 		return err
 	}
-	rowAffected := result.RowsAffected()
 
-	if rowAffected == 0 {
-		r.Logger.Warn("user not found for deletion", zap.Int("user_id", id))
-		return errors.New("no rows affected")
+	log.Info("user deleted successfully", zap.Int("user_id", id))
+	return nil
+}
+
+func (r *usersRepository) RestoreUsers(ctx context.Context, id int) (*model.Users, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+
+	var user model.Users
+	err := restoreDeleted(ctx, r.db, "users", "user", id,
+		"id, username, email, password, role, created_at, updated_at, version",
+		&user.Id, &user.Username, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("user restored successfully", zap.Int("user_id", id))
+	return &user, nil
+}
+
+// UpdatePassword menyimpan hash password baru tanpa cek If-Match; version tetap
+// dinaikkan supaya ETag lama tidak berlaku lagi
+func (r *usersRepository) UpdatePassword(ctx context.Context, id int, hashedPassword string) error {
+	log := utils.LoggerFromContext(ctx, r.Logger)
+	query := `
+		UPDATE users
+		SET password = $1, updated_at = NOW(), version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+	result, err := conn(ctx, r.db).Exec(ctx, query, hashedPassword, id)
+	if err != nil {
+		log.Error("failed to update password", zap.Int("user_id", id), zap.Error(err))
+		return apperror.FromDB(err, "user")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("user")
 	}
 
-	r.Logger.Info("user deleted successfully", zap.Int("user_id", id))
+	log.Info("user password updated", zap.Int("user_id", id))
 	return nil
-}
\ No newline at end of file
+}
+
+// RecordLoginFailure menambah hitungan login gagal secara atomik dan mengunci akun
+// sampai lockUntil saat hitungan mencapai maxAttempts. Lock yang sudah lewat
+// (locked_until <= now) dianggap selesai sehingga hitungan mulai lagi dari 1.
+func (r *usersRepository) RecordLoginFailure(ctx context.Context, id int, now time.Time, maxAttempts int, lockUntil time.Time) (int, *time.Time, error) {
+	query := `
+		WITH current AS (
+			SELECT id,
+			       CASE WHEN locked_until IS NOT NULL AND locked_until <= $2 THEN 1
+			            ELSE failed_login_attempts + 1 END AS attempts,
+			       CASE WHEN locked_until <= $2 THEN NULL ELSE locked_until END AS locked_until
+			FROM users
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE users u
+		SET failed_login_attempts = c.attempts,
+		    locked_until = CASE WHEN c.attempts >= $3 THEN $4 ELSE c.locked_until END
+		FROM current c
+		WHERE u.id = c.id
+		RETURNING u.failed_login_attempts, u.locked_until
+	`
+	var attempts int
+	var lockedUntil *time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, query, id, now, maxAttempts, lockUntil).Scan(&attempts, &lockedUntil)
+	if err != nil {
+		return 0, nil, apperror.FromDB(err, "user")
+	}
+	return attempts, lockedUntil, nil
+}
+
+// ResetLoginFailures menghapus hitungan login gagal dan lock akun (login berhasil atau unlock admin)
+func (r *usersRepository) ResetLoginFailures(ctx context.Context, id int) error {
+	query := `UPDATE users SET failed_login_attempts = 0, locked_until = NULL WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return apperror.FromDB(err, "user")
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("user")
+	}
+	return nil
+}
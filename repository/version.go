@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database"
+)
+
+// AnyVersion dipakai saat client mengirim "If-Match: *" (update tanpa cek versi)
+const AnyVersion = 0
+
+// resolveUpdateMiss dipanggil saat UPDATE ... WHERE id AND version tidak mengenai baris apa pun.
+// Membedakan data yang memang tidak ada atau sudah dihapus (404) dengan versi yang sudah berubah (412).
+func resolveUpdateMiss(ctx context.Context, db database.PgxIface, table, entity string, id int) error {
+	var current int
+	err := conn(ctx, db).QueryRow(ctx, "SELECT version FROM "+table+" WHERE id = $1 AND deleted_at IS NULL", id).Scan(&current)
+	if err != nil {
+		return apperror.FromDB(err, entity)
+	}
+	return apperror.PreconditionFailed(entity, current)
+}
@@ -2,20 +2,22 @@ package repository
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/database"
 	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
 
 	"go.uber.org/zap"
 )
 
 type WarehousesRepository interface {
-	GetWarehousesById(id int) (*model.Warehouses, error)
-	GetAllWarehouses(page, limit int) ([]model.Warehouses, int, error)
-	CreateWarehouses(data *model.Warehouses) error
-	UpdateWarehouses(id int, data *model.Warehouses) error
-	DeleteWarehouses(id int) error
+	GetWarehousesById(ctx context.Context, id int) (*model.Warehouses, error)
+	GetAllWarehouses(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Warehouses, int, error)
+	CreateWarehouses(ctx context.Context, data *model.Warehouses) error
+	UpdateWarehouses(ctx context.Context, id int, data *model.Warehouses) error
+	PatchWarehouses(ctx context.Context, id, version int, changes map[string]any) (*model.Warehouses, error)
+	DeleteWarehouses(ctx context.Context, id int) error
+	RestoreWarehouses(ctx context.Context, id int) (*model.Warehouses, error)
 }
 
 type warehousesRepository struct {
@@ -27,46 +29,49 @@ func NewWarehousesRepository(db database.PgxIface, log *zap.Logger) WarehousesRe
 	return &warehousesRepository{db: db, Logger: log}
 }
 
-func (r *warehousesRepository) GetWarehousesById(id int) (*model.Warehouses, error) {
+func (r *warehousesRepository) GetWarehousesById(ctx context.Context, id int) (*model.Warehouses, error) {
 	query := `
-		SELECT id, name, location, created_at, updated_at
+		SELECT id, name, location, created_at, updated_at, version
 		FROM warehouses
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	var warehouse model.Warehouses
-	err := r.db.QueryRow(context.Background(), query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&warehouse.Id,
 		&warehouse.Name,
 		&warehouse.Location,
 		&warehouse.CreatedAt,
 		&warehouse.UpdatedAt,
+		&warehouse.Version,
 	)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("warehouse not found")
+	if err != nil {
+		return nil, apperror.FromDB(err, "warehouse")
 	}
-	return &warehouse, err
+	return &warehouse, nil
 }
 
-func (r *warehousesRepository) GetAllWarehouses(page, limit int) ([]model.Warehouses, int, error) {
+func (r *warehousesRepository) GetAllWarehouses(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Warehouses, int, error) {
+	log := utils.LoggerFromContext(ctx, r.Logger)
 	offset := (page - 1) * limit
 
 	// get total data for pagination
 	var total int
-	countQuery := `SELECT COUNT(*) FROM warehouses`
-	err := r.db.QueryRow(context.Background(), countQuery).Scan(&total)
+	countQuery := `SELECT COUNT(*) FROM warehouses WHERE ($1 OR deleted_at IS NULL)`
+	err := conn(ctx, r.db).QueryRow(ctx, countQuery, includeDeleted).Scan(&total)
 	if err != nil {
-		r.Logger.Error("error query findall repo ", zap.Error(err))
+		log.Error("error query findall repo ", zap.Error(err))
 		return nil, 0, err
 	}
 
 	// get data with pagination
 	query := `
-		SELECT id, name, location, created_at, updated_at
+		SELECT id, name, location, created_at, updated_at, version, deleted_at
 		FROM warehouses
+		WHERE ($3 OR deleted_at IS NULL)
 		ORDER BY id
 		LIMIT $1 OFFSET $2
 	`
-	rows, err := r.db.Query(context.Background(), query, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, offset, includeDeleted)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -81,6 +86,8 @@ func (r *warehousesRepository) GetAllWarehouses(page, limit int) ([]model.Wareho
 			&warehouse.Location,
 			&warehouse.CreatedAt,
 			&warehouse.UpdatedAt,
+			&warehouse.Version,
+			&warehouse.DeletedAt,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -91,46 +98,65 @@ func (r *warehousesRepository) GetAllWarehouses(page, limit int) ([]model.Wareho
 	return warehouses, total, nil
 }
 
-func (r *warehousesRepository) CreateWarehouses(data *model.Warehouses) error {
+func (r *warehousesRepository) CreateWarehouses(ctx context.Context, data *model.Warehouses) error {
 	query := `
 		INSERT INTO warehouses (name, location, created_at, updated_at)
 		VALUES ($1, $2, NOW(), NOW())
-		RETURNING id
+		RETURNING id, version
 	`
-	err := r.db.QueryRow(context.Background(), query, data.Name, data.Location).Scan(&data.Id)
-	return err
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.Name, data.Location).Scan(&data.Id, &data.Version)
+	return apperror.FromDB(err, "warehouse")
 }
 
-func (r *warehousesRepository) UpdateWarehouses(id int, data *model.Warehouses) error {
+func (r *warehousesRepository) UpdateWarehouses(ctx context.Context, id int, data *model.Warehouses) error {
 	query := `
 		UPDATE warehouses
-		SET name = $1, location = $2, updated_at = NOW()
-		WHERE id = $3`
-
-	result, err := r.db.Exec(context.Background(), query, data.Name, data.Location, id)
-	if err != nil {
-		return err
-	}
-	rowAffected := result.RowsAffected()
+		SET name = $1, location = $2, updated_at = NOW(), version = version + 1
+		WHERE id = $3 AND ($4 = 0 OR version = $4) AND deleted_at IS NULL
+		RETURNING version`
 
-	if rowAffected == 0 {
-		return errors.New("no rows affected")
+	err := conn(ctx, r.db).QueryRow(ctx, query, data.Name, data.Location, id, data.Version).Scan(&data.Version)
+	if apperror.IsNoRows(err) {
+		return resolveUpdateMiss(ctx, r.db, "warehouses", "warehouse", id)
 	}
-	return err
+	return apperror.FromDB(err, "warehouse")
 }
 
-func (r *warehousesRepository) DeleteWarehouses(id int) error {
-	query := `DELETE FROM warehouses WHERE id = $1`
+var warehousesPatchColumns = map[string]bool{
+	"name":     true,
+	"location": true,
+}
 
-	result, err := r.db.Exec(context.Background(), query, id)
+func (r *warehousesRepository) PatchWarehouses(ctx context.Context, id, version int, changes map[string]any) (*model.Warehouses, error) {
+	query, args, err := buildPatch("warehouses", warehousesPatchColumns, changes, id, version,
+		"id, name, location, created_at, updated_at, version")
 	if err != nil {
-		return err
+		return nil, err
 	}
-	rowAffected := result.RowsAffected()
 
-	if rowAffected == 0 {
-		return errors.New("no rows affected")
+	var w model.Warehouses
+	err = conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&w.Id, &w.Name, &w.Location, &w.CreatedAt, &w.UpdatedAt, &w.Version)
+	if apperror.IsNoRows(err) {
+		return nil, resolveUpdateMiss(ctx, r.db, "warehouses", "warehouse", id)
 	}
+	if err != nil {
+		return nil, apperror.FromDB(err, "warehouse")
+	}
+	return &w, nil
+}
 
-	return err
+func (r *warehousesRepository) DeleteWarehouses(ctx context.Context, id int) error {
+	return softDelete(ctx, r.db, "warehouses", "warehouse", id,
+		"SELECT 1 FROM racks WHERE racks.warehouse_id = warehouses.id AND racks.deleted_at IS NULL")
 }
+
+func (r *warehousesRepository) RestoreWarehouses(ctx context.Context, id int) (*model.Warehouses, error) {
+	var w model.Warehouses
+	err := restoreDeleted(ctx, r.db, "warehouses", "warehouse", id,
+		"id, name, location, created_at, updated_at, version",
+		&w.Id, &w.Name, &w.Location, &w.CreatedAt, &w.UpdatedAt, &w.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
\ No newline at end of file
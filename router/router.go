@@ -1,9 +1,11 @@
 package router
 
 import (
+	"project-app-inventory-restapi-golang-azwin/docs"
 	"project-app-inventory-restapi-golang-azwin/handler"
 	mCostume "project-app-inventory-restapi-golang-azwin/middleware"
 	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
 
 	"net/http"
 
@@ -11,11 +13,17 @@ import (
 	"go.uber.org/zap"
 )
 
-func NewRouter(handler handler.Handler, service service.Service, log *zap.Logger) http.Handler {
+func NewRouter(handler handler.Handler, service service.Service, config utils.Configuration, log *zap.Logger) http.Handler {
 	r := chi.NewRouter()
 
 
 	mw := mCostume.NewMiddlewareCustome(service, log)
+	mw.RateLimiter = mCostume.NewRateLimiter(config.RateLimit, mCostume.NewMemoryRateLimitStore())
+
+	// dokumentasi API (OpenAPI 3 + UI yang di-embed)
+	r.Get("/openapi.json", docs.ServeSpec)
+	r.Get("/docs", docs.ServeUI)
+
 	r.Mount("/", ApiV1(handler, mw))
 
 	return r
@@ -23,9 +31,33 @@ func NewRouter(handler handler.Handler, service service.Service, log *zap.Logger
 
 func ApiV1(handler handler.Handler, mw mCostume.MiddlewareCostume) *chi.Mux{
 	r := chi.NewRouter()
+	r.Use(mw.RequestID)
+	r.Use(mw.Tracing)
 	r.Use(mw.Logging)
+	r.Use(mw.Authentication)
+	r.Use(mw.RateLimit)
+
+	// Authentication meloloskan request tanpa token, jadi setiap route yang
+	// mengubah data wajib memakai salah satu guard ini. RequireRole dengan role
+	// juga menolak user yang belum menyelesaikan 2FA.
+	staff := mw.RequireRole("super_admin", "admin", "staff")
+	admin := mw.RequireRole("super_admin", "admin")
+	superAdmin := mw.RequireRole("super_admin")
+
+	r.Route("/auth", func(r chi.Router) {
+		// login dengan email dan password
+		r.Post("/login", handler.AuthHandler.Login)
+		// langkah kedua login untuk user dengan 2FA aktif
+		r.Post("/login/2fa", handler.AuthHandler.VerifyTwoFactor)
+		// logout (cabut token yang sedang dipakai)
+		r.With(mw.RequireRole()).Post("/logout", handler.AuthHandler.Logout)
+		// ganti password memakai token reset dari admin
+		r.Post("/password-reset", handler.PasswordHandler.ResetPassword)
+	})
 	
 	r.Route("/items", func(r chi.Router) {
+		// Idempotency-Key untuk semua POST
+		r.Use(mw.Idempotency)
 		// get low stock items (must be before /{id} to avoid conflict)
 		r.Get("/low-stock", handler.ItemsHandler.GetLowStockItems)
 		// get item by id
@@ -33,50 +65,72 @@ func ApiV1(handler handler.Handler, mw mCostume.MiddlewareCostume) *chi.Mux{
 		// get all items
 		r.Get("/", handler.ItemsHandler.GetAllItems)
 		// create item
-		r.Post("/", handler.ItemsHandler.CreateItems)
+		r.With(staff).Post("/", handler.ItemsHandler.CreateItems)
 		// update item
-		r.Put("/{id}", handler.ItemsHandler.UpdateItems)
+		r.With(staff).Put("/{id}", handler.ItemsHandler.UpdateItems)
+		// partial update item (JSON Merge Patch)
+		r.With(staff).Patch("/{id}", handler.ItemsHandler.PatchItems)
 		// delete item
-		r.Delete("/{id}", handler.ItemsHandler.DeleteItems)
+		r.With(admin).Delete("/{id}", handler.ItemsHandler.DeleteItems)
+		// restore soft deleted item
+		r.With(admin).Post("/{id}/restore", handler.ItemsHandler.RestoreItems)
 	})
 	
 	r.Route("/categories", func(r chi.Router) {
+		// Idempotency-Key untuk semua POST
+		r.Use(mw.Idempotency)
 		// get category by id
 		r.Get("/{id}", handler.CategoriesHandler.GetCategoriesById)
 		// get all categories
 		r.Get("/", handler.CategoriesHandler.GetAllCategories)
 		// create category
-		r.Post("/", handler.CategoriesHandler.CreateCategories)
+		r.With(staff).Post("/", handler.CategoriesHandler.CreateCategories)
 		// update category
-		r.Put("/{id}", handler.CategoriesHandler.UpdateCategories)
+		r.With(staff).Put("/{id}", handler.CategoriesHandler.UpdateCategories)
+		// partial update category (JSON Merge Patch)
+		r.With(staff).Patch("/{id}", handler.CategoriesHandler.PatchCategories)
 		// delete category
-		r.Delete("/{id}", handler.CategoriesHandler.DeleteCategories)
+		r.With(admin).Delete("/{id}", handler.CategoriesHandler.DeleteCategories)
+		// restore soft deleted category
+		r.With(admin).Post("/{id}/restore", handler.CategoriesHandler.RestoreCategories)
 	})
 
 	r.Route("/racks", func(r chi.Router) {
+		// Idempotency-Key untuk semua POST
+		r.Use(mw.Idempotency)
 		// get rack by id
 		r.Get("/{id}", handler.RacksHandler.GetRacksById)
 		// get all racks
 		r.Get("/", handler.RacksHandler.GetAllRacks)
 		// create rack
-		r.Post("/", handler.RacksHandler.CreateRacks)
+		r.With(staff).Post("/", handler.RacksHandler.CreateRacks)
 		// update rack
-		r.Put("/{id}", handler.RacksHandler.UpdateRacks)
+		r.With(staff).Put("/{id}", handler.RacksHandler.UpdateRacks)
+		// partial update rack (JSON Merge Patch)
+		r.With(staff).Patch("/{id}", handler.RacksHandler.PatchRacks)
 		// delete rack
-		r.Delete("/{id}", handler.RacksHandler.DeleteRacks)
+		r.With(admin).Delete("/{id}", handler.RacksHandler.DeleteRacks)
+		// restore soft deleted rack
+		r.With(admin).Post("/{id}/restore", handler.RacksHandler.RestoreRacks)
 	})
 
 	r.Route("/warehouses", func(r chi.Router) {
+		// Idempotency-Key untuk semua POST
+		r.Use(mw.Idempotency)
 		// get warehouse by id
 		r.Get("/{id}", handler.WarehousesHandler.GetWarehousesById)
 		// get all warehouses
 		r.Get("/", handler.WarehousesHandler.GetAllWarehouses)
 		// create warehouse
-		r.Post("/", handler.WarehousesHandler.CreateWarehouses)
+		r.With(staff).Post("/", handler.WarehousesHandler.CreateWarehouses)
 		// update warehouse
-		r.Put("/{id}", handler.WarehousesHandler.UpdateWarehouses)
+		r.With(staff).Put("/{id}", handler.WarehousesHandler.UpdateWarehouses)
+		// partial update warehouse (JSON Merge Patch)
+		r.With(staff).Patch("/{id}", handler.WarehousesHandler.PatchWarehouses)
 		// delete warehouse
-		r.Delete("/{id}", handler.WarehousesHandler.DeleteWarehouses)
+		r.With(admin).Delete("/{id}", handler.WarehousesHandler.DeleteWarehouses)
+		// restore soft deleted warehouse
+		r.With(admin).Post("/{id}/restore", handler.WarehousesHandler.RestoreWarehouses)
 	})
 
 	r.Route("/users", func(r chi.Router) {
@@ -87,24 +141,89 @@ func ApiV1(handler handler.Handler, mw mCostume.MiddlewareCostume) *chi.Mux{
 		// get user by email
 		r.Get("/email", handler.UsersHandler.GetUsersByEmail)
 		// create user
-		r.Post("/", handler.UsersHandler.CreateUsers)
+		r.With(superAdmin, mw.Idempotency).Post("/", handler.UsersHandler.CreateUsers)
 		// update user
-		r.Put("/{id}", handler.UsersHandler.UpdateUsers)
+		r.With(superAdmin).Put("/{id}", handler.UsersHandler.UpdateUsers)
+		// partial update user (JSON Merge Patch)
+		r.With(superAdmin).Patch("/{id}", handler.UsersHandler.PatchUsers)
 		// delete user
-		r.Delete("/{id}", handler.UsersHandler.DeleteUsers)
+		r.With(superAdmin).Delete("/{id}", handler.UsersHandler.DeleteUsers)
+		// restore soft deleted user
+		r.With(superAdmin, mw.Idempotency).Post("/{id}/restore", handler.UsersHandler.RestoreUsers)
+		// ganti password sendiri (wajib password lama)
+		r.With(mw.RequireRole()).Post("/me/password", handler.PasswordHandler.ChangeMyPassword)
+		// 2FA (TOTP) milik user yang sedang login
+		r.With(mw.RequireRole()).Post("/me/2fa", handler.TwoFactorHandler.Enroll)
+		r.With(mw.RequireRole()).Post("/me/2fa/confirm", handler.TwoFactorHandler.Confirm)
+		r.With(mw.RequireRole()).Post("/me/2fa/disable", handler.TwoFactorHandler.Disable)
+		r.With(mw.RequireRole()).Post("/me/2fa/recovery-codes", handler.TwoFactorHandler.RegenerateRecoveryCodes)
+		// admin membuat token reset password
+		r.With(superAdmin).Post("/{id}/password-reset", handler.PasswordHandler.IssuePasswordReset)
+		// buka akun yang terkunci karena login gagal
+		r.With(superAdmin).Post("/{id}/unlock", handler.AuthHandler.UnlockUser)
 	})
 
 	r.Route("/sales", func(r chi.Router) {
+		// Idempotency-Key: retry POS setelah timeout tidak membuat sale kedua
+		r.Use(mw.Idempotency)
 		// get sale by id
 		r.Get("/{id}", handler.SalesHandler.GetSalesById)
 		// get all sales
 		r.Get("/", handler.SalesHandler.GetAllSales)
 		// create sale
-		r.Post("/", handler.SalesHandler.CreateSales)
+		r.With(staff).Post("/", handler.SalesHandler.CreateSales)
 		// update sale
-		r.Put("/{id}", handler.SalesHandler.UpdateSales)
-		// delete sale
-		r.Delete("/{id}", handler.SalesHandler.DeleteSales)
+		r.With(staff).Put("/{id}", handler.SalesHandler.UpdateSales)
+		// hard delete hanya untuk draft; sale completed dibatalkan lewat void
+		r.With(superAdmin).Delete("/{id}", handler.SalesHandler.DeleteSales)
+		// void sale: stock dikembalikan, data tetap disimpan
+		r.With(staff).Post("/{id}/void", handler.SalesHandler.VoidSale)
+		// lifecycle draft/quote -> held -> completed
+		r.With(staff).Post("/{id}/hold", handler.SalesHandler.HoldSale)
+		r.With(staff).Post("/{id}/release", handler.SalesHandler.ReleaseHold)
+		r.With(staff).Post("/{id}/complete", handler.SalesHandler.CompleteSale)
+		// pembayaran sale (split tender)
+		r.Get("/{id}/payments", handler.PaymentsHandler.GetSalePayments)
+		r.With(staff).Post("/{id}/payments", handler.PaymentsHandler.CreatePayments)
+		// cetak struk thermal / invoice PDF
+		r.Get("/{id}/receipt", handler.ReceiptsHandler.GetReceipt)
+	})
+
+	r.Route("/customers", func(r chi.Router) {
+		// Idempotency-Key untuk semua POST
+		r.Use(mw.Idempotency)
+		// get customer by id
+		r.Get("/{id}", handler.CustomersHandler.GetCustomersById)
+		// get all customers
+		r.Get("/", handler.CustomersHandler.GetAllCustomers)
+		// riwayat penjualan customer
+		r.Get("/{id}/sales", handler.CustomersHandler.GetCustomerSales)
+		// create customer
+		r.With(staff).Post("/", handler.CustomersHandler.CreateCustomers)
+		// update customer
+		r.With(staff).Put("/{id}", handler.CustomersHandler.UpdateCustomers)
+		// partial update customer (JSON Merge Patch)
+		r.With(staff).Patch("/{id}", handler.CustomersHandler.PatchCustomers)
+		// delete customer
+		r.With(admin).Delete("/{id}", handler.CustomersHandler.DeleteCustomers)
+		// restore soft deleted customer
+		r.With(admin).Post("/{id}/restore", handler.CustomersHandler.RestoreCustomers)
+	})
+
+	r.Route("/reservations", func(r chi.Router) {
+		// created_by diambil dari user yang login / pemilik API key
+		r.Use(mw.RequireRole())
+		// Idempotency-Key: retry order online tidak memesan stock dua kali
+		r.Use(mw.Idempotency)
+		// get all reservations (?status=, ?reference=)
+		r.Get("/", handler.ReservationsHandler.GetAllReservations)
+		// get reservation by id
+		r.Get("/{id}", handler.ReservationsHandler.GetReservationById)
+		// pesan stock tanpa menguranginya
+		r.With(staff).Post("/", handler.ReservationsHandler.CreateReservation)
+		// active -> confirmed (stock dikurangi) / released (pesanan dilepas)
+		r.With(staff).Post("/{id}/confirm", handler.ReservationsHandler.ConfirmReservation)
+		r.With(staff).Post("/{id}/release", handler.ReservationsHandler.ReleaseReservation)
 	})
 
 	r.Route("/reports", func(r chi.Router) {
@@ -114,6 +233,30 @@ func ApiV1(handler handler.Handler, mw mCostume.MiddlewareCostume) *chi.Mux{
 		r.Get("/sales", handler.ReportsHandler.GetSalesReport)
 		// get revenue report - pendapatan
 		r.Get("/revenue", handler.ReportsHandler.GetRevenueReport)
+		// get customer purchase history report
+		r.Get("/customers/{id}", handler.ReportsHandler.GetCustomerReport)
+		// rekap kas harian per kasir
+		r.Get("/cash-drawer", handler.ReportsHandler.GetCashDrawerReport)
+	})
+
+	r.Route("/api-keys", func(r chi.Router) {
+		r.Use(mw.RequireRole())
+		// get all api keys (milik sendiri, super_admin: semua)
+		r.Get("/", handler.APIKeysHandler.GetAllAPIKeys)
+		// get api key by id
+		r.Get("/{id}", handler.APIKeysHandler.GetAPIKeyById)
+		// create api key (key hanya ditampilkan sekali)
+		r.Post("/", handler.APIKeysHandler.CreateAPIKey)
+		// update nama, scope dan expiry
+		r.Put("/{id}", handler.APIKeysHandler.UpdateAPIKey)
+		// revoke api key
+		r.Delete("/{id}", handler.APIKeysHandler.RevokeAPIKey)
+	})
+
+	r.Route("/audit", func(r chi.Router) {
+		r.Use(superAdmin)
+		// get audit log
+		r.Get("/", handler.AuditHandler.GetAuditLogs)
 	})
 
 	return r
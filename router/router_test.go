@@ -0,0 +1,126 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"project-app-inventory-restapi-golang-azwin/docs"
+	"project-app-inventory-restapi-golang-azwin/handler"
+	mCostume "project-app-inventory-restapi-golang-azwin/middleware"
+	"project-app-inventory-restapi-golang-azwin/service"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// registeredRoutes mengembalikan "METHOD /path" untuk setiap route di ApiV1
+func registeredRoutes(t *testing.T) map[string]bool {
+	t.Helper()
+	mw := mCostume.NewMiddlewareCustome(service.Service{}, zap.NewNop())
+	r := ApiV1(handler.Handler{}, mw)
+
+	routes := map[string]bool{}
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(strings.ReplaceAll(route, "/*/", "/"), "/")
+		if route == "" {
+			route = "/"
+		}
+		routes[method+" "+route] = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, routes)
+	return routes
+}
+
+func documentedRoutes() map[string]bool {
+	routes := map[string]bool{}
+	for path, item := range docs.Spec().Paths {
+		for method := range item {
+			routes[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+	return routes
+}
+
+func TestOpenAPI_CoversEveryRoute(t *testing.T) {
+	documented := documentedRoutes()
+	for route := range registeredRoutes(t) {
+		assert.True(t, documented[route], "route %q is registered in ApiV1 but missing from docs.Routes", route)
+	}
+}
+
+func TestOpenAPI_HasNoStaleRoutes(t *testing.T) {
+	registered := registeredRoutes(t)
+	for route := range documentedRoutes() {
+		assert.True(t, registered[route], "route %q is documented but not registered in ApiV1", route)
+	}
+}
+
+func TestOpenAPI_SchemasFromValidateTags(t *testing.T) {
+	spec := docs.Spec()
+
+	items := spec.Components.Schemas["ItemsRequest"]
+	require.NotNil(t, items)
+	assert.ElementsMatch(t, []string{"category_id", "rack_id", "name", "sku", "price"}, items.Required)
+	require.NotNil(t, items.Properties["name"].MinLength)
+	assert.Equal(t, 3, *items.Properties["name"].MinLength)
+	require.NotNil(t, items.Properties["stock"].Minimum)
+	assert.Equal(t, float64(0), *items.Properties["stock"].Minimum)
+	// model.Money: angka fixed-point, bukan string walaupun punya MarshalJSON
+	assert.Equal(t, "number", items.Properties["price"].Type)
+	assert.Equal(t, "decimal", items.Properties["price"].Format)
+
+	users := spec.Components.Schemas["Usersrequest"]
+	require.NotNil(t, users)
+	assert.Equal(t, "email", users.Properties["email"].Format)
+
+	// password tidak pernah muncul di response
+	assert.NotContains(t, spec.Components.Schemas["Users"].Properties, "password")
+}
+
+func TestNewRouter_ServesSpecAndDocs(t *testing.T) {
+	r := NewRouter(handler.Handler{}, service.Service{}, utils.Configuration{}, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/openapi.json")
+}
+
+// Authentication meloloskan request anonymous, jadi setiap route yang mengubah
+// data harus ditolak RequireRole sebelum sampai ke handler
+func TestApiV1_MutatingRoutesRequireLogin(t *testing.T) {
+	mw := mCostume.NewMiddlewareCustome(service.Service{}, zap.NewNop())
+	r := ApiV1(handler.Handler{}, mw)
+	public := map[string]bool{
+		"POST /auth/login":          true,
+		"POST /auth/login/2fa":      true,
+		"POST /auth/password-reset": true,
+	}
+
+	for route := range registeredRoutes(t) {
+		method, path, _ := strings.Cut(route, " ")
+		if method == http.MethodGet || public[route] {
+			continue
+		}
+		target := strings.NewReplacer("{id}", "1").Replace(path)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader("{}")))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, "%s must require login", route)
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"time"
+)
+
+const (
+	// apiKeyPrefix membedakan API key dari token lain (mis. saat ter-commit ke repo)
+	apiKeyPrefix = "ik_"
+	apiKeyBytes  = 32
+	// bagian awal key yang disimpan apa adanya untuk ditampilkan di daftar
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+)
+
+type APIKeysService interface {
+	GetAllAPIKeys(ctx context.Context, actor utils.Actor, page, limit int) ([]model.APIKey, int, error)
+	GetAPIKeyById(ctx context.Context, actor utils.Actor, id int) (*model.APIKey, error)
+	CreateAPIKey(ctx context.Context, actor utils.Actor, req dto.APIKeyRequest) (*dto.APIKeyCreatedResponse, error)
+	UpdateAPIKey(ctx context.Context, actor utils.Actor, id int, req dto.APIKeyUpdateRequest) (*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, actor utils.Actor, id int) error
+}
+
+type apiKeysService struct {
+	Repo  repository.APIKeysRepository
+	Users repository.UsersRepository
+	Audit Auditor
+	now   func() time.Time
+}
+
+func NewAPIKeysService(repo repository.APIKeysRepository, users repository.UsersRepository, audit Auditor) APIKeysService {
+	return &apiKeysService{Repo: repo, Users: users, Audit: audit, now: time.Now}
+}
+
+// GetAllAPIKeys: super_admin melihat semua key, user lain hanya miliknya
+func (s *apiKeysService) GetAllAPIKeys(ctx context.Context, actor utils.Actor, page, limit int) ([]model.APIKey, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "APIKeysService.GetAllAPIKeys")
+	defer span.End()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var userId *int
+	if !isSuperAdmin(actor) {
+		userId = &actor.UserId
+	}
+	return s.Repo.GetAllAPIKeys(ctx, userId, page, limit)
+}
+
+func (s *apiKeysService) GetAPIKeyById(ctx context.Context, actor utils.Actor, id int) (*model.APIKey, error) {
+	ctx, span := utils.Tracer().Start(ctx, "APIKeysService.GetAPIKeyById")
+	defer span.End()
+
+	return s.getOwned(ctx, actor, id)
+}
+
+// CreateAPIKey membuat key dengan role pemiliknya. Key lengkap hanya ada di response ini.
+func (s *apiKeysService) CreateAPIKey(ctx context.Context, actor utils.Actor, req dto.APIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "APIKeysService.CreateAPIKey")
+	defer span.End()
+
+	// session yang belum menyelesaikan 2FA tidak boleh membuat kredensial baru
+	// yang melewati 2FA (grup /api-keys memakai RequireRole() tanpa role)
+	if actor.TwoFactorPending {
+		return nil, apperror.TwoFactorRequired()
+	}
+
+	ownerId := actor.UserId
+	if req.UserId != 0 && req.UserId != actor.UserId {
+		if !isSuperAdmin(actor) {
+			return nil, apperror.Forbidden("only super_admin can create api keys for other users")
+		}
+		ownerId = req.UserId
+	}
+	if err := s.validateExpiry(req.ExpiredAt); err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateRandomToken(apiKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	rawKey := apiKeyPrefix + secret
+
+	key := model.APIKey{
+		UserId:    ownerId,
+		Name:      req.Name,
+		Prefix:    rawKey[:apiKeyDisplayLength],
+		Scopes:    req.Scopes,
+		ExpiredAt: req.ExpiredAt,
+	}
+	err = s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		owner, err := s.Users.GetUsersByID(ctx, ownerId)
+		if err != nil {
+			return err
+		}
+		key.Role = owner.Role
+		if err := s.Repo.CreateAPIKey(ctx, &key, utils.HashToken(rawKey)); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditCreate, "api_key", key.Id, nil, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.APIKeyCreatedResponse{APIKey: key, Key: rawKey}, nil
+}
+
+func (s *apiKeysService) UpdateAPIKey(ctx context.Context, actor utils.Actor, id int, req dto.APIKeyUpdateRequest) (*model.APIKey, error) {
+	ctx, span := utils.Tracer().Start(ctx, "APIKeysService.UpdateAPIKey")
+	defer span.End()
+
+	if err := s.validateExpiry(req.ExpiredAt); err != nil {
+		return nil, err
+	}
+
+	var updated *model.APIKey
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getOwned(ctx, actor, id)
+		if err != nil {
+			return err
+		}
+
+		after := *before
+		after.Name, after.Scopes, after.ExpiredAt = req.Name, req.Scopes, req.ExpiredAt
+		if err := s.Repo.UpdateAPIKey(ctx, &after); err != nil {
+			return err
+		}
+		updated = &after
+		return s.Audit.Record(ctx, model.AuditUpdate, "api_key", id, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// RevokeAPIKey mencabut key; request berikutnya dengan key ini ditolak 401
+func (s *apiKeysService) RevokeAPIKey(ctx context.Context, actor utils.Actor, id int) error {
+	ctx, span := utils.Tracer().Start(ctx, "APIKeysService.RevokeAPIKey")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getOwned(ctx, actor, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.RevokeAPIKey(ctx, id); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditDelete, "api_key", id, before, nil)
+	})
+}
+
+// getOwned menyembunyikan key milik user lain sebagai not found (kecuali untuk super_admin)
+func (s *apiKeysService) getOwned(ctx context.Context, actor utils.Actor, id int) (*model.APIKey, error) {
+	key, err := s.Repo.GetAPIKeyById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.UserId != actor.UserId && !isSuperAdmin(actor) {
+		return nil, apperror.NotFound("api_key")
+	}
+	return key, nil
+}
+
+func (s *apiKeysService) validateExpiry(expiredAt *time.Time) error {
+	if expiredAt != nil && !expiredAt.After(s.now()) {
+		return apperror.Validation("expired_at must be in the future", nil)
+	}
+	return nil
+}
+
+func isSuperAdmin(actor utils.Actor) bool {
+	return actor.Role == "super_admin"
+}
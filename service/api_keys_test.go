@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAPIKeysRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeysRepository) CreateAPIKey(ctx context.Context, key *model.APIKey, keyHash string) error {
+	args := m.Called(key, keyHash)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeysRepository) GetAPIKeyById(ctx context.Context, id int) (*model.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeysRepository) GetAllAPIKeys(ctx context.Context, userId *int, page, limit int) ([]model.APIKey, int, error) {
+	args := m.Called(userId, page, limit)
+	return args.Get(0).([]model.APIKey), args.Int(1), args.Error(2)
+}
+
+func (m *MockAPIKeysRepository) UpdateAPIKey(ctx context.Context, key *model.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeysRepository) RevokeAPIKey(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeysRepository) RevokeUserAPIKeys(ctx context.Context, userId int) (int64, error) {
+	args := m.Called(userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAPIKeysRepository) GetActiveAPIKey(ctx context.Context, keyHash string) (*model.APIKey, *model.Users, error) {
+	args := m.Called(keyHash)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.APIKey), args.Get(1).(*model.Users), args.Error(2)
+}
+
+func (m *MockAPIKeysRepository) TouchAPIKey(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestAPIKeysService_CreateAPIKey(t *testing.T) {
+	repo := new(MockAPIKeysRepository)
+	users := new(MockUsersRepository)
+	audit := &fakeAuditor{}
+	service := NewAPIKeysService(repo, users, audit)
+
+	users.On("GetUsersByID", 9).Return(model.Users{Id: 9, Role: "cashier"}, nil)
+	repo.On("CreateAPIKey", mock.AnythingOfType("*model.APIKey"), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { args.Get(0).(*model.APIKey).Id = 3 }).
+		Return(nil)
+
+	req := dto.APIKeyRequest{Name: "POS 1", Scopes: []string{"items:read", "sales:write"}}
+	result, err := service.CreateAPIKey(context.Background(), utils.Actor{UserId: 9, Role: "cashier"}, req)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result.Key, apiKeyPrefix))
+	assert.Equal(t, result.Key[:apiKeyDisplayLength], result.Prefix)
+	assert.Equal(t, "cashier", result.Role)
+
+	// yang disimpan hanya hash
+	assert.Equal(t, utils.HashToken(result.Key), repo.Calls[0].Arguments.String(1))
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, "api_key", audit.entries[0].Entity)
+		assert.Equal(t, 3, audit.entries[0].EntityId)
+	}
+}
+
+func TestAPIKeysService_CreateAPIKey_TwoFactorPending(t *testing.T) {
+	repo := new(MockAPIKeysRepository)
+	service := NewAPIKeysService(repo, new(MockUsersRepository), &fakeAuditor{})
+
+	req := dto.APIKeyRequest{Name: "POS 1", Scopes: []string{"items:read"}}
+	_, err := service.CreateAPIKey(context.Background(), utils.Actor{UserId: 9, Role: "admin", TwoFactorPending: true}, req)
+
+	assert.Equal(t, "two_factor_required", apperror.Code(err))
+	repo.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeysService_CreateAPIKey_ForOtherUser(t *testing.T) {
+	repo := new(MockAPIKeysRepository)
+	users := new(MockUsersRepository)
+	service := NewAPIKeysService(repo, users, &fakeAuditor{})
+	req := dto.APIKeyRequest{Name: "ERP sync", Scopes: []string{"items:read"}, UserId: 12}
+
+	_, err := service.CreateAPIKey(context.Background(), utils.Actor{UserId: 9, Role: "admin"}, req)
+	assert.True(t, apperror.Is(err, apperror.KindForbidden))
+
+	users.On("GetUsersByID", 12).Return(model.Users{Id: 12, Role: "integration"}, nil)
+	repo.On("CreateAPIKey", mock.AnythingOfType("*model.APIKey"), mock.Anything).Return(nil)
+
+	result, err := service.CreateAPIKey(context.Background(), utils.Actor{UserId: 1, Role: "super_admin"}, req)
+	require.NoError(t, err)
+	assert.Equal(t, 12, result.UserId)
+	assert.Equal(t, "integration", result.Role)
+}
+
+func TestAPIKeysService_CreateAPIKey_ExpiryInPast(t *testing.T) {
+	service := NewAPIKeysService(new(MockAPIKeysRepository), new(MockUsersRepository), &fakeAuditor{})
+	past := time.Now().Add(-time.Hour)
+
+	_, err := service.CreateAPIKey(context.Background(), utils.Actor{UserId: 9}, dto.APIKeyRequest{Name: "x", Scopes: []string{"items:read"}, ExpiredAt: &past})
+
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+}
+
+func TestAPIKeysService_GetAPIKeyById_HidesOtherUsersKeys(t *testing.T) {
+	repo := new(MockAPIKeysRepository)
+	service := NewAPIKeysService(repo, new(MockUsersRepository), &fakeAuditor{})
+	repo.On("GetAPIKeyById", 3).Return(&model.APIKey{Id: 3, UserId: 12}, nil)
+
+	_, err := service.GetAPIKeyById(context.Background(), utils.Actor{UserId: 9, Role: "admin"}, 3)
+	assert.True(t, apperror.IsNotFound(err))
+
+	key, err := service.GetAPIKeyById(context.Background(), utils.Actor{UserId: 1, Role: "super_admin"}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, key.UserId)
+}
+
+func TestAPIKeysService_GetAllAPIKeys_ScopedToOwner(t *testing.T) {
+	repo := new(MockAPIKeysRepository)
+	service := NewAPIKeysService(repo, new(MockUsersRepository), &fakeAuditor{})
+	owner := 9
+	repo.On("GetAllAPIKeys", &owner, 1, 10).Return([]model.APIKey{}, 0, nil)
+	repo.On("GetAllAPIKeys", (*int)(nil), 1, 10).Return([]model.APIKey{}, 0, nil)
+
+	_, _, err := service.GetAllAPIKeys(context.Background(), utils.Actor{UserId: 9, Role: "admin"}, 0, 0)
+	assert.NoError(t, err)
+	_, _, err = service.GetAllAPIKeys(context.Background(), utils.Actor{UserId: 1, Role: "super_admin"}, 1, 10)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestAuthService_AuthenticateAPIKey(t *testing.T) {
+	service := newTestAuthService(new(MockSessionsRepository), new(MockUsersRepository), time.Now())
+	apiKeys := service.APIKeys.(*MockAPIKeysRepository)
+
+	valid := "ik_valid"
+	stale := "ik_stale"
+	apiKeys.On("GetActiveAPIKey", utils.HashToken(valid)).
+		Return(&model.APIKey{Id: 3, Role: "cashier", Scopes: []string{"sales:write"}}, &model.Users{Id: 9, Username: "pos", Role: "cashier"}, nil)
+	// role user sudah berubah sejak key dibuat
+	apiKeys.On("GetActiveAPIKey", utils.HashToken(stale)).
+		Return(&model.APIKey{Id: 4, Role: "cashier"}, &model.Users{Id: 9, Role: "admin"}, nil)
+	apiKeys.On("GetActiveAPIKey", utils.HashToken("ik_unknown")).Return(nil, nil, apperror.NotFound("api_key"))
+	apiKeys.On("TouchAPIKey", 3).Return(nil)
+
+	actor, err := service.AuthenticateAPIKey(context.Background(), valid)
+	require.NoError(t, err)
+	assert.Equal(t, utils.Actor{UserId: 9, Username: "pos", Role: "cashier", APIKeyId: 3, Scopes: []string{"sales:write"}}, *actor)
+	assert.True(t, actor.HasScope("sales:write"))
+	assert.False(t, actor.HasScope("items:write"))
+
+	for _, key := range []string{stale, "ik_unknown", "no-prefix"} {
+		_, err := service.AuthenticateAPIKey(context.Background(), key)
+		assert.True(t, apperror.Is(err, apperror.KindUnauthorized), key)
+	}
+	apiKeys.AssertNotCalled(t, "TouchAPIKey", 4)
+}
+
+// dto memakai oneof yang ditulis ulang, jadi pastikan sama dengan model.APIKeyScopes
+func TestAPIKeyScopes_MatchRequestValidation(t *testing.T) {
+	_, err := utils.ValidateErrors(dto.APIKeyRequest{Name: "all", Scopes: model.APIKeyScopes})
+	assert.NoError(t, err)
+
+	_, err = utils.ValidateErrors(dto.APIKeyUpdateRequest{Name: "all", Scopes: model.APIKeyScopes})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"reflect"
+	"sort"
+)
+
+// Auditor dipakai service lain untuk mencatat create/update/delete ke audit_log
+// di dalam transaksi yang sama dengan perubahan datanya.
+type Auditor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	Record(ctx context.Context, action, entity string, entityId int, before, after any) error
+}
+
+type auditor struct {
+	Tx   repository.Transactor
+	Repo repository.AuditRepository
+}
+
+func NewAuditor(tx repository.Transactor, repo repository.AuditRepository) Auditor {
+	return &auditor{Tx: tx, Repo: repo}
+}
+
+func (a *auditor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return a.Tx.WithinTx(ctx, fn)
+}
+
+// Record menyimpan diff before/after beserta actor, IP dan request id dari context
+func (a *auditor) Record(ctx context.Context, action, entity string, entityId int, before, after any) error {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	entry := &model.AuditLog{
+		Action:    action,
+		Entity:    entity,
+		EntityId:  entityId,
+		Changes:   changes,
+		IP:        utils.ClientIPFromContext(ctx),
+		RequestId: utils.RequestIDFromContext(ctx),
+	}
+	if actor, ok := utils.ActorFromContext(ctx); ok {
+		entry.ActorId = &actor.UserId
+		entry.ActorUsername = actor.Username
+		entry.ActorRole = actor.Role
+	}
+
+	return a.Repo.CreateAuditLog(ctx, entry)
+}
+
+// fieldChange adalah nilai satu field sebelum dan sesudah perubahan
+type fieldChange struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// auditDiff membandingkan representasi JSON before dan after (nil untuk create/delete)
+// dan hanya menyimpan field yang berubah. updated_at selalu berubah sehingga diabaikan.
+func auditDiff(before, after any) (json.RawMessage, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for name := range beforeFields {
+		names[name] = true
+	}
+	for name := range afterFields {
+		names[name] = true
+	}
+	delete(names, "updated_at")
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	diff := map[string]fieldChange{}
+	for _, name := range sorted {
+		if reflect.DeepEqual(beforeFields[name], afterFields[name]) {
+			continue
+		}
+		diff[name] = fieldChange{Before: beforeFields[name], After: afterFields[name]}
+	}
+	return json.Marshal(diff)
+}
+
+func jsonFields(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+type AuditService interface {
+	GetAuditLogs(ctx context.Context, filter repository.AuditFilter, page, limit int) ([]model.AuditLog, int, error)
+}
+
+type auditService struct {
+	Repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{Repo: repo}
+}
+
+func (s *auditService) GetAuditLogs(ctx context.Context, filter repository.AuditFilter, page, limit int) ([]model.AuditLog, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "AuditService.GetAuditLogs")
+	defer span.End()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	return s.Repo.GetAuditLogs(ctx, filter, page, limit)
+}
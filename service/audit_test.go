@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuditor menjalankan fn langsung (tanpa transaksi) dan menyimpan entry yang dicatat
+type fakeAuditor struct {
+	entries []auditEntry
+	err     error
+}
+
+type auditEntry struct {
+	Action, Entity string
+	EntityId       int
+	Before, After  any
+}
+
+func (f *fakeAuditor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (f *fakeAuditor) Record(ctx context.Context, action, entity string, entityId int, before, after any) error {
+	f.entries = append(f.entries, auditEntry{action, entity, entityId, before, after})
+	return f.err
+}
+
+type fakeTransactor struct{ calls int }
+
+func (f *fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.calls++
+	return fn(ctx)
+}
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) CreateAuditLog(ctx context.Context, data *model.AuditLog) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) GetAuditLogs(ctx context.Context, filter repository.AuditFilter, page, limit int) ([]model.AuditLog, int, error) {
+	args := m.Called(filter, page, limit)
+	return args.Get(0).([]model.AuditLog), args.Int(1), args.Error(2)
+}
+
+func TestAuditDiff_OnlyChangedFields(t *testing.T) {
+	before := model.Items{Id: 1, Name: "Laptop", Stock: 10, Price: model.MustParseMoney("100"), UpdatedAt: time.Now(), Version: 1}
+	after := before
+	after.Stock = 7
+	after.Version = 2
+	after.UpdatedAt = time.Now().Add(time.Minute)
+
+	changes, err := auditDiff(before, after)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"stock":{"before":10,"after":7},"version":{"before":1,"after":2}}`, string(changes))
+}
+
+func TestAuditDiff_CreateHasOnlyAfter(t *testing.T) {
+	changes, err := auditDiff(nil, model.Categories{Id: 3, Name: "Tools"})
+	require.NoError(t, err)
+
+	var diff map[string]map[string]any
+	require.NoError(t, json.Unmarshal(changes, &diff))
+	assert.Equal(t, map[string]any{"after": "Tools"}, diff["name"])
+	assert.NotContains(t, diff, "updated_at")
+}
+
+func TestAuditDiff_UserPasswordIsNeverRecorded(t *testing.T) {
+	before := model.Users{Id: 1, Username: "ani", Password: "hash-lama"}
+	after := before
+	after.Password = "hash-baru"
+
+	changes, err := auditDiff(userAudit(before, false), userAudit(after, true))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"password_changed":{"after":true}}`, string(changes))
+	assert.NotContains(t, string(changes), "hash")
+}
+
+func TestAuditor_RecordUsesActorFromContext(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	tx := &fakeTransactor{}
+	auditor := NewAuditor(tx, mockRepo)
+
+	ctx := utils.WithActor(context.Background(), utils.Actor{UserId: 7, Username: "admin", Role: "super_admin"})
+	ctx = utils.WithClientIP(ctx, "10.0.0.1")
+	ctx = utils.WithRequestID(ctx, "req-1")
+
+	mockRepo.On("CreateAuditLog", mock.MatchedBy(func(entry *model.AuditLog) bool {
+		return entry.ActorId != nil && *entry.ActorId == 7 &&
+			entry.ActorUsername == "admin" && entry.ActorRole == "super_admin" &&
+			entry.Action == model.AuditDelete && entry.Entity == "rack" && entry.EntityId == 4 &&
+			entry.IP == "10.0.0.1" && entry.RequestId == "req-1"
+	})).Return(nil)
+
+	err := auditor.WithinTx(ctx, func(ctx context.Context) error {
+		return auditor.Record(ctx, model.AuditDelete, "rack", 4, model.Racks{Id: 4}, nil)
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tx.calls)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditor_RecordAnonymous(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	auditor := NewAuditor(&fakeTransactor{}, mockRepo)
+
+	mockRepo.On("CreateAuditLog", mock.MatchedBy(func(entry *model.AuditLog) bool {
+		return entry.ActorId == nil && entry.ActorUsername == ""
+	})).Return(nil)
+
+	err := auditor.Record(context.Background(), model.AuditCreate, "item", 1, nil, model.Items{Id: 1})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_GetAuditLogs_ClampsLimit(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+	filter := repository.AuditFilter{Entity: "item"}
+
+	mockRepo.On("GetAuditLogs", filter, 1, 100).Return([]model.AuditLog{}, 0, nil)
+
+	_, _, err := service.GetAuditLogs(context.Background(), filter, 0, 500)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestItemsService_DeleteItems_AuditFailureRollsBack(t *testing.T) {
+	mockRepo := new(MockItemsRepository)
+	audit := &fakeAuditor{err: errors.New("audit insert failed")}
+	service := NewItemsService(mockRepo, audit)
+
+	mockRepo.On("GetItemsById", 1).Return(&model.Items{Id: 1}, nil)
+	mockRepo.On("DeleteItems", 1).Return(nil)
+
+	err := service.DeleteItems(context.Background(), 1)
+
+	assert.EqualError(t, err, "audit insert failed")
+	require.Len(t, audit.entries, 1)
+	assert.Equal(t, model.AuditDelete, audit.entries[0].Action)
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, email, password string) (*dto.LoginResponse, error)
+	Authenticate(ctx context.Context, token string) (*utils.Actor, error)
+	AuthenticateAPIKey(ctx context.Context, key string) (*utils.Actor, error)
+	Logout(ctx context.Context, token string) error
+	VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*dto.LoginResponse, error)
+	UnlockAccount(ctx context.Context, userId int) error
+}
+
+// challengeTokenBytes adalah panjang challenge token 2FA sebelum di-hex
+const challengeTokenBytes = 32
+
+type authService struct {
+	Sessions        repository.SessionsRepository
+	Users           repository.UsersRepository
+	TwoFactor       repository.TwoFactorRepository
+	APIKeys         repository.APIKeysRepository
+	Audit           Auditor
+	SessionTTL      time.Duration
+	Lockout         utils.LockoutConfig
+	TwoFactorConfig utils.TwoFactorConfig
+	throttle        *loginThrottle
+	now             func() time.Time
+}
+
+func NewAuthService(sessions repository.SessionsRepository, users repository.UsersRepository, twoFactor repository.TwoFactorRepository, apiKeys repository.APIKeysRepository, audit Auditor, config utils.AuthConfig) AuthService {
+	return &authService{
+		Sessions:        sessions,
+		Users:           users,
+		TwoFactor:       twoFactor,
+		APIKeys:         apiKeys,
+		Audit:           audit,
+		SessionTTL:      config.SessionTTL,
+		Lockout:         config.Lockout,
+		TwoFactorConfig: config.TwoFactor,
+		throttle:        newLoginThrottle(config.Lockout.IPMaxAttempts, config.Lockout.IPWindow),
+		now:             time.Now,
+	}
+}
+
+// Login memeriksa email dan password lalu membuat session baru.
+// Pesan error sengaja sama untuk email tidak terdaftar dan password salah.
+// Login gagal dihitung per akun (akun dikunci setelah Lockout.MaxAttempts) dan per IP.
+// User dengan 2FA aktif mendapat challenge token, bukan session.
+func (s *authService) Login(ctx context.Context, email, password string) (*dto.LoginResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "AuthService.Login")
+	defer span.End()
+
+	log := utils.LoggerFromContext(ctx, nil)
+	now := s.now()
+	ip := utils.ClientIPFromContext(ctx)
+
+	if wait := s.throttle.blocked(ip, now); wait > 0 {
+		log.Warn("login blocked for ip", zap.String("ip", ip), zap.Duration("retry_after", wait))
+		return nil, apperror.TooManyRequests("too_many_attempts", "too many failed login attempts, try again later", wait)
+	}
+
+	user, err := s.Users.GetUsersByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		s.throttle.fail(ip, now)
+		return nil, apperror.Unauthorized("invalid email or password")
+	}
+
+	// akun terkunci: password tidak diperiksa sama sekali
+	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		log.Warn("login rejected, account locked",
+			zap.Int("user_id", user.Id),
+			zap.Time("locked_until", *user.LockedUntil),
+			zap.String("ip", ip),
+		)
+		return nil, accountLocked(user.LockedUntil.Sub(now))
+	}
+
+	if !utils.CheckPassword(password, user.Password) {
+		s.throttle.fail(ip, now)
+
+		attempts, lockedUntil, err := s.Users.RecordLoginFailure(ctx, user.Id, now, s.Lockout.MaxAttempts, now.Add(s.Lockout.Duration))
+		if err != nil {
+			return nil, err
+		}
+		if lockedUntil != nil && lockedUntil.After(now) {
+			log.Warn("account locked",
+				zap.Int("user_id", user.Id),
+				zap.Int("failed_attempts", attempts),
+				zap.Time("locked_until", *lockedUntil),
+				zap.String("ip", ip),
+			)
+			return nil, accountLocked(lockedUntil.Sub(now))
+		}
+		return nil, apperror.Unauthorized("invalid email or password")
+	}
+
+	// dengan 2FA, password benar belum berarti login berhasil: counter gagal
+	// baru di-reset setelah kode 2FA benar di VerifyTwoFactor
+	if user.TwoFactorEnabled {
+		return s.createChallenge(ctx, user)
+	}
+
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := s.Users.ResetLoginFailures(ctx, user.Id); err != nil {
+			return nil, err
+		}
+		user.FailedLoginAttempts, user.LockedUntil = 0, nil
+	}
+	return s.createSession(ctx, user)
+}
+
+// VerifyTwoFactor menukar challenge dari Login + kode TOTP (atau recovery code) dengan session.
+// Kode salah dihitung sebagai login gagal sehingga ikut lockout.
+func (s *authService) VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*dto.LoginResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "AuthService.VerifyTwoFactor")
+	defer span.End()
+
+	log := utils.LoggerFromContext(ctx, nil)
+	now := s.now()
+	ip := utils.ClientIPFromContext(ctx)
+
+	if wait := s.throttle.blocked(ip, now); wait > 0 {
+		log.Warn("login blocked for ip", zap.String("ip", ip), zap.Duration("retry_after", wait))
+		return nil, apperror.TooManyRequests("too_many_attempts", "too many failed login attempts, try again later", wait)
+	}
+
+	tokenHash := utils.HashToken(challengeToken)
+	userId, err := s.TwoFactor.GetLoginChallenge(ctx, tokenHash)
+	if apperror.IsNotFound(err) {
+		s.throttle.fail(ip, now)
+		return nil, apperror.Unauthorized("invalid or expired login challenge")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.Users.GetUsersByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	// akun terkunci setelah challenge dibuat (misal lewat login password lain):
+	// kode tidak diperiksa sama sekali
+	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		log.Warn("two-factor login rejected, account locked",
+			zap.Int("user_id", userId),
+			zap.Time("locked_until", *user.LockedUntil),
+			zap.String("ip", ip),
+		)
+		return nil, accountLocked(user.LockedUntil.Sub(now))
+	}
+
+	ok, err := verifySecondFactor(ctx, s.TwoFactor, userId, code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.throttle.fail(ip, now)
+
+		attempts, lockedUntil, err := s.Users.RecordLoginFailure(ctx, userId, now, s.Lockout.MaxAttempts, now.Add(s.Lockout.Duration))
+		if err != nil {
+			return nil, err
+		}
+		if lockedUntil != nil && lockedUntil.After(now) {
+			// challenge tidak boleh dipakai lagi setelah akun terkunci
+			if err := s.TwoFactor.ConsumeLoginChallenge(ctx, tokenHash); err != nil && !apperror.IsNotFound(err) {
+				return nil, err
+			}
+			log.Warn("account locked",
+				zap.Int("user_id", userId),
+				zap.Int("failed_attempts", attempts),
+				zap.Time("locked_until", *lockedUntil),
+				zap.String("ip", ip),
+			)
+			return nil, accountLocked(lockedUntil.Sub(now))
+		}
+		return nil, apperror.Unauthorized("invalid two-factor code")
+	}
+
+	// challenge hanya bisa ditukar sekali
+	if err := s.TwoFactor.ConsumeLoginChallenge(ctx, tokenHash); apperror.IsNotFound(err) {
+		return nil, apperror.Unauthorized("invalid or expired login challenge")
+	} else if err != nil {
+		return nil, err
+	}
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := s.Users.ResetLoginFailures(ctx, userId); err != nil {
+			return nil, err
+		}
+		user.FailedLoginAttempts, user.LockedUntil = 0, nil
+	}
+	user.TwoFactorEnabled = true
+	return s.createSession(ctx, &user)
+}
+
+func (s *authService) createSession(ctx context.Context, user *model.Users) (*dto.LoginResponse, error) {
+	session, err := s.Sessions.CreateSession(ctx, user.Id, s.now().Add(s.SessionTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{
+		Token:                  session.Token,
+		ExpiredAt:              session.ExpiredAt,
+		User:                   *user,
+		TwoFactorSetupRequired: !user.TwoFactorEnabled && s.TwoFactorConfig.Requires(user.Role),
+	}, nil
+}
+
+func (s *authService) createChallenge(ctx context.Context, user *model.Users) (*dto.LoginResponse, error) {
+	token, err := utils.GenerateRandomToken(challengeTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	expiredAt := s.now().Add(s.TwoFactorConfig.ChallengeTTL)
+	if err := s.TwoFactor.CreateLoginChallenge(ctx, user.Id, utils.HashToken(token), expiredAt); err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{
+		ExpiredAt:         expiredAt,
+		User:              *user,
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+	}, nil
+}
+
+// Authenticate mengubah token session menjadi Actor
+func (s *authService) Authenticate(ctx context.Context, token string) (*utils.Actor, error) {
+	ctx, span := utils.Tracer().Start(ctx, "AuthService.Authenticate")
+	defer span.End()
+
+	if _, err := uuid.Parse(token); err != nil {
+		return nil, apperror.Unauthorized("invalid or expired token")
+	}
+
+	session, user, err := s.Sessions.GetActiveSession(ctx, token)
+	if apperror.IsNotFound(err) {
+		return nil, apperror.Unauthorized("invalid or expired token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &utils.Actor{
+		UserId:           user.Id,
+		Username:         user.Username,
+		Role:             user.Role,
+		SessionId:        session.Id,
+		TwoFactorPending: !user.TwoFactorEnabled && s.TwoFactorConfig.Requires(user.Role),
+	}, nil
+}
+
+// AuthenticateAPIKey mengubah API key menjadi Actor dengan scope key tersebut.
+// Key ditolak jika role pemiliknya sudah berubah sejak key dibuat.
+func (s *authService) AuthenticateAPIKey(ctx context.Context, key string) (*utils.Actor, error) {
+	ctx, span := utils.Tracer().Start(ctx, "AuthService.AuthenticateAPIKey")
+	defer span.End()
+
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, apperror.Unauthorized("invalid or expired api key")
+	}
+
+	apiKey, user, err := s.APIKeys.GetActiveAPIKey(ctx, utils.HashToken(key))
+	if apperror.IsNotFound(err) {
+		return nil, apperror.Unauthorized("invalid or expired api key")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.Role != user.Role {
+		return nil, apperror.Unauthorized("invalid or expired api key")
+	}
+
+	// last_used_at hanya informasi, gagal update tidak menggagalkan request
+	if err := s.APIKeys.TouchAPIKey(ctx, apiKey.Id); err != nil {
+		utils.LoggerFromContext(ctx, nil).Warn("failed to update api key last_used_at", zap.Int("api_key_id", apiKey.Id), zap.Error(err))
+	}
+
+	return &utils.Actor{
+		UserId:   user.Id,
+		Username: user.Username,
+		Role:     apiKey.Role,
+		APIKeyId: apiKey.Id,
+		Scopes:   apiKey.Scopes,
+	}, nil
+}
+
+func (s *authService) Logout(ctx context.Context, token string) error {
+	ctx, span := utils.Tracer().Start(ctx, "AuthService.Logout")
+	defer span.End()
+
+	return s.Sessions.RevokeSession(ctx, token)
+}
+
+// UnlockAccount membuka akun yang terkunci karena login gagal (super_admin)
+func (s *authService) UnlockAccount(ctx context.Context, userId int) error {
+	ctx, span := utils.Tracer().Start(ctx, "AuthService.UnlockAccount")
+	defer span.End()
+
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Users.ResetLoginFailures(ctx, userId); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditUnlock, "user", userId, nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	utils.LoggerFromContext(ctx, nil).Info("account unlocked", zap.Int("user_id", userId))
+	return nil
+}
+
+func accountLocked(retryAfter time.Duration) error {
+	return apperror.TooManyRequests("account_locked", "account is temporarily locked due to too many failed login attempts", retryAfter)
+}
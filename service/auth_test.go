@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSessionsRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionsRepository) CreateSession(ctx context.Context, userId int, expiredAt time.Time) (*model.Sessions, error) {
+	args := m.Called(userId, expiredAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Sessions), args.Error(1)
+}
+
+func (m *MockSessionsRepository) GetActiveSession(ctx context.Context, token string) (*model.Sessions, *model.Users, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.Sessions), args.Get(1).(*model.Users), args.Error(2)
+}
+
+func (m *MockSessionsRepository) RevokeSession(ctx context.Context, token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockSessionsRepository) RevokeUserSessions(ctx context.Context, userId int) (int64, error) {
+	args := m.Called(userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var testAuthConfig = utils.AuthConfig{
+	SessionTTL: time.Hour,
+	Lockout:    utils.LockoutConfig{MaxAttempts: 3, Duration: 15 * time.Minute, IPMaxAttempts: 5, IPWindow: 10 * time.Minute},
+	TwoFactor:  utils.TwoFactorConfig{RequiredRoles: []string{"admin"}, ChallengeTTL: 5 * time.Minute},
+}
+
+func newTestAuthService(sessions *MockSessionsRepository, users *MockUsersRepository, now time.Time) *authService {
+	s := NewAuthService(sessions, users, new(MockTwoFactorRepository), new(MockAPIKeysRepository), &fakeAuditor{}, testAuthConfig).(*authService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestAuthService_Login_Success(t *testing.T) {
+	sessions := new(MockSessionsRepository)
+	users := new(MockUsersRepository)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	service := newTestAuthService(sessions, users, now)
+
+	user := &model.Users{Id: 4, Email: "ani@example.com", Password: mustHash(t, "rahasia123"), Role: "admin"}
+	users.On("GetUsersByEmail", "ani@example.com").Return(user, nil)
+	sessions.On("CreateSession", 4, now.Add(time.Hour)).
+		Return(&model.Sessions{Id: 1, UserId: 4, Token: "tok", ExpiredAt: now.Add(time.Hour)}, nil)
+
+	result, err := service.Login(context.Background(), "ani@example.com", "rahasia123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tok", result.Token)
+	assert.Equal(t, 4, result.User.Id)
+	sessions.AssertExpectations(t)
+}
+
+func TestAuthService_Login_InvalidCredentials(t *testing.T) {
+	sessions := new(MockSessionsRepository)
+	users := new(MockUsersRepository)
+	service := newTestAuthService(sessions, users, time.Now())
+
+	user := &model.Users{Id: 4, Email: "ani@example.com", Password: mustHash(t, "rahasia123")}
+	users.On("GetUsersByEmail", "ani@example.com").Return(user, nil)
+	users.On("GetUsersByEmail", "nobody@example.com").Return(nil, nil)
+	users.On("RecordLoginFailure", 4, mock.Anything, 3, mock.Anything).Return(1, nil, nil)
+
+	for _, email := range []string{"ani@example.com", "nobody@example.com"} {
+		_, err := service.Login(context.Background(), email, "salah")
+
+		assert.True(t, apperror.Is(err, apperror.KindUnauthorized))
+		assert.Equal(t, "invalid email or password", err.Error())
+	}
+	sessions.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_LocksAccountAfterMaxAttempts(t *testing.T) {
+	sessions := new(MockSessionsRepository)
+	users := new(MockUsersRepository)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	service := newTestAuthService(sessions, users, now)
+
+	lockedUntil := now.Add(15 * time.Minute)
+	user := &model.Users{Id: 4, Email: "ani@example.com", Password: mustHash(t, "rahasia123"), FailedLoginAttempts: 2}
+	users.On("GetUsersByEmail", "ani@example.com").Return(user, nil)
+	users.On("RecordLoginFailure", 4, now, 3, lockedUntil).Return(3, &lockedUntil, nil)
+
+	_, err := service.Login(context.Background(), "ani@example.com", "salah")
+
+	assert.True(t, apperror.Is(err, apperror.KindTooManyRequests))
+	assert.Equal(t, 15*time.Minute, apperror.RetryAfter(err))
+	var appErr *apperror.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, "account_locked", appErr.Code)
+	}
+	users.AssertExpectations(t)
+}
+
+func TestAuthService_Login_LockedAccountSkipsPasswordCheck(t *testing.T) {
+	sessions := new(MockSessionsRepository)
+	users := new(MockUsersRepository)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	service := newTestAuthService(sessions, users, now)
+
+	lockedUntil := now.Add(5 * time.Minute)
+	user := &model.Users{Id: 4, Email: "ani@example.com", Password: mustHash(t, "rahasia123"), FailedLoginAttempts: 3, LockedUntil: &lockedUntil}
+	users.On("GetUsersByEmail", "ani@example.com").Return(user, nil)
+
+	// password benar pun ditolak selama akun terkunci
+	_, err := service.Login(context.Background(), "ani@example.com", "rahasia123")
+
+	assert.True(t, apperror.Is(err, apperror.KindTooManyRequests))
+	assert.Equal(t, 5*time.Minute, apperror.RetryAfter(err))
+	users.AssertNotCalled(t, "RecordLoginFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	sessions.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_ExpiredLockResetOnSuccess(t *testing.T) {
+	sessions := new(MockSessionsRepository)
+	users := new(MockUsersRepository)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	service := newTestAuthService(sessions, users, now)
+
+	lockedUntil := now.Add(-time.Minute)
+	user := &model.Users{Id: 4, Email: "ani@example.com", Password: mustHash(t, "rahasia123"), FailedLoginAttempts: 3, LockedUntil: &lockedUntil}
+	users.On("GetUsersByEmail", "ani@example.com").Return(user, nil)
+	users.On("ResetLoginFailures", 4).Return(nil)
+	sessions.On("CreateSession", 4, now.Add(time.Hour)).
+		Return(&model.Sessions{Id: 1, UserId: 4, Token: "tok", ExpiredAt: now.Add(time.Hour)}, nil)
+
+	result, err := service.Login(context.Background(), "ani@example.com", "rahasia123")
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.User.LockedUntil)
+	users.AssertExpectations(t)
+}
+
+func TestAuthService_Login_ThrottlesIP(t *testing.T) {
+	sessions := new(MockSessionsRepository)
+	users := new(MockUsersRepository)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	service := newTestAuthService(sessions, users, now)
+	users.On("GetUsersByEmail", "nobody@example.com").Return(nil, nil)
+
+	ctx := utils.WithClientIP(context.Background(), "10.0.0.9")
+	for i := 0; i < 5; i++ {
+		_, err := service.Login(ctx, "nobody@example.com", "salah")
+		assert.True(t, apperror.Is(err, apperror.KindUnauthorized))
+	}
+
+	service.now = func() time.Time { return now.Add(4 * time.Minute) }
+	_, err := service.Login(ctx, "nobody@example.com", "salah")
+	assert.True(t, apperror.Is(err, apperror.KindTooManyRequests))
+	assert.Equal(t, 6*time.Minute, apperror.RetryAfter(err))
+	users.AssertNumberOfCalls(t, "GetUsersByEmail", 5)
+
+	// IP lain tidak ikut terblokir
+	_, err = service.Login(utils.WithClientIP(context.Background(), "10.0.0.10"), "nobody@example.com", "salah")
+	assert.True(t, apperror.Is(err, apperror.KindUnauthorized))
+
+	// window sudah lewat
+	service.now = func() time.Time { return now.Add(10 * time.Minute) }
+	_, err = service.Login(ctx, "nobody@example.com", "salah")
+	assert.True(t, apperror.Is(err, apperror.KindUnauthorized))
+}
+
+func TestAuthService_UnlockAccount(t *testing.T) {
+	users := new(MockUsersRepository)
+	service := newTestAuthService(new(MockSessionsRepository), users, time.Now())
+	audit := service.Audit.(*fakeAuditor)
+	users.On("ResetLoginFailures", 4).Return(nil)
+	users.On("ResetLoginFailures", 99).Return(apperror.NotFound("user"))
+
+	assert.NoError(t, service.UnlockAccount(context.Background(), 4))
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, model.AuditUnlock, audit.entries[0].Action)
+		assert.Equal(t, 4, audit.entries[0].EntityId)
+	}
+
+	err := service.UnlockAccount(context.Background(), 99)
+	assert.True(t, apperror.IsNotFound(err))
+	assert.Len(t, audit.entries, 1)
+}
+
+func TestAuthService_Login_TwoFactorChallenge(t *testing.T) {
+	sessions := new(MockSessionsRepository)
+	users := new(MockUsersRepository)
+	now := time.Unix(1234567890, 0)
+	service := newTestAuthService(sessions, users, now)
+	twoFactor := service.TwoFactor.(*MockTwoFactorRepository)
+	enabledAt := now.Add(-24 * time.Hour)
+
+	user := &model.Users{Id: 4, Email: "ani@example.com", Password: mustHash(t, "rahasia123"), Role: "admin", TwoFactorEnabled: true, FailedLoginAttempts: 2}
+	users.On("GetUsersByEmail", "ani@example.com").Return(user, nil)
+	twoFactor.On("CreateLoginChallenge", 4, mock.AnythingOfType("string"), now.Add(5*time.Minute)).Return(nil)
+
+	login, err := service.Login(context.Background(), "ani@example.com", "rahasia123")
+	require.NoError(t, err)
+	assert.True(t, login.TwoFactorRequired)
+	assert.Empty(t, login.Token)
+	assert.Equal(t, utils.HashToken(login.ChallengeToken), twoFactor.Calls[0].Arguments.String(1))
+	sessions.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	// password benar saja belum me-reset counter login gagal
+	users.AssertNotCalled(t, "ResetLoginFailures", mock.Anything)
+
+	challengeHash := utils.HashToken(login.ChallengeToken)
+	twoFactor.On("GetLoginChallenge", challengeHash).Return(4, nil)
+	twoFactor.On("GetTOTP", 4).Return(&model.UserTOTP{UserId: 4, Secret: rfcSecret, EnabledAt: &enabledAt}, nil)
+	twoFactor.On("UseTOTPStep", 4, totpStep(now)).Return(true, nil)
+	twoFactor.On("ConsumeLoginChallenge", challengeHash).Return(nil)
+	users.On("ResetLoginFailures", 4).Return(nil)
+	users.On("GetUsersByID", 4).Return(model.Users{Id: 4, Role: "admin", FailedLoginAttempts: 2}, nil)
+	sessions.On("CreateSession", 4, now.Add(time.Hour)).
+		Return(&model.Sessions{Id: 1, UserId: 4, Token: "tok", ExpiredAt: now.Add(time.Hour)}, nil)
+
+	result, err := service.VerifyTwoFactor(context.Background(), login.ChallengeToken, "005924")
+
+	require.NoError(t, err)
+	assert.Equal(t, "tok", result.Token)
+	assert.False(t, result.TwoFactorRequired)
+	twoFactor.AssertExpectations(t)
+	users.AssertCalled(t, "ResetLoginFailures", 4)
+}
+
+func TestAuthService_VerifyTwoFactor_LockedAccount(t *testing.T) {
+	users := new(MockUsersRepository)
+	now := time.Unix(1234567890, 0)
+	service := newTestAuthService(new(MockSessionsRepository), users, now)
+	twoFactor := service.TwoFactor.(*MockTwoFactorRepository)
+	lockedUntil := now.Add(10 * time.Minute)
+
+	challengeHash := utils.HashToken("challenge")
+	twoFactor.On("GetLoginChallenge", challengeHash).Return(4, nil)
+	users.On("GetUsersByID", 4).Return(model.Users{Id: 4, Role: "admin", FailedLoginAttempts: 3, LockedUntil: &lockedUntil}, nil)
+
+	// kode benar pun ditolak selama akun terkunci
+	_, err := service.VerifyTwoFactor(context.Background(), "challenge", "005924")
+
+	assert.Equal(t, "account_locked", apperror.Code(err))
+	assert.Equal(t, 10*time.Minute, apperror.RetryAfter(err))
+	twoFactor.AssertNotCalled(t, "GetTOTP", mock.Anything)
+	twoFactor.AssertNotCalled(t, "ConsumeLoginChallenge", mock.Anything)
+	users.AssertNotCalled(t, "ResetLoginFailures", mock.Anything)
+}
+
+func TestAuthService_VerifyTwoFactor_WrongCodeLocksAccount(t *testing.T) {
+	users := new(MockUsersRepository)
+	now := time.Unix(1234567890, 0)
+	service := newTestAuthService(new(MockSessionsRepository), users, now)
+	twoFactor := service.TwoFactor.(*MockTwoFactorRepository)
+	enabledAt := now.Add(-24 * time.Hour)
+	lockedUntil := now.Add(15 * time.Minute)
+
+	challengeHash := utils.HashToken("challenge")
+	twoFactor.On("GetLoginChallenge", challengeHash).Return(4, nil)
+	users.On("GetUsersByID", 4).Return(model.Users{Id: 4, Role: "admin"}, nil)
+	twoFactor.On("GetTOTP", 4).Return(&model.UserTOTP{UserId: 4, Secret: rfcSecret, EnabledAt: &enabledAt}, nil)
+	twoFactor.On("UseRecoveryCode", 4, mock.Anything).Return(apperror.NotFound("recovery_code"))
+	users.On("RecordLoginFailure", 4, now, 3, lockedUntil).Return(1, nil, nil).Once()
+	users.On("RecordLoginFailure", 4, now, 3, lockedUntil).Return(3, &lockedUntil, nil).Once()
+	twoFactor.On("ConsumeLoginChallenge", challengeHash).Return(nil)
+
+	_, err := service.VerifyTwoFactor(context.Background(), "challenge", "111111")
+	assert.True(t, apperror.Is(err, apperror.KindUnauthorized))
+	twoFactor.AssertNotCalled(t, "ConsumeLoginChallenge", mock.Anything)
+
+	_, err = service.VerifyTwoFactor(context.Background(), "challenge", "111111")
+	assert.True(t, apperror.Is(err, apperror.KindTooManyRequests))
+	twoFactor.AssertCalled(t, "ConsumeLoginChallenge", challengeHash)
+}
+
+func TestAuthService_Authenticate_TwoFactorPending(t *testing.T) {
+	sessions := new(MockSessionsRepository)
+	service := newTestAuthService(sessions, new(MockUsersRepository), time.Now())
+
+	token := "3f1c2a5e-8d4b-4c1e-9a7f-2b6d8e0c1a23"
+	sessions.On("GetActiveSession", token).
+		Return(&model.Sessions{Id: 2}, &model.Users{Id: 1, Role: "admin"}, nil)
+
+	actor, err := service.Authenticate(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.True(t, actor.TwoFactorPending)
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	sessions := new(MockSessionsRepository)
+	service := newTestAuthService(sessions, new(MockUsersRepository), time.Now())
+
+	valid := "3f1c2a5e-8d4b-4c1e-9a7f-2b6d8e0c1a23"
+	expired := "9e8d7c6b-5a49-4382-9170-6f5e4d3c2b1a"
+	sessions.On("GetActiveSession", valid).
+		Return(&model.Sessions{Id: 2}, &model.Users{Id: 4, Username: "ani", Role: "super_admin"}, nil)
+	sessions.On("GetActiveSession", expired).Return(nil, nil, apperror.NotFound("session"))
+
+	actor, err := service.Authenticate(context.Background(), valid)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.Actor{UserId: 4, Username: "ani", Role: "super_admin", SessionId: 2}, *actor)
+
+	for _, token := range []string{expired, "bukan-uuid"} {
+		_, err := service.Authenticate(context.Background(), token)
+		assert.True(t, apperror.Is(err, apperror.KindUnauthorized))
+	}
+}
+
+func mustHash(t *testing.T, password string) string {
+	t.Helper()
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hashed
+}
@@ -1,31 +1,42 @@
 package service
 
 import (
+	"context"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
 )
 
 type CategoriesService interface {
-	GetCategoriesById(id int) (*model.Categories, error)
-	GetAllCategories(page, limit int) ([]model.Categories, int, error)
-	CreateCategories(data *model.Categories) error
-	UpdateCategories(id int, data *model.Categories) error
-	DeleteCategories(id int) error
+	GetCategoriesById(ctx context.Context, id int) (*model.Categories, error)
+	GetAllCategories(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Categories, int, error)
+	CreateCategories(ctx context.Context, data *model.Categories) error
+	UpdateCategories(ctx context.Context, id int, data *model.Categories) error
+	PatchCategories(ctx context.Context, id, version int, changes map[string]any) (*model.Categories, error)
+	DeleteCategories(ctx context.Context, id int) error
+	RestoreCategories(ctx context.Context, id int) (*model.Categories, error)
 }
 
 type categoriesService struct {
-	Repo repository.CategoriesRepository
+	Repo  repository.CategoriesRepository
+	Audit Auditor
 }
 
-func NewCategoriesService(repo repository.CategoriesRepository) CategoriesService {
-	return &categoriesService{Repo: repo}
+func NewCategoriesService(repo repository.CategoriesRepository, audit Auditor) CategoriesService {
+	return &categoriesService{Repo: repo, Audit: audit}
 }
 
-func (s *categoriesService) GetCategoriesById(id int) (*model.Categories, error) {
-	return s.Repo.GetCategoriesById(id)
+func (s *categoriesService) GetCategoriesById(ctx context.Context, id int) (*model.Categories, error) {
+	ctx, span := utils.Tracer().Start(ctx, "CategoriesService.GetCategoriesById")
+	defer span.End()
+
+	return s.Repo.GetCategoriesById(ctx, id)
 }
 
-func (s *categoriesService) GetAllCategories(page, limit int) ([]model.Categories, int, error) {
+func (s *categoriesService) GetAllCategories(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Categories, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "CategoriesService.GetAllCategories")
+	defer span.End()
+
 	// Validate pagination parameters
 	if page < 1 {
 		page = 1
@@ -37,17 +48,97 @@ func (s *categoriesService) GetAllCategories(page, limit int) ([]model.Categorie
 		limit = 100
 	}
 	
-	return s.Repo.GetAllCategories(page, limit)
+	return s.Repo.GetAllCategories(ctx, page, limit, includeDeleted)
+}
+
+func (s *categoriesService) CreateCategories(ctx context.Context, data *model.Categories) error {
+	ctx, span := utils.Tracer().Start(ctx, "CategoriesService.CreateCategories")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Repo.CreateCategories(ctx, data); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditCreate, "category", data.Id, nil, data)
+	})
+}
+
+func (s *categoriesService) UpdateCategories(ctx context.Context, id int, data *model.Categories) error {
+	ctx, span := utils.Tracer().Start(ctx, "CategoriesService.UpdateCategories")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetCategoriesById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.UpdateCategories(ctx, id, data); err != nil {
+			return err
+		}
+
+		// baca ulang agar response dan audit berisi data yang benar-benar tersimpan
+		after, err := s.Repo.GetCategoriesById(ctx, id)
+		if err != nil {
+			return err
+		}
+		*data = *after
+		return s.Audit.Record(ctx, model.AuditUpdate, "category", id, before, after)
+	})
 }
 
-func (s *categoriesService) CreateCategories(data *model.Categories) error {
-	return s.Repo.CreateCategories(data)
+func (s *categoriesService) PatchCategories(ctx context.Context, id, version int, changes map[string]any) (*model.Categories, error) {
+	ctx, span := utils.Tracer().Start(ctx, "CategoriesService.PatchCategories")
+	defer span.End()
+
+	var patched *model.Categories
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetCategoriesById(ctx, id)
+		if err != nil {
+			return err
+		}
+		patched, err = s.Repo.PatchCategories(ctx, id, version, changes)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditUpdate, "category", id, before, patched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
 }
 
-func (s *categoriesService) UpdateCategories(id int, data *model.Categories) error {
-	return s.Repo.UpdateCategories(id, data)
+func (s *categoriesService) DeleteCategories(ctx context.Context, id int) error {
+	ctx, span := utils.Tracer().Start(ctx, "CategoriesService.DeleteCategories")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetCategoriesById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.DeleteCategories(ctx, id); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditDelete, "category", id, before, nil)
+	})
 }
 
-func (s *categoriesService) DeleteCategories(id int) error {
-	return s.Repo.DeleteCategories(id)
+func (s *categoriesService) RestoreCategories(ctx context.Context, id int) (*model.Categories, error) {
+	ctx, span := utils.Tracer().Start(ctx, "CategoriesService.RestoreCategories")
+	defer span.End()
+
+	var restored *model.Categories
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		restored, err = s.Repo.RestoreCategories(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditRestore, "category", id, nil, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
 }
\ No newline at end of file
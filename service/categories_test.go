@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"testing"
@@ -15,7 +16,7 @@ type MockCategoriesRepository struct {
 	mock.Mock
 }
 
-func (m *MockCategoriesRepository) GetCategoriesById(id int) (*model.Categories, error) {
+func (m *MockCategoriesRepository) GetCategoriesById(ctx context.Context, id int) (*model.Categories, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -23,29 +24,45 @@ func (m *MockCategoriesRepository) GetCategoriesById(id int) (*model.Categories,
 	return args.Get(0).(*model.Categories), args.Error(1)
 }
 
-func (m *MockCategoriesRepository) GetAllCategories(page, limit int) ([]model.Categories, int, error) {
-	args := m.Called(page, limit)
+func (m *MockCategoriesRepository) GetAllCategories(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Categories, int, error) {
+	args := m.Called(page, limit, includeDeleted)
 	return args.Get(0).([]model.Categories), args.Int(1), args.Error(2)
 }
 
-func (m *MockCategoriesRepository) CreateCategories(data *model.Categories) error {
+func (m *MockCategoriesRepository) CreateCategories(ctx context.Context, data *model.Categories) error {
 	args := m.Called(data)
 	return args.Error(0)
 }
 
-func (m *MockCategoriesRepository) UpdateCategories(id int, data *model.Categories) error {
+func (m *MockCategoriesRepository) UpdateCategories(ctx context.Context, id int, data *model.Categories) error {
 	args := m.Called(id, data)
 	return args.Error(0)
 }
 
-func (m *MockCategoriesRepository) DeleteCategories(id int) error {
+func (m *MockCategoriesRepository) PatchCategories(ctx context.Context, id, version int, changes map[string]any) (*model.Categories, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Categories), args.Error(1)
+}
+
+func (m *MockCategoriesRepository) DeleteCategories(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockCategoriesRepository) RestoreCategories(ctx context.Context, id int) (*model.Categories, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Categories), args.Error(1)
+}
+
 func TestCategoriesService_GetCategoriesById_Success(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	service := NewCategoriesService(mockRepo, &fakeAuditor{})
 
 	now := time.Now()
 	expected := &model.Categories{
@@ -57,7 +74,7 @@ func TestCategoriesService_GetCategoriesById_Success(t *testing.T) {
 
 	mockRepo.On("GetCategoriesById", 1).Return(expected, nil)
 
-	result, err := service.GetCategoriesById(1)
+	result, err := service.GetCategoriesById(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -66,11 +83,11 @@ func TestCategoriesService_GetCategoriesById_Success(t *testing.T) {
 
 func TestCategoriesService_GetCategoriesById_Error(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	service := NewCategoriesService(mockRepo, &fakeAuditor{})
 
 	mockRepo.On("GetCategoriesById", 999).Return(nil, errors.New("category not found"))
 
-	result, err := service.GetCategoriesById(999)
+	result, err := service.GetCategoriesById(context.Background(), 999)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -79,7 +96,7 @@ func TestCategoriesService_GetCategoriesById_Error(t *testing.T) {
 
 func TestCategoriesService_GetAllCategories_Success(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	service := NewCategoriesService(mockRepo, &fakeAuditor{})
 
 	now := time.Now()
 	categories := []model.Categories{
@@ -87,9 +104,9 @@ func TestCategoriesService_GetAllCategories_Success(t *testing.T) {
 		{Id: 2, Name: "Furniture", CreatedAt: now, UpdatedAt: now},
 	}
 
-	mockRepo.On("GetAllCategories", 1, 10).Return(categories, 2, nil)
+	mockRepo.On("GetAllCategories", 1, 10, false).Return(categories, 2, nil)
 
-	result, total, err := service.GetAllCategories(1, 10)
+	result, total, err := service.GetAllCategories(context.Background(), 1, 10, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, total)
@@ -99,13 +116,13 @@ func TestCategoriesService_GetAllCategories_Success(t *testing.T) {
 
 func TestCategoriesService_GetAllCategories_ValidationPage(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	service := NewCategoriesService(mockRepo, &fakeAuditor{})
 
 	categories := []model.Categories{}
-	mockRepo.On("GetAllCategories", 1, 10).Return(categories, 0, nil)
+	mockRepo.On("GetAllCategories", 1, 10, false).Return(categories, 0, nil)
 
 	// Test with invalid page (should default to 1)
-	result, total, err := service.GetAllCategories(0, 10)
+	result, total, err := service.GetAllCategories(context.Background(), 0, 10, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, total)
@@ -115,13 +132,13 @@ func TestCategoriesService_GetAllCategories_ValidationPage(t *testing.T) {
 
 func TestCategoriesService_GetAllCategories_ValidationLimit(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	service := NewCategoriesService(mockRepo, &fakeAuditor{})
 
 	categories := []model.Categories{}
 	
 	// Test with limit > 100 (should cap at 100)
-	mockRepo.On("GetAllCategories", 1, 100).Return(categories, 0, nil)
-	result, total, err := service.GetAllCategories(1, 150)
+	mockRepo.On("GetAllCategories", 1, 100, false).Return(categories, 0, nil)
+	result, total, err := service.GetAllCategories(context.Background(), 1, 150, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, total)
@@ -131,13 +148,13 @@ func TestCategoriesService_GetAllCategories_ValidationLimit(t *testing.T) {
 
 func TestCategoriesService_GetAllCategories_ValidationLimitMin(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	service := NewCategoriesService(mockRepo, &fakeAuditor{})
 
 	categories := []model.Categories{}
 	
 	// Test with limit < 1 (should default to 10)
-	mockRepo.On("GetAllCategories", 1, 10).Return(categories, 0, nil)
-	result, total, err := service.GetAllCategories(1, 0)
+	mockRepo.On("GetAllCategories", 1, 10, false).Return(categories, 0, nil)
+	result, total, err := service.GetAllCategories(context.Background(), 1, 0, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, total)
@@ -147,7 +164,7 @@ func TestCategoriesService_GetAllCategories_ValidationLimitMin(t *testing.T) {
 
 func TestCategoriesService_CreateCategories_Success(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	service := NewCategoriesService(mockRepo, &fakeAuditor{})
 
 	category := &model.Categories{
 		Name: "New Category",
@@ -155,7 +172,7 @@ func TestCategoriesService_CreateCategories_Success(t *testing.T) {
 
 	mockRepo.On("CreateCategories", category).Return(nil)
 
-	err := service.CreateCategories(category)
+	err := service.CreateCategories(context.Background(), category)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -163,7 +180,7 @@ func TestCategoriesService_CreateCategories_Success(t *testing.T) {
 
 func TestCategoriesService_CreateCategories_Error(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	service := NewCategoriesService(mockRepo, &fakeAuditor{})
 
 	category := &model.Categories{
 		Name: "New Category",
@@ -171,7 +188,7 @@ func TestCategoriesService_CreateCategories_Error(t *testing.T) {
 
 	mockRepo.On("CreateCategories", category).Return(errors.New("database error"))
 
-	err := service.CreateCategories(category)
+	err := service.CreateCategories(context.Background(), category)
 
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
@@ -179,27 +196,35 @@ func TestCategoriesService_CreateCategories_Error(t *testing.T) {
 
 func TestCategoriesService_UpdateCategories_Success(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	audit := &fakeAuditor{}
+	service := NewCategoriesService(mockRepo, audit)
 
 	category := &model.Categories{
 		Name: "Updated Category",
 	}
+	before := &model.Categories{Id: 1, Name: "Old Category", Version: 1}
+	after := &model.Categories{Id: 1, Name: "Updated Category", Version: 2}
 
+	mockRepo.On("GetCategoriesById", 1).Return(before, nil).Once()
 	mockRepo.On("UpdateCategories", 1, category).Return(nil)
+	mockRepo.On("GetCategoriesById", 1).Return(after, nil).Once()
 
-	err := service.UpdateCategories(1, category)
+	err := service.UpdateCategories(context.Background(), 1, category)
 
 	assert.NoError(t, err)
+	assert.Equal(t, 2, category.Version)
+	assert.Equal(t, []auditEntry{{model.AuditUpdate, "category", 1, before, after}}, audit.entries)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestCategoriesService_DeleteCategories_Success(t *testing.T) {
 	mockRepo := new(MockCategoriesRepository)
-	service := NewCategoriesService(mockRepo)
+	service := NewCategoriesService(mockRepo, &fakeAuditor{})
 
+	mockRepo.On("GetCategoriesById", 1).Return(&model.Categories{Id: 1}, nil)
 	mockRepo.On("DeleteCategories", 1).Return(nil)
 
-	err := service.DeleteCategories(1)
+	err := service.DeleteCategories(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
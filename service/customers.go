@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+)
+
+type CustomersService interface {
+	GetCustomersById(ctx context.Context, id int) (*model.Customers, error)
+	GetAllCustomers(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Customers, int, error)
+	CreateCustomers(ctx context.Context, data *model.Customers) error
+	UpdateCustomers(ctx context.Context, id int, data *model.Customers) error
+	PatchCustomers(ctx context.Context, id, version int, changes map[string]any) (*model.Customers, error)
+	DeleteCustomers(ctx context.Context, id int) error
+	RestoreCustomers(ctx context.Context, id int) (*model.Customers, error)
+	GetCustomerSales(ctx context.Context, id, page, limit int) ([]dto.SalesResponse, int, error)
+}
+
+type customersService struct {
+	Repo  repository.CustomersRepository
+	Sales repository.SalesRepository
+	Audit Auditor
+}
+
+func NewCustomersService(repo repository.CustomersRepository, sales repository.SalesRepository, audit Auditor) CustomersService {
+	return &customersService{Repo: repo, Sales: sales, Audit: audit}
+}
+
+func (s *customersService) GetCustomersById(ctx context.Context, id int) (*model.Customers, error) {
+	ctx, span := utils.Tracer().Start(ctx, "CustomersService.GetCustomersById")
+	defer span.End()
+
+	return s.Repo.GetCustomersById(ctx, id)
+}
+
+func (s *customersService) GetAllCustomers(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Customers, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "CustomersService.GetAllCustomers")
+	defer span.End()
+
+	// Validate pagination parameters
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	return s.Repo.GetAllCustomers(ctx, page, limit, includeDeleted)
+}
+
+func (s *customersService) CreateCustomers(ctx context.Context, data *model.Customers) error {
+	ctx, span := utils.Tracer().Start(ctx, "CustomersService.CreateCustomers")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Repo.CreateCustomers(ctx, data); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditCreate, "customer", data.Id, nil, data)
+	})
+}
+
+func (s *customersService) UpdateCustomers(ctx context.Context, id int, data *model.Customers) error {
+	ctx, span := utils.Tracer().Start(ctx, "CustomersService.UpdateCustomers")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetCustomersById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.UpdateCustomers(ctx, id, data); err != nil {
+			return err
+		}
+
+		after, err := s.Repo.GetCustomersById(ctx, id)
+		if err != nil {
+			return err
+		}
+		*data = *after
+		return s.Audit.Record(ctx, model.AuditUpdate, "customer", id, before, after)
+	})
+}
+
+func (s *customersService) PatchCustomers(ctx context.Context, id, version int, changes map[string]any) (*model.Customers, error) {
+	ctx, span := utils.Tracer().Start(ctx, "CustomersService.PatchCustomers")
+	defer span.End()
+
+	var patched *model.Customers
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetCustomersById(ctx, id)
+		if err != nil {
+			return err
+		}
+		patched, err = s.Repo.PatchCustomers(ctx, id, version, changes)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditUpdate, "customer", id, before, patched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+func (s *customersService) DeleteCustomers(ctx context.Context, id int) error {
+	ctx, span := utils.Tracer().Start(ctx, "CustomersService.DeleteCustomers")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetCustomersById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.DeleteCustomers(ctx, id); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditDelete, "customer", id, before, nil)
+	})
+}
+
+func (s *customersService) RestoreCustomers(ctx context.Context, id int) (*model.Customers, error) {
+	ctx, span := utils.Tracer().Start(ctx, "CustomersService.RestoreCustomers")
+	defer span.End()
+
+	var restored *model.Customers
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		restored, err = s.Repo.RestoreCustomers(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditRestore, "customer", id, nil, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// GetCustomerSales mengembalikan riwayat penjualan customer (terbaru dulu, dengan item)
+func (s *customersService) GetCustomerSales(ctx context.Context, id, page, limit int) ([]dto.SalesResponse, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "CustomersService.GetCustomerSales")
+	defer span.End()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// customer yang tidak ada -> 404, bukan list kosong
+	if _, err := s.Repo.GetCustomersById(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	sales, total, err := s.Sales.GetSalesByCustomer(ctx, id, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var response []dto.SalesResponse
+	for i := range sales {
+		response = append(response, *toSalesResponse(&sales[i], sales[i].Items))
+	}
+	return response, total, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCustomersRepository for testing
+type MockCustomersRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomersRepository) GetCustomersById(ctx context.Context, id int) (*model.Customers, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customers), args.Error(1)
+}
+
+func (m *MockCustomersRepository) GetAllCustomers(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Customers, int, error) {
+	args := m.Called(page, limit, includeDeleted)
+	return args.Get(0).([]model.Customers), args.Int(1), args.Error(2)
+}
+
+func (m *MockCustomersRepository) CreateCustomers(ctx context.Context, data *model.Customers) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockCustomersRepository) UpdateCustomers(ctx context.Context, id int, data *model.Customers) error {
+	args := m.Called(id, data)
+	return args.Error(0)
+}
+
+func (m *MockCustomersRepository) PatchCustomers(ctx context.Context, id, version int, changes map[string]any) (*model.Customers, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customers), args.Error(1)
+}
+
+func (m *MockCustomersRepository) DeleteCustomers(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCustomersRepository) RestoreCustomers(ctx context.Context, id int) (*model.Customers, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customers), args.Error(1)
+}
+
+func (m *MockCustomersRepository) LockCustomerCredit(ctx context.Context, id int) (*repository.CustomerCredit, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.CustomerCredit), args.Error(1)
+}
+
+func TestCustomersService_CreateCustomers_Audited(t *testing.T) {
+	mockRepo := new(MockCustomersRepository)
+	audit := &fakeAuditor{}
+	service := NewCustomersService(mockRepo, new(MockSalesRepository), audit)
+
+	customer := &model.Customers{Name: "PT Maju", TaxId: "01.234.567.8-901.000", CreditLimit: model.MustParseMoney("1000000")}
+	mockRepo.On("CreateCustomers", customer).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.Customers).Id = 3
+	}).Return(nil)
+
+	err := service.CreateCustomers(context.Background(), customer)
+
+	assert.NoError(t, err)
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, "customer", audit.entries[0].Entity)
+		assert.Equal(t, 3, audit.entries[0].EntityId)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomersService_GetAllCustomers_ValidationLimit(t *testing.T) {
+	mockRepo := new(MockCustomersRepository)
+	service := NewCustomersService(mockRepo, new(MockSalesRepository), &fakeAuditor{})
+
+	mockRepo.On("GetAllCustomers", 1, 100, true).Return([]model.Customers{}, 0, nil)
+
+	_, _, err := service.GetAllCustomers(context.Background(), 1, 500, true)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomersService_DeleteCustomers_NotFound(t *testing.T) {
+	mockRepo := new(MockCustomersRepository)
+	audit := &fakeAuditor{}
+	service := NewCustomersService(mockRepo, new(MockSalesRepository), audit)
+
+	mockRepo.On("GetCustomersById", 9).Return(nil, apperror.NotFound("customer"))
+
+	err := service.DeleteCustomers(context.Background(), 9)
+
+	assert.Equal(t, "customer_not_found", apperror.Code(err))
+	assert.Empty(t, audit.entries)
+	mockRepo.AssertNotCalled(t, "DeleteCustomers", 9)
+}
+
+func TestCustomersService_GetCustomerSales(t *testing.T) {
+	mockRepo := new(MockCustomersRepository)
+	salesRepo := new(MockSalesRepository)
+	service := NewCustomersService(mockRepo, salesRepo, &fakeAuditor{})
+
+	customerId := 3
+	mockRepo.On("GetCustomersById", 3).Return(&model.Customers{Id: 3}, nil)
+	salesRepo.On("GetSalesByCustomer", 3, 1, 10).Return([]model.Sales{
+		{Id: 12, CustomerId: &customerId, PaymentType: model.PaymentCredit, TotalAmount: model.MustParseMoney("200"),
+			Items: []model.SaleItems{{Id: 1, SaleId: 12, ItemId: 4, Quantity: 2, Price: model.MustParseMoney("100"), Subtotal: model.MustParseMoney("200")}}},
+	}, 1, nil)
+
+	sales, total, err := service.GetCustomerSales(context.Background(), 3, 0, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, sales, 1) {
+		assert.Equal(t, 3, *sales[0].CustomerId)
+		assert.Equal(t, model.PaymentCredit, sales[0].PaymentType)
+		assert.Len(t, sales[0].Items, 1)
+	}
+	salesRepo.AssertExpectations(t)
+}
+
+func TestCustomersService_GetCustomerSales_CustomerNotFound(t *testing.T) {
+	mockRepo := new(MockCustomersRepository)
+	salesRepo := new(MockSalesRepository)
+	service := NewCustomersService(mockRepo, salesRepo, &fakeAuditor{})
+
+	mockRepo.On("GetCustomersById", 9).Return(nil, apperror.NotFound("customer"))
+
+	_, _, err := service.GetCustomerSales(context.Background(), 9, 1, 10)
+
+	assert.Equal(t, "customer_not_found", apperror.Code(err))
+	salesRepo.AssertNotCalled(t, "GetSalesByCustomer", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"time"
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, scope, key, method, path, requestHash string) (*model.IdempotencyKey, error)
+	Complete(ctx context.Context, id, statusCode int, headers map[string]string, body []byte) error
+	Release(ctx context.Context, id int) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	Repo repository.IdempotencyRepository
+	TTL  time.Duration
+	now  func() time.Time
+}
+
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl time.Duration) IdempotencyService {
+	return &idempotencyService{Repo: repo, TTL: ttl, now: time.Now}
+}
+
+// Begin memesan key untuk request ini. Hasilnya:
+//   - record baru (belum Completed): jalankan request lalu Complete / Release
+//   - record Completed: replay response yang tersimpan
+//
+// Key yang sama dengan method, path atau body berbeda ditolak 422; key yang
+// request pertamanya masih diproses ditolak 409.
+func (s *idempotencyService) Begin(ctx context.Context, scope, key, method, path, requestHash string) (*model.IdempotencyKey, error) {
+	ctx, span := utils.Tracer().Start(ctx, "IdempotencyService.Begin")
+	defer span.End()
+
+	record := &model.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		Method:      method,
+		Path:        path,
+		RequestHash: requestHash,
+		ExpiredAt:   s.now().Add(s.TTL),
+	}
+	existing, created, err := s.Repo.ReserveIdempotencyKey(ctx, record)
+	if err != nil {
+		if apperror.IsNotFound(err) {
+			// request pertama baru saja gagal dan key-nya dilepas
+			return nil, idempotencyInProgress()
+		}
+		return nil, err
+	}
+	if created {
+		return existing, nil
+	}
+
+	if existing.Method != method || existing.Path != path || existing.RequestHash != requestHash {
+		return nil, apperror.IdempotencyKeyReused()
+	}
+	if !existing.Completed() {
+		return nil, idempotencyInProgress()
+	}
+	return existing, nil
+}
+
+func idempotencyInProgress() error {
+	return apperror.Conflict("idempotency_request_in_progress", "a request with this Idempotency-Key is still being processed")
+}
+
+// Complete menyimpan response request pertama
+func (s *idempotencyService) Complete(ctx context.Context, id, statusCode int, headers map[string]string, body []byte) error {
+	ctx, span := utils.Tracer().Start(ctx, "IdempotencyService.Complete")
+	defer span.End()
+
+	return s.Repo.CompleteIdempotencyKey(ctx, id, statusCode, headers, body)
+}
+
+// Release melepas key yang request-nya gagal (5xx) supaya client bisa mencoba lagi
+func (s *idempotencyService) Release(ctx context.Context, id int) error {
+	ctx, span := utils.Tracer().Start(ctx, "IdempotencyService.Release")
+	defer span.End()
+
+	return s.Repo.ReleaseIdempotencyKey(ctx, id)
+}
+
+// PurgeExpired menghapus key yang melewati TTL
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	ctx, span := utils.Tracer().Start(ctx, "IdempotencyService.PurgeExpired")
+	defer span.End()
+
+	return s.Repo.DeleteExpiredIdempotencyKeys(ctx)
+}
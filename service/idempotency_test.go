@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) ReserveIdempotencyKey(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, bool, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*model.IdempotencyKey), args.Bool(1), args.Error(2)
+}
+
+func (m *MockIdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, id, statusCode int, headers map[string]string, body []byte) error {
+	args := m.Called(id, statusCode, headers, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) ReleaseIdempotencyKey(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newTestIdempotencyService(repo *MockIdempotencyRepository, now time.Time) *idempotencyService {
+	return &idempotencyService{Repo: repo, TTL: 24 * time.Hour, now: func() time.Time { return now }}
+}
+
+func TestIdempotencyService_Begin_NewKey(t *testing.T) {
+	repo := new(MockIdempotencyRepository)
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	svc := newTestIdempotencyService(repo, now)
+
+	repo.On("ReserveIdempotencyKey", mock.MatchedBy(func(k *model.IdempotencyKey) bool {
+		return k.Scope == "user:1" && k.Key == "k1" && k.RequestHash == "h1" && k.ExpiredAt.Equal(now.Add(24*time.Hour))
+	})).Run(func(args mock.Arguments) { args.Get(0).(*model.IdempotencyKey).Id = 7 }).
+		Return(&model.IdempotencyKey{Id: 7}, true, nil)
+
+	record, err := svc.Begin(context.Background(), "user:1", "k1", "POST", "/sales", "h1")
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, record.Id)
+	assert.False(t, record.Completed())
+	repo.AssertExpectations(t)
+}
+
+func TestIdempotencyService_Begin_ExistingKey(t *testing.T) {
+	status := 201
+	completed := &model.IdempotencyKey{Id: 7, Method: "POST", Path: "/sales", RequestHash: "h1", StatusCode: &status, ResponseBody: []byte(`{"status":true}`)}
+	pending := &model.IdempotencyKey{Id: 7, Method: "POST", Path: "/sales", RequestHash: "h1"}
+
+	tests := []struct {
+		name     string
+		existing *model.IdempotencyKey
+		path     string
+		hash     string
+		wantCode string
+	}{
+		{"replay completed response", completed, "/sales", "h1", ""},
+		{"different body", completed, "/sales", "h2", "idempotency_key_reused"},
+		{"different route", completed, "/items", "h1", "idempotency_key_reused"},
+		{"first request still running", pending, "/sales", "h1", "idempotency_request_in_progress"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockIdempotencyRepository)
+			svc := newTestIdempotencyService(repo, time.Now())
+			repo.On("ReserveIdempotencyKey", mock.Anything).Return(tt.existing, false, nil)
+
+			record, err := svc.Begin(context.Background(), "user:1", "k1", "POST", tt.path, tt.hash)
+
+			if tt.wantCode == "" {
+				require.NoError(t, err)
+				assert.True(t, record.Completed())
+				assert.Equal(t, completed.ResponseBody, record.ResponseBody)
+				return
+			}
+			assert.Equal(t, tt.wantCode, apperror.Code(err))
+		})
+	}
+}
+
+func TestIdempotencyService_Begin_KeyReleasedMeanwhile(t *testing.T) {
+	repo := new(MockIdempotencyRepository)
+	svc := newTestIdempotencyService(repo, time.Now())
+	repo.On("ReserveIdempotencyKey", mock.Anything).Return(nil, false, apperror.NotFound("idempotency_key"))
+
+	_, err := svc.Begin(context.Background(), "user:1", "k1", "POST", "/sales", "h1")
+
+	assert.Equal(t, "idempotency_request_in_progress", apperror.Code(err))
+}
@@ -1,32 +1,43 @@
 package service
 
 import (
+	"context"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
 )
 
 type ItemsService interface {
-	GetItemsById(id int) (*model.Items, error) 
-	GetAllItems(page, limit int) ([]model.Items, int, error)
-	GetLowStockItems(threshold int) ([]model.Items, error)
-	CreateItems(data *model.Items) error
-	UpdateItems(id int, data *model.Items) error
-	DeleteItems(id int) error
+	GetItemsById(ctx context.Context, id int) (*model.Items, error) 
+	GetAllItems(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Items, int, error)
+	GetLowStockItems(ctx context.Context, threshold int) ([]model.Items, error)
+	CreateItems(ctx context.Context, data *model.Items) error
+	UpdateItems(ctx context.Context, id int, data *model.Items) error
+	PatchItems(ctx context.Context, id, version int, changes map[string]any) (*model.Items, error)
+	DeleteItems(ctx context.Context, id int) error
+	RestoreItems(ctx context.Context, id int) (*model.Items, error)
 }
 
 type itemsService struct {
-	Repo repository.ItemsRepository
+	Repo  repository.ItemsRepository
+	Audit Auditor
 }
 
-func NewItemsService(repo repository.ItemsRepository) ItemsService {
-	return &itemsService{Repo: repo}
+func NewItemsService(repo repository.ItemsRepository, audit Auditor) ItemsService {
+	return &itemsService{Repo: repo, Audit: audit}
 }
 
-func (s *itemsService) GetItemsById(id int) (*model.Items, error) {
-	return s.Repo.GetItemsById(id)
+func (s *itemsService) GetItemsById(ctx context.Context, id int) (*model.Items, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ItemsService.GetItemsById")
+	defer span.End()
+
+	return s.Repo.GetItemsById(ctx, id)
 }
 
-func (s *itemsService) GetAllItems(page, limit int) ([]model.Items, int, error) {
+func (s *itemsService) GetAllItems(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Items, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ItemsService.GetAllItems")
+	defer span.End()
+
 	// Validate pagination parameters
 	if page < 1 {
 		page = 1
@@ -38,26 +49,109 @@ func (s *itemsService) GetAllItems(page, limit int) ([]model.Items, int, error)
 		limit = 100
 	}
 	
-	return s.Repo.GetAllItems(page, limit)
+	return s.Repo.GetAllItems(ctx, page, limit, includeDeleted)
 }
 
-func (s *itemsService) GetLowStockItems(threshold int) ([]model.Items, error) {
+func (s *itemsService) GetLowStockItems(ctx context.Context, threshold int) ([]model.Items, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ItemsService.GetLowStockItems")
+	defer span.End()
+
 	// Default threshold to 5 if not provided or invalid
 	if threshold < 1 {
 		threshold = 5
 	}
 	
-	return s.Repo.GetLowStockItems(threshold)
+	return s.Repo.GetLowStockItems(ctx, threshold)
 }
 
-func (s *itemsService) CreateItems(data *model.Items) error {
-	return s.Repo.CreateItems(data)
+func (s *itemsService) CreateItems(ctx context.Context, data *model.Items) error {
+	ctx, span := utils.Tracer().Start(ctx, "ItemsService.CreateItems")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Repo.CreateItems(ctx, data); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditCreate, "item", data.Id, nil, data)
+	})
 }
 
-func (s *itemsService) UpdateItems(id int, data *model.Items) error {
-	return s.Repo.UpdateItems(id, data)
+func (s *itemsService) UpdateItems(ctx context.Context, id int, data *model.Items) error {
+	ctx, span := utils.Tracer().Start(ctx, "ItemsService.UpdateItems")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetItemsById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.UpdateItems(ctx, id, data); err != nil {
+			return err
+		}
+
+		// baca ulang agar response dan audit berisi data yang benar-benar tersimpan
+		after, err := s.Repo.GetItemsById(ctx, id)
+		if err != nil {
+			return err
+		}
+		*data = *after
+		return s.Audit.Record(ctx, model.AuditUpdate, "item", id, before, after)
+	})
 }
 
-func (s *itemsService) DeleteItems(id int) error {
-	return s.Repo.DeleteItems(id)
+func (s *itemsService) PatchItems(ctx context.Context, id, version int, changes map[string]any) (*model.Items, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ItemsService.PatchItems")
+	defer span.End()
+
+	var patched *model.Items
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetItemsById(ctx, id)
+		if err != nil {
+			return err
+		}
+		patched, err = s.Repo.PatchItems(ctx, id, version, changes)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditUpdate, "item", id, before, patched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+func (s *itemsService) DeleteItems(ctx context.Context, id int) error {
+	ctx, span := utils.Tracer().Start(ctx, "ItemsService.DeleteItems")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetItemsById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.DeleteItems(ctx, id); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditDelete, "item", id, before, nil)
+	})
+}
+
+func (s *itemsService) RestoreItems(ctx context.Context, id int) (*model.Items, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ItemsService.RestoreItems")
+	defer span.End()
+
+	var restored *model.Items
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		restored, err = s.Repo.RestoreItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditRestore, "item", id, nil, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
 }
\ No newline at end of file
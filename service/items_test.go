@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"testing"
@@ -15,7 +16,7 @@ type MockItemsRepository struct {
 	mock.Mock
 }
 
-func (m *MockItemsRepository) GetItemsById(id int) (*model.Items, error) {
+func (m *MockItemsRepository) GetItemsById(ctx context.Context, id int) (*model.Items, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -23,12 +24,12 @@ func (m *MockItemsRepository) GetItemsById(id int) (*model.Items, error) {
 	return args.Get(0).(*model.Items), args.Error(1)
 }
 
-func (m *MockItemsRepository) GetAllItems(page, limit int) ([]model.Items, int, error) {
-	args := m.Called(page, limit)
+func (m *MockItemsRepository) GetAllItems(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Items, int, error) {
+	args := m.Called(page, limit, includeDeleted)
 	return args.Get(0).([]model.Items), args.Int(1), args.Error(2)
 }
 
-func (m *MockItemsRepository) GetLowStockItems(threshold int) ([]model.Items, error) {
+func (m *MockItemsRepository) GetLowStockItems(ctx context.Context, threshold int) ([]model.Items, error) {
 	args := m.Called(threshold)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -36,25 +37,41 @@ func (m *MockItemsRepository) GetLowStockItems(threshold int) ([]model.Items, er
 	return args.Get(0).([]model.Items), args.Error(1)
 }
 
-func (m *MockItemsRepository) CreateItems(data *model.Items) error {
+func (m *MockItemsRepository) CreateItems(ctx context.Context, data *model.Items) error {
 	args := m.Called(data)
 	return args.Error(0)
 }
 
-func (m *MockItemsRepository) UpdateItems(id int, data *model.Items) error {
+func (m *MockItemsRepository) UpdateItems(ctx context.Context, id int, data *model.Items) error {
 	args := m.Called(id, data)
 	return args.Error(0)
 }
 
-func (m *MockItemsRepository) DeleteItems(id int) error {
+func (m *MockItemsRepository) PatchItems(ctx context.Context, id, version int, changes map[string]any) (*model.Items, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Items), args.Error(1)
+}
+
+func (m *MockItemsRepository) DeleteItems(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockItemsRepository) RestoreItems(ctx context.Context, id int) (*model.Items, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Items), args.Error(1)
+}
+
 func TestGetItemsById_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	expectedItem := &model.Items{
 		Id:         1,
@@ -64,7 +81,7 @@ func TestGetItemsById_Success(t *testing.T) {
 		Sku:        "TEST-001",
 		Stock:      100,
 		MinStock:   10,
-		Price:      50000,
+		Price:      model.MustParseMoney("50000"),
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -73,7 +90,7 @@ func TestGetItemsById_Success(t *testing.T) {
 	mockRepo.On("GetItemsById", 1).Return(expectedItem, nil)
 
 	// Execute
-	result, err := service.GetItemsById(1)
+	result, err := service.GetItemsById(context.Background(), 1)
 
 	// Assert
 	assert.NoError(t, err)
@@ -87,13 +104,13 @@ func TestGetItemsById_Success(t *testing.T) {
 func TestGetItemsById_NotFound(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	// Mock expectations
 	mockRepo.On("GetItemsById", 999).Return(nil, errors.New("item not found"))
 
 	// Execute
-	result, err := service.GetItemsById(999)
+	result, err := service.GetItemsById(context.Background(), 999)
 
 	// Assert
 	assert.Error(t, err)
@@ -105,7 +122,7 @@ func TestGetItemsById_NotFound(t *testing.T) {
 func TestGetAllItems_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	expectedItems := []model.Items{
 		{
@@ -114,7 +131,7 @@ func TestGetAllItems_Success(t *testing.T) {
 			Sku:       "SKU-001",
 			Stock:     100,
 			MinStock:  10,
-			Price:     10000,
+			Price:     model.MustParseMoney("10000"),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
@@ -124,17 +141,17 @@ func TestGetAllItems_Success(t *testing.T) {
 			Sku:       "SKU-002",
 			Stock:     200,
 			MinStock:  20,
-			Price:     20000,
+			Price:     model.MustParseMoney("20000"),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
 	}
 
 	// Mock expectations
-	mockRepo.On("GetAllItems", 1, 10).Return(expectedItems, 25, nil)
+	mockRepo.On("GetAllItems", 1, 10, false).Return(expectedItems, 25, nil)
 
 	// Execute
-	items, total, err := service.GetAllItems(1, 10)
+	items, total, err := service.GetAllItems(context.Background(), 1, 10, false)
 
 	// Assert
 	assert.NoError(t, err)
@@ -148,17 +165,17 @@ func TestGetAllItems_Success(t *testing.T) {
 func TestGetAllItems_WithInvalidPage(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	expectedItems := []model.Items{
 		{Id: 1, Name: "Item 1"},
 	}
 
 	// Mock expectations - should default to page 1
-	mockRepo.On("GetAllItems", 1, 10).Return(expectedItems, 10, nil)
+	mockRepo.On("GetAllItems", 1, 10, false).Return(expectedItems, 10, nil)
 
 	// Execute with invalid page (0)
-	items, total, err := service.GetAllItems(0, 10)
+	items, total, err := service.GetAllItems(context.Background(), 0, 10, false)
 
 	// Assert
 	assert.NoError(t, err)
@@ -170,17 +187,17 @@ func TestGetAllItems_WithInvalidPage(t *testing.T) {
 func TestGetAllItems_WithInvalidLimit(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	expectedItems := []model.Items{
 		{Id: 1, Name: "Item 1"},
 	}
 
 	// Mock expectations - should default to limit 10
-	mockRepo.On("GetAllItems", 1, 10).Return(expectedItems, 10, nil)
+	mockRepo.On("GetAllItems", 1, 10, false).Return(expectedItems, 10, nil)
 
 	// Execute with invalid limit (0)
-	items, total, err := service.GetAllItems(1, 0)
+	items, total, err := service.GetAllItems(context.Background(), 1, 0, false)
 
 	// Assert
 	assert.NoError(t, err)
@@ -192,17 +209,17 @@ func TestGetAllItems_WithInvalidLimit(t *testing.T) {
 func TestGetAllItems_WithLimitExceedsMax(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	expectedItems := []model.Items{
 		{Id: 1, Name: "Item 1"},
 	}
 
 	// Mock expectations - should cap at limit 100
-	mockRepo.On("GetAllItems", 1, 100).Return(expectedItems, 10, nil)
+	mockRepo.On("GetAllItems", 1, 100, false).Return(expectedItems, 10, nil)
 
 	// Execute with limit exceeding max (150)
-	items, total, err := service.GetAllItems(1, 150)
+	items, total, err := service.GetAllItems(context.Background(), 1, 150, false)
 
 	// Assert
 	assert.NoError(t, err)
@@ -214,7 +231,7 @@ func TestGetAllItems_WithLimitExceedsMax(t *testing.T) {
 func TestGetLowStockItems_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	expectedItems := []model.Items{
 		{
@@ -223,7 +240,7 @@ func TestGetLowStockItems_Success(t *testing.T) {
 			Sku:       "LOW-001",
 			Stock:     3,
 			MinStock:  10,
-			Price:     10000,
+			Price:     model.MustParseMoney("10000"),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
@@ -233,7 +250,7 @@ func TestGetLowStockItems_Success(t *testing.T) {
 	mockRepo.On("GetLowStockItems", 10).Return(expectedItems, nil)
 
 	// Execute
-	items, err := service.GetLowStockItems(10)
+	items, err := service.GetLowStockItems(context.Background(), 10)
 
 	// Assert
 	assert.NoError(t, err)
@@ -246,7 +263,7 @@ func TestGetLowStockItems_Success(t *testing.T) {
 func TestGetLowStockItems_WithInvalidThreshold(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	expectedItems := []model.Items{
 		{Id: 1, Name: "Low Stock Item", Stock: 3},
@@ -256,7 +273,7 @@ func TestGetLowStockItems_WithInvalidThreshold(t *testing.T) {
 	mockRepo.On("GetLowStockItems", 5).Return(expectedItems, nil)
 
 	// Execute with invalid threshold (0)
-	items, err := service.GetLowStockItems(0)
+	items, err := service.GetLowStockItems(context.Background(), 0)
 
 	// Assert
 	assert.NoError(t, err)
@@ -267,7 +284,7 @@ func TestGetLowStockItems_WithInvalidThreshold(t *testing.T) {
 func TestGetLowStockItems_WithNegativeThreshold(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	expectedItems := []model.Items{
 		{Id: 1, Name: "Low Stock Item", Stock: 3},
@@ -277,7 +294,7 @@ func TestGetLowStockItems_WithNegativeThreshold(t *testing.T) {
 	mockRepo.On("GetLowStockItems", 5).Return(expectedItems, nil)
 
 	// Execute with negative threshold
-	items, err := service.GetLowStockItems(-10)
+	items, err := service.GetLowStockItems(context.Background(), -10)
 
 	// Assert
 	assert.NoError(t, err)
@@ -288,7 +305,7 @@ func TestGetLowStockItems_WithNegativeThreshold(t *testing.T) {
 func TestCreateItems_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	newItem := &model.Items{
 		CategoryId: 1,
@@ -297,14 +314,14 @@ func TestCreateItems_Success(t *testing.T) {
 		Sku:        "NEW-001",
 		Stock:      100,
 		MinStock:   10,
-		Price:      25000,
+		Price:      model.MustParseMoney("25000"),
 	}
 
 	// Mock expectations
 	mockRepo.On("CreateItems", newItem).Return(nil)
 
 	// Execute
-	err := service.CreateItems(newItem)
+	err := service.CreateItems(context.Background(), newItem)
 
 	// Assert
 	assert.NoError(t, err)
@@ -314,7 +331,7 @@ func TestCreateItems_Success(t *testing.T) {
 func TestCreateItems_Error(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	newItem := &model.Items{
 		CategoryId: 1,
@@ -323,14 +340,14 @@ func TestCreateItems_Error(t *testing.T) {
 		Sku:        "NEW-001",
 		Stock:      100,
 		MinStock:   10,
-		Price:      25000,
+		Price:      model.MustParseMoney("25000"),
 	}
 
 	// Mock expectations
 	mockRepo.On("CreateItems", newItem).Return(errors.New("database error"))
 
 	// Execute
-	err := service.CreateItems(newItem)
+	err := service.CreateItems(context.Background(), newItem)
 
 	// Assert
 	assert.Error(t, err)
@@ -341,7 +358,7 @@ func TestCreateItems_Error(t *testing.T) {
 func TestUpdateItems_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	updateItem := &model.Items{
 		CategoryId: 1,
@@ -350,14 +367,16 @@ func TestUpdateItems_Success(t *testing.T) {
 		Sku:        "UPD-001",
 		Stock:      150,
 		MinStock:   15,
-		Price:      30000,
+		Price:      model.MustParseMoney("30000"),
 	}
 
 	// Mock expectations
+	mockRepo.On("GetItemsById", 1).Return(&model.Items{Id: 1, Name: "Old Item", Version: 1}, nil).Once()
 	mockRepo.On("UpdateItems", 1, updateItem).Return(nil)
+	mockRepo.On("GetItemsById", 1).Return(&model.Items{Id: 1, Name: "Updated Item", Version: 2}, nil).Once()
 
 	// Execute
-	err := service.UpdateItems(1, updateItem)
+	err := service.UpdateItems(context.Background(), 1, updateItem)
 
 	// Assert
 	assert.NoError(t, err)
@@ -367,7 +386,7 @@ func TestUpdateItems_Success(t *testing.T) {
 func TestUpdateItems_Error(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	updateItem := &model.Items{
 		CategoryId: 1,
@@ -376,14 +395,15 @@ func TestUpdateItems_Error(t *testing.T) {
 		Sku:        "UPD-001",
 		Stock:      150,
 		MinStock:   15,
-		Price:      30000,
+		Price:      model.MustParseMoney("30000"),
 	}
 
 	// Mock expectations
+	mockRepo.On("GetItemsById", 999).Return(&model.Items{Id: 999}, nil)
 	mockRepo.On("UpdateItems", 999, updateItem).Return(errors.New("item not found"))
 
 	// Execute
-	err := service.UpdateItems(999, updateItem)
+	err := service.UpdateItems(context.Background(), 999, updateItem)
 
 	// Assert
 	assert.Error(t, err)
@@ -394,13 +414,14 @@ func TestUpdateItems_Error(t *testing.T) {
 func TestDeleteItems_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	// Mock expectations
+	mockRepo.On("GetItemsById", 1).Return(&model.Items{Id: 1}, nil)
 	mockRepo.On("DeleteItems", 1).Return(nil)
 
 	// Execute
-	err := service.DeleteItems(1)
+	err := service.DeleteItems(context.Background(), 1)
 
 	// Assert
 	assert.NoError(t, err)
@@ -410,13 +431,14 @@ func TestDeleteItems_Success(t *testing.T) {
 func TestDeleteItems_Error(t *testing.T) {
 	// Setup
 	mockRepo := new(MockItemsRepository)
-	service := NewItemsService(mockRepo)
+	service := NewItemsService(mockRepo, &fakeAuditor{})
 
 	// Mock expectations
+	mockRepo.On("GetItemsById", 999).Return(&model.Items{Id: 999}, nil)
 	mockRepo.On("DeleteItems", 999).Return(errors.New("item not found"))
 
 	// Execute
-	err := service.DeleteItems(999)
+	err := service.DeleteItems(context.Background(), 999)
 
 	// Assert
 	assert.Error(t, err)
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// loginThrottle menghitung login gagal per IP dalam fixed window (in-memory,
+// per instance). Cukup untuk menahan brute force dari satu sumber; lockout
+// per akun tetap disimpan di database.
+type loginThrottle struct {
+	mu          sync.Mutex
+	maxAttempts int
+	window      time.Duration
+	attempts    map[string]*ipAttempts
+	lastSweep   time.Time
+}
+
+type ipAttempts struct {
+	count       int
+	windowStart time.Time
+}
+
+func newLoginThrottle(maxAttempts int, window time.Duration) *loginThrottle {
+	return &loginThrottle{
+		maxAttempts: maxAttempts,
+		window:      window,
+		attempts:    make(map[string]*ipAttempts),
+	}
+}
+
+// blocked mengembalikan sisa waktu blokir untuk ip, 0 jika ip masih boleh login
+func (t *loginThrottle) blocked(ip string, now time.Time) time.Duration {
+	if ip == "" || t.maxAttempts <= 0 {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	a, ok := t.attempts[ip]
+	if !ok || now.Sub(a.windowStart) >= t.window {
+		return 0
+	}
+	if a.count < t.maxAttempts {
+		return 0
+	}
+	return a.windowStart.Add(t.window).Sub(now)
+}
+
+// fail mencatat satu login gagal dari ip dan mengembalikan jumlahnya di window ini
+func (t *loginThrottle) fail(ip string, now time.Time) int {
+	if ip == "" || t.maxAttempts <= 0 {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sweep(now)
+
+	a, ok := t.attempts[ip]
+	if !ok || now.Sub(a.windowStart) >= t.window {
+		a = &ipAttempts{windowStart: now}
+		t.attempts[ip] = a
+	}
+	a.count++
+	return a.count
+}
+
+// sweep membuang window yang sudah lewat supaya map tidak tumbuh terus
+func (t *loginThrottle) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < t.window {
+		return
+	}
+	for ip, a := range t.attempts {
+		if now.Sub(a.windowStart) >= t.window {
+			delete(t.attempts, ip)
+		}
+	}
+	t.lastSweep = now
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// resetTokenBytes adalah panjang token reset sebelum di-hex (256 bit)
+const resetTokenBytes = 32
+
+type PasswordService interface {
+	ChangePassword(ctx context.Context, userId int, oldPassword, newPassword string) error
+	IssuePasswordReset(ctx context.Context, userId int) (*dto.PasswordResetResponse, error)
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type passwordService struct {
+	Users    repository.UsersRepository
+	Sessions repository.SessionsRepository
+	Resets   repository.PasswordResetsRepository
+	APIKeys  repository.APIKeysRepository
+	Audit    Auditor
+	ResetTTL time.Duration
+	now      func() time.Time
+}
+
+func NewPasswordService(users repository.UsersRepository, sessions repository.SessionsRepository, resets repository.PasswordResetsRepository, apiKeys repository.APIKeysRepository, audit Auditor, resetTTL time.Duration) PasswordService {
+	return &passwordService{Users: users, Sessions: sessions, Resets: resets, APIKeys: apiKeys, Audit: audit, ResetTTL: resetTTL, now: time.Now}
+}
+
+// ChangePassword dipakai user untuk mengganti password sendiri (wajib password lama)
+func (s *passwordService) ChangePassword(ctx context.Context, userId int, oldPassword, newPassword string) error {
+	ctx, span := utils.Tracer().Start(ctx, "PasswordService.ChangePassword")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := s.Users.GetUsersByID(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if !utils.CheckPassword(oldPassword, user.Password) {
+			return apperror.Validation("old password is incorrect", nil)
+		}
+		return s.setPassword(ctx, user, newPassword)
+	})
+}
+
+// IssuePasswordReset membuat token reset sekali pakai untuk user (oleh admin).
+// Token lama yang belum dipakai dibatalkan.
+func (s *passwordService) IssuePasswordReset(ctx context.Context, userId int) (*dto.PasswordResetResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "PasswordService.IssuePasswordReset")
+	defer span.End()
+
+	token, err := utils.GenerateRandomToken(resetTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	response := &dto.PasswordResetResponse{Token: token, ExpiredAt: s.now().Add(s.ResetTTL)}
+
+	var createdBy *int
+	if actor, ok := utils.ActorFromContext(ctx); ok {
+		createdBy = &actor.UserId
+	}
+
+	err = s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.Users.GetUsersByID(ctx, userId); err != nil {
+			return err
+		}
+		if err := s.Resets.InvalidatePasswordResets(ctx, userId); err != nil {
+			return err
+		}
+		if err := s.Resets.CreatePasswordReset(ctx, userId, utils.HashToken(token), response.ExpiredAt, createdBy); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditPasswordReset, "user", userId, nil, map[string]any{"expired_at": response.ExpiredAt})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// ResetPassword memakai token dari IssuePasswordReset untuk mengganti password
+func (s *passwordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	ctx, span := utils.Tracer().Start(ctx, "PasswordService.ResetPassword")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		userId, err := s.Resets.ConsumePasswordReset(ctx, utils.HashToken(token))
+		if apperror.IsNotFound(err) {
+			return apperror.Validation("invalid or expired reset token", nil)
+		}
+		if err != nil {
+			return err
+		}
+
+		user, err := s.Users.GetUsersByID(ctx, userId)
+		if err != nil {
+			return err
+		}
+		return s.setPassword(ctx, user, newPassword)
+	})
+}
+
+// setPassword menyimpan hash password baru, mencabut semua session user dan
+// token reset lain yang masih berlaku, lalu mencatat audit
+func (s *passwordService) setPassword(ctx context.Context, user model.Users, newPassword string) error {
+	if utils.CheckPassword(newPassword, user.Password) {
+		return apperror.Validation("new password must be different from the current password", nil)
+	}
+
+	hashed, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.Users.UpdatePassword(ctx, user.Id, hashed); err != nil {
+		return err
+	}
+	if err := s.Resets.InvalidatePasswordResets(ctx, user.Id); err != nil {
+		return err
+	}
+
+	revoked, err := s.Sessions.RevokeUserSessions(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+	// API key juga kredensial user: key yang bocor bersama password lama ikut dicabut
+	revokedKeys, err := s.APIKeys.RevokeUserAPIKeys(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+	utils.LoggerFromContext(ctx, nil).Info("password changed, sessions and api keys revoked",
+		zap.Int("user_id", user.Id),
+		zap.Int64("revoked_sessions", revoked),
+		zap.Int64("revoked_api_keys", revokedKeys),
+	)
+
+	return s.Audit.Record(ctx, model.AuditUpdate, "user", user.Id, userAudit(user, false), userAudit(user, true))
+}
+
+// userAudit dipakai saat password berubah: hash password tidak pernah masuk audit,
+// yang dicatat hanya penanda password_changed
+func userAudit(user model.Users, passwordChanged bool) any {
+	return struct {
+		model.Users
+		PasswordChanged bool `json:"password_changed,omitempty"`
+	}{user, passwordChanged}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPasswordResetsRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetsRepository) CreatePasswordReset(ctx context.Context, userId int, tokenHash string, expiredAt time.Time, createdBy *int) error {
+	args := m.Called(userId, tokenHash, expiredAt, createdBy)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetsRepository) ConsumePasswordReset(ctx context.Context, tokenHash string) (int, error) {
+	args := m.Called(tokenHash)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPasswordResetsRepository) InvalidatePasswordResets(ctx context.Context, userId int) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
+
+type passwordMocks struct {
+	users    *MockUsersRepository
+	sessions *MockSessionsRepository
+	resets   *MockPasswordResetsRepository
+	apiKeys  *MockAPIKeysRepository
+	audit    *fakeAuditor
+}
+
+func newTestPasswordService(now time.Time) (*passwordService, passwordMocks) {
+	m := passwordMocks{new(MockUsersRepository), new(MockSessionsRepository), new(MockPasswordResetsRepository), new(MockAPIKeysRepository), &fakeAuditor{}}
+	s := NewPasswordService(m.users, m.sessions, m.resets, m.apiKeys, m.audit, time.Hour).(*passwordService)
+	s.now = func() time.Time { return now }
+	return s, m
+}
+
+// expectPasswordSaved memastikan hash baru cocok dengan password dan semua session serta API key dicabut
+func (m passwordMocks) expectPasswordSaved(userId int, newPassword string) {
+	m.users.On("UpdatePassword", userId, mock.MatchedBy(func(hashed string) bool {
+		return utils.CheckPassword(newPassword, hashed)
+	})).Return(nil)
+	m.resets.On("InvalidatePasswordResets", userId).Return(nil)
+	m.sessions.On("RevokeUserSessions", userId).Return(int64(2), nil)
+	m.apiKeys.On("RevokeUserAPIKeys", userId).Return(int64(1), nil)
+}
+
+func TestPasswordService_ChangePassword_Success(t *testing.T) {
+	service, m := newTestPasswordService(time.Now())
+
+	m.users.On("GetUsersByID", 3).Return(model.Users{Id: 3, Password: mustHash(t, "OldPass123")}, nil)
+	m.expectPasswordSaved(3, "NewPass456")
+
+	err := service.ChangePassword(context.Background(), 3, "OldPass123", "NewPass456")
+
+	assert.NoError(t, err)
+	m.users.AssertExpectations(t)
+	m.sessions.AssertExpectations(t)
+	m.apiKeys.AssertExpectations(t)
+	require.Len(t, m.audit.entries, 1)
+	changes, err := auditDiff(m.audit.entries[0].Before, m.audit.entries[0].After)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"password_changed":{"after":true}}`, string(changes))
+}
+
+func TestPasswordService_ChangePassword_WrongOldPassword(t *testing.T) {
+	service, m := newTestPasswordService(time.Now())
+
+	m.users.On("GetUsersByID", 3).Return(model.Users{Id: 3, Password: mustHash(t, "OldPass123")}, nil)
+
+	err := service.ChangePassword(context.Background(), 3, "Salah123", "NewPass456")
+
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+	assert.Equal(t, "old password is incorrect", err.Error())
+	m.users.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+	m.sessions.AssertNotCalled(t, "RevokeUserSessions", mock.Anything)
+}
+
+func TestPasswordService_ChangePassword_SameAsCurrent(t *testing.T) {
+	service, m := newTestPasswordService(time.Now())
+
+	m.users.On("GetUsersByID", 3).Return(model.Users{Id: 3, Password: mustHash(t, "OldPass123")}, nil)
+
+	err := service.ChangePassword(context.Background(), 3, "OldPass123", "OldPass123")
+
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+	m.users.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+func TestPasswordService_IssuePasswordReset_StoresOnlyHash(t *testing.T) {
+	now := time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)
+	service, m := newTestPasswordService(now)
+	ctx := utils.WithActor(context.Background(), utils.Actor{UserId: 1, Role: "super_admin"})
+
+	var storedHash string
+	m.users.On("GetUsersByID", 5).Return(model.Users{Id: 5}, nil)
+	m.resets.On("InvalidatePasswordResets", 5).Return(nil)
+	m.resets.On("CreatePasswordReset", 5, mock.AnythingOfType("string"), now.Add(time.Hour), mock.MatchedBy(func(by *int) bool {
+		return by != nil && *by == 1
+	})).Run(func(args mock.Arguments) { storedHash = args.String(1) }).Return(nil)
+
+	reset, err := service.IssuePasswordReset(ctx, 5)
+
+	require.NoError(t, err)
+	assert.Len(t, reset.Token, 2*resetTokenBytes)
+	assert.Equal(t, now.Add(time.Hour), reset.ExpiredAt)
+	assert.NotEqual(t, reset.Token, storedHash)
+	assert.Equal(t, utils.HashToken(reset.Token), storedHash)
+	require.Len(t, m.audit.entries, 1)
+	assert.Equal(t, model.AuditPasswordReset, m.audit.entries[0].Action)
+	assert.NotContains(t, m.audit.entries[0].After, "token")
+}
+
+func TestPasswordService_ResetPassword_Success(t *testing.T) {
+	service, m := newTestPasswordService(time.Now())
+
+	m.resets.On("ConsumePasswordReset", utils.HashToken("reset-token")).Return(5, nil)
+	m.users.On("GetUsersByID", 5).Return(model.Users{Id: 5, Password: mustHash(t, "Lupa1234")}, nil)
+	m.expectPasswordSaved(5, "Baru12345")
+
+	err := service.ResetPassword(context.Background(), "reset-token", "Baru12345")
+
+	assert.NoError(t, err)
+	m.users.AssertExpectations(t)
+	m.sessions.AssertExpectations(t)
+}
+
+func TestPasswordService_ResetPassword_InvalidToken(t *testing.T) {
+	service, m := newTestPasswordService(time.Now())
+
+	m.resets.On("ConsumePasswordReset", utils.HashToken("expired")).Return(0, apperror.NotFound("password_reset"))
+
+	err := service.ResetPassword(context.Background(), "expired", "Baru12345")
+
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+	assert.Equal(t, "invalid or expired reset token", err.Error())
+	m.users.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+)
+
+type PaymentsService interface {
+	GetSalePayments(ctx context.Context, saleId int) (*dto.PaymentsResponse, error)
+	CreatePayments(ctx context.Context, saleId, userId int, data *dto.PaymentsRequest) (*dto.PaymentsResponse, error)
+}
+
+type paymentsService struct {
+	Repo  repository.PaymentsRepository
+	Audit Auditor
+}
+
+func NewPaymentsService(repo repository.PaymentsRepository, audit Auditor) PaymentsService {
+	return &paymentsService{Repo: repo, Audit: audit}
+}
+
+func (s *paymentsService) GetSalePayments(ctx context.Context, saleId int) (*dto.PaymentsResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "PaymentsService.GetSalePayments")
+	defer span.End()
+
+	balance, err := s.Repo.GetSaleBalance(ctx, saleId)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.Repo.GetPaymentsBySale(ctx, saleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentsResponse(saleId, balance, 0, payments), nil
+}
+
+// CreatePayments mencatat satu atau beberapa tender untuk sale saleId dan
+// memperbarui paid_amount serta payment_status. Sale dikunci selama transaksi.
+func (s *paymentsService) CreatePayments(ctx context.Context, saleId, userId int, data *dto.PaymentsRequest) (*dto.PaymentsResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "PaymentsService.CreatePayments")
+	defer span.End()
+
+	if len(data.Payments) == 0 {
+		return nil, apperror.Validation("at least one payment is required", nil)
+	}
+
+	var response *dto.PaymentsResponse
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		balance, err := s.Repo.LockSaleBalance(ctx, saleId)
+		if err != nil {
+			return err
+		}
+		if balance.Status == model.SaleVoided {
+			return apperror.SaleVoided()
+		}
+		// draft, quote dan held belum menjadi piutang
+		if balance.Status != model.SaleCompleted {
+			return apperror.InvalidSaleStatus(balance.Status, model.SaleCompleted)
+		}
+
+		payments, change, err := applyTenders(balance.TotalAmount-balance.PaidAmount, data.Payments)
+		if err != nil {
+			return err
+		}
+
+		for i := range payments {
+			payments[i].SaleId = saleId
+			payments[i].UserId = userId
+			if err := s.Repo.CreatePayment(ctx, &payments[i]); err != nil {
+				return err
+			}
+			balance.PaidAmount += payments[i].Amount
+			if err := s.Audit.Record(ctx, model.AuditCreate, "payment", payments[i].Id, nil, payments[i]); err != nil {
+				return err
+			}
+		}
+
+		status := model.PaymentStatusFor(balance.TotalAmount, balance.PaidAmount)
+		if err := s.Repo.UpdateSalePayment(ctx, saleId, balance.PaidAmount, status); err != nil {
+			return err
+		}
+
+		response = toPaymentsResponse(saleId, balance, change, payments)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// applyTenders membagi tender ke sisa tagihan due. Tender non-tunai diproses
+// lebih dulu dan tidak boleh melebihi sisa tagihan; tender cash menutup sisanya
+// dan kelebihannya menjadi kembalian. Urutan hasil sama dengan urutan request.
+func applyTenders(due model.Money, tenders []dto.TenderRequest) ([]model.Payments, model.Money, error) {
+	if due <= 0 {
+		return nil, 0, apperror.SaleAlreadyPaid()
+	}
+
+	payments := make([]model.Payments, len(tenders))
+	for i, tender := range tenders {
+		if tender.Amount <= 0 {
+			return nil, 0, apperror.Validation("payment amount must be greater than 0", nil)
+		}
+		payments[i] = model.Payments{Method: tender.Method, Tendered: tender.Amount, Reference: tender.Reference}
+	}
+
+	remaining := due
+	for i := range payments {
+		p := &payments[i]
+		if p.Method == model.TenderCash {
+			continue
+		}
+		if p.Tendered > remaining {
+			return nil, 0, apperror.PaymentExceedsBalance(map[string]any{
+				"method":      p.Method,
+				"amount":      p.Tendered,
+				"balance_due": remaining,
+			})
+		}
+		p.Amount = p.Tendered
+		remaining -= p.Amount
+	}
+
+	var change model.Money
+	for i := range payments {
+		p := &payments[i]
+		if p.Method != model.TenderCash {
+			continue
+		}
+		if remaining == 0 {
+			return nil, 0, apperror.Validation("cash tender is not needed, balance is already covered", nil)
+		}
+		p.Amount = min(p.Tendered, remaining)
+		p.Change = p.Tendered - p.Amount
+		remaining -= p.Amount
+		change += p.Change
+	}
+
+	return payments, change, nil
+}
+
+func toPaymentsResponse(saleId int, balance *repository.SaleBalance, change model.Money, payments []model.Payments) *dto.PaymentsResponse {
+	return &dto.PaymentsResponse{
+		SaleId:        saleId,
+		PaymentStatus: model.PaymentStatusFor(balance.TotalAmount, balance.PaidAmount),
+		TotalAmount:   balance.TotalAmount,
+		PaidAmount:    balance.PaidAmount,
+		BalanceDue:    max(balance.TotalAmount-balance.PaidAmount, 0),
+		Change:        change,
+		Payments:      payments,
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPaymentsRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentsRepository) GetSaleBalance(ctx context.Context, saleId int) (*repository.SaleBalance, error) {
+	args := m.Called(saleId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.SaleBalance), args.Error(1)
+}
+
+func (m *MockPaymentsRepository) LockSaleBalance(ctx context.Context, saleId int) (*repository.SaleBalance, error) {
+	args := m.Called(saleId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.SaleBalance), args.Error(1)
+}
+
+func (m *MockPaymentsRepository) CreatePayment(ctx context.Context, payment *model.Payments) error {
+	args := m.Called(payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentsRepository) UpdateSalePayment(ctx context.Context, saleId int, paid model.Money, status string) error {
+	args := m.Called(saleId, paid, status)
+	return args.Error(0)
+}
+
+func (m *MockPaymentsRepository) GetPaymentsBySale(ctx context.Context, saleId int) ([]model.Payments, error) {
+	args := m.Called(saleId)
+	return args.Get(0).([]model.Payments), args.Error(1)
+}
+
+func tender(method, amount string) dto.TenderRequest {
+	return dto.TenderRequest{Method: method, Amount: model.MustParseMoney(amount)}
+}
+
+func TestApplyTenders(t *testing.T) {
+	tests := []struct {
+		name    string
+		due     string
+		tenders []dto.TenderRequest
+		amounts []string
+		change  string
+	}{
+		{"cash pas", "100", []dto.TenderRequest{tender("cash", "100")}, []string{"100"}, "0"},
+		{"cash dengan kembalian", "87.50", []dto.TenderRequest{tender("cash", "100")}, []string{"87.50"}, "12.50"},
+		{"bayar sebagian", "100", []dto.TenderRequest{tender("transfer", "40")}, []string{"40"}, "0"},
+		// non-tunai dihitung dulu walau di request cash lebih awal
+		{"split cash dan qris", "120000", []dto.TenderRequest{tender("cash", "50000"), tender("qris", "100000")}, []string{"20000", "100000"}, "30000"},
+		{"split tiga tender", "300", []dto.TenderRequest{tender("card", "100"), tender("cash", "50"), tender("cash", "200")}, []string{"100", "50", "150"}, "50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payments, change, err := applyTenders(model.MustParseMoney(tt.due), tt.tenders)
+
+			require.NoError(t, err)
+			require.Len(t, payments, len(tt.amounts))
+			for i, p := range payments {
+				assert.Equal(t, tt.tenders[i].Method, p.Method)
+				assert.Equal(t, model.MustParseMoney(tt.amounts[i]), p.Amount)
+				assert.Equal(t, p.Tendered, p.Amount+p.Change)
+			}
+			assert.Equal(t, model.MustParseMoney(tt.change), change)
+		})
+	}
+}
+
+func TestApplyTenders_Errors(t *testing.T) {
+	_, _, err := applyTenders(0, []dto.TenderRequest{tender("cash", "10")})
+	assert.Equal(t, "sale_already_paid", err.(*apperror.Error).Code)
+
+	_, _, err = applyTenders(model.MustParseMoney("100"), []dto.TenderRequest{tender("card", "60"), tender("qris", "60")})
+	assert.Equal(t, "payment_exceeds_balance", err.(*apperror.Error).Code)
+
+	// kartu sudah menutup tagihan, tidak ada yang perlu dibayar tunai
+	_, _, err = applyTenders(model.MustParseMoney("100"), []dto.TenderRequest{tender("cash", "20"), tender("card", "100")})
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+}
+
+func TestPaymentsService_CreatePayments(t *testing.T) {
+	repo := new(MockPaymentsRepository)
+	audit := &fakeAuditor{}
+	service := NewPaymentsService(repo, audit)
+
+	repo.On("LockSaleBalance", 5).Return(&repository.SaleBalance{
+		TotalAmount: model.MustParseMoney("150"), PaidAmount: model.MustParseMoney("30"), Status: model.SaleCompleted,
+	}, nil)
+	nextId := 10
+	repo.On("CreatePayment", mock.AnythingOfType("*model.Payments")).Run(func(args mock.Arguments) {
+		p := args.Get(0).(*model.Payments)
+		p.Id = nextId
+		nextId++
+	}).Return(nil).Twice()
+	repo.On("UpdateSalePayment", 5, model.MustParseMoney("150"), model.PaymentPaid).Return(nil)
+
+	res, err := service.CreatePayments(context.Background(), 5, 7, &dto.PaymentsRequest{
+		Payments: []dto.TenderRequest{tender("card", "20"), tender("cash", "200")},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.PaymentPaid, res.PaymentStatus)
+	assert.Equal(t, model.MustParseMoney("150"), res.PaidAmount)
+	assert.Equal(t, model.Money(0), res.BalanceDue)
+	assert.Equal(t, model.MustParseMoney("100"), res.Change)
+	require.Len(t, res.Payments, 2)
+	assert.Equal(t, 7, res.Payments[1].UserId)
+	assert.Equal(t, model.MustParseMoney("100"), res.Payments[1].Amount)
+	require.Len(t, audit.entries, 2)
+	assert.Equal(t, "payment", audit.entries[0].Entity)
+	assert.Equal(t, 11, audit.entries[1].EntityId)
+	repo.AssertExpectations(t)
+}
+
+func TestPaymentsService_CreatePayments_Partial(t *testing.T) {
+	repo := new(MockPaymentsRepository)
+	service := NewPaymentsService(repo, &fakeAuditor{})
+
+	repo.On("LockSaleBalance", 5).Return(&repository.SaleBalance{TotalAmount: model.MustParseMoney("150"), Status: model.SaleCompleted}, nil)
+	repo.On("CreatePayment", mock.Anything).Return(nil)
+	repo.On("UpdateSalePayment", 5, model.MustParseMoney("50"), model.PaymentPartial).Return(nil)
+
+	res, err := service.CreatePayments(context.Background(), 5, 7, &dto.PaymentsRequest{
+		Payments: []dto.TenderRequest{tender("transfer", "50")},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.PaymentPartial, res.PaymentStatus)
+	assert.Equal(t, model.MustParseMoney("100"), res.BalanceDue)
+	repo.AssertExpectations(t)
+}
+
+func TestPaymentsService_CreatePayments_ExceedsBalance(t *testing.T) {
+	repo := new(MockPaymentsRepository)
+	service := NewPaymentsService(repo, &fakeAuditor{})
+
+	repo.On("LockSaleBalance", 5).Return(&repository.SaleBalance{
+		TotalAmount: model.MustParseMoney("150"), PaidAmount: model.MustParseMoney("100"), Status: model.SaleCompleted,
+	}, nil)
+
+	_, err := service.CreatePayments(context.Background(), 5, 7, &dto.PaymentsRequest{
+		Payments: []dto.TenderRequest{tender("card", "60")},
+	})
+
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+	repo.AssertNotCalled(t, "CreatePayment", mock.Anything)
+	repo.AssertNotCalled(t, "UpdateSalePayment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPaymentsService_CreatePayments_VoidedSale(t *testing.T) {
+	repo := new(MockPaymentsRepository)
+	service := NewPaymentsService(repo, &fakeAuditor{})
+
+	repo.On("LockSaleBalance", 5).Return(&repository.SaleBalance{
+		TotalAmount: model.MustParseMoney("150"), Status: model.SaleVoided,
+	}, nil)
+
+	_, err := service.CreatePayments(context.Background(), 5, 7, &dto.PaymentsRequest{
+		Payments: []dto.TenderRequest{tender("cash", "150")},
+	})
+
+	assert.Equal(t, "sale_voided", apperror.Code(err))
+	repo.AssertNotCalled(t, "CreatePayment", mock.Anything)
+}
+
+func TestPaymentsService_CreatePayments_HeldSale(t *testing.T) {
+	repo := new(MockPaymentsRepository)
+	service := NewPaymentsService(repo, &fakeAuditor{})
+
+	repo.On("LockSaleBalance", 5).Return(&repository.SaleBalance{
+		TotalAmount: model.MustParseMoney("150"), Status: model.SaleHeld,
+	}, nil)
+
+	_, err := service.CreatePayments(context.Background(), 5, 7, &dto.PaymentsRequest{
+		Payments: []dto.TenderRequest{tender("cash", "150")},
+	})
+
+	assert.Equal(t, "invalid_sale_status", apperror.Code(err))
+	repo.AssertNotCalled(t, "CreatePayment", mock.Anything)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"math/big"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"sort"
+)
+
+// Perhitungan harga sale memakai bilangan bulat supaya eksak: uang dalam sen
+// (model.Money) dan tarif dalam basis point (1/100 persen, PPN 11% = 1100).
+//
+// Urutan per baris: subtotal (qty x price) -> diskon baris -> bagian diskon order
+// -> pajak. Setiap pembulatan ke sen memakai half-up, sekali per baris, dan total
+// header adalah jumlah baris sehingga selalu konsisten.
+
+// bpsScale adalah 100% dalam basis point
+const bpsScale = 10000
+
+type lineInput struct {
+	ItemId       int
+	Quantity     int64
+	Price        int64 // sen per unit
+	Discount     *dto.DiscountRequest
+	TaxRate      int64 // basis point
+	TaxInclusive bool
+}
+
+type linePricing struct {
+	Subtotal int64
+	Discount int64
+	Tax      int64
+	Total    int64
+}
+
+type salePricing struct {
+	Lines    []linePricing
+	Subtotal int64
+	Discount int64
+	Tax      int64
+	Total    int64
+}
+
+// priceSale menghitung diskon, pajak dan total tiap baris serta header.
+// Diskon order dibagi ke baris secara proporsional terhadap nilai setelah diskon
+// baris; sisa pembulatan diberikan ke baris dengan sisa pecahan terbesar.
+func priceSale(lines []lineInput, orderDiscount *dto.DiscountRequest) (*salePricing, error) {
+	pricing := &salePricing{Lines: make([]linePricing, len(lines))}
+	net := make([]int64, len(lines))
+	var netTotal int64
+
+	for i, line := range lines {
+		subtotal := line.Quantity * line.Price
+		discount, err := discountAmount(line.Discount, subtotal)
+		if err != nil {
+			return nil, apperror.Validation(err.Error(), map[string]int{"item_id": line.ItemId})
+		}
+		pricing.Lines[i].Subtotal = subtotal
+		pricing.Lines[i].Discount = discount
+		net[i] = subtotal - discount
+		netTotal += net[i]
+	}
+
+	orderAmount, err := discountAmount(orderDiscount, netTotal)
+	if err != nil {
+		return nil, apperror.Validation("order "+err.Error(), nil)
+	}
+	shares := allocate(orderAmount, net)
+
+	for i, line := range lines {
+		l := &pricing.Lines[i]
+		base := net[i] - shares[i]
+		l.Discount += shares[i]
+		if line.TaxInclusive {
+			// base sudah termasuk pajak: DPP = base / (1 + rate)
+			l.Tax = base - mulDivRound(base, bpsScale, bpsScale+line.TaxRate)
+			l.Total = base
+		} else {
+			l.Tax = mulDivRound(base, line.TaxRate, bpsScale)
+			l.Total = base + l.Tax
+		}
+
+		pricing.Subtotal += l.Subtotal
+		pricing.Discount += l.Discount
+		pricing.Tax += l.Tax
+		pricing.Total += l.Total
+	}
+
+	return pricing, nil
+}
+
+// discountAmount mengubah diskon request menjadi nominal (sen) terhadap amount
+func discountAmount(discount *dto.DiscountRequest, amount int64) (int64, error) {
+	if discount == nil {
+		return 0, nil
+	}
+	// Value 2 desimal: untuk percent, jumlah "sen"-nya sama dengan basis point
+	value := discount.Value.Cents()
+	if value < 0 {
+		return 0, fmt.Errorf("discount must not be negative")
+	}
+
+	switch discount.Type {
+	case model.DiscountPercent:
+		if value > bpsScale {
+			return 0, fmt.Errorf("discount percent must be between 0 and 100")
+		}
+		return mulDivRound(amount, value, bpsScale), nil
+	case model.DiscountFixed:
+		if value > amount {
+			return 0, fmt.Errorf("discount exceeds subtotal")
+		}
+		return value, nil
+	}
+	return 0, fmt.Errorf("discount type must be percent or fixed")
+}
+
+// allocate membagi total ke weights secara proporsional (largest remainder),
+// hasilnya selalu berjumlah tepat total. total tidak boleh melebihi jumlah weights.
+func allocate(total int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	var sum int64
+	for _, w := range weights {
+		sum += w
+	}
+	if total == 0 || sum == 0 {
+		return shares
+	}
+
+	type remainder struct {
+		index int
+		rem   *big.Int
+	}
+	bigTotal, bigSum := big.NewInt(total), big.NewInt(sum)
+	rems := make([]remainder, len(weights))
+	allocated := int64(0)
+	for i, w := range weights {
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(bigTotal, big.NewInt(w)), bigSum, new(big.Int))
+		shares[i] = q.Int64()
+		allocated += shares[i]
+		rems[i] = remainder{i, r}
+	}
+
+	sort.SliceStable(rems, func(a, b int) bool { return rems[a].rem.Cmp(rems[b].rem) > 0 })
+	for i := int64(0); i < total-allocated; i++ {
+		shares[rems[i].index]++
+	}
+	return shares
+}
+
+// mulDivRound menghitung a*b/c dibulatkan half-up untuk a, b >= 0 dan c > 0.
+// Memakai big.Int supaya a*b tidak overflow.
+func mulDivRound(a, b, c int64) int64 {
+	q, r := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(a), big.NewInt(b)), big.NewInt(c), new(big.Int))
+	if r.Lsh(r, 1).Cmp(big.NewInt(c)) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q.Int64()
+}
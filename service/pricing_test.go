@@ -0,0 +1,235 @@
+package service
+
+import (
+	"math/big"
+	"math/rand"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func percent(v string) *dto.DiscountRequest {
+	return &dto.DiscountRequest{Type: model.DiscountPercent, Value: model.MustParseMoney(v)}
+}
+
+func fixed(v string) *dto.DiscountRequest {
+	return &dto.DiscountRequest{Type: model.DiscountFixed, Value: model.MustParseMoney(v)}
+}
+
+func TestPriceSale(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []lineInput
+		order *dto.DiscountRequest
+		want  []linePricing
+	}{
+		{
+			name:  "tanpa pajak dan diskon",
+			lines: []lineInput{{Quantity: 3, Price: 1999}},
+			want:  []linePricing{{Subtotal: 5997, Total: 5997}},
+		},
+		{
+			// 10.05 x 11% = 1.1055 -> 1.11 (half-up)
+			name:  "PPN exclusive dibulatkan half-up",
+			lines: []lineInput{{Quantity: 1, Price: 1005, TaxRate: 1100}},
+			want:  []linePricing{{Subtotal: 1005, Tax: 111, Total: 1116}},
+		},
+		{
+			// 0.05 x 10% = 0.005 -> 0.01
+			name:  "PPN exclusive tepat di setengah sen",
+			lines: []lineInput{{Quantity: 1, Price: 5, TaxRate: 1000}},
+			want:  []linePricing{{Subtotal: 5, Tax: 1, Total: 6}},
+		},
+		{
+			// 111.00 termasuk PPN 11%: DPP 100.00, pajak 11.00
+			name:  "PPN inclusive",
+			lines: []lineInput{{Quantity: 1, Price: 11100, TaxRate: 1100, TaxInclusive: true}},
+			want:  []linePricing{{Subtotal: 11100, Tax: 1100, Total: 11100}},
+		},
+		{
+			// 10.00 / 1.11 = 9.009 -> DPP 9.01, pajak 0.99
+			name:  "PPN inclusive dengan pembulatan",
+			lines: []lineInput{{Quantity: 1, Price: 1000, TaxRate: 1100, TaxInclusive: true}},
+			want:  []linePricing{{Subtotal: 1000, Tax: 99, Total: 1000}},
+		},
+		{
+			// 33.33 x 12.5% = 4.16625 -> 4.17
+			name:  "diskon baris percent",
+			lines: []lineInput{{Quantity: 1, Price: 3333, Discount: percent("12.5")}},
+			want:  []linePricing{{Subtotal: 3333, Discount: 417, Total: 2916}},
+		},
+		{
+			name:  "diskon baris fixed sebelum pajak",
+			lines: []lineInput{{Quantity: 2, Price: 5000, Discount: fixed("10"), TaxRate: 1100}},
+			want:  []linePricing{{Subtotal: 10000, Discount: 1000, Tax: 990, Total: 9990}},
+		},
+		{
+			// 0.10 dibagi tiga baris sama besar: sisa 1 sen ke baris pertama
+			name:  "diskon order dibagi rata dengan sisa",
+			lines: []lineInput{{Quantity: 1, Price: 100}, {Quantity: 1, Price: 100}, {Quantity: 1, Price: 100}},
+			order: fixed("0.10"),
+			want: []linePricing{
+				{Subtotal: 100, Discount: 4, Total: 96},
+				{Subtotal: 100, Discount: 3, Total: 97},
+				{Subtotal: 100, Discount: 3, Total: 97},
+			},
+		},
+		{
+			// diskon order 10% dari nilai setelah diskon baris (90 + 10)
+			name: "diskon order percent setelah diskon baris",
+			lines: []lineInput{
+				{Quantity: 1, Price: 10000, Discount: fixed("10")},
+				{Quantity: 1, Price: 1000, TaxRate: 1100},
+			},
+			order: percent("10"),
+			want: []linePricing{
+				{Subtotal: 10000, Discount: 1900, Total: 8100},
+				{Subtotal: 1000, Discount: 100, Tax: 99, Total: 999},
+			},
+		},
+		{
+			name:  "diskon 100 persen",
+			lines: []lineInput{{Quantity: 1, Price: 1000, TaxRate: 1100}},
+			order: percent("100"),
+			want:  []linePricing{{Subtotal: 1000, Discount: 1000, Total: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := priceSale(tt.lines, tt.order)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Lines)
+
+			var want linePricing
+			for _, l := range tt.want {
+				want.Subtotal += l.Subtotal
+				want.Discount += l.Discount
+				want.Tax += l.Tax
+				want.Total += l.Total
+			}
+			assert.Equal(t, want, linePricing{got.Subtotal, got.Discount, got.Tax, got.Total})
+		})
+	}
+}
+
+func TestPriceSale_InvalidDiscount(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []lineInput
+		order *dto.DiscountRequest
+	}{
+		{"fixed melebihi subtotal baris", []lineInput{{ItemId: 3, Quantity: 1, Price: 1000, Discount: fixed("10.01")}}, nil},
+		{"percent di atas 100", []lineInput{{Quantity: 1, Price: 1000, Discount: percent("100.5")}}, nil},
+		{"diskon order melebihi total", []lineInput{{Quantity: 1, Price: 1000, Discount: fixed("5")}}, fixed("5.01")},
+		{"tipe tidak dikenal", []lineInput{{Quantity: 1, Price: 1000}}, &dto.DiscountRequest{Type: "bogo", Value: model.MustParseMoney("1")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := priceSale(tt.lines, tt.order)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMulDivRound(t *testing.T) {
+	assert.Equal(t, int64(1), mulDivRound(1, 1, 2)) // 0.5 -> 1
+	assert.Equal(t, int64(0), mulDivRound(1, 1, 3)) // 0.33 -> 0
+	assert.Equal(t, int64(1), mulDivRound(2, 1, 3)) // 0.67 -> 1
+	assert.Equal(t, int64(3), mulDivRound(5, 1, 2)) // 2.5 -> 3
+	// a*b melebihi int64 tetap eksak
+	assert.Equal(t, int64(922337203685477580), mulDivRound(922337203685477580, 10000, 10000))
+}
+
+// ratCents mengubah sen ke big.Rat (rupiah) dari string desimal, tanpa lewat float64
+func ratCents(cents int64) *big.Rat {
+	r, _ := new(big.Rat).SetString(big.NewInt(cents).String() + "/100")
+	return r
+}
+
+// roundHalfUpCents membulatkan nilai rupiah eksak ke sen (half-up, nilai >= 0)
+func roundHalfUpCents(r *big.Rat) int64 {
+	scaled := new(big.Rat).Mul(r, big.NewRat(100, 1))
+	scaled.Add(scaled, big.NewRat(1, 2))
+	return new(big.Int).Quo(scaled.Num(), scaled.Denom()).Int64()
+}
+
+// TestPriceSale_ExactArithmetic membandingkan hasil priceSale dengan perhitungan
+// ulang memakai big.Rat untuk input acak, dan memastikan total baris selalu sama
+// dengan total header
+func TestPriceSale_ExactArithmetic(t *testing.T) {
+	rng := rand.New(rand.NewSource(43))
+	rates := []int64{0, 500, 1000, 1100, 1200, 1250}
+
+	for n := 0; n < 2000; n++ {
+		lines := make([]lineInput, 1+rng.Intn(6))
+		for i := range lines {
+			lines[i] = lineInput{
+				ItemId:       i + 1,
+				Quantity:     int64(1 + rng.Intn(20)),
+				Price:        int64(1 + rng.Intn(5_000_000)),
+				TaxRate:      rates[rng.Intn(len(rates))],
+				TaxInclusive: rng.Intn(2) == 0,
+			}
+			if rng.Intn(3) == 0 {
+				lines[i].Discount = &dto.DiscountRequest{Type: model.DiscountPercent, Value: model.Money(rng.Intn(10001))}
+			}
+		}
+		var order *dto.DiscountRequest
+		if rng.Intn(2) == 0 {
+			order = &dto.DiscountRequest{Type: model.DiscountPercent, Value: model.Money(rng.Intn(5001))}
+		}
+
+		got, err := priceSale(lines, order)
+		require.NoError(t, err)
+
+		var net, header, exclusiveTax int64
+		for i, line := range lines {
+			// diskon baris dan pajak dihitung ulang secara eksak
+			subtotal := new(big.Rat).Mul(ratCents(line.Price), big.NewRat(line.Quantity, 1))
+			require.Equal(t, line.Quantity*line.Price, roundHalfUpCents(subtotal))
+
+			l := got.Lines[i]
+			base := l.Subtotal - l.Discount
+			rate := big.NewRat(line.TaxRate, bpsScale)
+			if line.TaxInclusive {
+				dpp := new(big.Rat).Quo(ratCents(base), new(big.Rat).Add(big.NewRat(1, 1), rate))
+				assert.Equal(t, base-roundHalfUpCents(dpp), l.Tax)
+				assert.Equal(t, base, l.Total)
+			} else {
+				assert.Equal(t, roundHalfUpCents(new(big.Rat).Mul(ratCents(base), rate)), l.Tax)
+				assert.Equal(t, base+l.Tax, l.Total)
+				exclusiveTax += l.Tax
+			}
+			assert.GreaterOrEqual(t, base, int64(0))
+
+			lineDiscount, _ := discountAmount(line.Discount, l.Subtotal)
+			net += l.Subtotal - lineDiscount
+			header += l.Total
+		}
+
+		// diskon order terbagi habis ke baris, tidak lebih dan tidak kurang
+		orderDiscount, _ := discountAmount(order, net)
+		var allocated int64
+		for i, line := range lines {
+			lineDiscount, _ := discountAmount(line.Discount, got.Lines[i].Subtotal)
+			allocated += got.Lines[i].Discount - lineDiscount
+		}
+		assert.Equal(t, orderDiscount, allocated)
+
+		assert.Equal(t, header, got.Total)
+		assert.Equal(t, got.Subtotal-got.Discount+exclusiveTax, got.Total)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	assert.Equal(t, []int64{34, 33, 33}, allocate(100, []int64{1, 1, 1}))
+	assert.Equal(t, []int64{0, 0}, allocate(0, []int64{5, 5}))
+	assert.Equal(t, []int64{0, 10}, allocate(10, []int64{0, 10}))
+	// sisa diberikan ke pecahan terbesar: 7 x (1/6, 2/6, 3/6) = 1.17, 2.33, 3.5
+	assert.Equal(t, []int64{1, 2, 4}, allocate(7, []int64{1, 2, 3}))
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"time"
+)
+
+type PurgeService interface {
+	PurgeDeleted(ctx context.Context, retention time.Duration) (map[string]int64, error)
+}
+
+type purgeService struct {
+	Repo repository.PurgeRepository
+	now  func() time.Time
+}
+
+func NewPurgeService(repo repository.PurgeRepository) PurgeService {
+	return &purgeService{Repo: repo, now: time.Now}
+}
+
+// PurgeDeleted menghapus permanen data yang sudah di-soft delete lebih lama dari retention
+func (s *purgeService) PurgeDeleted(ctx context.Context, retention time.Duration) (map[string]int64, error) {
+	ctx, span := utils.Tracer().Start(ctx, "PurgeService.PurgeDeleted")
+	defer span.End()
+
+	if retention < 0 {
+		retention = 0
+	}
+	return s.Repo.PurgeDeleted(ctx, s.now().Add(-retention))
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPurgeRepository struct {
+	mock.Mock
+}
+
+func (m *MockPurgeRepository) PurgeDeleted(ctx context.Context, before time.Time) (map[string]int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func TestPurgeService_PurgeDeleted_UsesRetentionCutoff(t *testing.T) {
+	mockRepo := new(MockPurgeRepository)
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	svc := &purgeService{Repo: mockRepo, now: func() time.Time { return now }}
+
+	cutoff := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo.On("PurgeDeleted", cutoff).Return(map[string]int64{"items": 3}, nil)
+
+	purged, err := svc.PurgeDeleted(context.Background(), 30*24*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged["items"])
+	mockRepo.AssertExpectations(t)
+}
@@ -1,31 +1,42 @@
 package service
 
 import (
+	"context"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
 )
 
 type RacksService interface {
-	GetRacksById(id int) (*model.Racks, error)
-	GetAllRacks(page, limit int) ([]model.Racks, int, error)
-	CreateRacks(data *model.Racks) error
-	UpdateRacks(id int, data *model.Racks) error
-	DeleteRacks(id int) error
+	GetRacksById(ctx context.Context, id int) (*model.Racks, error)
+	GetAllRacks(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Racks, int, error)
+	CreateRacks(ctx context.Context, data *model.Racks) error
+	UpdateRacks(ctx context.Context, id int, data *model.Racks) error
+	PatchRacks(ctx context.Context, id, version int, changes map[string]any) (*model.Racks, error)
+	DeleteRacks(ctx context.Context, id int) error
+	RestoreRacks(ctx context.Context, id int) (*model.Racks, error)
 }
 
 type racksService struct {
-	Repo repository.RacksRepository
+	Repo  repository.RacksRepository
+	Audit Auditor
 }
 
-func NewRacksService(repo repository.RacksRepository) RacksService {
-	return &racksService{Repo: repo}
+func NewRacksService(repo repository.RacksRepository, audit Auditor) RacksService {
+	return &racksService{Repo: repo, Audit: audit}
 }
 
-func (s *racksService) GetRacksById(id int) (*model.Racks, error) {
-	return s.Repo.GetRacksById(id)
+func (s *racksService) GetRacksById(ctx context.Context, id int) (*model.Racks, error) {
+	ctx, span := utils.Tracer().Start(ctx, "RacksService.GetRacksById")
+	defer span.End()
+
+	return s.Repo.GetRacksById(ctx, id)
 }
 
-func (s *racksService) GetAllRacks(page, limit int) ([]model.Racks, int, error) {
+func (s *racksService) GetAllRacks(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Racks, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "RacksService.GetAllRacks")
+	defer span.End()
+
 	// Validate pagination parameters
 	if page < 1 {
 		page = 1
@@ -37,17 +48,97 @@ func (s *racksService) GetAllRacks(page, limit int) ([]model.Racks, int, error)
 		limit = 100
 	}
 	
-	return s.Repo.GetAllRacks(page, limit)
+	return s.Repo.GetAllRacks(ctx, page, limit, includeDeleted)
 }
 
-func (s *racksService) CreateRacks(data *model.Racks) error {
-	return s.Repo.CreateRacks(data)
+func (s *racksService) CreateRacks(ctx context.Context, data *model.Racks) error {
+	ctx, span := utils.Tracer().Start(ctx, "RacksService.CreateRacks")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Repo.CreateRacks(ctx, data); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditCreate, "rack", data.Id, nil, data)
+	})
 }
 
-func (s *racksService) UpdateRacks(id int, data *model.Racks) error {
-	return s.Repo.UpdateRacks(id, data)
+func (s *racksService) UpdateRacks(ctx context.Context, id int, data *model.Racks) error {
+	ctx, span := utils.Tracer().Start(ctx, "RacksService.UpdateRacks")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetRacksById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.UpdateRacks(ctx, id, data); err != nil {
+			return err
+		}
+
+		// baca ulang agar response dan audit berisi data yang benar-benar tersimpan
+		after, err := s.Repo.GetRacksById(ctx, id)
+		if err != nil {
+			return err
+		}
+		*data = *after
+		return s.Audit.Record(ctx, model.AuditUpdate, "rack", id, before, after)
+	})
 }
 
-func (s *racksService) DeleteRacks(id int) error {
-	return s.Repo.DeleteRacks(id)
+func (s *racksService) PatchRacks(ctx context.Context, id, version int, changes map[string]any) (*model.Racks, error) {
+	ctx, span := utils.Tracer().Start(ctx, "RacksService.PatchRacks")
+	defer span.End()
+
+	var patched *model.Racks
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetRacksById(ctx, id)
+		if err != nil {
+			return err
+		}
+		patched, err = s.Repo.PatchRacks(ctx, id, version, changes)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditUpdate, "rack", id, before, patched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
 }
+
+func (s *racksService) DeleteRacks(ctx context.Context, id int) error {
+	ctx, span := utils.Tracer().Start(ctx, "RacksService.DeleteRacks")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetRacksById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.DeleteRacks(ctx, id); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditDelete, "rack", id, before, nil)
+	})
+}
+
+func (s *racksService) RestoreRacks(ctx context.Context, id int) (*model.Racks, error) {
+	ctx, span := utils.Tracer().Start(ctx, "RacksService.RestoreRacks")
+	defer span.End()
+
+	var restored *model.Racks
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		restored, err = s.Repo.RestoreRacks(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditRestore, "rack", id, nil, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
\ No newline at end of file
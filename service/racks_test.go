@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"testing"
@@ -15,7 +16,7 @@ type MockRacksRepository struct {
 	mock.Mock
 }
 
-func (m *MockRacksRepository) GetRacksById(id int) (*model.Racks, error) {
+func (m *MockRacksRepository) GetRacksById(ctx context.Context, id int) (*model.Racks, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -23,29 +24,45 @@ func (m *MockRacksRepository) GetRacksById(id int) (*model.Racks, error) {
 	return args.Get(0).(*model.Racks), args.Error(1)
 }
 
-func (m *MockRacksRepository) GetAllRacks(page, limit int) ([]model.Racks, int, error) {
-	args := m.Called(page, limit)
+func (m *MockRacksRepository) GetAllRacks(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Racks, int, error) {
+	args := m.Called(page, limit, includeDeleted)
 	return args.Get(0).([]model.Racks), args.Int(1), args.Error(2)
 }
 
-func (m *MockRacksRepository) CreateRacks(data *model.Racks) error {
+func (m *MockRacksRepository) CreateRacks(ctx context.Context, data *model.Racks) error {
 	args := m.Called(data)
 	return args.Error(0)
 }
 
-func (m *MockRacksRepository) UpdateRacks(id int, data *model.Racks) error {
+func (m *MockRacksRepository) UpdateRacks(ctx context.Context, id int, data *model.Racks) error {
 	args := m.Called(id, data)
 	return args.Error(0)
 }
 
-func (m *MockRacksRepository) DeleteRacks(id int) error {
+func (m *MockRacksRepository) PatchRacks(ctx context.Context, id, version int, changes map[string]any) (*model.Racks, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Racks), args.Error(1)
+}
+
+func (m *MockRacksRepository) DeleteRacks(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockRacksRepository) RestoreRacks(ctx context.Context, id int) (*model.Racks, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Racks), args.Error(1)
+}
+
 func TestRacksService_GetRacksById_Success(t *testing.T) {
 	mockRepo := new(MockRacksRepository)
-	service := NewRacksService(mockRepo)
+	service := NewRacksService(mockRepo, &fakeAuditor{})
 
 	now := time.Now()
 	expected := &model.Racks{
@@ -58,7 +75,7 @@ func TestRacksService_GetRacksById_Success(t *testing.T) {
 
 	mockRepo.On("GetRacksById", 1).Return(expected, nil)
 
-	result, err := service.GetRacksById(1)
+	result, err := service.GetRacksById(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -67,7 +84,7 @@ func TestRacksService_GetRacksById_Success(t *testing.T) {
 
 func TestRacksService_GetAllRacks_Success(t *testing.T) {
 	mockRepo := new(MockRacksRepository)
-	service := NewRacksService(mockRepo)
+	service := NewRacksService(mockRepo, &fakeAuditor{})
 
 	now := time.Now()
 	racks := []model.Racks{
@@ -75,9 +92,9 @@ func TestRacksService_GetAllRacks_Success(t *testing.T) {
 		{Id: 2, WarehouseId: 1, Name: "Rack A2", CreatedAt: now, UpdatedAt: now},
 	}
 
-	mockRepo.On("GetAllRacks", 1, 10).Return(racks, 2, nil)
+	mockRepo.On("GetAllRacks", 1, 10, false).Return(racks, 2, nil)
 
-	result, total, err := service.GetAllRacks(1, 10)
+	result, total, err := service.GetAllRacks(context.Background(), 1, 10, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, total)
@@ -87,12 +104,12 @@ func TestRacksService_GetAllRacks_Success(t *testing.T) {
 
 func TestRacksService_GetAllRacks_ValidationPage(t *testing.T) {
 	mockRepo := new(MockRacksRepository)
-	service := NewRacksService(mockRepo)
+	service := NewRacksService(mockRepo, &fakeAuditor{})
 
 	racks := []model.Racks{}
-	mockRepo.On("GetAllRacks", 1, 10).Return(racks, 0, nil)
+	mockRepo.On("GetAllRacks", 1, 10, false).Return(racks, 0, nil)
 
-	result, total, err := service.GetAllRacks(-1, 10)
+	result, total, err := service.GetAllRacks(context.Background(), -1, 10, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, total)
@@ -102,12 +119,12 @@ func TestRacksService_GetAllRacks_ValidationPage(t *testing.T) {
 
 func TestRacksService_GetAllRacks_ValidationLimit(t *testing.T) {
 	mockRepo := new(MockRacksRepository)
-	service := NewRacksService(mockRepo)
+	service := NewRacksService(mockRepo, &fakeAuditor{})
 
 	racks := []model.Racks{}
-	mockRepo.On("GetAllRacks", 1, 100).Return(racks, 0, nil)
+	mockRepo.On("GetAllRacks", 1, 100, false).Return(racks, 0, nil)
 
-	result, total, err := service.GetAllRacks(1, 200)
+	result, total, err := service.GetAllRacks(context.Background(), 1, 200, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, total)
@@ -117,7 +134,7 @@ func TestRacksService_GetAllRacks_ValidationLimit(t *testing.T) {
 
 func TestRacksService_CreateRacks_Success(t *testing.T) {
 	mockRepo := new(MockRacksRepository)
-	service := NewRacksService(mockRepo)
+	service := NewRacksService(mockRepo, &fakeAuditor{})
 
 	rack := &model.Racks{
 		WarehouseId: 1,
@@ -126,7 +143,7 @@ func TestRacksService_CreateRacks_Success(t *testing.T) {
 
 	mockRepo.On("CreateRacks", rack).Return(nil)
 
-	err := service.CreateRacks(rack)
+	err := service.CreateRacks(context.Background(), rack)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -134,16 +151,18 @@ func TestRacksService_CreateRacks_Success(t *testing.T) {
 
 func TestRacksService_UpdateRacks_Success(t *testing.T) {
 	mockRepo := new(MockRacksRepository)
-	service := NewRacksService(mockRepo)
+	service := NewRacksService(mockRepo, &fakeAuditor{})
 
 	rack := &model.Racks{
 		WarehouseId: 1,
 		Name:        "Updated Rack",
 	}
 
+	mockRepo.On("GetRacksById", 1).Return(&model.Racks{Id: 1, Version: 1}, nil).Once()
 	mockRepo.On("UpdateRacks", 1, rack).Return(nil)
+	mockRepo.On("GetRacksById", 1).Return(&model.Racks{Id: 1, Version: 2}, nil).Once()
 
-	err := service.UpdateRacks(1, rack)
+	err := service.UpdateRacks(context.Background(), 1, rack)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -151,11 +170,12 @@ func TestRacksService_UpdateRacks_Success(t *testing.T) {
 
 func TestRacksService_DeleteRacks_Success(t *testing.T) {
 	mockRepo := new(MockRacksRepository)
-	service := NewRacksService(mockRepo)
+	service := NewRacksService(mockRepo, &fakeAuditor{})
 
+	mockRepo.On("GetRacksById", 1).Return(&model.Racks{Id: 1}, nil)
 	mockRepo.On("DeleteRacks", 1).Return(nil)
 
-	err := service.DeleteRacks(1)
+	err := service.DeleteRacks(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -163,11 +183,12 @@ func TestRacksService_DeleteRacks_Success(t *testing.T) {
 
 func TestRacksService_DeleteRacks_Error(t *testing.T) {
 	mockRepo := new(MockRacksRepository)
-	service := NewRacksService(mockRepo)
+	service := NewRacksService(mockRepo, &fakeAuditor{})
 
+	mockRepo.On("GetRacksById", 999).Return(&model.Racks{Id: 999}, nil)
 	mockRepo.On("DeleteRacks", 999).Return(errors.New("rack not found"))
 
-	err := service.DeleteRacks(999)
+	err := service.DeleteRacks(context.Background(), 999)
 
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
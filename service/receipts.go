@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+)
+
+type ReceiptsService interface {
+	GetReceipt(ctx context.Context, saleId int) (*dto.Receipt, error)
+}
+
+type receiptsService struct {
+	Sales    repository.SalesRepository
+	Payments repository.PaymentsRepository
+	Repo     repository.ReceiptsRepository
+	Config   utils.ReceiptConfig
+}
+
+func NewReceiptsService(sales repository.SalesRepository, payments repository.PaymentsRepository, repo repository.ReceiptsRepository, config utils.ReceiptConfig) ReceiptsService {
+	return &receiptsService{Sales: sales, Payments: payments, Repo: repo, Config: config}
+}
+
+// GetReceipt mengumpulkan sale, item, pembayaran dan header toko untuk dicetak
+func (s *receiptsService) GetReceipt(ctx context.Context, saleId int) (*dto.Receipt, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReceiptsService.GetReceipt")
+	defer span.End()
+
+	sale, items, err := s.Sales.GetSalesById(ctx, saleId)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.Repo.GetReceiptInfo(ctx, saleId)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.Payments.GetPaymentsBySale(ctx, saleId)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &dto.Receipt{
+		StoreName:         s.Config.StoreName,
+		StoreAddress:      s.Config.StoreAddress,
+		StorePhone:        s.Config.StorePhone,
+		Footer:            s.Config.Footer,
+		Warehouse:         info.Warehouse,
+		WarehouseLocation: info.WarehouseLocation,
+
+		SaleId:        sale.Id,
+		InvoiceNo:     sale.InvoiceNo,
+		CreatedAt:     sale.CreatedAt,
+		Cashier:       info.Cashier,
+		Customer:      info.Customer,
+		PaymentType:   sale.PaymentType,
+		Status:        sale.Status,
+		VoidReason:    sale.VoidReason,
+		HoldExpiresAt: sale.HoldExpiresAt,
+
+		Subtotal:       sale.Subtotal,
+		DiscountAmount: sale.DiscountAmount,
+		TaxAmount:      sale.TaxAmount,
+		TotalAmount:    sale.TotalAmount,
+
+		PaymentStatus: sale.PaymentStatus,
+		Payments:      payments,
+		PaidAmount:    sale.PaidAmount,
+		BalanceDue:    max(sale.TotalAmount-sale.PaidAmount, 0),
+	}
+
+	for _, item := range items {
+		name, ok := info.ItemNames[item.Id]
+		if !ok {
+			name = fmt.Sprintf("Item #%d", item.ItemId)
+		}
+		receipt.Lines = append(receipt.Lines, dto.ReceiptLine{
+			Name:           name,
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			DiscountAmount: item.DiscountAmount,
+			TaxRate:        item.TaxRate,
+			TaxInclusive:   item.TaxInclusive,
+			TaxAmount:      item.TaxAmount,
+			Total:          item.Total,
+		})
+	}
+	for _, p := range payments {
+		if p.Method == model.TenderCash {
+			receipt.Change += p.Change
+		}
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReceiptsRepository struct {
+	mock.Mock
+}
+
+func (m *MockReceiptsRepository) GetReceiptInfo(ctx context.Context, saleId int) (*repository.ReceiptInfo, error) {
+	args := m.Called(saleId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ReceiptInfo), args.Error(1)
+}
+
+var testReceiptConfig = utils.ReceiptConfig{StoreName: "Toko Azwin", Footer: "Terima kasih", Width: 42}
+
+func TestReceiptsService_GetReceipt(t *testing.T) {
+	sales, payments, repo := new(MockSalesRepository), new(MockPaymentsRepository), new(MockReceiptsRepository)
+	s := NewReceiptsService(sales, payments, repo, testReceiptConfig)
+
+	sale := &model.Sales{
+		Id: 5, InvoiceNo: "INV/2026/10/000005", PaymentType: model.PaymentCash,
+		Subtotal: model.MustParseMoney("65000"), TotalAmount: model.MustParseMoney("65000"),
+		PaidAmount: model.MustParseMoney("40000"), PaymentStatus: model.PaymentPartial,
+	}
+	items := []model.SaleItems{
+		{Id: 11, ItemId: 1, Quantity: 2, Price: model.MustParseMoney("25000"), Total: model.MustParseMoney("50000")},
+		{Id: 12, ItemId: 9, Quantity: 1, Price: model.MustParseMoney("15000"), Total: model.MustParseMoney("15000")},
+	}
+	sales.On("GetSalesById", 5).Return(sale, items, nil)
+	repo.On("GetReceiptInfo", 5).Return(&repository.ReceiptInfo{
+		Cashier: "Budi", Warehouse: "Gudang Utama", ItemNames: map[int]string{11: "Kopi Arabika"},
+	}, nil)
+	payments.On("GetPaymentsBySale", 5).Return([]model.Payments{
+		{Method: model.TenderCard, Amount: model.MustParseMoney("20000"), Tendered: model.MustParseMoney("20000")},
+		{Method: model.TenderCash, Amount: model.MustParseMoney("20000"), Tendered: model.MustParseMoney("50000"), Change: model.MustParseMoney("30000")},
+	}, nil)
+
+	got, err := s.GetReceipt(context.Background(), 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Toko Azwin", got.StoreName)
+	assert.Equal(t, "Terima kasih", got.Footer)
+	assert.Equal(t, "INV/2026/10/000005", got.InvoiceNo)
+	assert.Equal(t, "Budi", got.Cashier)
+	assert.Equal(t, "Gudang Utama", got.Warehouse)
+	require.Len(t, got.Lines, 2)
+	assert.Equal(t, "Kopi Arabika", got.Lines[0].Name)
+	// item yang namanya tidak ditemukan tetap dicetak dengan id-nya
+	assert.Equal(t, "Item #9", got.Lines[1].Name)
+	assert.Equal(t, model.MustParseMoney("25000"), got.BalanceDue)
+	assert.Equal(t, model.MustParseMoney("30000"), got.Change)
+	assert.Len(t, got.Payments, 2)
+}
+
+func TestReceiptsService_GetReceipt_NotFound(t *testing.T) {
+	sales, payments, repo := new(MockSalesRepository), new(MockPaymentsRepository), new(MockReceiptsRepository)
+	s := NewReceiptsService(sales, payments, repo, testReceiptConfig)
+	sales.On("GetSalesById", 99).Return(nil, nil, apperror.NotFound("sale"))
+
+	_, err := s.GetReceipt(context.Background(), 99)
+
+	assert.True(t, apperror.Is(err, apperror.KindNotFound))
+	repo.AssertNotCalled(t, "GetReceiptInfo", mock.Anything)
+}
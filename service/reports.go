@@ -1,13 +1,18 @@
 package service
 
 import (
+	"context"
 	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"time"
 )
 
 type ReportsService interface {
-	GetItemsReport() (*repository.ItemsReport, error)
-	GetSalesReport() (*repository.SalesReport, error)
-	GetRevenueReport() (*repository.RevenueReport, error)
+	GetItemsReport(ctx context.Context) (*repository.ItemsReport, error)
+	GetSalesReport(ctx context.Context) (*repository.SalesReport, error)
+	GetRevenueReport(ctx context.Context) (*repository.RevenueReport, error)
+	GetCustomerReport(ctx context.Context, customerId int) (*repository.CustomerPurchaseReport, error)
+	GetCashDrawerReport(ctx context.Context, date time.Time, userId *int) (*repository.CashDrawerReport, error)
 }
 
 type reportsService struct {
@@ -18,14 +23,37 @@ func NewReportsService(repo repository.ReportsRepository) ReportsService {
 	return &reportsService{Repo: repo}
 }
 
-func (s *reportsService) GetItemsReport() (*repository.ItemsReport, error) {
-	return s.Repo.GetItemsReport()
+func (s *reportsService) GetItemsReport(ctx context.Context) (*repository.ItemsReport, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReportsService.GetItemsReport")
+	defer span.End()
+
+	return s.Repo.GetItemsReport(ctx)
 }
 
-func (s *reportsService) GetSalesReport() (*repository.SalesReport, error) {
-	return s.Repo.GetSalesReport()
+func (s *reportsService) GetSalesReport(ctx context.Context) (*repository.SalesReport, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReportsService.GetSalesReport")
+	defer span.End()
+
+	return s.Repo.GetSalesReport(ctx)
+}
+
+func (s *reportsService) GetRevenueReport(ctx context.Context) (*repository.RevenueReport, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReportsService.GetRevenueReport")
+	defer span.End()
+
+	return s.Repo.GetRevenueReport(ctx)
 }
 
-func (s *reportsService) GetRevenueReport() (*repository.RevenueReport, error) {
-	return s.Repo.GetRevenueReport()
+func (s *reportsService) GetCustomerReport(ctx context.Context, customerId int) (*repository.CustomerPurchaseReport, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReportsService.GetCustomerReport")
+	defer span.End()
+
+	return s.Repo.GetCustomerReport(ctx, customerId)
+}
+
+func (s *reportsService) GetCashDrawerReport(ctx context.Context, date time.Time, userId *int) (*repository.CashDrawerReport, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReportsService.GetCashDrawerReport")
+	defer span.End()
+
+	return s.Repo.GetCashDrawerReport(ctx, date, userId)
 }
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ReservationsService interface {
+	GetReservationById(ctx context.Context, id int) (*model.StockReservation, error)
+	GetAllReservations(ctx context.Context, filter repository.ReservationFilter, page, limit int) ([]model.StockReservation, int, error)
+	CreateReservation(ctx context.Context, userId int, data *dto.ReservationRequest) (*model.StockReservation, error)
+	ConfirmReservation(ctx context.Context, id int) (*model.StockReservation, error)
+	ReleaseReservation(ctx context.Context, id int) (*model.StockReservation, error)
+	ExpireReservations(ctx context.Context) (int64, error)
+}
+
+type reservationsService struct {
+	Repo   repository.ReservationsRepository
+	Audit  Auditor
+	Config utils.ReservationConfig
+}
+
+func NewReservationsService(repo repository.ReservationsRepository, audit Auditor, config utils.ReservationConfig) ReservationsService {
+	return &reservationsService{Repo: repo, Audit: audit, Config: config}
+}
+
+func (s *reservationsService) GetReservationById(ctx context.Context, id int) (*model.StockReservation, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReservationsService.GetReservationById")
+	defer span.End()
+
+	return s.Repo.GetReservationById(ctx, id)
+}
+
+func (s *reservationsService) GetAllReservations(ctx context.Context, filter repository.ReservationFilter, page, limit int) ([]model.StockReservation, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReservationsService.GetAllReservations")
+	defer span.End()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	return s.Repo.GetAllReservations(ctx, filter, page, limit)
+}
+
+// CreateReservation memesan stock atas nama userId sampai expires_in_minutes
+// (default RESERVATION_TTL). Ditolak insufficient_stock jika stock tersedia
+// (stock - reserved) salah satu item tidak cukup.
+func (s *reservationsService) CreateReservation(ctx context.Context, userId int, data *dto.ReservationRequest) (*model.StockReservation, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReservationsService.CreateReservation")
+	defer span.End()
+
+	reference := strings.TrimSpace(data.Reference)
+	if reference == "" {
+		return nil, apperror.Validation("reference is required", nil)
+	}
+	if len(data.Items) == 0 {
+		return nil, apperror.Validation("at least one item is required", nil)
+	}
+	for _, item := range data.Items {
+		if item.Quantity <= 0 {
+			return nil, apperror.Validation("quantity must be greater than 0", nil)
+		}
+	}
+
+	ttl := s.Config.TTL
+	if data.ExpiresInMinutes > 0 {
+		ttl = time.Duration(data.ExpiresInMinutes) * time.Minute
+	}
+
+	reservation := &model.StockReservation{
+		Reference: reference,
+		CreatedBy: userId,
+		Items:     mergeReservationItems(data.Items),
+	}
+
+	var created *model.StockReservation
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Repo.CreateReservation(ctx, reservation, ttl); err != nil {
+			return err
+		}
+		var err error
+		created, err = s.Repo.GetReservationById(ctx, reservation.Id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditCreate, "reservation", created.Id, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ConfirmReservation mengurangi stock sebesar quantity reservasi (order sudah dibayar / dikirim)
+func (s *reservationsService) ConfirmReservation(ctx context.Context, id int) (*model.StockReservation, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReservationsService.ConfirmReservation")
+	defer span.End()
+
+	return s.transition(ctx, id, model.AuditConfirm, s.Repo.ConfirmReservation)
+}
+
+// ReleaseReservation melepas stock yang dipesan tanpa mengurangi stock (order dibatalkan)
+func (s *reservationsService) ReleaseReservation(ctx context.Context, id int) (*model.StockReservation, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReservationsService.ReleaseReservation")
+	defer span.End()
+
+	return s.transition(ctx, id, model.AuditRelease, s.Repo.ReleaseReservation)
+}
+
+// ExpireReservations dipanggil sweeper: reservasi active yang kedaluwarsa dilepas
+func (s *reservationsService) ExpireReservations(ctx context.Context) (int64, error) {
+	ctx, span := utils.Tracer().Start(ctx, "ReservationsService.ExpireReservations")
+	defer span.End()
+
+	return s.Repo.ExpireReservations(ctx)
+}
+
+func (s *reservationsService) transition(ctx context.Context, id int, action string, fn func(ctx context.Context, id int) error) (*model.StockReservation, error) {
+	var after *model.StockReservation
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetReservationById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx, id); err != nil {
+			return err
+		}
+
+		after, err = s.Repo.GetReservationById(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, action, "reservation", id, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// mergeReservationItems menjumlahkan quantity item yang muncul di beberapa baris
+// dan mengurutkannya per item_id, supaya reservasi bersamaan mengunci baris
+// items dengan urutan yang sama
+func mergeReservationItems(items []dto.ReservationItemRequest) []model.StockReservationItem {
+	quantities := make(map[int]int, len(items))
+	for _, item := range items {
+		quantities[item.ItemId] += item.Quantity
+	}
+
+	merged := make([]model.StockReservationItem, 0, len(quantities))
+	for itemId, quantity := range quantities {
+		merged = append(merged, model.StockReservationItem{ItemId: itemId, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ItemId < merged[j].ItemId })
+	return merged
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database/dbtest"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestReservationsService_Postgres_ParallelWithSales_NeverOversell: reservasi
+// online dan penjualan POS paralel lewat pool ke Postgres sungguhan, sementara
+// sweeper hold dan reservasi berjalan di pool yang sama, tidak pernah oversell.
+// Reference dibuat unik per item karena reference active unik di database.
+func TestReservationsService_Postgres_ParallelWithSales_NeverOversell(t *testing.T) {
+	pool := dbtest.Open(t)
+	const stock, workers = 20, 30
+	f := dbtest.Seed(t, pool, stock)
+
+	repo := repository.NewRepository(pool, zap.NewNop())
+	audit := NewAuditor(repo.Transactor, repo.AuditRepo)
+	reservations := NewReservationsService(repo.ReservationsRepo, audit, testReservationConfig)
+	sales := NewSalesService(repo.SalesRepo, repo.CustomersRepo, audit, testInvoiceConfig, testHoldConfig)
+
+	// sweeper seperti di main.go, dijalankan terus selama test
+	sweepCtx, stopSweep := context.WithCancel(context.Background())
+	var sweepers sync.WaitGroup
+	for _, sweep := range []func(context.Context) (int64, error){reservations.ExpireReservations, sales.ExpireHolds} {
+		sweepers.Add(1)
+		go func(sweep func(context.Context) (int64, error)) {
+			defer sweepers.Done()
+			for sweepCtx.Err() == nil {
+				_, err := sweep(sweepCtx)
+				if err != nil && sweepCtx.Err() == nil {
+					t.Errorf("sweeper: %v", err)
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}(sweep)
+	}
+
+	var wg sync.WaitGroup
+	reserveErrs := make([]error, workers)
+	saleErrs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_, reserveErrs[i] = reservations.CreateReservation(context.Background(), f.UserId, &dto.ReservationRequest{
+				Reference: fmt.Sprintf("WEB-%d-%d", f.ItemId, i),
+				Items:     []dto.ReservationItemRequest{{ItemId: f.ItemId, Quantity: 1}},
+			})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			_, saleErrs[i] = sales.CreateSales(context.Background(), &dto.SalesRequest{
+				UserId: f.UserId,
+				Items:  []dto.SaleItemRequest{{ItemId: f.ItemId, Quantity: 1, Price: model.MustParseMoney("10")}},
+			})
+		}(i)
+	}
+	wg.Wait()
+	stopSweep()
+	sweepers.Wait()
+
+	reservedOK, soldOK := 0, 0
+	for i := 0; i < workers; i++ {
+		if reserveErrs[i] == nil {
+			reservedOK++
+		} else {
+			assert.Equal(t, "insufficient_stock", apperror.Code(reserveErrs[i]))
+		}
+		if saleErrs[i] == nil {
+			soldOK++
+		} else {
+			assert.Equal(t, "insufficient_stock", apperror.Code(saleErrs[i]))
+		}
+	}
+	assert.Equal(t, stock, reservedOK+soldOK)
+
+	dbStock, dbReserved := dbtest.Stock(t, pool, f.ItemId)
+	assert.Equal(t, stock-soldOK, dbStock)
+	assert.Equal(t, reservedOK, dbReserved)
+	assert.LessOrEqual(t, dbReserved, dbStock)
+}
+
+// TestReservationsService_Postgres_ParallelCreate_NoDoubleAllocation: reservasi
+// paralel untuk item yang sama di Postgres tidak pernah memesan melebihi stock
+func TestReservationsService_Postgres_ParallelCreate_NoDoubleAllocation(t *testing.T) {
+	pool := dbtest.Open(t)
+	const stock, workers = 10, 50
+	f := dbtest.Seed(t, pool, stock)
+
+	repo := repository.NewRepository(pool, zap.NewNop())
+	reservations := NewReservationsService(repo.ReservationsRepo, NewAuditor(repo.Transactor, repo.AuditRepo), testReservationConfig)
+
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = reservations.CreateReservation(context.Background(), f.UserId, &dto.ReservationRequest{
+				Reference: fmt.Sprintf("WEB-%d-%d", f.ItemId, i),
+				Items:     []dto.ReservationItemRequest{{ItemId: f.ItemId, Quantity: 1}},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.Equal(t, "insufficient_stock", apperror.Code(err))
+	}
+	require.Equal(t, stock, succeeded)
+
+	dbStock, dbReserved := dbtest.Stock(t, pool, f.ItemId)
+	assert.Equal(t, stock, dbStock)
+	assert.Equal(t, stock, dbReserved)
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testReservationConfig = utils.ReservationConfig{TTL: 15 * time.Minute}
+
+// fakeStockDB meniru UPDATE bersyarat di Postgres untuk items.stock dan
+// items.reserved: syarat (stock - reserved >= qty, reservasi masih active)
+// dicek dan diubah atomic selagi baris terkunci. Dipakai sebagai
+// ReservationsRepository, SalesRepository (CreateSales) dan Auditor sekaligus.
+type fakeStockDB struct {
+	*MockSalesRepository
+
+	mu           sync.Mutex
+	stock        map[int]int
+	reserved     map[int]int
+	reservations map[int]*model.StockReservation
+	invoiceNo    int
+	lastTTL      time.Duration
+}
+
+func newFakeStockDB(stock map[int]int) *fakeStockDB {
+	return &fakeStockDB{
+		MockSalesRepository: new(MockSalesRepository),
+		stock:               stock,
+		reserved:            map[int]int{},
+		reservations:        map[int]*model.StockReservation{},
+	}
+}
+
+func (db *fakeStockDB) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (db *fakeStockDB) Record(ctx context.Context, action, entity string, entityId int, before, after any) error {
+	return nil
+}
+
+// shortItems harus dipanggil dengan db.mu terkunci; item yang tidak memenuhi
+// syarat dikembalikan seperti updateStock
+func (db *fakeStockDB) shortItems(itemIds, quantities []int) []int {
+	var short []int
+	for i, id := range itemIds {
+		if db.stock[id]-db.reserved[id] < quantities[i] {
+			short = append(short, id)
+		}
+	}
+	return short
+}
+
+func (db *fakeStockDB) GetReservationById(ctx context.Context, id int) (*model.StockReservation, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	res, ok := db.reservations[id]
+	if !ok {
+		return nil, apperror.NotFound("reservation")
+	}
+	copied := *res
+	return &copied, nil
+}
+
+func (db *fakeStockDB) GetAllReservations(ctx context.Context, filter repository.ReservationFilter, page, limit int) ([]model.StockReservation, int, error) {
+	return nil, 0, nil
+}
+
+func (db *fakeStockDB) CreateReservation(ctx context.Context, reservation *model.StockReservation, ttl time.Duration) error {
+	itemIds, quantities := make([]int, 0, len(reservation.Items)), make([]int, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		itemIds = append(itemIds, item.ItemId)
+		quantities = append(quantities, item.Quantity)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.lastTTL = ttl
+	if short := db.shortItems(itemIds, quantities); len(short) > 0 {
+		return apperror.InsufficientStock("insufficient stock for one or more items", map[string]any{"item_ids": short})
+	}
+	for i, id := range itemIds {
+		db.reserved[id] += quantities[i]
+	}
+	reservation.Id = len(db.reservations) + 1
+	reservation.Status = model.ReservationActive
+	stored := *reservation
+	db.reservations[reservation.Id] = &stored
+	return nil
+}
+
+func (db *fakeStockDB) finish(id int, status string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	res, ok := db.reservations[id]
+	if !ok {
+		return apperror.NotFound("reservation")
+	}
+	if res.Status != model.ReservationActive {
+		return apperror.ReservationNotActive(res.Status)
+	}
+	for _, item := range res.Items {
+		db.reserved[item.ItemId] -= item.Quantity
+		if status == model.ReservationConfirmed {
+			db.stock[item.ItemId] -= item.Quantity
+		}
+	}
+	res.Status = status
+	return nil
+}
+
+func (db *fakeStockDB) ConfirmReservation(ctx context.Context, id int) error {
+	return db.finish(id, model.ReservationConfirmed)
+}
+
+func (db *fakeStockDB) ReleaseReservation(ctx context.Context, id int) error {
+	return db.finish(id, model.ReservationReleased)
+}
+
+func (db *fakeStockDB) ExpireReservations(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (db *fakeStockDB) GetItemTaxes(ctx context.Context, itemIds []int) (map[int]repository.ItemTax, error) {
+	return map[int]repository.ItemTax{}, nil
+}
+
+func (db *fakeStockDB) NextInvoiceNo(ctx context.Context, warehouseId int) (int, time.Time, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.invoiceNo++
+	return db.invoiceNo, invoiceTime, nil
+}
+
+// CreateSales mengurangi stock seperti queryDecrementStock: stock yang dipesan tidak ikut dijual
+func (db *fakeStockDB) CreateSales(ctx context.Context, sale *model.Sales, items []model.SaleItems) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	itemIds, quantities := make([]int, len(items)), make([]int, len(items))
+	for i, item := range items {
+		itemIds[i], quantities[i] = item.ItemId, item.Quantity
+	}
+	if short := db.shortItems(itemIds, quantities); len(short) > 0 {
+		return apperror.InsufficientStock("insufficient stock for one or more items", map[string]any{"item_ids": short})
+	}
+	for i, id := range itemIds {
+		db.stock[id] -= quantities[i]
+	}
+	return nil
+}
+
+func reserveOne(service ReservationsService, reference string) error {
+	_, err := service.CreateReservation(context.Background(), 2, &dto.ReservationRequest{
+		Reference: reference,
+		Items:     []dto.ReservationItemRequest{{ItemId: 1, Quantity: 1}},
+	})
+	return err
+}
+
+func TestReservationsService_CreateReservation_MergesItemsAndAudits(t *testing.T) {
+	db := newFakeStockDB(map[int]int{1: 10, 2: 10})
+	audit := &fakeAuditor{}
+	service := NewReservationsService(db, audit, testReservationConfig)
+
+	reservation, err := service.CreateReservation(context.Background(), 2, &dto.ReservationRequest{
+		Reference: " WEB-1001 ",
+		Items: []dto.ReservationItemRequest{
+			{ItemId: 2, Quantity: 1}, {ItemId: 1, Quantity: 2}, {ItemId: 2, Quantity: 3},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "WEB-1001", reservation.Reference)
+	assert.Equal(t, 2, reservation.CreatedBy)
+	assert.Equal(t, []model.StockReservationItem{{ItemId: 1, Quantity: 2}, {ItemId: 2, Quantity: 4}}, reservation.Items)
+	assert.Equal(t, testReservationConfig.TTL, db.lastTTL)
+	assert.Equal(t, map[int]int{1: 2, 2: 4}, db.reserved)
+	require.Len(t, audit.entries, 1)
+	assert.Equal(t, auditEntry{model.AuditCreate, "reservation", reservation.Id, nil, reservation}, audit.entries[0])
+}
+
+func TestReservationsService_CreateReservation_CustomExpiry(t *testing.T) {
+	db := newFakeStockDB(map[int]int{1: 10})
+	service := NewReservationsService(db, db, testReservationConfig)
+
+	_, err := service.CreateReservation(context.Background(), 2, &dto.ReservationRequest{
+		Reference:        "WEB-1002",
+		Items:            []dto.ReservationItemRequest{{ItemId: 1, Quantity: 1}},
+		ExpiresInMinutes: 120,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, db.lastTTL)
+}
+
+func TestReservationsService_CreateReservation_Validation(t *testing.T) {
+	service := NewReservationsService(newFakeStockDB(nil), &fakeAuditor{}, testReservationConfig)
+
+	_, err := service.CreateReservation(context.Background(), 2, &dto.ReservationRequest{
+		Reference: "  ",
+		Items:     []dto.ReservationItemRequest{{ItemId: 1, Quantity: 1}},
+	})
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+
+	_, err = service.CreateReservation(context.Background(), 2, &dto.ReservationRequest{
+		Reference: "WEB-1003",
+		Items:     []dto.ReservationItemRequest{{ItemId: 1, Quantity: 0}},
+	})
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+}
+
+func TestReservationsService_ConfirmAndRelease(t *testing.T) {
+	db := newFakeStockDB(map[int]int{1: 10})
+	audit := &fakeAuditor{}
+	service := NewReservationsService(db, audit, testReservationConfig)
+	require.NoError(t, reserveOne(service, "WEB-1"))
+	require.NoError(t, reserveOne(service, "WEB-2"))
+
+	confirmed, err := service.ConfirmReservation(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.ReservationConfirmed, confirmed.Status)
+
+	released, err := service.ReleaseReservation(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, model.ReservationReleased, released.Status)
+
+	assert.Equal(t, 9, db.stock[1])
+	assert.Equal(t, 0, db.reserved[1])
+	require.Len(t, audit.entries, 4)
+	assert.Equal(t, model.AuditConfirm, audit.entries[2].Action)
+	assert.Equal(t, model.ReservationActive, audit.entries[2].Before.(*model.StockReservation).Status)
+	assert.Equal(t, model.AuditRelease, audit.entries[3].Action)
+
+	_, err = service.ReleaseReservation(context.Background(), 1)
+	assert.Equal(t, "reservation_not_active", apperror.Code(err))
+}
+
+// TestReservationsService_ParallelCreate_NoDoubleAllocation: reservasi paralel
+// untuk item yang sama tidak pernah memesan melebihi stock
+func TestReservationsService_ParallelCreate_NoDoubleAllocation(t *testing.T) {
+	const stock, workers = 10, 50
+	db := newFakeStockDB(map[int]int{1: stock})
+	service := NewReservationsService(db, db, testReservationConfig)
+
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = reserveOne(service, "WEB")
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.Equal(t, "insufficient_stock", apperror.Code(err))
+	}
+	assert.Equal(t, stock, succeeded)
+	assert.Equal(t, stock, db.reserved[1])
+	assert.Equal(t, stock, db.stock[1])
+}
+
+// TestReservationsService_ParallelWithSales_NeverOversell: reservasi online dan
+// penjualan POS bersamaan berbagi stock yang sama tanpa oversell
+func TestReservationsService_ParallelWithSales_NeverOversell(t *testing.T) {
+	const stock, workers = 20, 30
+	db := newFakeStockDB(map[int]int{1: stock})
+	reservations := NewReservationsService(db, db, testReservationConfig)
+	sales := NewSalesService(db, new(MockCustomersRepository), db, testInvoiceConfig, testHoldConfig)
+
+	var wg sync.WaitGroup
+	reserveErrs := make([]error, workers)
+	saleErrs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			reserveErrs[i] = reserveOne(reservations, "WEB")
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			_, saleErrs[i] = sales.CreateSales(context.Background(), &dto.SalesRequest{
+				UserId: 1,
+				Items:  []dto.SaleItemRequest{{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("10")}},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	reservedOK, soldOK := 0, 0
+	for i := 0; i < workers; i++ {
+		if reserveErrs[i] == nil {
+			reservedOK++
+		} else {
+			assert.Equal(t, "insufficient_stock", apperror.Code(reserveErrs[i]))
+		}
+		if saleErrs[i] == nil {
+			soldOK++
+		} else {
+			assert.Equal(t, "insufficient_stock", apperror.Code(saleErrs[i]))
+		}
+	}
+	assert.Equal(t, stock, reservedOK+soldOK)
+	assert.Equal(t, stock-soldOK, db.stock[1])
+	assert.Equal(t, reservedOK, db.reserved[1])
+	assert.LessOrEqual(t, db.reserved[1], db.stock[1])
+}
+
+// TestReservationsService_ConcurrentConfirmRelease_SingleWinner: confirm dan
+// release bersamaan pada reservasi yang sama hanya berhasil sekali
+func TestReservationsService_ConcurrentConfirmRelease_SingleWinner(t *testing.T) {
+	db := newFakeStockDB(map[int]int{1: 10})
+	service := NewReservationsService(db, db, testReservationConfig)
+	_, err := service.CreateReservation(context.Background(), 2, &dto.ReservationRequest{
+		Reference: "WEB-1",
+		Items:     []dto.ReservationItemRequest{{ItemId: 1, Quantity: 3}},
+	})
+	require.NoError(t, err)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				_, errs[i] = service.ConfirmReservation(context.Background(), 1)
+			} else {
+				_, errs[i] = service.ReleaseReservation(context.Background(), 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.Equal(t, "reservation_not_active", apperror.Code(err))
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 0, db.reserved[1])
+	if db.reservations[1].Status == model.ReservationConfirmed {
+		assert.Equal(t, 7, db.stock[1])
+	} else {
+		assert.Equal(t, 10, db.stock[1])
+	}
+}
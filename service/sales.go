@@ -1,58 +1,61 @@
 package service
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/dto"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
 )
 
 type SalesService interface {
-	GetSalesById(id int) (*dto.SalesResponse, error)
-	GetAllSales(page, limit int) ([]dto.SalesResponse, int, error)
-	CreateSales(data *dto.SalesRequest) error
-	UpdateSales(id int, data *dto.SalesRequest) error
-	DeleteSales(id int) error
+	GetSalesById(ctx context.Context, id int) (*dto.SalesResponse, error)
+	GetAllSales(ctx context.Context, filter repository.SalesFilter, page, limit int, includeItems bool) ([]dto.SalesResponse, int, error)
+	CreateSales(ctx context.Context, data *dto.SalesRequest) (*dto.SalesResponse, error)
+	UpdateSales(ctx context.Context, id int, data *dto.SalesRequest) error
+	DeleteSales(ctx context.Context, id int) error
+	VoidSale(ctx context.Context, id, userId int, reason string) (*dto.SalesResponse, error)
+	HoldSale(ctx context.Context, id int) (*dto.SalesResponse, error)
+	ReleaseHold(ctx context.Context, id int) (*dto.SalesResponse, error)
+	CompleteSale(ctx context.Context, id int) (*dto.SalesResponse, error)
+	ExpireHolds(ctx context.Context) (int64, error)
 }
 
 type salesService struct {
-	Repo repository.SalesRepository
+	Repo      repository.SalesRepository
+	Customers repository.CustomersRepository
+	Audit     Auditor
+	Invoice   utils.InvoiceConfig
+	Hold      utils.SaleHoldConfig
 }
 
-func NewSalesService(repo repository.SalesRepository) SalesService {
-	return &salesService{Repo: repo}
+func NewSalesService(repo repository.SalesRepository, customers repository.CustomersRepository, audit Auditor, invoice utils.InvoiceConfig, hold utils.SaleHoldConfig) SalesService {
+	return &salesService{Repo: repo, Customers: customers, Audit: audit, Invoice: invoice, Hold: hold}
 }
 
-func (s *salesService) GetSalesById(id int) (*dto.SalesResponse, error) {
-	sale, items, err := s.Repo.GetSalesById(id)
+func (s *salesService) GetSalesById(ctx context.Context, id int) (*dto.SalesResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.GetSalesById")
+	defer span.End()
+
+	sale, items, err := s.Repo.GetSalesById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to DTO
-	var itemsResponse []dto.SaleItemResponse
-	for _, item := range items {
-		itemsResponse = append(itemsResponse, dto.SaleItemResponse{
-			Id:       item.Id,
-			ItemId:   item.ItemId,
-			Quantity: item.Quantity,
-			Price:    item.Price,
-			Subtotal: item.Subtotal,
-		})
-	}
-
-	response := &dto.SalesResponse{
-		Id:          sale.Id,
-		UserId:      sale.UserId,
-		TotalAmount: sale.TotalAmount,
-		Items:       itemsResponse,
-		CreatedAt:   sale.CreatedAt,
-	}
-
-	return response, nil
+	return toSalesResponse(sale, items), nil
 }
 
-func (s *salesService) GetAllSales(page, limit int) ([]dto.SalesResponse, int, error) {
+// GetAllSales: item tiap sale hanya diambil jika includeItems true (?include=items)
+func (s *salesService) GetAllSales(ctx context.Context, filter repository.SalesFilter, page, limit int, includeItems bool) ([]dto.SalesResponse, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.GetAllSales")
+	defer span.End()
+
 	// Validate pagination parameters
 	if page < 1 {
 		page = 1
@@ -64,99 +67,508 @@ func (s *salesService) GetAllSales(page, limit int) ([]dto.SalesResponse, int, e
 		limit = 100
 	}
 
-	sales, total, err := s.Repo.GetAllSales(page, limit)
+	sales, total, err := s.Repo.GetAllSales(ctx, filter, page, limit, includeItems)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// Convert to DTO with items detail
 	var salesResponse []dto.SalesResponse
-	for _, sale := range sales {
-		// Convert items to DTO
-		var itemsResponse []dto.SaleItemResponse
-		for _, item := range sale.Items {
-			itemsResponse = append(itemsResponse, dto.SaleItemResponse{
-				Id:       item.Id,
-				ItemId:   item.ItemId,
-				Quantity: item.Quantity,
-				Price:    item.Price,
-				Subtotal: item.Subtotal,
-			})
-		}
-
-		salesResponse = append(salesResponse, dto.SalesResponse{
-			Id:          sale.Id,
-			UserId:      sale.UserId,
-			TotalAmount: sale.TotalAmount,
-			Items:       itemsResponse,
-			CreatedAt:   sale.CreatedAt,
-		})
+	for i := range sales {
+		salesResponse = append(salesResponse, *toSalesResponse(&sales[i], sales[i].Items))
 	}
 
 	return salesResponse, total, nil
 }
 
-func (s *salesService) CreateSales(data *dto.SalesRequest) error {
+// CreateSales mengembalikan sale yang dibuat beserta id dan nomor invoice-nya,
+// supaya client bisa langsung mencetak struk atau mencatat pembayaran
+func (s *salesService) CreateSales(ctx context.Context, data *dto.SalesRequest) (*dto.SalesResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.CreateSales")
+	defer span.End()
+
 	// Validate request
 	if data.UserId <= 0 {
-		return errors.New("user_id is required")
+		return nil, apperror.Validation("user_id is required", nil)
 	}
-	if len(data.Items) == 0 {
-		return errors.New("at least one item is required")
+	paymentType, err := salePaymentType(data)
+	if err != nil {
+		return nil, err
+	}
+	status := data.Status
+	if status == "" {
+		status = model.SaleCompleted
+	}
+	switch status {
+	case model.SaleDraft, model.SaleQuote, model.SaleHeld, model.SaleCompleted:
+	default:
+		return nil, apperror.Validation("status must be draft, quote, held or completed", nil)
+	}
+	if s.Invoice.PerWarehouse && data.WarehouseId == nil {
+		return nil, apperror.Validation("warehouse_id is required for per-warehouse invoice numbers", nil)
+	}
+	if err := validateSaleItems(data.Items); err != nil {
+		return nil, err
 	}
 
-	// Calculate total amount
-	var totalAmount float64
-	var saleItems []model.SaleItems
-	for _, item := range data.Items {
-		if item.Quantity <= 0 {
-			return errors.New("quantity must be greater than 0")
+	sale := &model.Sales{
+		UserId:        data.UserId,
+		CustomerId:    data.CustomerId,
+		WarehouseId:   data.WarehouseId,
+		PaymentType:   paymentType,
+		PaymentStatus: model.PaymentUnpaid,
+		Status:        status,
+	}
+	saleItems, err := s.priceItems(ctx, sale, data)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.LoggerFromContext(ctx, nil).Debug("creating sale",
+		zap.Int("user_id", sale.UserId),
+		zap.Int("items_count", len(saleItems)),
+		zap.Stringer("total_amount", sale.TotalAmount),
+		zap.String("status", sale.Status),
+	)
+
+	err = s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkCustomer(ctx, sale); err != nil {
+			return err
 		}
-		if item.Price <= 0 {
-			return errors.New("price must be greater than 0")
+		if sale.Status == model.SaleCompleted {
+			// nomor invoice diambil terakhir supaya lock counter dipegang sesingkat mungkin
+			if err := s.assignInvoiceNo(ctx, sale); err != nil {
+				return err
+			}
+		}
+		// held dibuat sebagai draft lalu dipesan lewat jalur yang sama dengan HoldSale
+		if status == model.SaleHeld {
+			sale.Status = model.SaleDraft
 		}
+		if err := s.Repo.CreateSales(ctx, sale, saleItems); err != nil {
+			return err
+		}
+		if status == model.SaleHeld {
+			if err := s.Repo.HoldSale(ctx, sale.Id, s.Hold.TTL); err != nil {
+				return err
+			}
+			// hold_expires_at diisi database
+			held, err := s.saleWithItems(ctx, sale.Id)
+			if err != nil {
+				return err
+			}
+			sale.Status, sale.HoldExpiresAt = held.Status, held.HoldExpiresAt
+		}
+		sale.Items = saleItems
+		return s.Audit.Record(ctx, model.AuditCreate, "sale", sale.Id, nil, sale)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		subtotal := float64(item.Quantity) * item.Price
-		totalAmount += subtotal
+	return toSalesResponse(sale, saleItems), nil
+}
 
-		saleItems = append(saleItems, model.SaleItems{
-			ItemId:   item.ItemId,
-			Quantity: item.Quantity,
-			Price:    item.Price,
-		})
+func (s *salesService) UpdateSales(ctx context.Context, id int, data *dto.SalesRequest) error {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.UpdateSales")
+	defer span.End()
+
+	// Validate request
+	if data.UserId <= 0 {
+		return apperror.Validation("user_id is required", nil)
+	}
+	if err := validateSaleItems(data.Items); err != nil {
+		return err
+	}
+
+	paymentType, err := salePaymentType(data)
+	if err != nil {
+		return err
+	}
+	// gudang menentukan seri invoice yang diambil saat sale di-complete
+	if s.Invoice.PerWarehouse && data.WarehouseId == nil {
+		return apperror.Validation("warehouse_id is required for per-warehouse invoice numbers", nil)
 	}
 
-	// Create sale model
+	// Update sale model; total dan baris item dihitung ulang dengan aturan diskon dan pajak yang sama
 	sale := &model.Sales{
 		UserId:      data.UserId,
-		TotalAmount: totalAmount,
+		CustomerId:  data.CustomerId,
+		WarehouseId: data.WarehouseId,
+		PaymentType: paymentType,
 	}
+	saleItems, err := s.priceItems(ctx, sale, data)
+	if err != nil {
+		return err
+	}
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.saleWithItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		// held/completed sudah memakai stock (dan completed sudah punya invoice);
+		// status dicek ulang di repository dengan baris sale terkunci
+		switch before.Status {
+		case model.SaleDraft, model.SaleQuote:
+		case model.SaleVoided:
+			return apperror.SaleVoided()
+		default:
+			return apperror.InvalidSaleStatus(before.Status, model.SaleDraft+" or "+model.SaleQuote)
+		}
+		// customer dicek ulang; credit limit terhadap total baru dicek saat sale di-complete
+		sale.Status = before.Status
+		if err := s.checkCustomer(ctx, sale); err != nil {
+			return err
+		}
+		if err := s.Repo.UpdateSales(ctx, id, sale, saleItems); err != nil {
+			return err
+		}
 
-	return s.Repo.CreateSales(sale, saleItems)
+		after, err := s.saleWithItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditUpdate, "sale", id, before, after)
+	})
 }
 
-func (s *salesService) UpdateSales(id int, data *dto.SalesRequest) error {
-	// Validate request
-	if data.UserId <= 0 {
-		return errors.New("user_id is required")
+// DeleteSales menghapus permanen sale draft. Sale yang sudah mengurangi stock
+// tidak boleh dihapus, batalkan dengan VoidSale supaya riwayatnya tetap ada.
+func (s *salesService) DeleteSales(ctx context.Context, id int) error {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.DeleteSales")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.saleWithItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.Status != model.SaleDraft {
+			return apperror.InvalidSaleStatus(before.Status, model.SaleDraft)
+		}
+		if err := s.Repo.DeleteSales(ctx, id); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditDelete, "sale", id, before, nil)
+	})
+}
+
+// VoidSale membatalkan sale completed: status menjadi voided, stock setiap baris
+// dikembalikan dan sale tidak lagi dihitung di laporan. Data sale, item dan
+// pembayarannya tetap tersimpan dan tidak bisa diubah lagi.
+func (s *salesService) VoidSale(ctx context.Context, id, userId int, reason string) (*dto.SalesResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.VoidSale")
+	defer span.End()
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperror.Validation("reason is required", nil)
 	}
 
-	// Calculate total amount
-	var totalAmount float64
-	for _, item := range data.Items {
-		subtotal := float64(item.Quantity) * item.Price
-		totalAmount += subtotal
+	var after *model.Sales
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.saleWithItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.Status == model.SaleVoided {
+			return apperror.SaleVoided()
+		}
+		if err := s.Repo.VoidSale(ctx, id, userId, reason); err != nil {
+			return err
+		}
+
+		after, err = s.saleWithItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditVoid, "sale", id, before, after)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Update sale model
-	sale := &model.Sales{
-		UserId:      data.UserId,
-		TotalAmount: totalAmount,
+	return toSalesResponse(after, after.Items), nil
+}
+
+// HoldSale memesan stock untuk sale draft/quote selama SALE_HOLD_TTL. Sale yang
+// sudah held diperpanjang masa hold-nya.
+func (s *salesService) HoldSale(ctx context.Context, id int) (*dto.SalesResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.HoldSale")
+	defer span.End()
+
+	return s.transition(ctx, id, model.AuditHold, func(ctx context.Context, before *model.Sales) error {
+		return s.Repo.HoldSale(ctx, id, s.Hold.TTL)
+	})
+}
+
+// ReleaseHold melepas stock yang dipesan sale held; sale kembali menjadi draft
+func (s *salesService) ReleaseHold(ctx context.Context, id int) (*dto.SalesResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.ReleaseHold")
+	defer span.End()
+
+	return s.transition(ctx, id, model.AuditRelease, func(ctx context.Context, before *model.Sales) error {
+		return s.Repo.ReleaseHold(ctx, id)
+	})
+}
+
+// CompleteSale menyelesaikan sale draft, quote atau held: credit limit dicek,
+// nomor invoice diberikan dan stock dikurangi seperti CreateSales
+func (s *salesService) CompleteSale(ctx context.Context, id int) (*dto.SalesResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.CompleteSale")
+	defer span.End()
+
+	return s.transition(ctx, id, model.AuditComplete, func(ctx context.Context, before *model.Sales) error {
+		switch before.Status {
+		case model.SaleVoided:
+			return apperror.SaleVoided()
+		case model.SaleCompleted:
+			return apperror.InvalidSaleStatus(before.Status, model.SaleHeld)
+		}
+
+		sale := *before
+		sale.Status = model.SaleCompleted
+		if err := s.checkCustomer(ctx, &sale); err != nil {
+			return err
+		}
+		if err := s.assignInvoiceNo(ctx, &sale); err != nil {
+			return err
+		}
+		return s.Repo.CompleteSale(ctx, id, sale.InvoiceNo)
+	})
+}
+
+// ExpireHolds dipanggil sweeper: sale held yang kedaluwarsa kembali menjadi draft
+func (s *salesService) ExpireHolds(ctx context.Context) (int64, error) {
+	ctx, span := utils.Tracer().Start(ctx, "SalesService.ExpireHolds")
+	defer span.End()
+
+	return s.Repo.ExpireHolds(ctx)
+}
+
+// transition menjalankan perubahan status sale di satu transaksi dan mencatat
+// kondisi sebelum/sesudahnya di audit log
+func (s *salesService) transition(ctx context.Context, id int, action string, fn func(ctx context.Context, before *model.Sales) error) (*dto.SalesResponse, error) {
+	var after *model.Sales
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.saleWithItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx, before); err != nil {
+			return err
+		}
+
+		after, err = s.saleWithItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, action, "sale", id, before, after)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.Repo.UpdateSales(id, sale)
+	return toSalesResponse(after, after.Items), nil
 }
 
-func (s *salesService) DeleteSales(id int) error {
-	return s.Repo.DeleteSales(id)
+// validateSaleItems dipakai create dan update sale: minimal satu item,
+// quantity dan price harus positif
+func validateSaleItems(items []dto.SaleItemRequest) error {
+	if len(items) == 0 {
+		return apperror.Validation("at least one item is required", nil)
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return apperror.Validation("quantity must be greater than 0", nil)
+		}
+		if item.Price <= 0 {
+			return apperror.Validation("price must be greater than 0", nil)
+		}
+	}
+	return nil
+}
+
+// priceItems menghitung diskon dan pajak tiap item request (tarif dari item
+// atau category-nya) lalu mengisi subtotal, diskon, pajak dan total di sale
+func (s *salesService) priceItems(ctx context.Context, sale *model.Sales, data *dto.SalesRequest) ([]model.SaleItems, error) {
+	if len(data.Items) == 0 {
+		return nil, nil
+	}
+
+	itemIds := make([]int, len(data.Items))
+	for i, item := range data.Items {
+		itemIds[i] = item.ItemId
+	}
+	taxes, err := s.Repo.GetItemTaxes(ctx, itemIds)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]lineInput, len(data.Items))
+	for i, item := range data.Items {
+		// item yang tidak ditemukan ditolak saat update stok, di sini cukup tanpa pajak
+		tax := taxes[item.ItemId]
+		lines[i] = lineInput{
+			ItemId:       item.ItemId,
+			Quantity:     int64(item.Quantity),
+			Price:        item.Price.Cents(),
+			Discount:     item.Discount,
+			TaxRate:      tax.Rate.BasisPoints(),
+			TaxInclusive: tax.Inclusive,
+		}
+	}
+
+	pricing, err := priceSale(lines, data.Discount)
+	if err != nil {
+		return nil, err
+	}
+
+	saleItems := make([]model.SaleItems, len(data.Items))
+	for i, item := range data.Items {
+		line := pricing.Lines[i]
+		saleItems[i] = model.SaleItems{
+			ItemId:         item.ItemId,
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			Subtotal:       model.Money(line.Subtotal),
+			DiscountAmount: model.Money(line.Discount),
+			TaxRate:        model.TaxRate(lines[i].TaxRate),
+			TaxInclusive:   lines[i].TaxInclusive,
+			TaxAmount:      model.Money(line.Tax),
+			Total:          model.Money(line.Total),
+		}
+	}
+	sale.Subtotal = model.Money(pricing.Subtotal)
+	sale.DiscountAmount = model.Money(pricing.Discount)
+	sale.TaxAmount = model.Money(pricing.Tax)
+	sale.TotalAmount = model.Money(pricing.Total)
+	return saleItems, nil
+}
+
+// checkCustomer memastikan customer ada dan, untuk penjualan kredit, total
+// piutang setelah sale ini tidak melebihi credit limit. Baris customer dikunci
+// sampai transaksi selesai agar penjualan kredit bersamaan tidak lolos berdua.
+func (s *salesService) checkCustomer(ctx context.Context, sale *model.Sales) error {
+	if sale.CustomerId == nil {
+		return nil
+	}
+	customerId := *sale.CustomerId
+
+	// credit limit baru dicek saat sale completed; draft, quote dan held belum menjadi piutang
+	if sale.PaymentType != model.PaymentCredit || sale.Status != model.SaleCompleted {
+		_, err := s.Customers.GetCustomersById(ctx, customerId)
+		if apperror.Code(err) == "customer_not_found" {
+			return apperror.Validation("customer not found", map[string]int{"customer_id": customerId})
+		}
+		return err
+	}
+
+	credit, err := s.Customers.LockCustomerCredit(ctx, customerId)
+	if apperror.Code(err) == "customer_not_found" {
+		return apperror.Validation("customer not found", map[string]int{"customer_id": customerId})
+	}
+	if err != nil {
+		return err
+	}
+
+	if credit.Outstanding+sale.TotalAmount > credit.CreditLimit {
+		return apperror.CreditLimitExceeded(map[string]model.Money{
+			"credit_limit": credit.CreditLimit,
+			"outstanding":  credit.Outstanding,
+			"available":    max(credit.CreditLimit-credit.Outstanding, 0),
+			"amount":       sale.TotalAmount,
+		})
+	}
+	return nil
+}
+
+// assignInvoiceNo mengisi sale.InvoiceNo dari counter tahun berjalan; seri per
+// gudang hanya jika INVOICE_PER_WAREHOUSE aktif
+func (s *salesService) assignInvoiceNo(ctx context.Context, sale *model.Sales) error {
+	warehouseId := 0
+	if s.Invoice.PerWarehouse && sale.WarehouseId != nil {
+		warehouseId = *sale.WarehouseId
+	}
+	seq, at, err := s.Repo.NextInvoiceNo(ctx, warehouseId)
+	if err != nil {
+		return err
+	}
+	sale.InvoiceNo = formatInvoiceNo(s.Invoice.Prefix, warehouseId, at, seq)
+	return nil
+}
+
+// formatInvoiceNo: INV/2026/10/000123, atau INV/W2/2026/10/000123 untuk seri per gudang.
+// Nomor urut per tahun; bulan hanya penanda.
+func formatInvoiceNo(prefix string, warehouseId int, at time.Time, seq int) string {
+	if warehouseId > 0 {
+		prefix = fmt.Sprintf("%s/W%d", prefix, warehouseId)
+	}
+	return fmt.Sprintf("%s/%04d/%02d/%06d", prefix, at.Year(), int(at.Month()), seq)
+}
+
+func toSalesResponse(sale *model.Sales, items []model.SaleItems) *dto.SalesResponse {
+	var itemsResponse []dto.SaleItemResponse
+	for _, item := range items {
+		itemsResponse = append(itemsResponse, dto.SaleItemResponse{
+			Id:             item.Id,
+			ItemId:         item.ItemId,
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			Subtotal:       item.Subtotal,
+			DiscountAmount: item.DiscountAmount,
+			TaxRate:        item.TaxRate,
+			TaxInclusive:   item.TaxInclusive,
+			TaxAmount:      item.TaxAmount,
+			Total:          item.Total,
+		})
+	}
+
+	return &dto.SalesResponse{
+		Id:             sale.Id,
+		InvoiceNo:      sale.InvoiceNo,
+		UserId:         sale.UserId,
+		CustomerId:     sale.CustomerId,
+		WarehouseId:    sale.WarehouseId,
+		PaymentType:    sale.PaymentType,
+		Subtotal:       sale.Subtotal,
+		DiscountAmount: sale.DiscountAmount,
+		TaxAmount:      sale.TaxAmount,
+		TotalAmount:    sale.TotalAmount,
+		PaidAmount:     sale.PaidAmount,
+		PaymentStatus:  sale.PaymentStatus,
+		Status:         sale.Status,
+		VoidedAt:       sale.VoidedAt,
+		VoidedBy:       sale.VoidedBy,
+		VoidReason:     sale.VoidReason,
+		HoldExpiresAt:  sale.HoldExpiresAt,
+		Items:          itemsResponse,
+		CreatedAt:      sale.CreatedAt,
+	}
+}
+
+// salePaymentType memvalidasi payment_type (default cash); sale kredit wajib customer_id
+func salePaymentType(data *dto.SalesRequest) (string, error) {
+	paymentType := data.PaymentType
+	if paymentType == "" {
+		paymentType = model.PaymentCash
+	}
+	if paymentType != model.PaymentCash && paymentType != model.PaymentCredit {
+		return "", apperror.Validation("payment_type must be cash or credit", nil)
+	}
+	if paymentType == model.PaymentCredit && data.CustomerId == nil {
+		return "", apperror.Validation("customer_id is required for credit sales", nil)
+	}
+	return paymentType, nil
+}
+
+// saleWithItems mengambil sale lengkap dengan item-nya untuk dicatat di audit log
+func (s *salesService) saleWithItems(ctx context.Context, id int) (*model.Sales, error) {
+	sale, items, err := s.Repo.GetSalesById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sale.Items = items
+	return sale, nil
 }
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/database/dbtest"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestSalesService_Postgres_ParallelInvoiceNumbers: sale paralel lewat pool
+// ke Postgres sungguhan mendapat nomor invoice unik tanpa celah, termasuk saat
+// sebagian sale gagal (stock habis) dan rollback mengembalikan nomornya
+func TestSalesService_Postgres_ParallelInvoiceNumbers(t *testing.T) {
+	pool := dbtest.Open(t)
+	const stock, workers = 30, 40
+	f := dbtest.Seed(t, pool, stock)
+
+	repo := repository.NewRepository(pool, zap.NewNop())
+	sales := NewSalesService(repo.SalesRepo, repo.CustomersRepo, NewAuditor(repo.Transactor, repo.AuditRepo),
+		utils.InvoiceConfig{Prefix: "INV", PerWarehouse: true}, testHoldConfig)
+
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = sales.CreateSales(context.Background(), &dto.SalesRequest{
+				UserId:      f.UserId,
+				WarehouseId: &f.WarehouseId,
+				Items:       []dto.SaleItemRequest{{ItemId: f.ItemId, Quantity: 1, Price: model.MustParseMoney("10")}},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.Equal(t, "insufficient_stock", apperror.Code(err))
+	}
+	require.Equal(t, stock, succeeded)
+
+	rows, err := pool.Query(context.Background(), `SELECT invoice_no FROM sales WHERE warehouse_id = $1`, f.WarehouseId)
+	require.NoError(t, err)
+	var seqs []int
+	for rows.Next() {
+		var invoiceNo string
+		require.NoError(t, rows.Scan(&invoiceNo))
+		// warehouse baru: seri INV/W<id>/YYYY/MM/000001 dan seterusnya
+		var year, month, seq int
+		_, err := fmt.Sscanf(invoiceNo, fmt.Sprintf("INV/W%d/%%d/%%d/%%d", f.WarehouseId), &year, &month, &seq)
+		require.NoError(t, err, invoiceNo)
+		seqs = append(seqs, seq)
+	}
+	require.NoError(t, rows.Err())
+
+	sort.Ints(seqs)
+	require.Len(t, seqs, stock)
+	for i, seq := range seqs {
+		assert.Equal(t, i+1, seq)
+	}
+}
@@ -1,8 +1,17 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/dto"
 	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,12 +19,18 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+var (
+	testInvoiceConfig = utils.InvoiceConfig{Prefix: "INV"}
+	testHoldConfig    = utils.SaleHoldConfig{TTL: 30 * time.Minute}
+	invoiceTime       = time.Date(2026, 10, 5, 9, 30, 0, 0, time.UTC)
+)
+
 // MockSalesRepository for testing
 type MockSalesRepository struct {
 	mock.Mock
 }
 
-func (m *MockSalesRepository) GetSalesById(id int) (*model.Sales, []model.SaleItems, error) {
+func (m *MockSalesRepository) GetSalesById(ctx context.Context, id int) (*model.Sales, []model.SaleItems, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, nil, args.Error(2)
@@ -23,44 +38,87 @@ func (m *MockSalesRepository) GetSalesById(id int) (*model.Sales, []model.SaleIt
 	return args.Get(0).(*model.Sales), args.Get(1).([]model.SaleItems), args.Error(2)
 }
 
-func (m *MockSalesRepository) GetAllSales(page, limit int) ([]model.Sales, int, error) {
-	args := m.Called(page, limit)
+func (m *MockSalesRepository) GetAllSales(ctx context.Context, filter repository.SalesFilter, page, limit int, includeItems bool) ([]model.Sales, int, error) {
+	args := m.Called(filter, page, limit, includeItems)
+	return args.Get(0).([]model.Sales), args.Int(1), args.Error(2)
+}
+
+func (m *MockSalesRepository) GetSalesByCustomer(ctx context.Context, customerId, page, limit int) ([]model.Sales, int, error) {
+	args := m.Called(customerId, page, limit)
 	return args.Get(0).([]model.Sales), args.Int(1), args.Error(2)
 }
 
-func (m *MockSalesRepository) CreateSales(sale *model.Sales, items []model.SaleItems) error {
+func (m *MockSalesRepository) GetItemTaxes(ctx context.Context, itemIds []int) (map[int]repository.ItemTax, error) {
+	args := m.Called(itemIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]repository.ItemTax), args.Error(1)
+}
+
+func (m *MockSalesRepository) NextInvoiceNo(ctx context.Context, warehouseId int) (int, time.Time, error) {
+	args := m.Called(warehouseId)
+	return args.Int(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockSalesRepository) CreateSales(ctx context.Context, sale *model.Sales, items []model.SaleItems) error {
 	args := m.Called(sale, items)
 	return args.Error(0)
 }
 
-func (m *MockSalesRepository) UpdateSales(id int, data *model.Sales) error {
-	args := m.Called(id, data)
+func (m *MockSalesRepository) UpdateSales(ctx context.Context, id int, data *model.Sales, items []model.SaleItems) error {
+	args := m.Called(id, data, items)
+	return args.Error(0)
+}
+
+func (m *MockSalesRepository) DeleteSales(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockSalesRepository) VoidSale(ctx context.Context, id, userId int, reason string) error {
+	args := m.Called(id, userId, reason)
+	return args.Error(0)
+}
+
+func (m *MockSalesRepository) HoldSale(ctx context.Context, id int, ttl time.Duration) error {
+	args := m.Called(id, ttl)
 	return args.Error(0)
 }
 
-func (m *MockSalesRepository) DeleteSales(id int) error {
+func (m *MockSalesRepository) ReleaseHold(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockSalesRepository) CompleteSale(ctx context.Context, id int, invoiceNo string) error {
+	args := m.Called(id, invoiceNo)
+	return args.Error(0)
+}
+
+func (m *MockSalesRepository) ExpireHolds(ctx context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestSalesService_GetSalesById_Success(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	now := time.Now()
 	sale := &model.Sales{
 		Id:          1,
 		UserId:      1,
-		TotalAmount: 100.50,
+		TotalAmount: model.MustParseMoney("100.50"),
 		CreatedAt:   now,
 	}
 	items := []model.SaleItems{
-		{Id: 1, SaleId: 1, ItemId: 1, Quantity: 2, Price: 50.25, Subtotal: 100.50},
+		{Id: 1, SaleId: 1, ItemId: 1, Quantity: 2, Price: model.MustParseMoney("50.25"), Subtotal: model.MustParseMoney("100.50")},
 	}
 
 	mockRepo.On("GetSalesById", 1).Return(sale, items, nil)
 
-	result, err := service.GetSalesById(1)
+	result, err := service.GetSalesById(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -72,11 +130,11 @@ func TestSalesService_GetSalesById_Success(t *testing.T) {
 
 func TestSalesService_GetSalesById_NotFound(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	mockRepo.On("GetSalesById", 999).Return(nil, nil, assert.AnError)
 
-	result, err := service.GetSalesById(999)
+	result, err := service.GetSalesById(context.Background(), 999)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -85,24 +143,24 @@ func TestSalesService_GetSalesById_NotFound(t *testing.T) {
 
 func TestSalesService_GetAllSales_Success(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	now := time.Now()
 	sales := []model.Sales{
 		{
 			Id:          1,
 			UserId:      1,
-			TotalAmount: 100.50,
+			TotalAmount: model.MustParseMoney("100.50"),
 			CreatedAt:   now,
 			Items: []model.SaleItems{
-				{Id: 1, SaleId: 1, ItemId: 1, Quantity: 2, Price: 50.25, Subtotal: 100.50},
+				{Id: 1, SaleId: 1, ItemId: 1, Quantity: 2, Price: model.MustParseMoney("50.25"), Subtotal: model.MustParseMoney("100.50")},
 			},
 		},
 	}
 
-	mockRepo.On("GetAllSales", 1, 10).Return(sales, 1, nil)
+	mockRepo.On("GetAllSales", repository.SalesFilter{}, 1, 10, true).Return(sales, 1, nil)
 
-	result, total, err := service.GetAllSales(1, 10)
+	result, total, err := service.GetAllSales(context.Background(), repository.SalesFilter{}, 1, 10, true)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, total)
@@ -113,12 +171,12 @@ func TestSalesService_GetAllSales_Success(t *testing.T) {
 
 func TestSalesService_GetAllSales_ValidationPage(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	sales := []model.Sales{}
-	mockRepo.On("GetAllSales", 1, 10).Return(sales, 0, nil)
+	mockRepo.On("GetAllSales", repository.SalesFilter{}, 1, 10, false).Return(sales, 0, nil)
 
-	result, total, err := service.GetAllSales(0, 10)
+	result, total, err := service.GetAllSales(context.Background(), repository.SalesFilter{}, 0, 10, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, total)
@@ -128,12 +186,12 @@ func TestSalesService_GetAllSales_ValidationPage(t *testing.T) {
 
 func TestSalesService_GetAllSales_ValidationLimit(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	sales := []model.Sales{}
-	mockRepo.On("GetAllSales", 1, 100).Return(sales, 0, nil)
+	mockRepo.On("GetAllSales", repository.SalesFilter{}, 1, 100, false).Return(sales, 0, nil)
 
-	result, total, err := service.GetAllSales(1, 150)
+	result, total, err := service.GetAllSales(context.Background(), repository.SalesFilter{}, 1, 150, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, total)
@@ -143,35 +201,258 @@ func TestSalesService_GetAllSales_ValidationLimit(t *testing.T) {
 
 func TestSalesService_CreateSales_Success(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	audit := &fakeAuditor{}
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), audit, testInvoiceConfig, testHoldConfig)
 
 	request := &dto.SalesRequest{
 		UserId: 1,
 		Items: []dto.SaleItemRequest{
-			{ItemId: 1, Quantity: 2, Price: 50.25},
+			{ItemId: 1, Quantity: 2, Price: model.MustParseMoney("50.25")},
 		},
 	}
 
-	mockRepo.On("CreateSales", mock.AnythingOfType("*model.Sales"), mock.AnythingOfType("[]model.SaleItems")).Return(nil)
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	mockRepo.On("NextInvoiceNo", 0).Return(1, invoiceTime, nil)
+	mockRepo.On("CreateSales", mock.AnythingOfType("*model.Sales"), mock.AnythingOfType("[]model.SaleItems")).
+		Run(func(args mock.Arguments) { args.Get(0).(*model.Sales).Id = 5 }).
+		Return(nil)
 
-	err := service.CreateSales(request)
+	res, err := service.CreateSales(context.Background(), request)
 
 	assert.NoError(t, err)
+	// client butuh id dan invoice_no untuk struk dan pembayaran
+	if assert.NotNil(t, res) {
+		assert.Equal(t, 5, res.Id)
+		assert.Equal(t, "INV/2026/10/000001", res.InvoiceNo)
+		assert.Equal(t, model.SaleCompleted, res.Status)
+		assert.Equal(t, model.MustParseMoney("100.50"), res.TotalAmount)
+		assert.Len(t, res.Items, 1)
+	}
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, "sale", audit.entries[0].Entity)
+		assert.Equal(t, 5, audit.entries[0].EntityId)
+		assert.Len(t, audit.entries[0].After.(*model.Sales).Items, 1)
+		assert.Equal(t, "INV/2026/10/000001", audit.entries[0].After.(*model.Sales).InvoiceNo)
+	}
 	mockRepo.AssertExpectations(t)
 }
 
+func TestSalesService_CreateSales_TaxAndDiscounts(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	request := &dto.SalesRequest{
+		UserId: 1,
+		Items: []dto.SaleItemRequest{
+			{ItemId: 1, Quantity: 2, Price: model.MustParseMoney("50.25"), Discount: &dto.DiscountRequest{Type: model.DiscountPercent, Value: model.MustParseMoney("10")}},
+			{ItemId: 2, Quantity: 1, Price: model.MustParseMoney("111")},
+		},
+		Discount: &dto.DiscountRequest{Type: model.DiscountFixed, Value: model.MustParseMoney("10")},
+	}
+
+	// item 1 PPN 11% exclusive, item 2 PPN 11% inclusive (dari category)
+	mockRepo.On("GetItemTaxes", []int{1, 2}).Return(map[int]repository.ItemTax{
+		1: {Rate: model.MustParseTaxRate("11")},
+		2: {Rate: model.MustParseTaxRate("11"), Inclusive: true},
+	}, nil)
+
+	var sale *model.Sales
+	var items []model.SaleItems
+	mockRepo.On("NextInvoiceNo", 0).Return(1, invoiceTime, nil)
+	mockRepo.On("CreateSales", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sale = args.Get(0).(*model.Sales)
+		items = args.Get(1).([]model.SaleItems)
+	}).Return(nil)
+
+	_, err := service.CreateSales(context.Background(), request)
+
+	assert.NoError(t, err)
+	// baris 1: 100.50 - 10.05 = 90.45, bagian diskon order 4.49 -> 85.96, pajak 9.46
+	// baris 2: 111.00, bagian diskon order 5.51 -> 105.49 termasuk pajak 10.45
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, model.SaleItems{ItemId: 1, Quantity: 2, Price: model.MustParseMoney("50.25"), Subtotal: model.MustParseMoney("100.50"), DiscountAmount: model.MustParseMoney("14.54"),
+			TaxRate: model.MustParseTaxRate("11"), TaxAmount: model.MustParseMoney("9.46"), Total: model.MustParseMoney("95.42")}, items[0])
+		assert.Equal(t, model.SaleItems{ItemId: 2, Quantity: 1, Price: model.MustParseMoney("111"), Subtotal: model.MustParseMoney("111"), DiscountAmount: model.MustParseMoney("5.51"),
+			TaxRate: model.MustParseTaxRate("11"), TaxInclusive: true, TaxAmount: model.MustParseMoney("10.45"), Total: model.MustParseMoney("105.49")}, items[1])
+	}
+	assert.Equal(t, model.MustParseMoney("211.50"), sale.Subtotal)
+	assert.Equal(t, model.MustParseMoney("20.05"), sale.DiscountAmount)
+	assert.Equal(t, model.MustParseMoney("19.91"), sale.TaxAmount)
+	assert.Equal(t, model.MustParseMoney("200.91"), sale.TotalAmount)
+}
+
+// TestSalesService_CreateSales_LineTotalsSumToHeader: untuk sale acak (harga
+// sampai sen, pajak inclusive/exclusive, diskon baris dan order), jumlah tiap
+// kolom di sale_items selalu sama persis dengan header sale
+func TestSalesService_CreateSales_LineTotalsSumToHeader(t *testing.T) {
+	rng := rand.New(rand.NewSource(44))
+	rates := []model.TaxRate{0, 1000, 1100, 1200, 1250}
+
+	for n := 0; n < 500; n++ {
+		request := &dto.SalesRequest{UserId: 1}
+		taxes := map[int]repository.ItemTax{}
+		var itemIds []int
+		for i := 1; i <= 1+rng.Intn(8); i++ {
+			item := dto.SaleItemRequest{ItemId: i, Quantity: 1 + rng.Intn(50), Price: model.Money(1 + rng.Int63n(100_000_000))}
+			if rng.Intn(3) == 0 {
+				item.Discount = &dto.DiscountRequest{Type: model.DiscountPercent, Value: model.Money(rng.Intn(10001))}
+			}
+			request.Items = append(request.Items, item)
+			taxes[i] = repository.ItemTax{Rate: rates[rng.Intn(len(rates))], Inclusive: rng.Intn(2) == 0}
+			itemIds = append(itemIds, i)
+		}
+		if rng.Intn(2) == 0 {
+			request.Discount = &dto.DiscountRequest{Type: model.DiscountFixed, Value: model.Money(rng.Int63n(100_000))}
+		}
+
+		mockRepo := new(MockSalesRepository)
+		service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+		mockRepo.On("GetItemTaxes", itemIds).Return(taxes, nil)
+		var sale *model.Sales
+		var items []model.SaleItems
+		mockRepo.On("NextInvoiceNo", 0).Return(1, invoiceTime, nil)
+		mockRepo.On("CreateSales", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			sale = args.Get(0).(*model.Sales)
+			items = args.Get(1).([]model.SaleItems)
+		}).Return(nil)
+
+		_, err := service.CreateSales(context.Background(), request)
+		if err != nil {
+			// diskon fixed bisa melebihi total sale kecil
+			assert.Equal(t, "validation_error", apperror.Code(err))
+			continue
+		}
+
+		var subtotal, discount, tax, total model.Money
+		for i, item := range items {
+			// quantity x price dihitung ulang dari representasi desimal
+			price, _ := new(big.Rat).SetString(request.Items[i].Price.String())
+			want := new(big.Rat).Mul(price, big.NewRat(int64(item.Quantity), 1))
+			assert.Equal(t, want.FloatString(2), item.Subtotal.String())
+
+			subtotal += item.Subtotal
+			discount += item.DiscountAmount
+			tax += item.TaxAmount
+			total += item.Total
+		}
+		assert.Equal(t, sale.Subtotal, subtotal)
+		assert.Equal(t, sale.DiscountAmount, discount)
+		assert.Equal(t, sale.TaxAmount, tax)
+		assert.Equal(t, sale.TotalAmount, total)
+	}
+}
+
+func TestSalesService_CreateSales_FixedDiscountExceedsSubtotal(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	request := &dto.SalesRequest{
+		UserId: 1,
+		Items: []dto.SaleItemRequest{
+			{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("10"), Discount: &dto.DiscountRequest{Type: model.DiscountFixed, Value: model.MustParseMoney("10.01")}},
+		},
+	}
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+
+	_, err := service.CreateSales(context.Background(), request)
+
+	assert.Equal(t, "validation_error", apperror.Code(err))
+	mockRepo.AssertNotCalled(t, "CreateSales", mock.Anything, mock.Anything)
+}
+
+func creditSaleRequest(customerId int, price model.Money) *dto.SalesRequest {
+	return &dto.SalesRequest{
+		UserId:      1,
+		CustomerId:  &customerId,
+		PaymentType: model.PaymentCredit,
+		Items:       []dto.SaleItemRequest{{ItemId: 1, Quantity: 2, Price: price}},
+	}
+}
+
+func TestSalesService_CreateSales_CreditWithinLimit(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	customers := new(MockCustomersRepository)
+	service := NewSalesService(mockRepo, customers, &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	// 400 + 2*50 = 500, pas di limit
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	customers.On("LockCustomerCredit", 7).Return(&repository.CustomerCredit{CreditLimit: model.MustParseMoney("500"), Outstanding: model.MustParseMoney("400")}, nil)
+	mockRepo.On("NextInvoiceNo", 0).Return(1, invoiceTime, nil)
+	mockRepo.On("CreateSales", mock.MatchedBy(func(sale *model.Sales) bool {
+		return *sale.CustomerId == 7 && sale.PaymentType == model.PaymentCredit && sale.TotalAmount == model.MustParseMoney("100")
+	}), mock.Anything).Return(nil)
+
+	_, err := service.CreateSales(context.Background(), creditSaleRequest(7, model.MustParseMoney("50")))
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	customers.AssertExpectations(t)
+}
+
+func TestSalesService_CreateSales_CreditLimitExceeded(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	customers := new(MockCustomersRepository)
+	service := NewSalesService(mockRepo, customers, &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	customers.On("LockCustomerCredit", 7).Return(&repository.CustomerCredit{CreditLimit: model.MustParseMoney("500"), Outstanding: model.MustParseMoney("450")}, nil)
+
+	_, err := service.CreateSales(context.Background(), creditSaleRequest(7, model.MustParseMoney("50")))
+
+	assert.Equal(t, "credit_limit_exceeded", apperror.Code(err))
+	var appErr *apperror.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, model.MustParseMoney("50"), appErr.Details.(map[string]model.Money)["available"])
+	}
+	mockRepo.AssertNotCalled(t, "CreateSales", mock.Anything, mock.Anything)
+}
+
+func TestSalesService_CreateSales_CreditRequiresCustomer(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	request := &dto.SalesRequest{
+		UserId:      1,
+		PaymentType: model.PaymentCredit,
+		Items:       []dto.SaleItemRequest{{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("10")}},
+	}
+	_, err := service.CreateSales(context.Background(), request)
+
+	assert.Equal(t, "validation_error", apperror.Code(err))
+	mockRepo.AssertNotCalled(t, "CreateSales", mock.Anything, mock.Anything)
+}
+
+func TestSalesService_CreateSales_UnknownCustomer(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	customers := new(MockCustomersRepository)
+	service := NewSalesService(mockRepo, customers, &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	customerId := 9
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	customers.On("GetCustomersById", 9).Return(nil, apperror.NotFound("customer"))
+	request := &dto.SalesRequest{
+		UserId:     1,
+		CustomerId: &customerId,
+		Items:      []dto.SaleItemRequest{{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("10")}},
+	}
+	_, err := service.CreateSales(context.Background(), request)
+
+	assert.Equal(t, "validation_error", apperror.Code(err))
+	mockRepo.AssertNotCalled(t, "CreateSales", mock.Anything, mock.Anything)
+}
+
 func TestSalesService_CreateSales_ValidationUserIdRequired(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	request := &dto.SalesRequest{
 		UserId: 0, // Invalid
 		Items: []dto.SaleItemRequest{
-			{ItemId: 1, Quantity: 2, Price: 50.25},
+			{ItemId: 1, Quantity: 2, Price: model.MustParseMoney("50.25")},
 		},
 	}
 
-	err := service.CreateSales(request)
+	_, err := service.CreateSales(context.Background(), request)
 
 	assert.Error(t, err)
 	assert.Equal(t, "user_id is required", err.Error())
@@ -179,14 +460,14 @@ func TestSalesService_CreateSales_ValidationUserIdRequired(t *testing.T) {
 
 func TestSalesService_CreateSales_ValidationItemsRequired(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	request := &dto.SalesRequest{
 		UserId: 1,
 		Items:  []dto.SaleItemRequest{}, // Empty
 	}
 
-	err := service.CreateSales(request)
+	_, err := service.CreateSales(context.Background(), request)
 
 	assert.Error(t, err)
 	assert.Equal(t, "at least one item is required", err.Error())
@@ -194,16 +475,16 @@ func TestSalesService_CreateSales_ValidationItemsRequired(t *testing.T) {
 
 func TestSalesService_CreateSales_ValidationQuantityInvalid(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	request := &dto.SalesRequest{
 		UserId: 1,
 		Items: []dto.SaleItemRequest{
-			{ItemId: 1, Quantity: 0, Price: 50.25}, // Invalid quantity
+			{ItemId: 1, Quantity: 0, Price: model.MustParseMoney("50.25")}, // Invalid quantity
 		},
 	}
 
-	err := service.CreateSales(request)
+	_, err := service.CreateSales(context.Background(), request)
 
 	assert.Error(t, err)
 	assert.Equal(t, "quantity must be greater than 0", err.Error())
@@ -211,16 +492,16 @@ func TestSalesService_CreateSales_ValidationQuantityInvalid(t *testing.T) {
 
 func TestSalesService_CreateSales_ValidationPriceInvalid(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	request := &dto.SalesRequest{
 		UserId: 1,
 		Items: []dto.SaleItemRequest{
-			{ItemId: 1, Quantity: 2, Price: 0}, // Invalid price
+			{ItemId: 1, Quantity: 2, Price: model.MustParseMoney("0")}, // Invalid price
 		},
 	}
 
-	err := service.CreateSales(request)
+	_, err := service.CreateSales(context.Background(), request)
 
 	assert.Error(t, err)
 	assert.Equal(t, "price must be greater than 0", err.Error())
@@ -228,18 +509,99 @@ func TestSalesService_CreateSales_ValidationPriceInvalid(t *testing.T) {
 
 func TestSalesService_UpdateSales_Success(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	request := &dto.SalesRequest{
 		UserId: 1,
 		Items: []dto.SaleItemRequest{
-			{ItemId: 1, Quantity: 3, Price: 50.25},
+			{ItemId: 1, Quantity: 3, Price: model.MustParseMoney("50.25")},
 		},
 	}
 
-	mockRepo.On("UpdateSales", 1, mock.AnythingOfType("*model.Sales")).Return(nil)
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{1: {Rate: model.MustParseTaxRate("11")}}, nil)
+	mockRepo.On("GetSalesById", 1).Return(&model.Sales{Id: 1, UserId: 1, Status: model.SaleDraft}, []model.SaleItems{}, nil)
+	mockRepo.On("UpdateSales", 1, mock.MatchedBy(func(sale *model.Sales) bool {
+		// 150.75 + PPN 11% (16.58)
+		return sale.Subtotal == model.MustParseMoney("150.75") && sale.TaxAmount == model.MustParseMoney("16.58") &&
+			sale.TotalAmount == model.MustParseMoney("167.33")
+	}), mock.MatchedBy(func(items []model.SaleItems) bool {
+		// baris item ikut dihitung ulang, bukan hanya header
+		return len(items) == 1 && items[0].ItemId == 1 && items[0].Quantity == 3 &&
+			items[0].TaxAmount == model.MustParseMoney("16.58") && items[0].Total == model.MustParseMoney("167.33")
+	})).Return(nil)
 
-	err := service.UpdateSales(1, request)
+	err := service.UpdateSales(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestSalesService_UpdateSales_CreditCustomer: draft yang diubah menjadi kredit
+// menyimpan customer dan payment_type; customer dicek ulang di transaksi
+func TestSalesService_UpdateSales_CreditCustomer(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	customers := new(MockCustomersRepository)
+	service := NewSalesService(mockRepo, customers, &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	mockRepo.On("GetSalesById", 1).Return(&model.Sales{Id: 1, UserId: 1, PaymentType: model.PaymentCash, Status: model.SaleDraft}, []model.SaleItems{}, nil)
+	customers.On("GetCustomersById", 7).Return(&model.Customers{Id: 7}, nil)
+	mockRepo.On("UpdateSales", 1, mock.MatchedBy(func(sale *model.Sales) bool {
+		return sale.CustomerId != nil && *sale.CustomerId == 7 && sale.PaymentType == model.PaymentCredit &&
+			sale.TotalAmount == model.MustParseMoney("100")
+	}), mock.Anything).Return(nil)
+
+	err := service.UpdateSales(context.Background(), 1, creditSaleRequest(7, model.MustParseMoney("50")))
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	customers.AssertExpectations(t)
+}
+
+func TestSalesService_UpdateSales_PaymentValidation(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	customers := new(MockCustomersRepository)
+	service := NewSalesService(mockRepo, customers, &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+	items := []dto.SaleItemRequest{{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("10")}}
+
+	// kredit tanpa customer dan payment_type tidak dikenal ditolak, bukan diabaikan
+	for _, request := range []*dto.SalesRequest{
+		{UserId: 1, PaymentType: model.PaymentCredit, Items: items},
+		{UserId: 1, PaymentType: "voucher", Items: items},
+	} {
+		err := service.UpdateSales(context.Background(), 1, request)
+		assert.Equal(t, "validation_error", apperror.Code(err))
+	}
+
+	// customer tidak dikenal
+	customerId := 9
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	mockRepo.On("GetSalesById", 1).Return(&model.Sales{Id: 1, Status: model.SaleDraft}, []model.SaleItems{}, nil)
+	customers.On("GetCustomersById", 9).Return(nil, apperror.NotFound("customer"))
+	err := service.UpdateSales(context.Background(), 1, &dto.SalesRequest{UserId: 1, CustomerId: &customerId, Items: items})
+
+	assert.Equal(t, "validation_error", apperror.Code(err))
+	mockRepo.AssertNotCalled(t, "UpdateSales", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSalesService_UpdateSales_PerWarehouse(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, utils.InvoiceConfig{Prefix: "INV", PerWarehouse: true}, testHoldConfig)
+	items := []dto.SaleItemRequest{{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("10")}}
+
+	err := service.UpdateSales(context.Background(), 1, &dto.SalesRequest{UserId: 1, Items: items})
+	assert.Equal(t, "validation_error", apperror.Code(err))
+	mockRepo.AssertNotCalled(t, "UpdateSales", mock.Anything, mock.Anything, mock.Anything)
+
+	// draft pindah gudang: seri INV/W<id> yang dipakai saat complete ikut berubah
+	warehouseId := 3
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	mockRepo.On("GetSalesById", 1).Return(&model.Sales{Id: 1, Status: model.SaleDraft}, []model.SaleItems{}, nil)
+	mockRepo.On("UpdateSales", 1, mock.MatchedBy(func(sale *model.Sales) bool {
+		return sale.WarehouseId != nil && *sale.WarehouseId == 3
+	}), mock.Anything).Return(nil)
+
+	err = service.UpdateSales(context.Background(), 1, &dto.SalesRequest{UserId: 1, WarehouseId: &warehouseId, Items: items})
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -247,16 +609,16 @@ func TestSalesService_UpdateSales_Success(t *testing.T) {
 
 func TestSalesService_UpdateSales_ValidationUserIdRequired(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
 	request := &dto.SalesRequest{
 		UserId: 0, // Invalid
 		Items: []dto.SaleItemRequest{
-			{ItemId: 1, Quantity: 2, Price: 50.25},
+			{ItemId: 1, Quantity: 2, Price: model.MustParseMoney("50.25")},
 		},
 	}
 
-	err := service.UpdateSales(1, request)
+	err := service.UpdateSales(context.Background(), 1, request)
 
 	assert.Error(t, err)
 	assert.Equal(t, "user_id is required", err.Error())
@@ -264,11 +626,12 @@ func TestSalesService_UpdateSales_ValidationUserIdRequired(t *testing.T) {
 
 func TestSalesService_DeleteSales_Success(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
+	mockRepo.On("GetSalesById", 1).Return(&model.Sales{Id: 1, Status: model.SaleDraft}, []model.SaleItems{}, nil)
 	mockRepo.On("DeleteSales", 1).Return(nil)
 
-	err := service.DeleteSales(1)
+	err := service.DeleteSales(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -276,12 +639,424 @@ func TestSalesService_DeleteSales_Success(t *testing.T) {
 
 func TestSalesService_DeleteSales_Error(t *testing.T) {
 	mockRepo := new(MockSalesRepository)
-	service := NewSalesService(mockRepo)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
 
+	mockRepo.On("GetSalesById", 999).Return(&model.Sales{Id: 999, Status: model.SaleDraft}, []model.SaleItems{}, nil)
 	mockRepo.On("DeleteSales", 999).Return(assert.AnError)
 
-	err := service.DeleteSales(999)
+	err := service.DeleteSales(context.Background(), 999)
 
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestSalesService_DeleteSales_OnlyDraft(t *testing.T) {
+	for _, status := range []string{model.SaleCompleted, model.SaleVoided} {
+		mockRepo := new(MockSalesRepository)
+		service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+		mockRepo.On("GetSalesById", 1).Return(&model.Sales{Id: 1, Status: status}, []model.SaleItems{}, nil)
+
+		err := service.DeleteSales(context.Background(), 1)
+
+		assert.Equal(t, "invalid_sale_status", apperror.Code(err))
+		mockRepo.AssertNotCalled(t, "DeleteSales", mock.Anything)
+	}
+}
+
+func TestSalesService_VoidSale(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	audit := &fakeAuditor{}
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), audit, testInvoiceConfig, testHoldConfig)
+
+	items := []model.SaleItems{{Id: 1, SaleId: 5, ItemId: 3, Quantity: 2}}
+	voidedAt, voidedBy := invoiceTime, 7
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: model.SaleCompleted}, items, nil).Once()
+	mockRepo.On("VoidSale", 5, 7, "salah input harga").Return(nil)
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{
+		Id: 5, Status: model.SaleVoided, VoidedAt: &voidedAt, VoidedBy: &voidedBy, VoidReason: "salah input harga",
+	}, items, nil).Once()
+
+	res, err := service.VoidSale(context.Background(), 5, 7, "  salah input harga ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.SaleVoided, res.Status)
+	assert.Equal(t, "salah input harga", res.VoidReason)
+	assert.Equal(t, 7, *res.VoidedBy)
+	assert.Len(t, res.Items, 1)
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, model.AuditVoid, audit.entries[0].Action)
+		assert.Equal(t, model.SaleCompleted, audit.entries[0].Before.(*model.Sales).Status)
+		assert.Equal(t, model.SaleVoided, audit.entries[0].After.(*model.Sales).Status)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSalesService_VoidSale_ReasonRequired(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	_, err := service.VoidSale(context.Background(), 5, 7, "   ")
+
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+	mockRepo.AssertNotCalled(t, "GetSalesById", mock.Anything)
+}
+
+func TestSalesService_VoidSale_AlreadyVoided(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: model.SaleVoided}, []model.SaleItems{}, nil)
+
+	_, err := service.VoidSale(context.Background(), 5, 7, "dobel")
+
+	assert.Equal(t, "sale_voided", apperror.Code(err))
+	mockRepo.AssertNotCalled(t, "VoidSale", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSalesService_UpdateSales_Voided(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	mockRepo.On("GetSalesById", 1).Return(&model.Sales{Id: 1, Status: model.SaleVoided}, []model.SaleItems{}, nil)
+
+	err := service.UpdateSales(context.Background(), 1, &dto.SalesRequest{
+		UserId: 1,
+		Items:  []dto.SaleItemRequest{{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("10")}},
+	})
+
+	assert.Equal(t, "sale_voided", apperror.Code(err))
+	mockRepo.AssertNotCalled(t, "UpdateSales", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSalesService_UpdateSales_CompletedRejected(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	mockRepo.On("GetSalesById", 1).Return(&model.Sales{Id: 1, Status: model.SaleCompleted, PaymentStatus: model.PaymentPaid}, []model.SaleItems{}, nil)
+
+	err := service.UpdateSales(context.Background(), 1, &dto.SalesRequest{
+		UserId: 1,
+		Items:  []dto.SaleItemRequest{{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("1")}},
+	})
+
+	assert.Equal(t, "invalid_sale_status", apperror.Code(err))
+	mockRepo.AssertNotCalled(t, "UpdateSales", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSalesService_UpdateSales_ItemsRequired(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	err := service.UpdateSales(context.Background(), 1, &dto.SalesRequest{UserId: 1})
+
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+	assert.Equal(t, "at least one item is required", err.Error())
+	mockRepo.AssertNotCalled(t, "GetSalesById", mock.Anything)
+}
+
+func TestSalesService_CreateSales_QuoteSkipsInvoiceAndCreditLimit(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	customers := new(MockCustomersRepository)
+	service := NewSalesService(mockRepo, customers, &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	request := creditSaleRequest(7, model.MustParseMoney("50"))
+	request.Status = model.SaleQuote
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	customers.On("GetCustomersById", 7).Return(&model.Customers{Id: 7}, nil)
+	mockRepo.On("CreateSales", mock.MatchedBy(func(sale *model.Sales) bool {
+		return sale.Status == model.SaleQuote && sale.InvoiceNo == ""
+	}), mock.Anything).Return(nil)
+
+	_, err := service.CreateSales(context.Background(), request)
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "NextInvoiceNo", mock.Anything)
+	mockRepo.AssertNotCalled(t, "HoldSale", mock.Anything, mock.Anything)
+	customers.AssertNotCalled(t, "LockCustomerCredit", mock.Anything)
+}
+
+func TestSalesService_CreateSales_HeldReservesStock(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	audit := &fakeAuditor{}
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), audit, testInvoiceConfig, testHoldConfig)
+
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	mockRepo.On("CreateSales", mock.MatchedBy(func(sale *model.Sales) bool {
+		return sale.Status == model.SaleDraft && sale.InvoiceNo == ""
+	}), mock.Anything).Run(func(args mock.Arguments) { args.Get(0).(*model.Sales).Id = 5 }).Return(nil)
+	mockRepo.On("HoldSale", 5, 30*time.Minute).Return(nil)
+	expires := invoiceTime.Add(30 * time.Minute)
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: model.SaleHeld, HoldExpiresAt: &expires}, []model.SaleItems{}, nil)
+
+	res, err := service.CreateSales(context.Background(), &dto.SalesRequest{
+		UserId: 1,
+		Status: model.SaleHeld,
+		Items:  []dto.SaleItemRequest{{ItemId: 1, Quantity: 2, Price: model.MustParseMoney("10")}},
+	})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, res) {
+		assert.Equal(t, 5, res.Id)
+		assert.Equal(t, model.SaleHeld, res.Status)
+		assert.Equal(t, expires, *res.HoldExpiresAt)
+	}
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "NextInvoiceNo", mock.Anything)
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, model.SaleHeld, audit.entries[0].After.(*model.Sales).Status)
+	}
+}
+
+func TestSalesService_CreateSales_HeldInsufficientStock(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	audit := &fakeAuditor{}
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), audit, testInvoiceConfig, testHoldConfig)
+
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	mockRepo.On("CreateSales", mock.Anything, mock.Anything).Run(func(args mock.Arguments) { args.Get(0).(*model.Sales).Id = 5 }).Return(nil)
+	mockRepo.On("HoldSale", 5, 30*time.Minute).Return(apperror.InsufficientStock("insufficient stock for one or more items", nil))
+
+	_, err := service.CreateSales(context.Background(), &dto.SalesRequest{
+		UserId: 1,
+		Status: model.SaleHeld,
+		Items:  []dto.SaleItemRequest{{ItemId: 1, Quantity: 2, Price: model.MustParseMoney("10")}},
+	})
+
+	// transaksi di-rollback, draft tidak tertinggal
+	assert.True(t, apperror.Is(err, apperror.KindInsufficientStock))
+	assert.Empty(t, audit.entries)
+}
+
+func TestSalesService_HoldSale(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	audit := &fakeAuditor{}
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), audit, testInvoiceConfig, testHoldConfig)
+
+	expires := invoiceTime.Add(30 * time.Minute)
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: model.SaleDraft}, []model.SaleItems{}, nil).Once()
+	mockRepo.On("HoldSale", 5, 30*time.Minute).Return(nil)
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: model.SaleHeld, HoldExpiresAt: &expires}, []model.SaleItems{}, nil).Once()
+
+	res, err := service.HoldSale(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.SaleHeld, res.Status)
+	assert.Equal(t, expires, *res.HoldExpiresAt)
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, model.AuditHold, audit.entries[0].Action)
+	}
+}
+
+func TestSalesService_ReleaseHold(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	audit := &fakeAuditor{}
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), audit, testInvoiceConfig, testHoldConfig)
+
+	expires := invoiceTime
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: model.SaleHeld, HoldExpiresAt: &expires}, []model.SaleItems{}, nil).Once()
+	mockRepo.On("ReleaseHold", 5).Return(nil)
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: model.SaleDraft}, []model.SaleItems{}, nil).Once()
+
+	res, err := service.ReleaseHold(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.SaleDraft, res.Status)
+	assert.Nil(t, res.HoldExpiresAt)
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, model.AuditRelease, audit.entries[0].Action)
+	}
+}
+
+func TestSalesService_CompleteSale_Held(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	audit := &fakeAuditor{}
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), audit, testInvoiceConfig, testHoldConfig)
+
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: model.SaleHeld, PaymentType: model.PaymentCash}, []model.SaleItems{}, nil).Once()
+	mockRepo.On("NextInvoiceNo", 0).Return(7, invoiceTime, nil)
+	mockRepo.On("CompleteSale", 5, "INV/2026/10/000007").Return(nil)
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: model.SaleCompleted, InvoiceNo: "INV/2026/10/000007"}, []model.SaleItems{}, nil).Once()
+
+	res, err := service.CompleteSale(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.SaleCompleted, res.Status)
+	assert.Equal(t, "INV/2026/10/000007", res.InvoiceNo)
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, model.AuditComplete, audit.entries[0].Action)
+		assert.Equal(t, model.SaleHeld, audit.entries[0].Before.(*model.Sales).Status)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSalesService_CompleteSale_CreditLimitExceeded(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	customers := new(MockCustomersRepository)
+	service := NewSalesService(mockRepo, customers, &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+
+	customerId := 7
+	mockRepo.On("GetSalesById", 5).Return(&model.Sales{
+		Id: 5, Status: model.SaleQuote, PaymentType: model.PaymentCredit, CustomerId: &customerId, TotalAmount: model.MustParseMoney("100"),
+	}, []model.SaleItems{}, nil)
+	customers.On("LockCustomerCredit", 7).Return(&repository.CustomerCredit{CreditLimit: model.MustParseMoney("500"), Outstanding: model.MustParseMoney("450")}, nil)
+
+	_, err := service.CompleteSale(context.Background(), 5)
+
+	assert.Equal(t, "credit_limit_exceeded", apperror.Code(err))
+	mockRepo.AssertNotCalled(t, "NextInvoiceNo", mock.Anything)
+	mockRepo.AssertNotCalled(t, "CompleteSale", mock.Anything, mock.Anything)
+}
+
+func TestSalesService_CompleteSale_InvalidStatus(t *testing.T) {
+	tests := map[string]string{
+		model.SaleCompleted: "invalid_sale_status",
+		model.SaleVoided:    "sale_voided",
+	}
+	for status, code := range tests {
+		mockRepo := new(MockSalesRepository)
+		service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, testInvoiceConfig, testHoldConfig)
+		mockRepo.On("GetSalesById", 5).Return(&model.Sales{Id: 5, Status: status}, []model.SaleItems{}, nil)
+
+		_, err := service.CompleteSale(context.Background(), 5)
+
+		assert.Equal(t, code, apperror.Code(err), status)
+		mockRepo.AssertNotCalled(t, "NextInvoiceNo", mock.Anything)
+	}
+}
+
+func TestFormatInvoiceNo(t *testing.T) {
+	assert.Equal(t, "INV/2026/10/000123", formatInvoiceNo("INV", 0, invoiceTime, 123))
+	assert.Equal(t, "INV/W2/2026/01/000001", formatInvoiceNo("INV", 2, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 1))
+	// lewat 6 digit tetap unik, hanya lebih panjang
+	assert.Equal(t, "SO/2026/10/1234567", formatInvoiceNo("SO", 0, invoiceTime, 1234567))
+}
+
+func TestSalesService_CreateSales_PerWarehouseInvoice(t *testing.T) {
+	mockRepo := new(MockSalesRepository)
+	service := NewSalesService(mockRepo, new(MockCustomersRepository), &fakeAuditor{}, utils.InvoiceConfig{Prefix: "INV", PerWarehouse: true}, testHoldConfig)
+
+	warehouseId := 2
+	mockRepo.On("GetItemTaxes", []int{1}).Return(map[int]repository.ItemTax{}, nil)
+	mockRepo.On("NextInvoiceNo", 2).Return(45, invoiceTime, nil)
+	mockRepo.On("CreateSales", mock.MatchedBy(func(sale *model.Sales) bool {
+		return sale.InvoiceNo == "INV/W2/2026/10/000045" && *sale.WarehouseId == 2
+	}), mock.Anything).Return(nil)
+
+	_, err := service.CreateSales(context.Background(), &dto.SalesRequest{
+		UserId: 1, WarehouseId: &warehouseId,
+		Items: []dto.SaleItemRequest{{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("10")}},
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// seri per gudang wajib warehouse_id
+	_, err = service.CreateSales(context.Background(), &dto.SalesRequest{
+		UserId: 1, Items: []dto.SaleItemRequest{{ItemId: 1, Quantity: 1, Price: model.MustParseMoney("10")}},
+	})
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+}
+
+type invoiceTxKey struct{}
+
+type fakeInvoiceTx struct {
+	locked bool
+	next   int
+}
+
+// fakeInvoiceDB meniru perilaku Postgres untuk invoice_counters: baris counter
+// terkunci dari increment pertama sampai commit/rollback, dan rollback
+// membatalkan increment. Sekaligus dipakai sebagai Auditor (pemilik transaksi).
+type fakeInvoiceDB struct {
+	*MockSalesRepository
+	rowLock sync.Mutex
+	lastNo  int
+
+	mu      sync.Mutex
+	created []string
+}
+
+func (db *fakeInvoiceDB) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx := &fakeInvoiceTx{}
+	err := fn(context.WithValue(ctx, invoiceTxKey{}, tx))
+	if tx.locked {
+		if err == nil {
+			db.lastNo = tx.next
+		}
+		db.rowLock.Unlock()
+	}
+	return err
+}
+
+func (db *fakeInvoiceDB) Record(ctx context.Context, action, entity string, entityId int, before, after any) error {
+	return nil
+}
+
+func (db *fakeInvoiceDB) NextInvoiceNo(ctx context.Context, warehouseId int) (int, time.Time, error) {
+	tx := ctx.Value(invoiceTxKey{}).(*fakeInvoiceTx)
+	if !tx.locked {
+		db.rowLock.Lock()
+		tx.locked = true
+		tx.next = db.lastNo
+	}
+	tx.next++
+	return tx.next, invoiceTime, nil
+}
+
+func (db *fakeInvoiceDB) GetItemTaxes(ctx context.Context, itemIds []int) (map[int]repository.ItemTax, error) {
+	return map[int]repository.ItemTax{}, nil
+}
+
+func (db *fakeInvoiceDB) CreateSales(ctx context.Context, sale *model.Sales, items []model.SaleItems) error {
+	if items[0].Quantity > 10 {
+		return apperror.InsufficientStock("insufficient stock for one or more items", nil)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.created = append(db.created, sale.InvoiceNo)
+	return nil
+}
+
+// TestSalesService_CreateSales_ParallelInvoiceNumbers: sale paralel mendapat
+// nomor unik dan berurutan; sale yang gagal (rollback) tidak meninggalkan celah
+func TestSalesService_CreateSales_ParallelInvoiceNumbers(t *testing.T) {
+	db := &fakeInvoiceDB{MockSalesRepository: new(MockSalesRepository)}
+	service := NewSalesService(db, new(MockCustomersRepository), db, testInvoiceConfig, testHoldConfig)
+
+	const workers, failEvery = 50, 5
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			quantity := 1
+			if i%failEvery == 0 {
+				quantity = 99
+			}
+			_, errs[i] = service.CreateSales(context.Background(), &dto.SalesRequest{
+				UserId: 1,
+				Items:  []dto.SaleItemRequest{{ItemId: 1, Quantity: quantity, Price: model.MustParseMoney("10")}},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			assert.Equal(t, "insufficient_stock", apperror.Code(err))
+			failed++
+		}
+	}
+	assert.Equal(t, workers/failEvery, failed)
+
+	sort.Strings(db.created)
+	expected := make([]string, 0, workers-failed)
+	for no := 1; no <= workers-failed; no++ {
+		expected = append(expected, fmt.Sprintf("INV/2026/10/%06d", no))
+	}
+	assert.Equal(t, expected, db.created)
+	assert.Equal(t, workers-failed, db.lastNo)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
 )
 
 type Service struct {
@@ -11,17 +12,41 @@ type Service struct {
 	WarehousesService WarehousesService
 	UsersService UsersService
 	SalesService SalesService
+	CustomersService CustomersService
+	PaymentsService PaymentsService
+	ReceiptsService ReceiptsService
 	ReportsService ReportsService
+	PurgeService PurgeService
+	AuditService AuditService
+	AuthService AuthService
+	PasswordService PasswordService
+	TwoFactorService TwoFactorService
+	APIKeysService APIKeysService
+	IdempotencyService IdempotencyService
+	ReservationsService ReservationsService
 }
 
-func NewService(Repo repository.Repository) Service {
+func NewService(Repo repository.Repository, config utils.Configuration) Service {
+	audit := NewAuditor(Repo.Transactor, Repo.AuditRepo)
+
 	return Service{
-		ItemsService: NewItemsService(Repo.ItemsRepo),
-		CategoriesService: NewCategoriesService(Repo.CategoriesRepo),
-		RacksService: NewRacksService(Repo.RacksRepo),
-		WarehousesService: NewWarehousesService(Repo.WarehousesRepo),
-		UsersService: NewUsersService(Repo.UsersRepo),
-		SalesService: NewSalesService(Repo.SalesRepo),
+		ItemsService: NewItemsService(Repo.ItemsRepo, audit),
+		CategoriesService: NewCategoriesService(Repo.CategoriesRepo, audit),
+		RacksService: NewRacksService(Repo.RacksRepo, audit),
+		WarehousesService: NewWarehousesService(Repo.WarehousesRepo, audit),
+		UsersService: NewUsersService(Repo.UsersRepo, audit),
+		SalesService: NewSalesService(Repo.SalesRepo, Repo.CustomersRepo, audit, config.Invoice, config.SaleHold),
+		CustomersService: NewCustomersService(Repo.CustomersRepo, Repo.SalesRepo, audit),
+		PaymentsService: NewPaymentsService(Repo.PaymentsRepo, audit),
+		ReceiptsService: NewReceiptsService(Repo.SalesRepo, Repo.PaymentsRepo, Repo.ReceiptsRepo, config.Receipt),
 		ReportsService: NewReportsService(Repo.ReportsRepo),
+		PurgeService: NewPurgeService(Repo.PurgeRepo),
+		AuditService: NewAuditService(Repo.AuditRepo),
+		AuthService: NewAuthService(Repo.SessionsRepo, Repo.UsersRepo, Repo.TwoFactorRepo, Repo.APIKeysRepo, audit, config.Auth),
+		PasswordService: NewPasswordService(Repo.UsersRepo, Repo.SessionsRepo, Repo.PasswordResetsRepo, Repo.APIKeysRepo, audit, config.Auth.PasswordResetTTL),
+		TwoFactorService: NewTwoFactorService(Repo.TwoFactorRepo, Repo.UsersRepo, audit, config.Auth.TwoFactor),
+		APIKeysService: NewAPIKeysService(Repo.APIKeysRepo, Repo.UsersRepo, audit),
+		IdempotencyService: NewIdempotencyService(Repo.IdempotencyRepo, config.Idempotency.TTL),
+		ReservationsService: NewReservationsService(Repo.ReservationsRepo, audit, config.Reservation),
 	}
 }
\ No newline at end of file
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	recoveryCodeCount = 10
+	// 10 karakter base32 = 50 bit per code, ditampilkan sebagai XXXXX-XXXXX
+	recoveryCodeBytes = 5
+)
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context, userId int) (*dto.TwoFactorEnrollResponse, error)
+	Confirm(ctx context.Context, userId int, code string) (*dto.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, userId int, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userId int, code string) (*dto.RecoveryCodesResponse, error)
+}
+
+type twoFactorService struct {
+	Repo   repository.TwoFactorRepository
+	Users  repository.UsersRepository
+	Audit  Auditor
+	Config utils.TwoFactorConfig
+	now    func() time.Time
+}
+
+func NewTwoFactorService(repo repository.TwoFactorRepository, users repository.UsersRepository, audit Auditor, config utils.TwoFactorConfig) TwoFactorService {
+	return &twoFactorService{Repo: repo, Users: users, Audit: audit, Config: config, now: time.Now}
+}
+
+// Enroll membuat secret baru yang belum aktif sampai dikonfirmasi lewat Confirm
+func (s *twoFactorService) Enroll(ctx context.Context, userId int) (*dto.TwoFactorEnrollResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "TwoFactorService.Enroll")
+	defer span.End()
+
+	user, err := s.Users.GetUsersByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.SavePendingTOTP(ctx, userId, secret); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(s.Config.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm mengaktifkan 2FA dengan kode pertama dari authenticator app dan
+// mengembalikan recovery code (hanya sekali)
+func (s *twoFactorService) Confirm(ctx context.Context, userId int, code string) (*dto.RecoveryCodesResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "TwoFactorService.Confirm")
+	defer span.End()
+
+	totp, err := s.Repo.GetTOTP(ctx, userId)
+	if apperror.IsNotFound(err) {
+		return nil, apperror.Validation("two-factor enrollment has not been started", nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if totp.Enabled() {
+		return nil, apperror.Conflict("two_factor_already_enabled", "two-factor authentication is already enabled")
+	}
+
+	step, ok := verifyTOTP(totp.Secret, code, s.now(), totp.LastUsedStep)
+	if !ok {
+		return nil, apperror.Validation("invalid two-factor code", nil)
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Repo.EnableTOTP(ctx, userId, step); err != nil {
+			return err
+		}
+		if err := s.Repo.ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditTwoFactorEnable, "user", userId, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable mematikan 2FA (wajib kode TOTP atau recovery code). Role yang wajib 2FA
+// tidak bisa mematikannya.
+func (s *twoFactorService) Disable(ctx context.Context, userId int, code string) error {
+	ctx, span := utils.Tracer().Start(ctx, "TwoFactorService.Disable")
+	defer span.End()
+
+	user, err := s.Users.GetUsersByID(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if s.Config.Requires(user.Role) {
+		return apperror.Forbidden("two-factor authentication is required for role " + user.Role)
+	}
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkCode(ctx, userId, code); err != nil {
+			return err
+		}
+		if err := s.Repo.DeleteTOTP(ctx, userId); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditTwoFactorDisable, "user", userId, nil, nil)
+	})
+}
+
+// RegenerateRecoveryCodes mengganti semua recovery code (yang lama tidak berlaku lagi)
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userId int, code string) (*dto.RecoveryCodesResponse, error) {
+	ctx, span := utils.Tracer().Start(ctx, "TwoFactorService.RegenerateRecoveryCodes")
+	defer span.End()
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkCode(ctx, userId, code); err != nil {
+			return err
+		}
+		if err := s.Repo.ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditRecoveryCodes, "user", userId, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *twoFactorService) checkCode(ctx context.Context, userId int, code string) error {
+	ok, err := verifySecondFactor(ctx, s.Repo, userId, code, s.now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperror.Validation("invalid two-factor code", nil)
+	}
+	return nil
+}
+
+// verifySecondFactor menerima kode TOTP atau recovery code dari user dengan 2FA aktif.
+// Kode yang cocok langsung ditandai terpakai.
+func verifySecondFactor(ctx context.Context, repo repository.TwoFactorRepository, userId int, code string, now time.Time) (bool, error) {
+	totp, err := repo.GetTOTP(ctx, userId)
+	if apperror.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !totp.Enabled() {
+		return false, nil
+	}
+
+	if step, ok := verifyTOTP(totp.Secret, code, now, totp.LastUsedStep); ok {
+		// false: step yang sama sudah dipakai request lain (replay)
+		return repo.UseTOTPStep(ctx, userId, step)
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	err = repo.UseRecoveryCode(ctx, userId, utils.HashToken(normalized))
+	if apperror.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	utils.LoggerFromContext(ctx, nil).Info("recovery code used", zap.Int("user_id", userId))
+	return true, nil
+}
+
+// generateRecoveryCodes mengembalikan code untuk ditampilkan dan hash untuk disimpan
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	raw := make([]byte, recoveryCodeBytes)
+	for i := range codes {
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		encoded := totpEncoding.EncodeToString(raw)
+		codes[i] = encoded[:5] + "-" + encoded[5:]
+		hashes[i] = utils.HashToken(encoded)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode membuat input user tidak peka huruf besar/kecil, spasi dan tanda hubung
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"project-app-inventory-restapi-golang-azwin/apperror"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"project-app-inventory-restapi-golang-azwin/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) GetTOTP(ctx context.Context, userId int) (*model.UserTOTP, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserTOTP), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) SavePendingTOTP(ctx context.Context, userId int, secret string) error {
+	args := m.Called(userId, secret)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) EnableTOTP(ctx context.Context, userId int, step int64) error {
+	args := m.Called(userId, step)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseTOTPStep(ctx context.Context, userId int, step int64) (bool, error) {
+	args := m.Called(userId, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) DeleteTOTP(ctx context.Context, userId int) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userId int, codeHashes []string) error {
+	args := m.Called(userId, codeHashes)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userId int, codeHash string) error {
+	args := m.Called(userId, codeHash)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) CreateLoginChallenge(ctx context.Context, userId int, tokenHash string, expiredAt time.Time) error {
+	args := m.Called(userId, tokenHash, expiredAt)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) GetLoginChallenge(ctx context.Context, tokenHash string) (int, error) {
+	args := m.Called(tokenHash)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) ConsumeLoginChallenge(ctx context.Context, tokenHash string) error {
+	args := m.Called(tokenHash)
+	return args.Error(0)
+}
+
+// rfcSecret adalah secret SHA1 dari RFC 6238 Appendix B ("12345678901234567890") dalam base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func mustTOTP(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := totpCode(secret, totpStep(at))
+	require.NoError(t, err)
+	return code
+}
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// 6 digit terakhir dari test vector 8 digit RFC 6238
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, v := range vectors {
+		assert.Equal(t, v.code, mustTOTP(t, rfcSecret, time.Unix(v.unix, 0)), "unix %d", v.unix)
+	}
+}
+
+func TestVerifyTOTP_SkewAndReplay(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := totpStep(now)
+
+	step, ok := verifyTOTP(rfcSecret, "005924", now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, current, step)
+
+	// kode step sebelumnya masih diterima, dua step sebelumnya tidak
+	assert.True(t, isValidTOTP(mustTOTP(t, rfcSecret, now.Add(-totpPeriod)), now, 0))
+	assert.False(t, isValidTOTP(mustTOTP(t, rfcSecret, now.Add(-2*totpPeriod)), now, 0))
+
+	// step yang sudah dipakai ditolak
+	assert.False(t, isValidTOTP("005924", now, current))
+	assert.False(t, isValidTOTP("12345", now, 0))
+}
+
+func isValidTOTP(code string, now time.Time, lastUsedStep int64) bool {
+	_, ok := verifyTOTP(rfcSecret, code, now, lastUsedStep)
+	return ok
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(totpProvisioningURI("Inventory API", "ani@example.com", rfcSecret))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Inventory API:ani@example.com", uri.Path)
+	assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(t, "Inventory API", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
+
+func newTestTwoFactorService(repo *MockTwoFactorRepository, users *MockUsersRepository, now time.Time) *twoFactorService {
+	config := utils.TwoFactorConfig{Issuer: "Inventory", RequiredRoles: []string{"super_admin"}}
+	s := NewTwoFactorService(repo, users, &fakeAuditor{}, config).(*twoFactorService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestTwoFactorService_Enroll(t *testing.T) {
+	repo := new(MockTwoFactorRepository)
+	users := new(MockUsersRepository)
+	service := newTestTwoFactorService(repo, users, time.Now())
+
+	users.On("GetUsersByID", 4).Return(model.Users{Id: 4, Email: "ani@example.com"}, nil)
+	repo.On("SavePendingTOTP", 4, mock.AnythingOfType("string")).Return(nil)
+
+	result, err := service.Enroll(context.Background(), 4)
+
+	require.NoError(t, err)
+	assert.Len(t, result.Secret, 32)
+	assert.Equal(t, result.Secret, repo.Calls[0].Arguments.String(1))
+	assert.True(t, strings.HasPrefix(result.ProvisioningURI, "otpauth://totp/Inventory:ani@example.com?"))
+}
+
+func TestTwoFactorService_Confirm(t *testing.T) {
+	repo := new(MockTwoFactorRepository)
+	now := time.Unix(1234567890, 0)
+	service := newTestTwoFactorService(repo, new(MockUsersRepository), now)
+	audit := service.Audit.(*fakeAuditor)
+
+	repo.On("GetTOTP", 4).Return(&model.UserTOTP{UserId: 4, Secret: rfcSecret}, nil)
+	repo.On("EnableTOTP", 4, totpStep(now)).Return(nil)
+	repo.On("ReplaceRecoveryCodes", 4, mock.Anything).Return(nil)
+
+	_, err := service.Confirm(context.Background(), 4, "000000")
+	assert.True(t, apperror.Is(err, apperror.KindValidation))
+	repo.AssertNotCalled(t, "EnableTOTP", mock.Anything, mock.Anything)
+
+	result, err := service.Confirm(context.Background(), 4, "005924")
+	require.NoError(t, err)
+	require.Len(t, result.RecoveryCodes, recoveryCodeCount)
+
+	// yang disimpan hanya hash, dan code yang ditampilkan cocok dengan hash-nya
+	hashes := repo.Calls[len(repo.Calls)-1].Arguments.Get(1).([]string)
+	assert.Equal(t, utils.HashToken(normalizeRecoveryCode(result.RecoveryCodes[0])), hashes[0])
+	assert.NotContains(t, hashes, result.RecoveryCodes[0])
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, model.AuditTwoFactorEnable, audit.entries[0].Action)
+	}
+}
+
+func TestTwoFactorService_Confirm_AlreadyEnabled(t *testing.T) {
+	repo := new(MockTwoFactorRepository)
+	service := newTestTwoFactorService(repo, new(MockUsersRepository), time.Unix(1234567890, 0))
+	enabledAt := time.Unix(1234000000, 0)
+	repo.On("GetTOTP", 4).Return(&model.UserTOTP{UserId: 4, Secret: rfcSecret, EnabledAt: &enabledAt}, nil)
+
+	_, err := service.Confirm(context.Background(), 4, "005924")
+
+	assert.True(t, apperror.Is(err, apperror.KindConflict))
+}
+
+func TestTwoFactorService_Disable_RequiredRole(t *testing.T) {
+	repo := new(MockTwoFactorRepository)
+	users := new(MockUsersRepository)
+	service := newTestTwoFactorService(repo, users, time.Now())
+	users.On("GetUsersByID", 1).Return(model.Users{Id: 1, Role: "super_admin"}, nil)
+
+	err := service.Disable(context.Background(), 1, "005924")
+
+	assert.True(t, apperror.Is(err, apperror.KindForbidden))
+	repo.AssertNotCalled(t, "DeleteTOTP", mock.Anything)
+}
+
+func TestTwoFactorService_Disable_WithRecoveryCode(t *testing.T) {
+	repo := new(MockTwoFactorRepository)
+	users := new(MockUsersRepository)
+	service := newTestTwoFactorService(repo, users, time.Unix(1234567890, 0))
+	enabledAt := time.Unix(1234000000, 0)
+
+	users.On("GetUsersByID", 4).Return(model.Users{Id: 4, Role: "admin"}, nil)
+	repo.On("GetTOTP", 4).Return(&model.UserTOTP{UserId: 4, Secret: rfcSecret, EnabledAt: &enabledAt}, nil)
+	repo.On("UseRecoveryCode", 4, utils.HashToken("ABCDE23456")).Return(nil)
+	repo.On("DeleteTOTP", 4).Return(nil)
+
+	err := service.Disable(context.Background(), 4, "abcde-23456")
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
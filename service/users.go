@@ -1,47 +1,140 @@
 package service
 
 import (
+	"context"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
 )
 
 type UsersService interface {
-	GetUsersByEmail(email string) (*model.Users, error)
-	GetUsersByID(id int) (model.Users, error)
-	GetAllUsers()([]model.Users, error)
-	CreateUsers(data *model.Users) error
-	UpdateUsers(id int, data *model.Users) error
-	DeleteUsers(id int) error
+	GetUsersByEmail(ctx context.Context, email string) (*model.Users, error)
+	GetUsersByID(ctx context.Context, id int) (model.Users, error)
+	GetAllUsers(ctx context.Context, includeDeleted bool) ([]model.Users, error)
+	CreateUsers(ctx context.Context, data *model.Users) error
+	UpdateUsers(ctx context.Context, id int, data *model.Users) error
+	PatchUsers(ctx context.Context, id, version int, changes map[string]any) (*model.Users, error)
+	DeleteUsers(ctx context.Context, id int) error
+	RestoreUsers(ctx context.Context, id int) (*model.Users, error)
 }
 
 type usersServiceImpl struct {
-	Repo repository.UsersRepository
+	Repo  repository.UsersRepository
+	Audit Auditor
 }
 
-func NewUsersService(repo repository.UsersRepository) UsersService {
-	return &usersServiceImpl{Repo: repo}
+func NewUsersService(repo repository.UsersRepository, audit Auditor) UsersService {
+	return &usersServiceImpl{Repo: repo, Audit: audit}
 }
 
-func (s *usersServiceImpl) GetUsersByEmail(email string) (*model.Users, error) {
-	return s.Repo.GetUsersByEmail(email)
+func (s *usersServiceImpl) GetUsersByEmail(ctx context.Context, email string) (*model.Users, error) {
+	ctx, span := utils.Tracer().Start(ctx, "UsersService.GetUsersByEmail")
+	defer span.End()
+
+	return s.Repo.GetUsersByEmail(ctx, email)
+}
+
+func (s *usersServiceImpl) GetAllUsers(ctx context.Context, includeDeleted bool) ([]model.Users, error) {
+	ctx, span := utils.Tracer().Start(ctx, "UsersService.GetAllUsers")
+	defer span.End()
+
+	return s.Repo.GetAllUsers(ctx, includeDeleted)
+}
+
+func (s *usersServiceImpl) GetUsersByID(ctx context.Context, id int) (model.Users, error) {
+	ctx, span := utils.Tracer().Start(ctx, "UsersService.GetUsersByID")
+	defer span.End()
+
+	return s.Repo.GetUsersByID(ctx, id)
 }
 
-func (s *usersServiceImpl) GetAllUsers() ([]model.Users, error) {
-	return s.Repo.GetAllUsers()
+func (s *usersServiceImpl) CreateUsers(ctx context.Context, data *model.Users) error {
+	ctx, span := utils.Tracer().Start(ctx, "UsersService.CreateUsers")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Repo.CreateUsers(ctx, data); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditCreate, "user", data.Id, nil, data)
+	})
 }
 
-func (s *usersServiceImpl) GetUsersByID(id int) (model.Users, error) {
-	return s.Repo.GetUsersByID(id)
+func (s *usersServiceImpl) UpdateUsers(ctx context.Context, id int, data *model.Users) error {
+	ctx, span := utils.Tracer().Start(ctx, "UsersService.UpdateUsers")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetUsersByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.UpdateUsers(ctx, id, data); err != nil {
+			return err
+		}
+
+		after, err := s.Repo.GetUsersByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		*data = after
+		return s.Audit.Record(ctx, model.AuditUpdate, "user", id, before, after)
+	})
 }
 
-func (s *usersServiceImpl) CreateUsers(data *model.Users) error {
-	return s.Repo.CreateUsers(data)
+func (s *usersServiceImpl) PatchUsers(ctx context.Context, id, version int, changes map[string]any) (*model.Users, error) {
+	ctx, span := utils.Tracer().Start(ctx, "UsersService.PatchUsers")
+	defer span.End()
+
+	var patched *model.Users
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetUsersByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		patched, err = s.Repo.PatchUsers(ctx, id, version, changes)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditUpdate, "user", id, before, patched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
 }
 
-func (s *usersServiceImpl) UpdateUsers(id int, data *model.Users) error {
-	return s.Repo.UpdateUsers(id, data)
+func (s *usersServiceImpl) DeleteUsers(ctx context.Context, id int) error {
+	ctx, span := utils.Tracer().Start(ctx, "UsersService.DeleteUsers")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetUsersByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.DeleteUsers(ctx, id); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditDelete, "user", id, before, nil)
+	})
 }
 
-func (s *usersServiceImpl) DeleteUsers(id int) error {
-	return s.Repo.DeleteUsers(id)
-}
\ No newline at end of file
+func (s *usersServiceImpl) RestoreUsers(ctx context.Context, id int) (*model.Users, error) {
+	ctx, span := utils.Tracer().Start(ctx, "UsersService.RestoreUsers")
+	defer span.End()
+
+	var restored *model.Users
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		restored, err = s.Repo.RestoreUsers(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditRestore, "user", id, nil, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"testing"
@@ -15,7 +16,7 @@ type MockUsersRepository struct {
 	mock.Mock
 }
 
-func (m *MockUsersRepository) GetUsersByEmail(email string) (*model.Users, error) {
+func (m *MockUsersRepository) GetUsersByEmail(ctx context.Context, email string) (*model.Users, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -23,34 +24,66 @@ func (m *MockUsersRepository) GetUsersByEmail(email string) (*model.Users, error
 	return args.Get(0).(*model.Users), args.Error(1)
 }
 
-func (m *MockUsersRepository) CreateUsers(data *model.Users) error {
+func (m *MockUsersRepository) CreateUsers(ctx context.Context, data *model.Users) error {
 	args := m.Called(data)
 	return args.Error(0)
 }
 
-func (m *MockUsersRepository) GetAllUsers() ([]model.Users, error) {
-	args := m.Called()
+func (m *MockUsersRepository) GetAllUsers(ctx context.Context, includeDeleted bool) ([]model.Users, error) {
+	args := m.Called(includeDeleted)
 	return args.Get(0).([]model.Users), args.Error(1)
 }
 
-func (m *MockUsersRepository) GetUsersByID(id int) (model.Users, error) {
+func (m *MockUsersRepository) GetUsersByID(ctx context.Context, id int) (model.Users, error) {
 	args := m.Called(id)
 	return args.Get(0).(model.Users), args.Error(1)
 }
 
-func (m *MockUsersRepository) UpdateUsers(id int, data *model.Users) error {
+func (m *MockUsersRepository) UpdateUsers(ctx context.Context, id int, data *model.Users) error {
 	args := m.Called(id, data)
 	return args.Error(0)
 }
 
-func (m *MockUsersRepository) DeleteUsers(id int) error {
+func (m *MockUsersRepository) PatchUsers(ctx context.Context, id, version int, changes map[string]any) (*model.Users, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Users), args.Error(1)
+}
+
+func (m *MockUsersRepository) DeleteUsers(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUsersRepository) RestoreUsers(ctx context.Context, id int) (*model.Users, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Users), args.Error(1)
+}
+
+func (m *MockUsersRepository) UpdatePassword(ctx context.Context, id int, hashedPassword string) error {
+	args := m.Called(id, hashedPassword)
+	return args.Error(0)
+}
+
+func (m *MockUsersRepository) RecordLoginFailure(ctx context.Context, id int, now time.Time, maxAttempts int, lockUntil time.Time) (int, *time.Time, error) {
+	args := m.Called(id, now, maxAttempts, lockUntil)
+	lockedUntil, _ := args.Get(1).(*time.Time)
+	return args.Int(0), lockedUntil, args.Error(2)
+}
+
+func (m *MockUsersRepository) ResetLoginFailures(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 func TestUsersService_GetUsersByEmail_Success(t *testing.T) {
 	mockRepo := new(MockUsersRepository)
-	service := NewUsersService(mockRepo)
+	service := NewUsersService(mockRepo, &fakeAuditor{})
 
 	now := time.Now()
 	expected := &model.Users{
@@ -65,7 +98,7 @@ func TestUsersService_GetUsersByEmail_Success(t *testing.T) {
 
 	mockRepo.On("GetUsersByEmail", "test@example.com").Return(expected, nil)
 
-	result, err := service.GetUsersByEmail("test@example.com")
+	result, err := service.GetUsersByEmail(context.Background(), "test@example.com")
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -74,11 +107,11 @@ func TestUsersService_GetUsersByEmail_Success(t *testing.T) {
 
 func TestUsersService_GetUsersByEmail_NotFound(t *testing.T) {
 	mockRepo := new(MockUsersRepository)
-	service := NewUsersService(mockRepo)
+	service := NewUsersService(mockRepo, &fakeAuditor{})
 
 	mockRepo.On("GetUsersByEmail", "notfound@example.com").Return(nil, errors.New("user not found"))
 
-	result, err := service.GetUsersByEmail("notfound@example.com")
+	result, err := service.GetUsersByEmail(context.Background(), "notfound@example.com")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -87,7 +120,7 @@ func TestUsersService_GetUsersByEmail_NotFound(t *testing.T) {
 
 func TestUsersService_GetAllUsers_Success(t *testing.T) {
 	mockRepo := new(MockUsersRepository)
-	service := NewUsersService(mockRepo)
+	service := NewUsersService(mockRepo, &fakeAuditor{})
 
 	now := time.Now()
 	users := []model.Users{
@@ -95,9 +128,9 @@ func TestUsersService_GetAllUsers_Success(t *testing.T) {
 		{Id: 2, Username: "user2", Email: "user2@example.com", Role: "user", CreatedAt: now, UpdatedAt: now},
 	}
 
-	mockRepo.On("GetAllUsers").Return(users, nil)
+	mockRepo.On("GetAllUsers", false).Return(users, nil)
 
-	result, err := service.GetAllUsers()
+	result, err := service.GetAllUsers(context.Background(), false)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
@@ -106,7 +139,7 @@ func TestUsersService_GetAllUsers_Success(t *testing.T) {
 
 func TestUsersService_GetUsersByID_Success(t *testing.T) {
 	mockRepo := new(MockUsersRepository)
-	service := NewUsersService(mockRepo)
+	service := NewUsersService(mockRepo, &fakeAuditor{})
 
 	now := time.Now()
 	expectedUser := model.Users{
@@ -121,7 +154,7 @@ func TestUsersService_GetUsersByID_Success(t *testing.T) {
 
 	mockRepo.On("GetUsersByID", 1).Return(expectedUser, nil)
 
-	result, err := service.GetUsersByID(1)
+	result, err := service.GetUsersByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser.Username, result.Username)
@@ -130,7 +163,7 @@ func TestUsersService_GetUsersByID_Success(t *testing.T) {
 
 func TestUsersService_CreateUsers_Success(t *testing.T) {
 	mockRepo := new(MockUsersRepository)
-	service := NewUsersService(mockRepo)
+	service := NewUsersService(mockRepo, &fakeAuditor{})
 
 	user := &model.Users{
 		Username: "newuser",
@@ -141,7 +174,7 @@ func TestUsersService_CreateUsers_Success(t *testing.T) {
 
 	mockRepo.On("CreateUsers", user).Return(nil)
 
-	err := service.CreateUsers(user)
+	err := service.CreateUsers(context.Background(), user)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -149,30 +182,40 @@ func TestUsersService_CreateUsers_Success(t *testing.T) {
 
 func TestUsersService_UpdateUsers_Success(t *testing.T) {
 	mockRepo := new(MockUsersRepository)
-	service := NewUsersService(mockRepo)
+	audit := &fakeAuditor{}
+	service := NewUsersService(mockRepo, audit)
 
 	user := &model.Users{
 		Username: "updateduser",
 		Email:    "updated@example.com",
-		Password: "newpassword",
 		Role:     "admin",
 	}
 
+	mockRepo.On("GetUsersByID", 1).Return(model.Users{Id: 1, Username: "olduser", Password: "the-hash", Version: 1}, nil).Once()
 	mockRepo.On("UpdateUsers", 1, user).Return(nil)
+	mockRepo.On("GetUsersByID", 1).Return(model.Users{Id: 1, Username: "updateduser", Password: "the-hash", Version: 2}, nil).Once()
 
-	err := service.UpdateUsers(1, user)
+	err := service.UpdateUsers(context.Background(), 1, user)
 
 	assert.NoError(t, err)
+	assert.Equal(t, 2, user.Version)
+	if assert.Len(t, audit.entries, 1) {
+		changes, err := auditDiff(audit.entries[0].Before, audit.entries[0].After)
+		assert.NoError(t, err)
+		assert.Contains(t, string(changes), `"username":{"before":"olduser","after":"updateduser"}`)
+		assert.NotContains(t, string(changes), "the-hash")
+	}
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUsersService_DeleteUsers_Success(t *testing.T) {
 	mockRepo := new(MockUsersRepository)
-	service := NewUsersService(mockRepo)
+	service := NewUsersService(mockRepo, &fakeAuditor{})
 
+	mockRepo.On("GetUsersByID", 1).Return(model.Users{Id: 1}, nil)
 	mockRepo.On("DeleteUsers", 1).Return(nil)
 
-	err := service.DeleteUsers(1)
+	err := service.DeleteUsers(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -180,11 +223,11 @@ func TestUsersService_DeleteUsers_Success(t *testing.T) {
 
 func TestUsersService_DeleteUsers_Error(t *testing.T) {
 	mockRepo := new(MockUsersRepository)
-	service := NewUsersService(mockRepo)
+	service := NewUsersService(mockRepo, &fakeAuditor{})
 
-	mockRepo.On("DeleteUsers", 999).Return(errors.New("user not found"))
+	mockRepo.On("GetUsersByID", 999).Return(model.Users{}, errors.New("user not found"))
 
-	err := service.DeleteUsers(999)
+	err := service.DeleteUsers(context.Background(), 999)
 
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
@@ -1,31 +1,42 @@
 package service
 
 import (
+	"context"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"project-app-inventory-restapi-golang-azwin/repository"
+	"project-app-inventory-restapi-golang-azwin/utils"
 )
 
 type WarehousesService interface {
-	GetWarehousesById(id int) (*model.Warehouses, error)
-	GetAllWarehouses(page, limit int) ([]model.Warehouses, int, error)
-	CreateWarehouses(data *model.Warehouses) error
-	UpdateWarehouses(id int, data *model.Warehouses) error
-	DeleteWarehouses(id int) error
+	GetWarehousesById(ctx context.Context, id int) (*model.Warehouses, error)
+	GetAllWarehouses(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Warehouses, int, error)
+	CreateWarehouses(ctx context.Context, data *model.Warehouses) error
+	UpdateWarehouses(ctx context.Context, id int, data *model.Warehouses) error
+	PatchWarehouses(ctx context.Context, id, version int, changes map[string]any) (*model.Warehouses, error)
+	DeleteWarehouses(ctx context.Context, id int) error
+	RestoreWarehouses(ctx context.Context, id int) (*model.Warehouses, error)
 }
 
 type warehousesService struct {
-	Repo repository.WarehousesRepository
+	Repo  repository.WarehousesRepository
+	Audit Auditor
 }
 
-func NewWarehousesService(repo repository.WarehousesRepository) WarehousesService {
-	return &warehousesService{Repo: repo}
+func NewWarehousesService(repo repository.WarehousesRepository, audit Auditor) WarehousesService {
+	return &warehousesService{Repo: repo, Audit: audit}
 }
 
-func (s *warehousesService) GetWarehousesById(id int) (*model.Warehouses, error) {
-	return s.Repo.GetWarehousesById(id)
+func (s *warehousesService) GetWarehousesById(ctx context.Context, id int) (*model.Warehouses, error) {
+	ctx, span := utils.Tracer().Start(ctx, "WarehousesService.GetWarehousesById")
+	defer span.End()
+
+	return s.Repo.GetWarehousesById(ctx, id)
 }
 
-func (s *warehousesService) GetAllWarehouses(page, limit int) ([]model.Warehouses, int, error) {
+func (s *warehousesService) GetAllWarehouses(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Warehouses, int, error) {
+	ctx, span := utils.Tracer().Start(ctx, "WarehousesService.GetAllWarehouses")
+	defer span.End()
+
 	// Validate pagination parameters
 	if page < 1 {
 		page = 1
@@ -37,17 +48,97 @@ func (s *warehousesService) GetAllWarehouses(page, limit int) ([]model.Warehouse
 		limit = 100
 	}
 	
-	return s.Repo.GetAllWarehouses(page, limit)
+	return s.Repo.GetAllWarehouses(ctx, page, limit, includeDeleted)
 }
 
-func (s *warehousesService) CreateWarehouses(data *model.Warehouses) error {
-	return s.Repo.CreateWarehouses(data)
+func (s *warehousesService) CreateWarehouses(ctx context.Context, data *model.Warehouses) error {
+	ctx, span := utils.Tracer().Start(ctx, "WarehousesService.CreateWarehouses")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Repo.CreateWarehouses(ctx, data); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditCreate, "warehouse", data.Id, nil, data)
+	})
 }
 
-func (s *warehousesService) UpdateWarehouses(id int, data *model.Warehouses) error {
-	return s.Repo.UpdateWarehouses(id, data)
+func (s *warehousesService) UpdateWarehouses(ctx context.Context, id int, data *model.Warehouses) error {
+	ctx, span := utils.Tracer().Start(ctx, "WarehousesService.UpdateWarehouses")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetWarehousesById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.UpdateWarehouses(ctx, id, data); err != nil {
+			return err
+		}
+
+		// baca ulang agar response dan audit berisi data yang benar-benar tersimpan
+		after, err := s.Repo.GetWarehousesById(ctx, id)
+		if err != nil {
+			return err
+		}
+		*data = *after
+		return s.Audit.Record(ctx, model.AuditUpdate, "warehouse", id, before, after)
+	})
 }
 
-func (s *warehousesService) DeleteWarehouses(id int) error {
-	return s.Repo.DeleteWarehouses(id)
+func (s *warehousesService) PatchWarehouses(ctx context.Context, id, version int, changes map[string]any) (*model.Warehouses, error) {
+	ctx, span := utils.Tracer().Start(ctx, "WarehousesService.PatchWarehouses")
+	defer span.End()
+
+	var patched *model.Warehouses
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetWarehousesById(ctx, id)
+		if err != nil {
+			return err
+		}
+		patched, err = s.Repo.PatchWarehouses(ctx, id, version, changes)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditUpdate, "warehouse", id, before, patched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
 }
+
+func (s *warehousesService) DeleteWarehouses(ctx context.Context, id int) error {
+	ctx, span := utils.Tracer().Start(ctx, "WarehousesService.DeleteWarehouses")
+	defer span.End()
+
+	return s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.Repo.GetWarehousesById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.DeleteWarehouses(ctx, id); err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditDelete, "warehouse", id, before, nil)
+	})
+}
+
+func (s *warehousesService) RestoreWarehouses(ctx context.Context, id int) (*model.Warehouses, error) {
+	ctx, span := utils.Tracer().Start(ctx, "WarehousesService.RestoreWarehouses")
+	defer span.End()
+
+	var restored *model.Warehouses
+	err := s.Audit.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		restored, err = s.Repo.RestoreWarehouses(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Audit.Record(ctx, model.AuditRestore, "warehouse", id, nil, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
\ No newline at end of file
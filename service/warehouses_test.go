@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"project-app-inventory-restapi-golang-azwin/model"
 	"testing"
@@ -15,7 +16,7 @@ type MockWarehousesRepository struct {
 	mock.Mock
 }
 
-func (m *MockWarehousesRepository) GetWarehousesById(id int) (*model.Warehouses, error) {
+func (m *MockWarehousesRepository) GetWarehousesById(ctx context.Context, id int) (*model.Warehouses, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -23,29 +24,45 @@ func (m *MockWarehousesRepository) GetWarehousesById(id int) (*model.Warehouses,
 	return args.Get(0).(*model.Warehouses), args.Error(1)
 }
 
-func (m *MockWarehousesRepository) GetAllWarehouses(page, limit int) ([]model.Warehouses, int, error) {
-	args := m.Called(page, limit)
+func (m *MockWarehousesRepository) GetAllWarehouses(ctx context.Context, page, limit int, includeDeleted bool) ([]model.Warehouses, int, error) {
+	args := m.Called(page, limit, includeDeleted)
 	return args.Get(0).([]model.Warehouses), args.Int(1), args.Error(2)
 }
 
-func (m *MockWarehousesRepository) CreateWarehouses(data *model.Warehouses) error {
+func (m *MockWarehousesRepository) CreateWarehouses(ctx context.Context, data *model.Warehouses) error {
 	args := m.Called(data)
 	return args.Error(0)
 }
 
-func (m *MockWarehousesRepository) UpdateWarehouses(id int, data *model.Warehouses) error {
+func (m *MockWarehousesRepository) UpdateWarehouses(ctx context.Context, id int, data *model.Warehouses) error {
 	args := m.Called(id, data)
 	return args.Error(0)
 }
 
-func (m *MockWarehousesRepository) DeleteWarehouses(id int) error {
+func (m *MockWarehousesRepository) PatchWarehouses(ctx context.Context, id, version int, changes map[string]any) (*model.Warehouses, error) {
+	args := m.Called(id, version, changes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Warehouses), args.Error(1)
+}
+
+func (m *MockWarehousesRepository) DeleteWarehouses(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockWarehousesRepository) RestoreWarehouses(ctx context.Context, id int) (*model.Warehouses, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Warehouses), args.Error(1)
+}
+
 func TestWarehousesService_GetWarehousesById_Success(t *testing.T) {
 	mockRepo := new(MockWarehousesRepository)
-	service := NewWarehousesService(mockRepo)
+	service := NewWarehousesService(mockRepo, &fakeAuditor{})
 
 	now := time.Now()
 	expected := &model.Warehouses{
@@ -58,7 +75,7 @@ func TestWarehousesService_GetWarehousesById_Success(t *testing.T) {
 
 	mockRepo.On("GetWarehousesById", 1).Return(expected, nil)
 
-	result, err := service.GetWarehousesById(1)
+	result, err := service.GetWarehousesById(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -67,7 +84,7 @@ func TestWarehousesService_GetWarehousesById_Success(t *testing.T) {
 
 func TestWarehousesService_GetAllWarehouses_Success(t *testing.T) {
 	mockRepo := new(MockWarehousesRepository)
-	service := NewWarehousesService(mockRepo)
+	service := NewWarehousesService(mockRepo, &fakeAuditor{})
 
 	now := time.Now()
 	warehouses := []model.Warehouses{
@@ -75,9 +92,9 @@ func TestWarehousesService_GetAllWarehouses_Success(t *testing.T) {
 		{Id: 2, Name: "Secondary Warehouse", Location: "Bandung", CreatedAt: now, UpdatedAt: now},
 	}
 
-	mockRepo.On("GetAllWarehouses", 1, 10).Return(warehouses, 2, nil)
+	mockRepo.On("GetAllWarehouses", 1, 10, false).Return(warehouses, 2, nil)
 
-	result, total, err := service.GetAllWarehouses(1, 10)
+	result, total, err := service.GetAllWarehouses(context.Background(), 1, 10, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, total)
@@ -87,12 +104,12 @@ func TestWarehousesService_GetAllWarehouses_Success(t *testing.T) {
 
 func TestWarehousesService_GetAllWarehouses_ValidationPage(t *testing.T) {
 	mockRepo := new(MockWarehousesRepository)
-	service := NewWarehousesService(mockRepo)
+	service := NewWarehousesService(mockRepo, &fakeAuditor{})
 
 	warehouses := []model.Warehouses{}
-	mockRepo.On("GetAllWarehouses", 1, 10).Return(warehouses, 0, nil)
+	mockRepo.On("GetAllWarehouses", 1, 10, false).Return(warehouses, 0, nil)
 
-	result, total, err := service.GetAllWarehouses(0, 10)
+	result, total, err := service.GetAllWarehouses(context.Background(), 0, 10, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, total)
@@ -102,12 +119,12 @@ func TestWarehousesService_GetAllWarehouses_ValidationPage(t *testing.T) {
 
 func TestWarehousesService_GetAllWarehouses_ValidationLimit(t *testing.T) {
 	mockRepo := new(MockWarehousesRepository)
-	service := NewWarehousesService(mockRepo)
+	service := NewWarehousesService(mockRepo, &fakeAuditor{})
 
 	warehouses := []model.Warehouses{}
-	mockRepo.On("GetAllWarehouses", 1, 100).Return(warehouses, 0, nil)
+	mockRepo.On("GetAllWarehouses", 1, 100, false).Return(warehouses, 0, nil)
 
-	result, total, err := service.GetAllWarehouses(1, 150)
+	result, total, err := service.GetAllWarehouses(context.Background(), 1, 150, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, total)
@@ -117,7 +134,7 @@ func TestWarehousesService_GetAllWarehouses_ValidationLimit(t *testing.T) {
 
 func TestWarehousesService_CreateWarehouses_Success(t *testing.T) {
 	mockRepo := new(MockWarehousesRepository)
-	service := NewWarehousesService(mockRepo)
+	service := NewWarehousesService(mockRepo, &fakeAuditor{})
 
 	warehouse := &model.Warehouses{
 		Name:     "New Warehouse",
@@ -126,7 +143,7 @@ func TestWarehousesService_CreateWarehouses_Success(t *testing.T) {
 
 	mockRepo.On("CreateWarehouses", warehouse).Return(nil)
 
-	err := service.CreateWarehouses(warehouse)
+	err := service.CreateWarehouses(context.Background(), warehouse)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -134,16 +151,18 @@ func TestWarehousesService_CreateWarehouses_Success(t *testing.T) {
 
 func TestWarehousesService_UpdateWarehouses_Success(t *testing.T) {
 	mockRepo := new(MockWarehousesRepository)
-	service := NewWarehousesService(mockRepo)
+	service := NewWarehousesService(mockRepo, &fakeAuditor{})
 
 	warehouse := &model.Warehouses{
 		Name:     "Updated Warehouse",
 		Location: "Medan",
 	}
 
+	mockRepo.On("GetWarehousesById", 1).Return(&model.Warehouses{Id: 1, Version: 1}, nil).Once()
 	mockRepo.On("UpdateWarehouses", 1, warehouse).Return(nil)
+	mockRepo.On("GetWarehousesById", 1).Return(&model.Warehouses{Id: 1, Version: 2}, nil).Once()
 
-	err := service.UpdateWarehouses(1, warehouse)
+	err := service.UpdateWarehouses(context.Background(), 1, warehouse)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -151,11 +170,12 @@ func TestWarehousesService_UpdateWarehouses_Success(t *testing.T) {
 
 func TestWarehousesService_DeleteWarehouses_Success(t *testing.T) {
 	mockRepo := new(MockWarehousesRepository)
-	service := NewWarehousesService(mockRepo)
+	service := NewWarehousesService(mockRepo, &fakeAuditor{})
 
+	mockRepo.On("GetWarehousesById", 1).Return(&model.Warehouses{Id: 1}, nil)
 	mockRepo.On("DeleteWarehouses", 1).Return(nil)
 
-	err := service.DeleteWarehouses(1)
+	err := service.DeleteWarehouses(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -163,11 +183,12 @@ func TestWarehousesService_DeleteWarehouses_Success(t *testing.T) {
 
 func TestWarehousesService_DeleteWarehouses_Error(t *testing.T) {
 	mockRepo := new(MockWarehousesRepository)
-	service := NewWarehousesService(mockRepo)
+	service := NewWarehousesService(mockRepo, &fakeAuditor{})
 
+	mockRepo.On("GetWarehousesById", 999).Return(&model.Warehouses{Id: 999}, nil)
 	mockRepo.On("DeleteWarehouses", 999).Return(errors.New("warehouse not found"))
 
-	err := service.DeleteWarehouses(999)
+	err := service.DeleteWarehouses(context.Background(), 999)
 
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
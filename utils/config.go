@@ -2,6 +2,9 @@ package utils
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,6 +16,95 @@ type Configuration struct {
 	Limit       int
 	PathLogging string
 	DB          DatabaseCofig
+	Tracing     TracingConfig
+	SoftDelete  SoftDeleteConfig
+	Auth        AuthConfig
+	RateLimit   RateLimitConfig
+	Idempotency IdempotencyConfig
+	Invoice     InvoiceConfig
+	Receipt     ReceiptConfig
+	SaleHold    SaleHoldConfig
+	Reservation ReservationConfig
+}
+
+type TracingConfig struct {
+	Exporter     string // none, stdout, file, otlp
+	ServiceName  string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	FilePath     string
+	SampleRatio  float64
+}
+
+// SoftDeleteConfig mengatur purge job untuk data yang sudah di-soft delete
+type SoftDeleteConfig struct {
+	Retention     time.Duration // umur minimal data terhapus sebelum di-purge
+	PurgeInterval time.Duration // 0 = purge job tidak dijalankan
+}
+
+// IdempotencyConfig mengatur penyimpanan response untuk header Idempotency-Key
+type IdempotencyConfig struct {
+	TTL           time.Duration // lama key disimpan; retry setelahnya dianggap request baru
+	PurgeInterval time.Duration // 0 = key kedaluwarsa tidak dihapus otomatis
+}
+
+// InvoiceConfig mengatur format nomor invoice sale
+type InvoiceConfig struct {
+	Prefix       string // default INV
+	PerWarehouse bool   // true = seri nomor terpisah per gudang (sale wajib warehouse_id)
+}
+
+// SaleHoldConfig mengatur berapa lama sale held memesan stock dan seberapa
+// sering hold yang kedaluwarsa dilepas
+type SaleHoldConfig struct {
+	TTL           time.Duration
+	SweepInterval time.Duration // 0 = sweeper tidak dijalankan
+}
+
+// ReservationConfig mengatur masa berlaku default reservasi stock dan seberapa
+// sering reservasi yang kedaluwarsa dilepas
+type ReservationConfig struct {
+	TTL           time.Duration // dipakai jika request tidak mengirim expires_in_minutes
+	SweepInterval time.Duration // 0 = sweeper tidak dijalankan
+}
+
+// ReceiptConfig mengatur header/footer struk dan lebar kertas thermal
+type ReceiptConfig struct {
+	StoreName    string
+	StoreAddress string
+	StorePhone   string
+	Footer       string
+	Width        int // karakter per baris: 32 (58mm) atau 42/48 (80mm)
+}
+
+// AuthConfig mengatur session login, password policy dan reset password
+type AuthConfig struct {
+	SessionTTL       time.Duration
+	PasswordResetTTL time.Duration
+	PasswordPolicy   PasswordPolicy
+	Lockout          LockoutConfig
+	TwoFactor        TwoFactorConfig
+}
+
+// TwoFactorConfig mengatur TOTP: issuer di authenticator app, role yang wajib 2FA
+// dan masa berlaku challenge login
+type TwoFactorConfig struct {
+	Issuer        string
+	RequiredRoles []string
+	ChallengeTTL  time.Duration
+}
+
+// Requires bernilai true jika role wajib memakai 2FA
+func (c TwoFactorConfig) Requires(role string) bool {
+	return slices.Contains(c.RequiredRoles, role)
+}
+
+// LockoutConfig mengatur batas login gagal per akun dan per IP
+type LockoutConfig struct {
+	MaxAttempts   int           // login gagal berturut-turut sebelum akun dikunci
+	Duration      time.Duration // lama akun dikunci
+	IPMaxAttempts int           // login gagal dari satu IP dalam IPWindow
+	IPWindow      time.Duration
 }
 
 type DatabaseCofig struct {
@@ -58,6 +150,175 @@ func ReadConfigration() (*Configuration, error) {
 	dbName := viper.GetString("DATABASE_NAME")
 	maxConn := viper.GetInt32("DATABASE_MAX_CONN")
 
+	tracingExporter := viper.GetString("TRACING_EXPORTER")
+	tracingServiceName := viper.GetString("TRACING_SERVICE_NAME")
+	tracingFilePath := viper.GetString("TRACING_FILE_PATH")
+	tracingSampleRatio := viper.GetFloat64("TRACING_SAMPLE_RATIO")
+	if tracingExporter == "" {
+		tracingExporter = "none"
+	}
+	if tracingServiceName == "" {
+		tracingServiceName = "inventory-restapi"
+	}
+	if tracingFilePath == "" {
+		tracingFilePath = "./logs/traces.jsonl"
+	}
+	if !viper.IsSet("TRACING_SAMPLE_RATIO") {
+		tracingSampleRatio = 1
+	}
+
+	retentionDays := viper.GetInt("SOFT_DELETE_RETENTION_DAYS")
+	purgeInterval := viper.GetDuration("SOFT_DELETE_PURGE_INTERVAL")
+	if !viper.IsSet("SOFT_DELETE_RETENTION_DAYS") {
+		retentionDays = 30
+	}
+	if !viper.IsSet("SOFT_DELETE_PURGE_INTERVAL") {
+		purgeInterval = 24 * time.Hour
+	}
+
+	sessionTTL := viper.GetDuration("SESSION_TTL")
+	if sessionTTL <= 0 {
+		sessionTTL = 24 * time.Hour
+	}
+	passwordResetTTL := viper.GetDuration("PASSWORD_RESET_TTL")
+	if passwordResetTTL <= 0 {
+		passwordResetTTL = time.Hour
+	}
+
+	passwordPolicy := DefaultPasswordPolicy()
+	if viper.IsSet("PASSWORD_MIN_LENGTH") {
+		passwordPolicy.MinLength = viper.GetInt("PASSWORD_MIN_LENGTH")
+	}
+	if viper.IsSet("PASSWORD_REQUIRE_UPPER") {
+		passwordPolicy.RequireUpper = viper.GetBool("PASSWORD_REQUIRE_UPPER")
+	}
+	if viper.IsSet("PASSWORD_REQUIRE_LOWER") {
+		passwordPolicy.RequireLower = viper.GetBool("PASSWORD_REQUIRE_LOWER")
+	}
+	if viper.IsSet("PASSWORD_REQUIRE_DIGIT") {
+		passwordPolicy.RequireDigit = viper.GetBool("PASSWORD_REQUIRE_DIGIT")
+	}
+	if viper.IsSet("PASSWORD_REQUIRE_SYMBOL") {
+		passwordPolicy.RequireSymbol = viper.GetBool("PASSWORD_REQUIRE_SYMBOL")
+	}
+
+	lockout := LockoutConfig{
+		MaxAttempts:   viper.GetInt("LOGIN_MAX_ATTEMPTS"),
+		Duration:      viper.GetDuration("LOGIN_LOCKOUT_DURATION"),
+		IPMaxAttempts: viper.GetInt("LOGIN_IP_MAX_ATTEMPTS"),
+		IPWindow:      viper.GetDuration("LOGIN_IP_WINDOW"),
+	}
+	if lockout.MaxAttempts <= 0 {
+		lockout.MaxAttempts = 5
+	}
+	if lockout.Duration <= 0 {
+		lockout.Duration = 15 * time.Minute
+	}
+	if lockout.IPMaxAttempts <= 0 {
+		lockout.IPMaxAttempts = 20
+	}
+	if lockout.IPWindow <= 0 {
+		lockout.IPWindow = 15 * time.Minute
+	}
+
+	twoFactor := TwoFactorConfig{
+		Issuer:       viper.GetString("TOTP_ISSUER"),
+		ChallengeTTL: viper.GetDuration("TWO_FACTOR_CHALLENGE_TTL"),
+	}
+	if twoFactor.Issuer == "" {
+		twoFactor.Issuer = appName
+	}
+	if twoFactor.ChallengeTTL <= 0 {
+		twoFactor.ChallengeTTL = 5 * time.Minute
+	}
+	for _, role := range strings.Split(viper.GetString("TWO_FACTOR_REQUIRED_ROLES"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			twoFactor.RequiredRoles = append(twoFactor.RequiredRoles, role)
+		}
+	}
+
+	rateLimit := RateLimitConfig{Enabled: true, Default: RateLimitRule{Requests: 300, Per: time.Minute}}
+	if viper.IsSet("RATE_LIMIT_ENABLED") {
+		rateLimit.Enabled = viper.GetBool("RATE_LIMIT_ENABLED")
+	}
+	if value := viper.GetString("RATE_LIMIT_DEFAULT"); value != "" {
+		rule, err := ParseRateLimitRule(value)
+		if err != nil {
+			return nil, err
+		}
+		rateLimit.Default = rule
+	}
+	routeLimits := "GET /sales=60/1m"
+	if viper.IsSet("RATE_LIMIT_ROUTES") {
+		routeLimits = viper.GetString("RATE_LIMIT_ROUTES")
+	}
+	routes, err := ParseRouteRateLimits(routeLimits)
+	if err != nil {
+		return nil, err
+	}
+	rateLimit.Routes = routes
+
+	idempotency := IdempotencyConfig{
+		TTL:           viper.GetDuration("IDEMPOTENCY_TTL"),
+		PurgeInterval: viper.GetDuration("IDEMPOTENCY_PURGE_INTERVAL"),
+	}
+	if idempotency.TTL <= 0 {
+		idempotency.TTL = 24 * time.Hour
+	}
+	if !viper.IsSet("IDEMPOTENCY_PURGE_INTERVAL") {
+		idempotency.PurgeInterval = time.Hour
+	}
+
+	invoice := InvoiceConfig{
+		Prefix:       strings.TrimSpace(viper.GetString("INVOICE_PREFIX")),
+		PerWarehouse: viper.GetBool("INVOICE_PER_WAREHOUSE"),
+	}
+	if invoice.Prefix == "" {
+		invoice.Prefix = "INV"
+	}
+
+	receipt := ReceiptConfig{
+		StoreName:    viper.GetString("RECEIPT_STORE_NAME"),
+		StoreAddress: viper.GetString("RECEIPT_STORE_ADDRESS"),
+		StorePhone:   viper.GetString("RECEIPT_STORE_PHONE"),
+		Footer:       viper.GetString("RECEIPT_FOOTER"),
+		Width:        viper.GetInt("RECEIPT_WIDTH"),
+	}
+	if receipt.StoreName == "" {
+		receipt.StoreName = appName
+	}
+	if !viper.IsSet("RECEIPT_FOOTER") {
+		receipt.Footer = "Terima kasih"
+	}
+	if receipt.Width == 0 {
+		receipt.Width = 42
+	}
+	if receipt.Width < 24 || receipt.Width > 80 {
+		return nil, fmt.Errorf("RECEIPT_WIDTH must be between 24 and 80, got %d", receipt.Width)
+	}
+
+	saleHold := SaleHoldConfig{
+		TTL:           viper.GetDuration("SALE_HOLD_TTL"),
+		SweepInterval: viper.GetDuration("SALE_HOLD_SWEEP_INTERVAL"),
+	}
+	if saleHold.TTL <= 0 {
+		saleHold.TTL = 30 * time.Minute
+	}
+	if !viper.IsSet("SALE_HOLD_SWEEP_INTERVAL") {
+		saleHold.SweepInterval = time.Minute
+	}
+
+	reservation := ReservationConfig{
+		TTL:           viper.GetDuration("RESERVATION_TTL"),
+		SweepInterval: viper.GetDuration("RESERVATION_SWEEP_INTERVAL"),
+	}
+	if reservation.TTL <= 0 {
+		reservation.TTL = 15 * time.Minute
+	}
+	if !viper.IsSet("RESERVATION_SWEEP_INTERVAL") {
+		reservation.SweepInterval = time.Minute
+	}
+
 	return &Configuration{
 		AppName: appName,
 		Port:    port,
@@ -72,5 +333,30 @@ func ReadConfigration() (*Configuration, error) {
 			Port:     dbPort,
 			MaxConn:  maxConn,
 		},
+		Tracing: TracingConfig{
+			Exporter:     tracingExporter,
+			ServiceName:  tracingServiceName,
+			OTLPEndpoint: viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"),
+			OTLPInsecure: viper.GetBool("OTEL_EXPORTER_OTLP_INSECURE"),
+			FilePath:     tracingFilePath,
+			SampleRatio:  tracingSampleRatio,
+		},
+		SoftDelete: SoftDeleteConfig{
+			Retention:     time.Duration(retentionDays) * 24 * time.Hour,
+			PurgeInterval: purgeInterval,
+		},
+		Auth: AuthConfig{
+			SessionTTL:       sessionTTL,
+			PasswordResetTTL: passwordResetTTL,
+			PasswordPolicy:   passwordPolicy,
+			Lockout:          lockout,
+			TwoFactor:        twoFactor,
+		},
+		RateLimit:   rateLimit,
+		Idempotency: idempotency,
+		Invoice:     invoice,
+		Receipt:     receipt,
+		SaleHold:    saleHold,
+		Reservation: reservation,
 	}, nil
 }
\ No newline at end of file
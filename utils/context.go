@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"context"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// HeaderRequestID is the header used to propagate and echo the request id
+const HeaderRequestID = "X-Request-ID"
+
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request_id"
+	loggerKey    contextKey = "logger"
+)
+
+// WithRequestID stores the request id in the context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request id, or an empty string outside a request
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithLogger stores a request-scoped logger in the context
+func WithLogger(ctx context.Context, log *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, log)
+}
+
+// LoggerFromContext returns the request-scoped logger, falling back to the given
+// logger (or the global one when nil) when the context carries none
+func LoggerFromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if log, ok := ctx.Value(loggerKey).(*zap.Logger); ok && log != nil {
+		return log
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return zap.L()
+}
+
+// Actor adalah user yang sedang memanggil API (diisi middleware Authentication)
+type Actor struct {
+	UserId    int
+	Username  string
+	Role      string
+	SessionId int
+	// TwoFactorPending: role wajib 2FA tapi user belum enroll
+	TwoFactorPending bool
+	// diisi jika request memakai API key (bukan session)
+	APIKeyId int
+	Scopes   []string
+}
+
+// HasScope hanya relevan untuk API key; session login tidak dibatasi scope
+func (a Actor) HasScope(scope string) bool {
+	return a.APIKeyId == 0 || slices.Contains(a.Scopes, scope)
+}
+
+const (
+	actorKey    contextKey = "actor"
+	clientIPKey contextKey = "client_ip"
+)
+
+// WithActor stores the authenticated caller in the context
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext returns the authenticated caller; ok is false for anonymous requests
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey).(Actor)
+	return actor, ok
+}
+
+// WithClientIP stores the caller's IP address in the context
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+// ClientIPFromContext returns the caller's IP address, or an empty string outside a request
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey).(string)
+	return ip
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"project-app-inventory-restapi-golang-azwin/apperror"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// ETag membentuk entity tag dari kolom version, misal version 3 -> "3"
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func SetETag(w http.ResponseWriter, version int) {
+	w.Header().Set(HeaderETag, ETag(version))
+}
+
+// NotModified men-set ETag dan menjawab 304 jika If-None-Match cocok dengan versi saat ini.
+// Mengembalikan true jika response sudah ditulis.
+func NotModified(w http.ResponseWriter, r *http.Request, version int) bool {
+	SetETag(w, version)
+
+	header := r.Header.Get(HeaderIfNoneMatch)
+	if header == "" {
+		return false
+	}
+
+	current := ETag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatchVersion membaca versi yang diharapkan dari header If-Match.
+// "*" dikembalikan sebagai 0 (update tanpa cek versi).
+func IfMatchVersion(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get(HeaderIfMatch))
+	if header == "" {
+		return 0, apperror.PreconditionRequired()
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, apperror.Validation("If-Match must be a single quoted entity tag", nil)
+	}
+
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version < 1 {
+		return 0, apperror.Validation("If-Match must be a single quoted entity tag", nil)
+	}
+	return version, nil
+}
@@ -1,13 +1,94 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"fmt"
+	"sync"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/crypto/bcrypt"
+)
 
 func CheckPassword(inputPassword, storedPassword string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(storedPassword), []byte(inputPassword))
 	return err == nil
 }
 
-func HashPassword(password string) string {
-	bytes, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes)
+func HashPassword(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	return string(bytes), nil
+}
+
+// PasswordPolicy adalah aturan password baru, dicek lewat tag validate:"password"
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// DefaultPasswordPolicy dipakai jika env PASSWORD_* tidak diisi
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{MinLength: 8, RequireUpper: true, RequireLower: true, RequireDigit: true}
+}
+
+var (
+	passwordPolicyMu sync.RWMutex
+	passwordPolicy   = DefaultPasswordPolicy()
+)
+
+// SetPasswordPolicy mengganti policy yang dipakai validator (dipanggil saat startup)
+func SetPasswordPolicy(policy PasswordPolicy) {
+	passwordPolicyMu.Lock()
+	defer passwordPolicyMu.Unlock()
+	passwordPolicy = policy
+}
+
+func CurrentPasswordPolicy() PasswordPolicy {
+	passwordPolicyMu.RLock()
+	defer passwordPolicyMu.RUnlock()
+	return passwordPolicy
+}
+
+// Check mengembalikan daftar aturan yang belum terpenuhi (kosong berarti valid)
+func (p PasswordPolicy) Check(password string) []string {
+	var upper, lower, digit, symbol bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsDigit(c):
+			digit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c):
+			symbol = true
+		}
+	}
+
+	var failed []string
+	if len([]rune(password)) < p.MinLength {
+		failed = append(failed, fmt.Sprintf("at least %d characters", p.MinLength))
+	}
+	if p.RequireUpper && !upper {
+		failed = append(failed, "an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		failed = append(failed, "a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		failed = append(failed, "a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		failed = append(failed, "a symbol")
+	}
+	return failed
+}
+
+func validatePasswordPolicy(fl validator.FieldLevel) bool {
+	return len(CurrentPasswordPolicy().Check(fl.Field().String())) == 0
 }
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"reflect"
+	"strings"
+)
+
+// MergePatch adalah hasil decode body JSON Merge Patch (RFC 7396)
+type MergePatch struct {
+	// Values berisi nilai yang dikirim client, dikunci nama json (= nama kolom)
+	Values map[string]any
+	// Fields berisi nama field struct untuk validasi parsial
+	Fields []string
+}
+
+// DecodeMergePatch decode body merge patch ke dst (pointer ke struct dto) dan
+// mencatat field mana saja yang dikirim. Nilai null (hapus field pada RFC 7396)
+// hanya diterima untuk field pointer (kolom nullable) dan di-set ke NULL;
+// field lain NOT NULL sehingga null ditolak.
+func DecodeMergePatch(body io.Reader, dst any) (*MergePatch, error) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil || members == nil {
+		return nil, errors.New("patch body must be a JSON object")
+	}
+	if len(members) == 0 {
+		return nil, errors.New("patch body must contain at least one field")
+	}
+
+	target := reflect.ValueOf(dst).Elem()
+	fieldsByJSON := jsonFieldIndex(target.Type())
+
+	patch := &MergePatch{Values: map[string]any{}}
+	for name, value := range members {
+		index, ok := fieldsByJSON[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %s", name)
+		}
+		field := target.Field(index)
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			if field.Kind() != reflect.Pointer {
+				return nil, fmt.Errorf("field %s cannot be null", name)
+			}
+			patch.Values[name] = nil
+			patch.Fields = append(patch.Fields, target.Type().Field(index).Name)
+			continue
+		}
+
+		if err := json.Unmarshal(value, field.Addr().Interface()); err != nil {
+			// presisi desimal berlebih dijawab sebagai validation error (ResponseDecodeError)
+			var places *model.DecimalPlacesError
+			if errors.As(err, &places) {
+				return nil, places
+			}
+			return nil, fmt.Errorf("invalid value for field %s", name)
+		}
+
+		patch.Values[name] = field.Interface()
+		patch.Fields = append(patch.Fields, target.Type().Field(index).Name)
+	}
+
+	return patch, nil
+}
+
+// jsonFieldIndex memetakan nama json ke index field struct
+func jsonFieldIndex(t reflect.Type) map[string]int {
+	index := map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		index[name] = i
+	}
+	return index
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitRule: Requests request per Per. Dipakai sebagai token bucket dengan
+// kapasitas Requests yang terisi ulang rata selama Per.
+type RateLimitRule struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (r RateLimitRule) String() string {
+	return fmt.Sprintf("%d/%s", r.Requests, r.Per)
+}
+
+// RouteRateLimit berlaku untuk method + path prefix, misal "GET /sales"
+type RouteRateLimit struct {
+	Method     string
+	PathPrefix string
+	Rule       RateLimitRule
+}
+
+// RateLimitConfig mengatur rate limiter per client (API key, user atau IP)
+type RateLimitConfig struct {
+	Enabled bool
+	Default RateLimitRule
+	Routes  []RouteRateLimit
+}
+
+// ParseRateLimitRule membaca format "<requests>/<duration>", misal "100/1m"
+func ParseRateLimitRule(value string) (RateLimitRule, error) {
+	requests, per, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return RateLimitRule{}, fmt.Errorf("invalid rate limit %q, expected <requests>/<duration>", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 1 {
+		return RateLimitRule{}, fmt.Errorf("invalid rate limit %q: requests must be a positive number", value)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(per))
+	if err != nil || d <= 0 {
+		return RateLimitRule{}, fmt.Errorf("invalid rate limit %q: duration must be positive", value)
+	}
+	return RateLimitRule{Requests: n, Per: d}, nil
+}
+
+// ParseRouteRateLimits membaca daftar "METHOD /path=<requests>/<duration>" dipisah ";",
+// misal "GET /sales=60/1m;POST /sales=30/1m". Method "*" berlaku untuk semua method.
+func ParseRouteRateLimits(value string) ([]RouteRateLimit, error) {
+	var routes []RouteRateLimit
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, limit, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid route rate limit %q, expected METHOD /path=<requests>/<duration>", entry)
+		}
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		path = strings.TrimSpace(path)
+		if !ok || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid route rate limit %q, expected METHOD /path=<requests>/<duration>", entry)
+		}
+		rule, err := ParseRateLimitRule(limit)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, RouteRateLimit{Method: strings.ToUpper(method), PathPrefix: path, Rule: rule})
+	}
+	return routes, nil
+}
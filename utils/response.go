@@ -2,48 +2,148 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"reflect"
+	"strconv"
+	"time"
 
+	"project-app-inventory-restapi-golang-azwin/apperror"
 	"project-app-inventory-restapi-golang-azwin/dto"
+	"project-app-inventory-restapi-golang-azwin/model"
+
+	"go.uber.org/zap"
 )
 
-type Reponse struct {
-	Status  bool   `json:"status"`
-	Message string `json:"message"`
-	Data    any    `json:"data,omitempty"`
-	Errors  any    `json:"errors,omitempty"`
+// Response adalah satu-satunya bentuk body JSON yang dikirim API:
+//
+//	success   : {"status": true,  "message": "...", "data": {...}}
+//	paginated : {"status": true,  "message": "...", "data": [...], "pagination": {...}}
+//	error     : {"status": false, "message": "...", "error": {"code": "...", "details": ...}, "request_id": "..."}
+type Response struct {
+	Status     bool            `json:"status"`
+	Message    string          `json:"message"`
+	Data       any             `json:"data,omitempty"`
+	Pagination *dto.Pagination `json:"pagination,omitempty"`
+	Error      *ErrorBody      `json:"error,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+}
+
+// ErrorBody berisi kode machine-readable dan detail (misal field validasi)
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Details any    `json:"details,omitempty"`
+}
+
+// Kode error untuk response yang tidak berasal dari apperror
+const (
+	CodeBadRequest      = "bad_request"
+	CodeValidationError = "validation_error"
+)
+
+func writeJSON(w http.ResponseWriter, code int, response Response) {
+	// request id sudah di-set di header oleh middleware RequestID
+	response.RequestID = w.Header().Get(HeaderRequestID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(response)
 }
 
 func ResponseSuccess(w http.ResponseWriter, code int, message string, data any) {
-	response := Reponse{
+	writeJSON(w, code, Response{
 		Status:  true,
 		Message: message,
 		Data:    data,
-	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(response)
+	})
+}
+
+func ResponsePagination(w http.ResponseWriter, code int, message string, data any, pagination dto.Pagination) {
+	writeJSON(w, code, Response{
+		Status:     true,
+		Message:    message,
+		Data:       emptySliceIfNil(data),
+		Pagination: &pagination,
+	})
 }
 
+// ResponseBadRequest dipakai untuk request yang tidak bisa diproses (body/param salah).
+// errors berisi detail tambahan, misal []FieldError dari ValidateErrors.
 func ResponseBadRequest(w http.ResponseWriter, code int, message string, errors any) {
-	response := Reponse{
+	errorCode := CodeBadRequest
+	if _, ok := errors.([]FieldError); ok {
+		errorCode = CodeValidationError
+	}
+
+	writeJSON(w, code, Response{
+		Status:  false,
+		Message: message,
+		Error: &ErrorBody{
+			Code:    errorCode,
+			Details: errors,
+		},
+	})
+}
+
+// ResponseDecodeError menjawab body JSON yang gagal di-decode. Angka dengan lebih
+// dari 2 desimal (misal tax_rate 11.005) dijawab sebagai validation error.
+func ResponseDecodeError(w http.ResponseWriter, err error, message string) {
+	var places *model.DecimalPlacesError
+	if errors.As(err, &places) {
+		ResponseBadRequest(w, http.StatusBadRequest, places.Error(), []FieldError{{Field: places.Name, Message: places.Error()}})
+		return
+	}
+	ResponseBadRequest(w, http.StatusBadRequest, message, nil)
+}
+
+// ResponseError memetakan error domain (apperror) ke HTTP status dan kode error.
+// Error non-domain dijawab 500 dengan fallbackMessage dan dicatat ke log request.
+func ResponseError(w http.ResponseWriter, r *http.Request, err error, fallbackMessage string) {
+	code := apperror.HTTPStatus(err)
+	message := err.Error()
+	if retryAfter := apperror.RetryAfter(err); retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(CeilSeconds(retryAfter)))
+	}
+	if code >= http.StatusInternalServerError {
+		LoggerFromContext(r.Context(), nil).Error(fallbackMessage, zap.Error(err))
+		message = fallbackMessage
+	}
+
+	writeJSON(w, code, Response{
 		Status:  false,
 		Message: message,
-		Errors:  errors,
+		Error: &ErrorBody{
+			Code:    apperror.Code(err),
+			Details: apperror.Details(err),
+		},
+	})
+}
+
+// NewPagination menghitung metadata pagination untuk list response
+func NewPagination(page, limit, total int) dto.Pagination {
+	return dto.Pagination{
+		CurrentPage:  page,
+		Limit:        limit,
+		TotalPages:   TotalPage(limit, int64(total)),
+		TotalRecords: total,
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(response)
 }
 
-func ResponsePagination(w http.ResponseWriter, code int, message string, data any, pagination dto.Pagination) {
-	response := map[string]interface{}{
-		"status":     true,
-		"message":    message,
-		"data":       data,
-		"pagination": pagination,
+// emptySliceIfNil membuat list kosong ter-encode sebagai [] bukan null
+func emptySliceIfNil(data any) any {
+	v := reflect.ValueOf(data)
+	if data == nil {
+		return []any{}
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(response)
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return data
+}
+
+// CeilSeconds membulatkan durasi ke atas dalam detik, supaya tunggu kurang
+// dari satu detik tidak dikirim sebagai Retry-After: 0
+func CeilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
@@ -2,7 +2,10 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -21,3 +24,29 @@ func GenerateRandomToken(length int) (string, error) {
 	}
 	return hex.EncodeToString(bytes), nil
 }
+
+// BearerToken mengambil token dari header "Authorization: Bearer <token>"
+func BearerToken(r *http.Request) string {
+	return authorizationCredentials(r, "Bearer")
+}
+
+// APIKeyToken mengambil key dari header "Authorization: ApiKey <key>"
+func APIKeyToken(r *http.Request) string {
+	return authorizationCredentials(r, "ApiKey")
+}
+
+func authorizationCredentials(r *http.Request, wantScheme string) string {
+	header := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, wantScheme) {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// HashToken mengembalikan SHA-256 (hex) dari token acak sebelum disimpan ke database.
+// Token acak panjang tidak perlu bcrypt, dan hash yang deterministik bisa dicari langsung.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
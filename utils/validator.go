@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"project-app-inventory-restapi-golang-azwin/model"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -45,9 +47,29 @@ type FieldError struct {
 
 // validator object struct message
 func ValidateErrors(data any) ([]FieldError, error) {
+	return fieldErrors(newValidator().Struct(data))
+}
+
+// ValidatePartialErrors hanya memvalidasi field yang disebut (nama field struct),
+// dipakai untuk PATCH agar field yang tidak dikirim tidak kena rule required
+func ValidatePartialErrors(data any, fields ...string) ([]FieldError, error) {
+	return fieldErrors(newValidator().StructPartial(data, fields...))
+}
+
+// newValidator membuat validator dengan rule custom milik aplikasi
+func newValidator() *validator.Validate {
 	validate := validator.New()
+	validate.RegisterValidation("password", validatePasswordPolicy)
+	validate.RegisterValidation("percent", validatePercent)
+	return validate
+}
 
-	err := validate.Struct(data)
+// validatePercent untuk model.TaxRate (basis point): 0-100%
+func validatePercent(fl validator.FieldLevel) bool {
+	return model.TaxRate(fl.Field().Int()).Valid()
+}
+
+func fieldErrors(err error) ([]FieldError, error) {
 	if err == nil {
 		return nil, nil
 	}
@@ -66,8 +88,14 @@ func ValidateErrors(data any) ([]FieldError, error) {
 				message = fmt.Sprintf("%s must be a non-negative number", err.Field())
 			case "min":
 				message = fmt.Sprintf("%s must be at least %s characters long", err.Field(), err.Param())
+			case "percent":
+				message = fmt.Sprintf("%s must be between 0 and 100", err.Field())
 			case "eqfield":
 				message = fmt.Sprintf("%s must match %s", err.Field(), err.Param())
+			case "password":
+				// sebutkan hanya aturan yang belum terpenuhi
+				missing := CurrentPasswordPolicy().Check(fmt.Sprint(err.Value()))
+				message = fmt.Sprintf("%s must contain %s", err.Field(), strings.Join(missing, ", "))
 			default:
 				message = fmt.Sprintf("%s is invalid", err.Field())
 			}